@@ -2,13 +2,15 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"kora_1/internal/config"
+	"kora_1/internal/logging"
 	"kora_1/internal/server"
 )
 
@@ -29,7 +31,12 @@ import (
 
 // @schemes   http https
 
-func gracefulShutdown(apiServer *http.Server, done chan bool) {
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 "Bearer" followed by an access token from /auth/login
+
+func gracefulShutdown(apiServer *http.Server, timeout time.Duration, done chan bool) {
 	// Create context that listens for the interrupt signal from the OS.
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -37,39 +44,52 @@ func gracefulShutdown(apiServer *http.Server, done chan bool) {
 	// Listen for the interrupt signal.
 	<-ctx.Done()
 
-	log.Println("shutting down gracefully, press Ctrl+C again to force")
+	slog.Info("shutting down gracefully, press Ctrl+C again to force")
 	stop() // Allow Ctrl+C to force shutdown
 
-	// The context is used to inform the server it has 5 seconds to finish
-	// the request it is currently handling
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// The context is used to inform the server it has the shutdown timeout
+	// to finish the request it is currently handling
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 	if err := apiServer.Shutdown(ctx); err != nil {
-		log.Printf("Server forced to shutdown with error: %v", err)
+		slog.Error("server forced to shutdown", "error", err.Error())
 	}
 
-	log.Println("Server exiting")
+	slog.Info("server exiting")
 
 	// Notify the main goroutine that the shutdown is complete
 	done <- true
 }
 
 func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Setup(os.Stderr, "info")
+		slog.Error("loading configuration failed", "error", err.Error())
+		os.Exit(1)
+	}
+	logging.Setup(os.Stdout, cfg.Log.Level)
 
-	server := server.NewServer()
+	server, err := server.NewServer(cfg)
+	if err != nil {
+		slog.Error("starting server failed", "error", err.Error())
+		os.Exit(1)
+	}
+	slog.Info("listening", "addr", server.Addr)
 
 	// Create a done channel to signal when the shutdown is complete
 	done := make(chan bool, 1)
 
 	// Run graceful shutdown in a separate goroutine
-	go gracefulShutdown(server, done)
+	go gracefulShutdown(server, cfg.Timeouts.Shutdown, done)
 
-	err := server.ListenAndServe()
+	err = server.ListenAndServe()
 	if err != nil && err != http.ErrServerClosed {
-		panic(fmt.Sprintf("http server error: %s", err))
+		slog.Error("http server error", "error", err.Error())
+		os.Exit(1)
 	}
 
 	// Wait for the graceful shutdown to complete
 	<-done
-	log.Println("Graceful shutdown complete.")
+	slog.Info("graceful shutdown complete")
 }
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"kora_1/internal/auth"
+	"kora_1/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func createAdmin(args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	email := fs.String("email", "", "email address of the administrator")
+	firstName := fs.String("first-name", "", "first name for a new user")
+	surname := fs.String("surname", "", "surname for a new user")
+	fs.Parse(args)
+
+	// The password is taken from the environment so it stays out of shell history.
+	password := os.Getenv("KORA_ADMIN_PASSWORD")
+	*email = strings.TrimSpace(*email)
+	if *email == "" {
+		fs.Usage()
+		return errors.New("-email is required")
+	}
+
+	_, db, err := openDB()
+	if err != nil {
+		return err
+	}
+
+	user, err := models.GetUserByEmail(db, *email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if password == "" {
+			return errors.New("set KORA_ADMIN_PASSWORD to create a new user")
+		}
+		hash, err := auth.HashPassword(password)
+		if err != nil {
+			return err
+		}
+		user = &models.User{FirstName: *firstName, Surname: *surname, Email: *email, Password: hash, Role: models.RoleAdmin}
+		if err := models.CreateUser(db, user); err != nil {
+			return err
+		}
+		fmt.Printf("created administrator %s (%d)\n", user.Email, user.ID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := models.UpdateUserRole(db, user.ID, models.RoleAdmin); err != nil {
+		return err
+	}
+	if password != "" {
+		hash, err := auth.HashPassword(password)
+		if err != nil {
+			return err
+		}
+		err = db.Model(user).Updates(map[string]any{
+			"password":      hash,
+			"token_version": gorm.Expr("token_version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Printf("promoted %s (%d) to administrator\n", user.Email, user.ID)
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"kora_1/internal/collectionio"
+)
+
+func importCollection(args []string) error {
+	fs := flag.NewFlagSet("import-collection", flag.ExitOnError)
+	collectionID := fs.Uint("collection", 0, "collection ID to import into")
+	path := fs.String("file", "", "CSV or JSON file to import, - for stdin")
+	formatFlag := fs.String("format", "", "csv or json, detected from the file name when omitted")
+	fs.Parse(args)
+
+	if *collectionID == 0 || *path == "" {
+		fs.Usage()
+		return fmt.Errorf("-collection and -file are required")
+	}
+
+	format, ok := collectionio.DetectFormat(*path, "")
+	if *formatFlag != "" {
+		var err error
+		if format, err = collectionio.ParseFormat(*formatFlag); err != nil {
+			return err
+		}
+		ok = true
+	}
+	if !ok {
+		return fmt.Errorf("cannot tell the format of %s, pass -format", *path)
+	}
+
+	in, err := input(*path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	rows, rowErrors, err := collectionio.Decode(in, format)
+	if err != nil {
+		return err
+	}
+	result := collectionio.Result{Errors: rowErrors}
+	if len(rowErrors) == 0 {
+		_, db, err := openDB()
+		if err != nil {
+			return err
+		}
+		if result, err = collectionio.Import(db, uint(*collectionID), rows); err != nil {
+			return err
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		for _, e := range result.Errors {
+			fmt.Fprintf(os.Stderr, "row %d (%s): %s\n", e.Row, e.Code, e.Message)
+		}
+		return fmt.Errorf("%d rows failed, nothing was imported", len(result.Errors))
+	}
+	fmt.Printf("created %d, updated %d, unchanged %d\n", result.Created, result.Updated, result.Unchanged)
+	return nil
+}
+
+func exportCollection(args []string) error {
+	fs := flag.NewFlagSet("export-collection", flag.ExitOnError)
+	collectionID := fs.Uint("collection", 0, "collection ID to export")
+	formatFlag := fs.String("format", "csv", "csv or json")
+	path := fs.String("file", "-", "file to write, - for stdout")
+	fs.Parse(args)
+
+	if *collectionID == 0 {
+		fs.Usage()
+		return fmt.Errorf("-collection is required")
+	}
+	format, err := collectionio.ParseFormat(*formatFlag)
+	if err != nil {
+		return err
+	}
+
+	_, db, err := openDB()
+	if err != nil {
+		return err
+	}
+	rows, err := collectionio.Export(db, uint(*collectionID))
+	if err != nil {
+		return err
+	}
+
+	out, err := output(*path)
+	if err != nil {
+		return err
+	}
+	if err := collectionio.Encode(out, format, rows); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"kora_1/internal/models"
+	"kora_1/internal/storage"
+	"kora_1/internal/workflow"
+
+	"gorm.io/gorm"
+)
+
+func purgeDrafts(args []string) error {
+	fs := flag.NewFlagSet("purge-drafts", flag.ExitOnError)
+	olderThan := fs.Duration("older-than", 30*24*time.Hour, "purge drafts not changed for this long")
+	dryRun := fs.Bool("dry-run", false, "only report how many drafts would be purged")
+	fs.Parse(args)
+
+	if *olderThan <= 0 {
+		return fmt.Errorf("-older-than must be positive")
+	}
+
+	cfg, db, err := openDB()
+	if err != nil {
+		return err
+	}
+	ids, err := models.GetStaleSubmissionIDs(db, workflow.Draft, time.Now().Add(-*olderThan))
+	if err != nil {
+		return err
+	}
+	if *dryRun || len(ids) == 0 {
+		fmt.Printf("%d drafts older than %s\n", len(ids), *olderThan)
+		return nil
+	}
+
+	// A storage setting that does not work fails the command before any row goes.
+	files, err := storage.New(cfg.Storage)
+	if err != nil {
+		return err
+	}
+
+	var attachments []models.Attachment
+	err = db.Transaction(func(tx *gorm.DB) error {
+		attachments, err = models.PurgeSubmissions(tx, ids)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Files go only after the rows are gone; a failure leaves an unreferenced object behind, not a broken answer.
+	for _, a := range attachments {
+		if err := files.Delete(context.Background(), a.StorageKey); err != nil {
+			slog.Error("deleting draft file failed", "storage_key", a.StorageKey, "error", err.Error())
+		}
+	}
+	fmt.Printf("purged %d drafts and %d attachments\n", len(ids), len(attachments))
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+
+	"kora_1/internal/formio"
+)
+
+func exportForm(args []string) error {
+	fs := flag.NewFlagSet("export-form", flag.ExitOnError)
+	formID := fs.Uint("form", 0, "form ID to export")
+	version := fs.Int("version", 0, "version to export, defaults to the latest published version")
+	path := fs.String("file", "-", "file to write, - for stdout")
+	fs.Parse(args)
+
+	if *formID == 0 {
+		fs.Usage()
+		return errors.New("-form is required")
+	}
+
+	_, db, err := openDB()
+	if err != nil {
+		return err
+	}
+	doc, err := formio.Export(db, uint(*formID), *version)
+	if err != nil {
+		return err
+	}
+
+	out, err := output(*path)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func importForm(args []string) error {
+	fs := flag.NewFlagSet("import-form", flag.ExitOnError)
+	path := fs.String("file", "", "document written by export-form, - for stdin")
+	serviceID := fs.Uint("service", 0, "service ID to attach the form to, instead of the service named in the document")
+	publish := fs.Bool("publish", false, "publish the imported version instead of leaving it as a draft")
+	fs.Parse(args)
+
+	if *path == "" {
+		fs.Usage()
+		return errors.New("-file is required")
+	}
+
+	in, err := input(*path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	var doc formio.Document
+	dec := json.NewDecoder(in)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("reading %s: %w", *path, err)
+	}
+
+	opts := formio.ImportOptions{Publish: *publish}
+	if *serviceID != 0 {
+		id := uint(*serviceID)
+		opts.ServiceID = &id
+	}
+
+	_, db, err := openDB()
+	if err != nil {
+		return err
+	}
+	form, version, err := formio.Import(db, doc, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created form %q (%d), version %d %s\n", form.FormName, form.ID, version.Version, version.Status)
+	return nil
+}
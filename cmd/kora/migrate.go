@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"kora_1/internal/database"
+	"kora_1/internal/migrations"
+)
+
+func migrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	steps := fs.Int("steps", 1, "number of migrations to revert with down")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: kora migrate up | down [-steps n] | status | to <version>")
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 {
+		fs.Usage()
+		return fmt.Errorf("missing subcommand")
+	}
+	action := args[0]
+	fs.Parse(args[1:])
+
+	cfg, db, err := openDB()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+
+	var changed []migrations.Migration
+	switch action {
+	case "up":
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if err := database.Migrate(ctx, db, cfg.Auth.AdminEmail); err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return nil
+	case "down":
+		if *steps < 1 {
+			return fmt.Errorf("-steps must be at least 1")
+		}
+		changed, err = migrator.Down(ctx, *steps)
+	case "to":
+		if fs.NArg() != 1 {
+			fs.Usage()
+			return fmt.Errorf("to needs a version")
+		}
+		version, perr := strconv.ParseInt(fs.Arg(0), 10, 64)
+		if perr != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", fs.Arg(0))
+		}
+		changed, err = migrator.To(ctx, version)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		return w.Flush()
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown subcommand %q", action)
+	}
+
+	for _, m := range changed {
+		fmt.Printf("%d %s\n", m.Version, m.Name)
+	}
+	if err == nil && len(changed) == 0 {
+		fmt.Println("nothing to do")
+	}
+	return err
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"kora_1/internal/collectionio"
+	"kora_1/internal/datatypes"
+	"kora_1/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func seed(args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	dir := fs.String("collections", "", "directory of CSV or JSON files, one collection per file named after it;\na numeric prefix such as 01- only orders the files")
+	fs.Parse(args)
+
+	_, db, err := openDB()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range datatypes.Names() {
+		_, err := models.GetDataTypeByName(db, name)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := models.CreateDataType(db, &models.DataType{DataType: name}); err != nil {
+			return err
+		}
+		fmt.Printf("data type %s created\n", name)
+	}
+
+	if *dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(*dir)
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	for _, entry := range entries {
+		format, ok := collectionio.DetectFormat(entry.Name(), "")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		if err := seedCollection(db, filepath.Join(*dir, entry.Name()), format); err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+func seedCollection(db *gorm.DB, path string, format collectionio.Format) error {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if prefix, rest, ok := strings.Cut(name, "-"); ok && strings.Trim(prefix, "0123456789") == "" {
+		name = rest
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	rows, rowErrors, err := collectionio.Decode(f, format)
+	if err != nil {
+		return err
+	}
+
+	collection, err := models.GetCollectionByName(db, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		collection = &models.Collection{CollectionName: name}
+		err = models.CreateCollection(db, collection)
+	}
+	if err != nil {
+		return err
+	}
+
+	result := collectionio.Result{Errors: rowErrors}
+	if len(rowErrors) == 0 {
+		if result, err = collectionio.Import(db, collection.ID, rows); err != nil {
+			return err
+		}
+	}
+	if len(result.Errors) > 0 {
+		for _, e := range result.Errors {
+			fmt.Fprintf(os.Stderr, "row %d (%s): %s\n", e.Row, e.Code, e.Message)
+		}
+		return fmt.Errorf("%d rows failed, nothing was imported", len(result.Errors))
+	}
+	fmt.Printf("collection %s (%d): created %d, updated %d, unchanged %d\n", name, collection.ID, result.Created, result.Updated, result.Unchanged)
+	return nil
+}
@@ -24,28 +24,33 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/collection_items": {
+        "/attachments": {
             "post": {
-                "description": "Create a new collection item",
+                "description": "Upload a file for a file-type form field. The field's max_size and mime rules are checked against the file's size and detected content type. Use the returned ID as the field's answer.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "collection-items"
+                    "attachments"
                 ],
-                "summary": "Create collection item",
+                "summary": "Upload a file",
                 "parameters": [
                     {
-                        "description": "Collection Item Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CollectionItemRequest"
-                        }
+                        "type": "integer",
+                        "description": "Form field the file answers",
+                        "name": "form_field_id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to upload",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -62,32 +67,52 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "The file exceeds the upload limit",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "The file breaks the field's max_size or mime rules",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/collection_items/{id}": {
+        "/attachments/{id}": {
             "get": {
-                "description": "Retrieve a collection item by its ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve the name, type, size and hash of an uploaded file",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "collection-items"
+                    "attachments"
                 ],
-                "summary": "Get collection item",
+                "summary": "Get file details",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Collection Item ID",
+                        "description": "Attachment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -112,45 +137,45 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
                     }
-                }
-            },
-            "put": {
-                "description": "Update an existing collection item by its ID",
-                "consumes": [
-                    "application/json"
-                ],
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/attachments/{id}/content": {
+            "get": {
+                "description": "Download the contents of an uploaded file",
                 "produces": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "collection-items"
+                    "attachments"
                 ],
-                "summary": "Update collection item",
+                "summary": "Download a file",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Collection Item ID",
+                        "description": "Attachment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Collection Item Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CollectionItemRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -171,10 +196,17 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
-            },
-            "delete": {
-                "description": "Delete a collection item by its ID",
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Verify a user's email and password and issue an access and refresh token",
                 "consumes": [
                     "application/json"
                 ],
@@ -182,16 +214,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "collection-items"
+                    "auth"
                 ],
-                "summary": "Delete collection item",
+                "summary": "Log in",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Collection Item ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Login Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LoginRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -208,6 +242,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -217,9 +257,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/collections": {
+        "/auth/me": {
             "get": {
-                "description": "Retrieve all collections",
+                "description": "Retrieve the user the access token was issued to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchange a valid refresh token for a new access and refresh token. Changing a user's password revokes the refresh tokens issued before.",
                 "consumes": [
                     "application/json"
                 ],
@@ -227,9 +299,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "collections"
+                    "auth"
+                ],
+                "summary": "Refresh tokens",
+                "parameters": [
+                    {
+                        "description": "Refresh Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RefreshRequest"
+                        }
+                    }
                 ],
-                "summary": "Get all collections",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -238,6 +321,18 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -245,9 +340,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/collection_items": {
             "post": {
-                "description": "Create a new collection with the provided name",
+                "description": "Create a new collection item",
                 "consumes": [
                     "application/json"
                 ],
@@ -255,17 +352,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "collections"
+                    "collection-items"
                 ],
-                "summary": "Create a new collection",
+                "summary": "Create collection item",
                 "parameters": [
                     {
-                        "description": "Collection Request",
+                        "description": "Collection Item Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CollectionRequest"
+                            "$ref": "#/definitions/handlers.CollectionItemRequest"
                         }
                     }
                 ],
@@ -283,18 +380,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/collections/{id}": {
+        "/collection_items/{id}": {
             "get": {
-                "description": "Retrieve a collection by its ID",
+                "description": "Retrieve a collection item by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -302,13 +410,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "collections"
+                    "collection-items"
                 ],
-                "summary": "Get collection by ID",
+                "summary": "Get collection item",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Collection ID",
+                        "description": "Collection Item ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -334,10 +442,15 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "put": {
-                "description": "Update an existing collection by its ID",
+                "description": "Update an existing collection item by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -345,24 +458,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "collections"
+                    "collection-items"
                 ],
-                "summary": "Update collection",
+                "summary": "Update collection item",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Collection ID",
+                        "description": "Collection Item ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Collection Request",
+                        "description": "Collection Item Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CollectionRequest"
+                            "$ref": "#/definitions/handlers.CollectionItemRequest"
                         }
                     }
                 ],
@@ -386,16 +499,27 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "delete": {
-                "description": "Delete a collection by its ID",
+                "description": "Delete a collection item by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -403,13 +527,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "collections"
+                    "collection-items"
                 ],
-                "summary": "Delete collection",
+                "summary": "Delete collection item",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Collection ID",
+                        "description": "Collection Item ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -429,18 +553,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/data_types": {
+        "/collections": {
             "get": {
-                "description": "Retrieve all data types",
+                "description": "Retrieve all collections",
                 "consumes": [
                     "application/json"
                 ],
@@ -448,9 +583,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "data-types"
+                    "collections"
+                ],
+                "summary": "Get all collections",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields, prefix - for descending (id, name)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only names containing this text",
+                        "name": "name",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all data types",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -459,16 +626,27 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
-            },
-            "post": {
-                "description": "Create a new data type",
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a new collection with the provided name",
                 "consumes": [
                     "application/json"
                 ],
@@ -476,17 +654,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "data-types"
+                    "collections"
                 ],
-                "summary": "Create data type",
+                "summary": "Create a new collection",
                 "parameters": [
                     {
-                        "description": "Data Type Request",
+                        "description": "Collection Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.DataTypeRequest"
+                            "$ref": "#/definitions/handlers.CollectionRequest"
                         }
                     }
                 ],
@@ -510,12 +688,17 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/data_types/{id}": {
+        "/collections/{id}": {
             "get": {
-                "description": "Retrieve a data type by its ID",
+                "description": "Retrieve a collection by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -523,13 +706,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "data-types"
+                    "collections"
                 ],
-                "summary": "Get data type",
+                "summary": "Get collection by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Data Type ID",
+                        "description": "Collection ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -555,10 +738,15 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "put": {
-                "description": "Update an existing data type by its ID",
+                "description": "Update an existing collection by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -566,24 +754,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "data-types"
+                    "collections"
                 ],
-                "summary": "Update data type",
+                "summary": "Update collection",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Data Type ID",
+                        "description": "Collection ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Data Type Request",
+                        "description": "Collection Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.DataTypeRequest"
+                            "$ref": "#/definitions/handlers.CollectionRequest"
                         }
                     }
                 ],
@@ -613,10 +801,15 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "delete": {
-                "description": "Delete a data type by its ID",
+                "description": "Delete a collection by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -624,13 +817,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "data-types"
+                    "collections"
                 ],
-                "summary": "Delete data type",
+                "summary": "Delete collection",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Data Type ID",
+                        "description": "Collection ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -650,45 +843,57 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/field": {
-            "post": {
-                "description": "Create a new field",
-                "consumes": [
-                    "application/json"
-                ],
+        "/collections/{id}/export": {
+            "get": {
+                "description": "Download a collection's items as CSV or JSON in the format accepted by the import endpoint",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/plain"
                 ],
                 "tags": [
-                    "fields"
+                    "collections"
                 ],
-                "summary": "Create field",
+                "summary": "Export collection items",
                 "parameters": [
                     {
-                        "description": "Field Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.FieldRequest"
-                        }
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (default) or json",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -697,35 +902,60 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/field/{id}": {
-            "get": {
-                "description": "Retrieve a field by its ID",
+        "/collections/{id}/import": {
+            "post": {
+                "description": "Upsert a collection's items by code from a CSV or JSON document with the columns code, label, parent_code and parent_collection_id. Send the document as the \"file\" field of a multipart form or as the raw request body. The whole import is rolled back if any row fails, and the failing rows are listed.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data",
+                    "application/json",
+                    "text/plain"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "fields"
+                    "collections"
                 ],
-                "summary": "Get field",
+                "summary": "Import collection items",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Field ID",
+                        "description": "Collection ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv or json, detected from the file name or content type when omitted",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV or JSON document",
+                        "name": "file",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
@@ -747,11 +977,36 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
                     }
-                }
-            },
-            "put": {
-                "description": "Update an existing field by its ID",
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/collections/{id}/items": {
+            "get": {
+                "description": "Retrieve the items of a collection. Pass parent_id to get only the items related to that parent item, e.g. the districts of a province.",
                 "consumes": [
                     "application/json"
                 ],
@@ -759,25 +1014,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "fields"
+                    "collections"
                 ],
-                "summary": "Update field",
+                "summary": "List collection items",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Field ID",
+                        "description": "Collection ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Field Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.FieldRequest"
-                        }
+                        "type": "integer",
+                        "description": "Parent collection item ID",
+                        "name": "parent_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -806,10 +1058,17 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
-            },
-            "delete": {
-                "description": "Delete a field by its ID",
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/collections/{id}/tree": {
+            "get": {
+                "description": "Retrieve a collection's top-level items with every related child item nested below them, following relations into other collections (e.g. provinces, their districts and their constituencies).",
                 "consumes": [
                     "application/json"
                 ],
@@ -817,13 +1076,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "fields"
+                    "collections"
                 ],
-                "summary": "Delete field",
+                "summary": "Get collection tree",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Field ID",
+                        "description": "Collection ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -843,18 +1102,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/form": {
-            "post": {
-                "description": "Create a new form with fields",
+        "/data_types": {
+            "get": {
+                "description": "Retrieve all data types",
                 "consumes": [
                     "application/json"
                 ],
@@ -862,23 +1132,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "form"
+                    "data-types"
                 ],
-                "summary": "Create a new form",
+                "summary": "Get all data types",
                 "parameters": [
                     {
-                        "description": "Form Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.FormRequest"
-                        }
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields, prefix - for descending (id, name)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only names containing this text",
+                        "name": "name",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -896,12 +1187,15 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/form/{id}": {
-            "get": {
-                "description": "Retrieve a form by its ID",
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a new data type",
                 "consumes": [
                     "application/json"
                 ],
@@ -909,21 +1203,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "form"
+                    "data-types"
                 ],
-                "summary": "Get form by ID",
+                "summary": "Create data type",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Form ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Data Type Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DataTypeRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -935,42 +1231,40 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
-            },
-            "put": {
-                "description": "Update an existing form by its ID",
-                "consumes": [
-                    "application/json"
-                ],
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/data_types/{id}": {
+            "get": {
+                "description": "Retrieve a data type by its ID",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "form"
+                    "data-types"
                 ],
-                "summary": "Update form",
+                "summary": "Get data type",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Form ID",
+                        "description": "Data Type ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Form Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.FormRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -992,17 +1286,16 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/structs.ErrorResponse"
-                        }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
-            "delete": {
-                "description": "Delete a form by its ID",
+            "put": {
+                "description": "Update an existing data type by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1010,16 +1303,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "form"
+                    "data-types"
                 ],
-                "summary": "Delete form",
+                "summary": "Update data type",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Form ID",
+                        "description": "Data Type ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Data Type Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DataTypeRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -1036,18 +1338,27 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/form_fields": {
-            "post": {
-                "description": "Create a form field association",
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a data type by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1055,23 +1366,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "form-fields"
+                    "data-types"
                 ],
-                "summary": "Create form field",
+                "summary": "Delete data type",
                 "parameters": [
                     {
-                        "description": "Form Field Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.FormFieldRequest"
-                        }
+                        "type": "integer",
+                        "description": "Data Type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1083,18 +1392,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/form_fields/multiple": {
-            "post": {
-                "description": "Create multiple form field associations",
+        "/field": {
+            "get": {
+                "description": "Retrieve a page of the reusable fields forms are built from",
                 "consumes": [
                     "application/json"
                 ],
@@ -1102,21 +1422,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "form-fields"
+                    "fields"
                 ],
-                "summary": "Create multiple form fields",
+                "summary": "List fields",
                 "parameters": [
                     {
-                        "description": "Form Field Requests",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handlers.FormFieldRequest"
-                            }
-                        }
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields, prefix - for descending (id, label)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only labels containing this text",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated data type IDs",
+                        "name": "data_type_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated group IDs",
+                        "name": "group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated collection IDs",
+                        "name": "collection_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Field status",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1139,40 +1501,15 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/form_groups": {
-            "get": {
-                "description": "Retrieve all form groups",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "form-groups"
-                ],
-                "summary": "Get all form groups",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/structs.ErrorResponse"
-                        }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
                     }
-                }
+                ]
             },
             "post": {
-                "description": "Create a new form group",
+                "description": "Create a new field",
                 "consumes": [
                     "application/json"
                 ],
@@ -1180,17 +1517,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "form-groups"
+                    "fields"
                 ],
-                "summary": "Create form group",
+                "summary": "Create field",
                 "parameters": [
                     {
-                        "description": "Form Group Request",
+                        "description": "Field Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.FormGroupRequest"
+                            "$ref": "#/definitions/handlers.FieldRequest"
                         }
                     }
                 ],
@@ -1214,12 +1551,17 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/form_groups/{id}": {
+        "/field/{id}": {
             "get": {
-                "description": "Retrieve a form group by its ID",
+                "description": "Retrieve a field by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1227,13 +1569,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "form-groups"
+                    "fields"
                 ],
-                "summary": "Get form group",
+                "summary": "Get field",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Form Group ID",
+                        "description": "Field ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1259,10 +1601,15 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "put": {
-                "description": "Update an existing form group by its ID",
+                "description": "Update an existing field by its ID. Once a published form version places the field only its status can change; create a new field and place it on the form's draft instead.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1270,24 +1617,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "form-groups"
+                    "fields"
                 ],
-                "summary": "Update form group",
+                "summary": "Update field",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Form Group ID",
+                        "description": "Field ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Form Group Request",
+                        "description": "Field Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.FormGroupRequest"
+                            "$ref": "#/definitions/handlers.FieldRequest"
                         }
                     }
                 ],
@@ -1311,16 +1658,27 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "A published form version places the field",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "delete": {
-                "description": "Delete a form group by its ID",
+                "description": "Delete a field by its ID. Fields placed on a published form version cannot be deleted.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1328,13 +1686,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "form-groups"
+                    "fields"
                 ],
-                "summary": "Delete form group",
+                "summary": "Delete field",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Form Group ID",
+                        "description": "Field ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1354,18 +1712,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/groups": {
+        "/form": {
             "get": {
-                "description": "Retrieve all groups",
+                "description": "Retrieve a page of forms, optionally only those of one service",
                 "consumes": [
                     "application/json"
                 ],
@@ -1373,27 +1742,88 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "form"
                 ],
-                "summary": "Get all groups",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
+                "summary": "List forms",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/structs.ErrorResponse"
-                        }
-                    }
-                }
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields, prefix - for descending (id, name)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only names containing this text",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated service IDs",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated data type IDs",
+                        "name": "data_type_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Form status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "post": {
-                "description": "Create a new group",
+                "description": "Create a new form with fields. The form and its fields are saved in a single transaction as draft version 1; publish it before accepting submissions.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1401,17 +1831,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "form"
                 ],
-                "summary": "Create group",
+                "summary": "Create a new form",
                 "parameters": [
                     {
-                        "description": "Group Request",
+                        "description": "Form Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.GroupRequest"
+                            "$ref": "#/definitions/handlers.FormRequest"
                         }
                     }
                 ],
@@ -1435,12 +1865,17 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/groups/{id}": {
+        "/form/{id}": {
             "get": {
-                "description": "Retrieve a group by its ID",
+                "description": "Retrieve a form version with its groups, fields, data types, validation rules and collection options in layout order.\nWithout a version the latest published version is returned, or the draft when the form was never published.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1448,16 +1883,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "form"
                 ],
-                "summary": "Get group",
+                "summary": "Get form definition by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
+                        "description": "Form ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version number, or \\",
+                        "name": "version",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1479,11 +1920,22 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "put": {
-                "description": "Update an existing group by its ID",
+                "description": "Update a form by its ID. Name and description changes go to the form's draft version, which is created\nfrom the latest version when none exists; published versions are never modified.\nOnce a version is published, the data type, service and status of the form can no longer change.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1491,24 +1943,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "form"
                 ],
-                "summary": "Update group",
+                "summary": "Update form",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
+                        "description": "Form ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Group Request",
+                        "description": "Form Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.GroupRequest"
+                            "$ref": "#/definitions/handlers.FormRequest"
                         }
                     }
                 ],
@@ -1532,16 +1984,27 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "The form has a published version and the data type, service or status changed",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
             "delete": {
-                "description": "Delete a group by its ID",
+                "description": "Delete a form by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1549,13 +2012,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "form"
                 ],
-                "summary": "Delete group",
+                "summary": "Delete form",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Group ID",
+                        "description": "Form ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1575,18 +2038,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/reserved-name": {
-            "post": {
-                "description": "Create a new reserved name",
+        "/form/{id}/fields": {
+            "get": {
+                "description": "Retrieve the form fields of a form version in layout order, without the nested definition returned by GET /form/{id}.\nWithout a version the latest published version is used, or the draft when the form was never published.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1594,23 +2068,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reserved-name"
+                    "form"
                 ],
-                "summary": "Create reserved name",
+                "summary": "List form fields",
                 "parameters": [
                     {
-                        "description": "Reserved Name Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ReservedNameRequest"
-                        }
+                        "type": "integer",
+                        "description": "Form ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version number, or \\",
+                        "name": "version",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1622,18 +2100,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/reserved-name/{id}": {
-            "delete": {
-                "description": "Delete a reserved name by its ID",
+        "/form/{id}/publish": {
+            "post": {
+                "description": "Freeze the form's draft version so it accepts submissions. Published versions can no longer be edited.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1641,13 +2130,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reserved-name"
+                    "form"
                 ],
-                "summary": "Delete reserved name",
+                "summary": "Publish form",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Reserved Name ID",
+                        "description": "Form ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1667,18 +2156,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/reserved-name/{name}": {
+        "/form/{id}/versions": {
             "get": {
-                "description": "Retrieve reserved names that match or are similar to the provided name parameter",
+                "description": "Retrieve every draft and published version of a form, oldest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -1686,14 +2192,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reserved-name"
+                    "form"
                 ],
-                "summary": "Get reserved names",
+                "summary": "List form versions",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Name to search for",
-                        "name": "name",
+                        "type": "integer",
+                        "description": "Form ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -1712,18 +2218,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/services": {
-            "get": {
-                "description": "Retrieve all services",
+        "/form_fields": {
+            "post": {
+                "description": "Add a field to the form's draft version, creating the draft from the latest version when none exists",
                 "consumes": [
                     "application/json"
                 ],
@@ -1731,27 +2248,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "services"
+                    "form-fields"
+                ],
+                "summary": "Create form field",
+                "parameters": [
+                    {
+                        "description": "Form Field Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FormFieldRequest"
+                        }
+                    }
                 ],
-                "summary": "Get all services",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
-            },
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/form_fields/multiple": {
             "post": {
-                "description": "Create a new service with the provided name",
+                "description": "Add fields to the draft versions of their forms. Either all fields are saved or none are.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1759,23 +2306,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "services"
+                    "form-fields"
                 ],
-                "summary": "Create a new service",
+                "summary": "Create multiple form fields",
                 "parameters": [
                     {
-                        "description": "Service Request",
+                        "description": "Form Field Requests",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.ServiceRequest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.FormFieldRequest"
+                            }
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1787,18 +2337,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/services/{id}": {
+        "/form_fields/{id}": {
             "get": {
-                "description": "Retrieve a service by its ID",
+                "description": "Retrieve a field's placement on a form version by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1806,13 +2367,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "services"
+                    "form-fields"
                 ],
-                "summary": "Get service by ID",
+                "summary": "Get form field",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Service ID",
+                        "description": "Form Field ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1838,10 +2399,17 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
-            },
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/form_fields/{id}/dependency": {
             "put": {
-                "description": "Update an existing service by its ID",
+                "description": "Make a collection-backed draft field cascade from another collection field of the same form version: its options are the children of the item chosen for that field. Send null to remove the dependency.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1849,24 +2417,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "services"
+                    "form-fields"
                 ],
-                "summary": "Update a service",
+                "summary": "Set form field dependency",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Service ID",
+                        "description": "Form Field ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Service Request",
+                        "description": "Dependency Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.ServiceRequest"
+                            "$ref": "#/definitions/handlers.FormFieldDependencyRequest"
                         }
                     }
                 ],
@@ -1890,16 +2458,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "The field belongs to a published version",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
-            },
-            "delete": {
-                "description": "Delete a service by its ID",
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/form_groups": {
+            "get": {
+                "description": "Retrieve all form groups",
                 "consumes": [
                     "application/json"
                 ],
@@ -1907,16 +2488,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "services"
+                    "form-groups"
                 ],
-                "summary": "Delete a service",
+                "summary": "Get all form groups",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Service ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields, prefix - for descending (id, name, row)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only names containing this text",
+                        "name": "name",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1939,12 +2543,15 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/submission": {
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
             "post": {
-                "description": "Create a new form submission",
+                "description": "Create a new form group",
                 "consumes": [
                     "application/json"
                 ],
@@ -1952,17 +2559,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "submissions"
+                    "form-groups"
                 ],
-                "summary": "Submit a form",
+                "summary": "Create form group",
                 "parameters": [
                     {
-                        "description": "Submission Request",
+                        "description": "Form Group Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.SubmitFormRequest"
+                            "$ref": "#/definitions/handlers.FormGroupRequest"
                         }
                     }
                 ],
@@ -1986,12 +2593,17 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/submission/service/{service_id}": {
+        "/form_groups/{id}": {
             "get": {
-                "description": "Retrieve all submissions for a specific service by its Service ID",
+                "description": "Retrieve a form group by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1999,14 +2611,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "submissions"
+                    "form-groups"
                 ],
-                "summary": "Get submissions by Service ID",
+                "summary": "Get form group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Service ID",
-                        "name": "service_id",
+                        "description": "Form Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -2025,18 +2637,21 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/submission/{id}": {
-            "get": {
-                "description": "Retrieve a submission by its ID",
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Update an existing form group by its ID, unless a published form version lays fields out in it",
                 "consumes": [
                     "application/json"
                 ],
@@ -2044,16 +2659,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "submissions"
+                    "form-groups"
                 ],
-                "summary": "Get submission by ID",
+                "summary": "Update form group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Submission ID",
+                        "description": "Form Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Form Group Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FormGroupRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -2076,18 +2700,27 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "A published form version uses the form group",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/users": {
-            "post": {
-                "description": "Create a new user",
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a form group by its ID, unless a published form version lays fields out in it",
                 "consumes": [
                     "application/json"
                 ],
@@ -2095,23 +2728,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "form-groups"
                 ],
-                "summary": "Create user",
+                "summary": "Delete form group",
                 "parameters": [
                     {
-                        "description": "User Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.UserRequest"
-                        }
+                        "type": "integer",
+                        "description": "Form Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -2123,18 +2754,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/users/{id}": {
+        "/groups": {
             "get": {
-                "description": "Retrieve a user by its ID",
+                "description": "Retrieve all groups",
                 "consumes": [
                     "application/json"
                 ],
@@ -2142,16 +2784,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "groups"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Get all groups",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields, prefix - for descending (id, name)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only names containing this text",
+                        "name": "name",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2168,16 +2833,21 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             },
-            "put": {
-                "description": "Update an existing user by its ID",
+            "post": {
+                "description": "Create a new group",
                 "consumes": [
                     "application/json"
                 ],
@@ -2185,30 +2855,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "groups"
                 ],
-                "summary": "Update user",
+                "summary": "Create group",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "User Request",
+                        "description": "Group Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.UserRequest"
+                            "$ref": "#/definitions/handlers.GroupRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -2220,22 +2883,23 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/structs.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
-            },
-            "delete": {
-                "description": "Delete a user by its ID",
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/groups/{id}": {
+            "get": {
+                "description": "Retrieve a group by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2243,13 +2907,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "groups"
                 ],
-                "summary": "Delete user",
+                "summary": "Get group",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2269,20 +2933,3383 @@ const docTemplate = `{
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/structs.ErrorResponse"
                         }
                     }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "handlers.CollectionItemRequest": {
-            "type": "object",
-            "properties": {
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Update an existing group by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Update group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Group Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a group by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Delete group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reserved-name": {
+            "get": {
+                "description": "Retrieve a page of reservations, including expired and released ones unless filtered by status. Names managers see every reservation and other users only their own; use GET /reserved-name/{name} to check whether a name is free. Unlike that check the name filter is a plain substring match.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reserved-name"
+                ],
+                "summary": "List reserved names",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields, prefix - for descending (id, name, reserved_on, expires_on)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only names containing this text",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only reservations in this status (held, expired, released)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only reservations held by this user (names managers)",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only reservations for this service",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only reservations that do, or do not, need review",
+                        "name": "needs_review",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Hold a name for the current user and a service for the configured hold period, after which the reservation expires unless renewed. The name is checked as GET /reserved-name/{name} checks it: one the check finds unavailable because a held name is the same or nearly so answers 409 NAME_TAKEN, and one using a prohibited term 422 NAME_PROHIBITED. A name the check sends for review, because it resembles a held name or uses a restricted term, is reserved with needs_review set, and terms lists the restricted terms it uses.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reserved-name"
+                ],
+                "summary": "Reserve a name",
+                "parameters": [
+                    {
+                        "description": "Reserved Name Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReservedNameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reserved-name/{id}": {
+            "delete": {
+                "description": "Delete a reservation by its ID. To free a name and keep the record, release it instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reserved-name"
+                ],
+                "summary": "Delete reserved name",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reserved Name ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reserved-name/{id}/release": {
+            "post": {
+                "description": "Give up a held name so others can reserve it. Only the holder or a names manager may release it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reserved-name"
+                ],
+                "summary": "Release a reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reserved Name ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reserved-name/{id}/renew": {
+            "post": {
+                "description": "Hold a reserved name for another hold period from now. Only the holder or a names manager may renew, and only while the reservation is held and has not lapsed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reserved-name"
+                ],
+                "summary": "Renew a reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reserved Name ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reserved-name/{name}": {
+            "get": {
+                "description": "Compare a name with the names currently held, ignoring case, punctuation and legal suffixes such as Ltd or PLC, by trigram similarity and by sound, and check it against the restricted terms. The verdict is unavailable when a reserved name is the same or nearly so or the name uses a prohibited term, review when a reserved name resembles it or it uses a restricted term, and available otherwise. Conflicts lists the resembling names, most similar first, and terms the restricted terms used.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reserved-name"
+                ],
+                "summary": "Check name availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name to check",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/restricted-terms": {
+            "get": {
+                "description": "Retrieve a page of the terms names may not use (prohibited) or may use only with approval (restricted)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restricted-terms"
+                ],
+                "summary": "List restricted terms",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields, prefix - for descending (id, term, severity)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only terms containing this text",
+                        "name": "term",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only prohibited or only restricted terms",
+                        "name": "severity",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Add a term that names may not use (prohibited) or may use only with approval (restricted). Terms match whole words, ignoring case, accents and punctuation, and the last word also matches inflections such as a plural.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restricted-terms"
+                ],
+                "summary": "Create restricted term",
+                "parameters": [
+                    {
+                        "description": "Restricted Term Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RestrictedTermRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/restricted-terms/{id}": {
+            "get": {
+                "description": "Retrieve a prohibited or restricted term by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restricted-terms"
+                ],
+                "summary": "Get restricted term",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Restricted Term ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Change a restricted term, its severity or its reason",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restricted-terms"
+                ],
+                "summary": "Update restricted term",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Restricted Term ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Restricted Term Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RestrictedTermRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a restricted term by its ID. Reservations already flagged for review stay flagged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restricted-terms"
+                ],
+                "summary": "Delete restricted term",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Restricted Term ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/services": {
+            "get": {
+                "description": "Retrieve all services",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get all services",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields, prefix - for descending (id, name)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only names containing this text",
+                        "name": "name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a new service with the provided name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Create a new service",
+                "parameters": [
+                    {
+                        "description": "Service Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ServiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/services/{id}": {
+            "get": {
+                "description": "Retrieve a service by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get service by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Update an existing service by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Update a service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Service Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ServiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a service by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Delete a service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/services/{id}/notification-templates": {
+            "get": {
+                "description": "Retrieve the subject and body of every email a service sends: submission_receipt, review_requested, status_change and reservation_expiry. Kinds the service has not customised show the default.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "List notification templates",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/services/{id}/notification-templates/{kind}": {
+            "put": {
+                "description": "Set the subject and body a service's emails of the kind are rendered from. Both are Go text templates executed with .Recipient.Name, .Recipient.Email, .Service,\n.Submission (ID, FormName, Status, FromStatus, Comment) for submission emails and .Reservation (ID, Name, ExpiresOn) for reservation_expiry; humanize turns under_review into \"under review\".\nA template that does not parse, or uses data its kind does not have, is rejected with INVALID_TEMPLATE.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Save notification template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Notification kind",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Notification Template Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.NotificationTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Remove a service's template of the kind, so that its emails use the default again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Delete notification template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Notification kind",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/services/{id}/webhooks": {
+            "get": {
+                "description": "Retrieve a page of the webhooks that receive the events of a service. Secrets are not returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List service webhooks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields, prefix - for descending (id, url, created_on)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only active or only inactive webhooks",
+                        "name": "active",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Subscribe a URL to the events of a service: submission.created, submission.status_changed and form.published, or every event when events is empty.\nEach delivery is a JSON POST signed with the secret: X-Kora-Signature is sha256= and the hex HMAC-SHA256 of X-Kora-Timestamp, a dot and the body. The secret is returned only here.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create service webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/services/{id}/workflow": {
+            "get": {
+                "description": "Retrieve the transitions a service's submissions may take. Services without their own configuration use the default lifecycle.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get service workflow",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replace the transitions a service's submissions may take. Send an empty list to go back to the default lifecycle.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Update service workflow",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Workflow Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WorkflowRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/submission": {
+            "get": {
+                "description": "Retrieve a page of submissions across services, newest first. Admins see all, reviewers those of their assigned services and applicants their own.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "submissions"
+                ],
+                "summary": "List submissions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields, prefix - for descending (id, created_on, updated_on, status)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated service IDs",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated statuses",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated user IDs",
+                        "name": "created_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated form version IDs",
+                        "name": "form_version_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or after, YYYY-MM-DD or RFC 3339",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or before, YYYY-MM-DD or RFC 3339",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Updated on or after, YYYY-MM-DD or RFC 3339",
+                        "name": "updated_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Updated on or before, YYYY-MM-DD or RFC 3339",
+                        "name": "updated_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a new form submission against a published form version. The submission and its answers are saved in a single transaction.\nSend an Idempotency-Key header to make retries safe: a repeated key returns the original submission with 200, and reusing it for a different request is refused with 422.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "submissions"
+                ],
+                "summary": "Submit a form",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client-generated key identifying this submit attempt",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Submission Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SubmitFormRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The form has no published version, the service's workflow does not let applicants submit, or the Idempotency-Key belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Answers failed type coercion or validation, services_id is not the form's service, or the Idempotency-Key was sent with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/submission/draft": {
+            "post": {
+                "description": "Start a draft submission against a published form version. Answers are type-checked and validated, but required fields may be left blank until the draft is submitted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "submissions"
+                ],
+                "summary": "Save a draft",
+                "parameters": [
+                    {
+                        "description": "Draft Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DraftRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The form has no published version",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Answers failed type coercion or validation, or services_id is not the form's service",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/submission/drafts": {
+            "get": {
+                "description": "Retrieve the current user's unsubmitted drafts with their saved answers, most recently updated first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "submissions"
+                ],
+                "summary": "List my drafts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/submission/service/{service_id}": {
+            "get": {
+                "description": "Retrieve a page of submissions for a specific service by its Service ID, newest first. Applicants only see their own submissions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "submissions"
+                ],
+                "summary": "Get submissions by Service ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "service_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields, prefix - for descending (id, created_on, updated_on, status)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated statuses",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated user IDs",
+                        "name": "created_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated form version IDs",
+                        "name": "form_version_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or after, YYYY-MM-DD or RFC 3339",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or before, YYYY-MM-DD or RFC 3339",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Updated on or after, YYYY-MM-DD or RFC 3339",
+                        "name": "updated_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Updated on or before, YYYY-MM-DD or RFC 3339",
+                        "name": "updated_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Reviewer is not assigned to the service",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/submission/{id}": {
+            "get": {
+                "description": "Retrieve a submission by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "submissions"
+                ],
+                "summary": "Get submission by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Submission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/submission/{id}/answers": {
+            "get": {
+                "description": "Retrieve the typed answers of a submission the caller may read",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "submissions"
+                ],
+                "summary": "List submission answers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Submission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Save or replace individual answers of a draft, or of a submission returned for correction. A blank answer clears the field. Required fields are not enforced until submit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "submissions"
+                ],
+                "summary": "Update draft answers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Submission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Draft Answers Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DraftAnswersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The submission can no longer be edited",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Answers failed type coercion or validation",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/submission/{id}/attachments": {
+            "get": {
+                "description": "Retrieve the details of the files a submission's answers reference",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "submissions"
+                ],
+                "summary": "List submission files",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Submission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/submission/{id}/history": {
+            "get": {
+                "description": "Retrieve every status change of a submission, oldest first, with who made it and their comment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "submissions"
+                ],
+                "summary": "Get submission history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Submission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/submission/{id}/submit": {
+            "post": {
+                "description": "Validate every answer of a draft, or of a submission returned for correction, including required fields, and move it to submitted, or to the status its service's workflow lets the applicant submit to.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "submissions"
+                ],
+                "summary": "Submit a draft",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Submission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The submission can no longer be edited, or the service's workflow does not let applicants submit it",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Answers failed validation",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/submission/{id}/transition": {
+            "post": {
+                "description": "Move a submission along its service's lifecycle. Applicants may move their own submissions and reviewers those of their services, each only along transitions granted to them.\nDrafts are submitted with POST /submission/{id}/submit, which checks every answer first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "submissions"
+                ],
+                "summary": "Transition submission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Submission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transition Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TransitionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The transition is not allowed from the current status",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Retrieve a page of users. search matches any part of a user's names or email.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields, prefix - for descending (id, email, first_name, surname)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text to find in names or email",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only emails containing this text",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated roles",
+                        "name": "role",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Register a new user. New users always start with the applicant role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create user",
+                "parameters": [
+                    {
+                        "description": "User Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Retrieve a user by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Update an existing user by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a user by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
+                "description": "Assign one of the roles admin, designer, reviewer or applicant to a user. Admins cannot change their own role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Set user role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/services": {
+            "get": {
+                "description": "Retrieve the services whose submissions a reviewer may review",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List reviewer services",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Allow a reviewer to review the submissions of a service",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Assign reviewer to service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reviewer Service Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReviewerServiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/services/{service_id}": {
+            "delete": {
+                "description": "Stop a reviewer from reviewing the submissions of a service",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Remove reviewer from service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "service_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "description": "Retrieve a webhook by its ID, without its secret",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Change a webhook's URL, events or active flag, and replace its secret when one is sent. Pending deliveries of an inactive webhook wait until it is active again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a webhook and its delivery log",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Retrieve a page of a webhook's deliveries, newest first: their status, attempts, the receiver's last answer and when the next attempt is due.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rows to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated sort fields, prefix - for descending (id, created_on, status)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only pending, succeeded or failed deliveries",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "description": "Queue a delivery to be sent again as soon as possible with a fresh set of attempts, whether it failed, succeeded or is still pending. The body and X-Kora-Delivery stay the same.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver webhook delivery",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/structs.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        }
+    },
+    "definitions": {
+        "handlers.AnswerRequest": {
+            "type": "object",
+            "required": [
+                "form_field_id"
+            ],
+            "properties": {
+                "answer": {
+                    "type": "string"
+                },
+                "form_field_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.CollectionItemRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Defaults to the item ID; kept as is on update when blank",
+                    "type": "string",
+                    "maxLength": 50
+                },
                 "collection_id": {
                     "type": "integer"
                 },
@@ -2316,6 +6343,40 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.DraftAnswersRequest": {
+            "type": "object",
+            "required": [
+                "answers"
+            ],
+            "properties": {
+                "answers": {
+                    "description": "A blank answer clears the field",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/handlers.AnswerRequest"
+                    }
+                }
+            }
+        },
+        "handlers.DraftRequest": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.AnswerRequest"
+                    }
+                },
+                "form_id": {
+                    "type": "integer"
+                },
+                "services_id": {
+                    "description": "Optional; defaults to, and must match, the form's service",
+                    "type": "integer"
+                }
+            }
+        },
         "handlers.FieldRequest": {
             "type": "object",
             "required": [
@@ -2340,6 +6401,15 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.FormFieldDependencyRequest": {
+            "type": "object",
+            "properties": {
+                "depends_on_form_field_id": {
+                    "description": "Null removes the dependency",
+                    "type": "integer"
+                }
+            }
+        },
         "handlers.FormFieldReference": {
             "type": "object",
             "required": [
@@ -2367,6 +6437,10 @@ const docTemplate = `{
                 "form_id"
             ],
             "properties": {
+                "depends_on_form_field_id": {
+                    "description": "Collection field whose answer filters this field's options",
+                    "type": "integer"
+                },
                 "field_id": {
                     "type": "integer"
                 },
@@ -2442,14 +6516,99 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.NotificationTemplateRequest": {
+            "type": "object",
+            "required": [
+                "body",
+                "subject"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 20000,
+                    "example": "Hello {{.Recipient.Name}},\n\nWe received your submission."
+                },
+                "subject": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Submission #{{.Submission.ID}} received"
+                }
+            }
+        },
+        "handlers.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
         "handlers.ReservedNameRequest": {
             "type": "object",
             "required": [
-                "reserved_name"
+                "reserved_name",
+                "service_id"
             ],
             "properties": {
                 "reserved_name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "service_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.RestrictedTermRequest": {
+            "type": "object",
+            "required": [
+                "severity",
+                "term"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "severity": {
+                    "type": "string",
+                    "enum": [
+                        "prohibited",
+                        "restricted"
+                    ]
+                },
+                "term": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "handlers.ReviewerServiceRequest": {
+            "type": "object",
+            "required": [
+                "service_id"
+            ],
+            "properties": {
+                "service_id": {
+                    "type": "integer"
                 }
             }
         },
@@ -2473,17 +6632,38 @@ const docTemplate = `{
                 "answers": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.FormAnswer"
+                        "$ref": "#/definitions/handlers.AnswerRequest"
                     }
                 },
                 "created_by": {
+                    "description": "Admins only; everyone else submits as themselves",
+                    "type": "integer"
+                },
+                "form_id": {
                     "type": "integer"
                 },
                 "services_id": {
+                    "description": "Optional; defaults to, and must match, the form's service",
                     "type": "integer"
                 }
             }
         },
+        "handlers.TransitionRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "status": {
+                    "type": "string",
+                    "example": "under_review"
+                }
+            }
+        },
         "handlers.UserRequest": {
             "type": "object",
             "required": [
@@ -2511,280 +6691,148 @@ const docTemplate = `{
                 }
             }
         },
-        "models.Collection": {
+        "handlers.UserRoleRequest": {
             "type": "object",
+            "required": [
+                "role"
+            ],
             "properties": {
-                "collectionName": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
+                "role": {
+                    "type": "string",
+                    "example": "reviewer"
                 }
             }
         },
-        "models.DataType": {
+        "handlers.WebhookRequest": {
             "type": "object",
+            "required": [
+                "url"
+            ],
             "properties": {
-                "dataType": {
-                    "type": "string"
+                "active": {
+                    "description": "Defaults to true",
+                    "type": "boolean"
                 },
-                "id": {
-                    "type": "integer"
+                "events": {
+                    "description": "Empty subscribes to every event",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "Generated when omitted on create; replaced when set on update",
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "https://payments.example.com/hooks/kora"
                 }
             }
         },
-        "models.Field": {
+        "handlers.WorkflowRequest": {
             "type": "object",
             "properties": {
-                "collection": {
-                    "$ref": "#/definitions/models.Collection"
-                },
-                "collectionID": {
-                    "type": "integer"
-                },
-                "dataType": {
-                    "description": "Associations",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.DataType"
-                        }
-                    ]
-                },
-                "dataTypeID": {
-                    "type": "integer"
-                },
-                "group": {
-                    "$ref": "#/definitions/models.Group"
-                },
-                "groupID": {
-                    "type": "integer"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "label": {
-                    "type": "string"
-                },
-                "status": {
-                    "description": "Using pointer for nullable boolean",
-                    "type": "boolean"
+                "transitions": {
+                    "description": "An empty list restores the default lifecycle",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/workflow.Transition"
+                    }
                 }
             }
         },
-        "models.Form": {
+        "structs.ErrorResponse": {
             "type": "object",
             "properties": {
-                "dataType": {
-                    "$ref": "#/definitions/models.DataType"
+                "code": {
+                    "type": "string",
+                    "example": "FORM_NOT_FOUND"
                 },
-                "dataTypeID": {
-                    "description": "Added as required",
-                    "type": "integer"
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/structs.FieldError"
+                    }
                 },
-                "description": {
+                "error": {
                     "type": "string"
                 },
-                "formName": {
+                "request_id": {
+                    "description": "Quote when reporting a problem; it finds the request in the logs",
                     "type": "string"
                 },
-                "id": {
-                    "description": "No auto-increment in schema based on \"id INT\" vs \"INT NOT NULL GENERATED BY DEFAULT...\"? Ah wait, schema says \"id INT\". It doesn't denote \"GENERATED\". Usually primary keys are generated. I will assume it's NOT auto-increment if the schema explicitly omitted it, or it might be input-provided. But typically for 'id' PK it is. The schema provided for 'forms' says: \"id INT, ... CONSTRAINT pk_forms_id PRIMARY KEY (id)\". It lacks \"GENERATED BY DEFAULT AS IDENTITY\". I will NOT add autoIncrement to be safe, or user might provide IDs. But wait, typically models use uint for ID.",
-                    "type": "integer"
-                },
-                "service": {
-                    "description": "Associations",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Service"
-                        }
-                    ]
-                },
-                "serviceID": {
-                    "type": "integer"
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/structs.RowError"
+                    }
                 },
                 "status": {
                     "type": "boolean"
                 }
             }
         },
-        "models.FormAnswer": {
-            "type": "object",
-            "properties": {
-                "answer": {
-                    "type": "string"
-                },
-                "formField": {
-                    "description": "Associations",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.FormFields"
-                        }
-                    ]
-                },
-                "formFieldID": {
-                    "type": "integer"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "submission": {
-                    "$ref": "#/definitions/models.Submission"
-                },
-                "submissionID": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.FormFields": {
+        "structs.FieldError": {
             "type": "object",
             "properties": {
                 "field": {
-                    "$ref": "#/definitions/models.Field"
-                },
-                "fieldID": {
-                    "type": "integer"
-                },
-                "fieldName": {
+                    "description": "JSON name of the request body field",
                     "type": "string"
                 },
-                "fieldRow": {
-                    "type": "integer"
-                },
-                "fieldSpan": {
-                    "type": "integer"
-                },
-                "form": {
-                    "description": "Associations",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Form"
-                        }
-                    ]
-                },
-                "formGroup": {
-                    "$ref": "#/definitions/models.FormGroup"
-                },
-                "formGroupID": {
-                    "type": "integer"
-                },
-                "formID": {
+                "form_field_id": {
                     "type": "integer"
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "validation": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.FormGroup": {
-            "type": "object",
-            "properties": {
-                "groupName": {
+                "message": {
                     "type": "string"
                 },
-                "groupRow": {
-                    "type": "integer"
-                },
-                "groupSpan": {
-                    "type": "integer"
-                },
-                "id": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.Group": {
-            "type": "object",
-            "properties": {
-                "groupName": {
+                "rule": {
                     "type": "string"
-                },
-                "id": {
-                    "type": "integer"
                 }
             }
         },
-        "models.Service": {
+        "structs.RowError": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
-                },
-                "serviceName": {
+                "code": {
                     "type": "string"
-                }
-            }
-        },
-        "models.Submission": {
-            "type": "object",
-            "properties": {
-                "createdBy": {
-                    "type": "integer"
                 },
-                "createdOn": {
+                "message": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "service": {
-                    "description": "Associations",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Service"
-                        }
-                    ]
-                },
-                "servicesID": {
+                "row": {
                     "type": "integer"
-                },
-                "user": {
-                    "$ref": "#/definitions/models.User"
                 }
             }
         },
-        "models.User": {
+        "workflow.Transition": {
             "type": "object",
             "properties": {
-                "dob": {
-                    "type": "string"
-                },
-                "email": {
+                "actor": {
                     "type": "string"
                 },
-                "firstName": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "middleName": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
+                "comment_required": {
+                    "type": "boolean"
                 },
-                "surname": {
+                "from": {
                     "type": "string"
-                }
-            }
-        },
-        "structs.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer"
                 },
-                "error": {
+                "to": {
                     "type": "string"
-                },
-                "status": {
-                    "type": "boolean"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "\"Bearer\" followed by an access token from /auth/login",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
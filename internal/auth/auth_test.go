@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"kora_1/internal/config"
+	"kora_1/internal/models"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsHashed(hash) {
+		t.Errorf("IsHashed(%q) = false, want true", hash)
+	}
+	if IsHashed("s3cret") {
+		t.Error("IsHashed(plaintext) = true, want false")
+	}
+	if !CheckPassword(hash, "s3cret") {
+		t.Error("CheckPassword rejected the correct password")
+	}
+	if CheckPassword(hash, "wrong") {
+		t.Error("CheckPassword accepted a wrong password")
+	}
+}
+
+func TestTokens(t *testing.T) {
+	cfg := config.Default().Auth
+	cfg.JWTSecret = "test-secret"
+	issuer := NewTokens(cfg)
+
+	user := &models.User{ID: 42, TokenVersion: 3}
+	tokens, err := issuer.Issue(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := issuer.Parse(tokens.AccessToken, AccessToken)
+	if err != nil {
+		t.Fatalf("Parse(access) error = %v", err)
+	}
+	if id, _ := claims.UserID(); id != 42 {
+		t.Errorf("UserID() = %d, want 42", id)
+	}
+	if claims.Revoked(user) {
+		t.Error("Revoked() = true before the password changed")
+	}
+	user.SetPassword("new hash")
+	if !claims.Revoked(user) {
+		t.Error("Revoked() = false after the password changed")
+	}
+
+	if _, err := issuer.Parse(tokens.RefreshToken, AccessToken); err == nil {
+		t.Error("refresh token accepted as an access token")
+	}
+	if _, err := issuer.Parse(tokens.AccessToken+"x", AccessToken); err == nil {
+		t.Error("tampered token accepted")
+	}
+
+	cfg.JWTSecret = "other-secret"
+	if _, err := NewTokens(cfg).Parse(tokens.AccessToken, AccessToken); err == nil {
+		t.Error("token signed with a different secret accepted")
+	}
+	if _, err := NewTokens(config.Auth{}).Issue(&models.User{ID: 42}); err != ErrNotConfigured {
+		t.Errorf("Issue() without a secret: error = %v, want ErrNotConfigured", err)
+	}
+}
+
+func TestCan(t *testing.T) {
+	tests := []struct {
+		role       string
+		permission Permission
+		want       bool
+	}{
+		{models.RoleAdmin, ManageServices, true},
+		{models.RoleDesigner, DesignForms, true},
+		{models.RoleDesigner, ViewSubmissions, false},
+		{models.RoleReviewer, ReviewSubmissions, true},
+		{models.RoleReviewer, ManageSubmissions, false},
+		{models.RoleApplicant, SubmitForms, true},
+		{models.RoleApplicant, DesignForms, false},
+		{"", ViewCatalog, false},
+	}
+	for _, tt := range tests {
+		if got := Can(tt.role, tt.permission); got != tt.want {
+			t.Errorf("Can(%q, %q) = %v, want %v", tt.role, tt.permission, got, tt.want)
+		}
+	}
+}
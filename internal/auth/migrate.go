@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"kora_1/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RehashPlaintextPasswords replaces passwords stored in plaintext, from before
+// hashing was introduced, with their bcrypt hash. Hashed passwords are left
+// alone, so it is safe to run on every migration. It returns how many users
+// were updated.
+func RehashPlaintextPasswords(db *gorm.DB) (int, error) {
+	var users []models.User
+	if err := db.Select("id", "password").Where("password <> ''").Find(&users).Error; err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, user := range users {
+		if IsHashed(user.Password) {
+			continue
+		}
+		hash, err := HashPassword(user.Password)
+		if err != nil {
+			return updated, err
+		}
+		err = db.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]any{
+			"password":      hash,
+			"token_version": gorm.Expr("token_version + 1"),
+		}).Error
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, nil
+}
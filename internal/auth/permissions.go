@@ -0,0 +1,45 @@
+package auth
+
+import "kora_1/internal/models"
+
+// Permission names an action guarded by role-based access control.
+type Permission string
+
+const (
+	ViewCatalog         Permission = "catalog:view"       // Read services, forms, fields and collections
+	ManageServices      Permission = "services:manage"    // Create, edit and delete services
+	DesignForms         Permission = "forms:design"       // Edit forms, fields, groups, collections and data types
+	ManageUsers         Permission = "users:manage"       // Read and edit any user, assign roles and reviewer services
+	ReserveNames        Permission = "names:reserve"      // Reserve and look up names
+	ManageReservedNames Permission = "names:manage"       // Release any reserved name and edit the restricted terms
+	SubmitForms         Permission = "submissions:create" // File submissions
+	ViewSubmissions     Permission = "submissions:view"   // Read submissions within the user's scope
+	ReviewSubmissions   Permission = "submissions:review" // Act on submissions of assigned services
+	ManageSubmissions   Permission = "submissions:manage" // Read and act on every submission
+)
+
+var rolePermissions = map[string][]Permission{
+	models.RoleAdmin: {
+		ViewCatalog, ManageServices, DesignForms, ManageUsers, ReserveNames, ManageReservedNames,
+		SubmitForms, ViewSubmissions, ReviewSubmissions, ManageSubmissions,
+	},
+	models.RoleDesigner: {
+		ViewCatalog, DesignForms, ReserveNames,
+	},
+	models.RoleReviewer: {
+		ViewCatalog, ReserveNames, ViewSubmissions, ReviewSubmissions,
+	},
+	models.RoleApplicant: {
+		ViewCatalog, ReserveNames, SubmitForms, ViewSubmissions,
+	},
+}
+
+// Can reports whether a role grants a permission. Unknown roles grant nothing.
+func Can(role string, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
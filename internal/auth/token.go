@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"kora_1/internal/config"
+	"kora_1/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AccessToken  = "access"
+	RefreshToken = "refresh"
+
+	issuer = "kora"
+)
+
+var (
+	ErrNotConfigured = errors.New("auth.jwt_secret is not set")
+	ErrInvalidToken  = errors.New("invalid or expired token")
+)
+
+// Tokens issues and verifies access and refresh tokens.
+type Tokens struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewTokens returns Tokens signing with the secret and lifetimes of cfg.
+func NewTokens(cfg config.Auth) *Tokens {
+	return &Tokens{secret: []byte(cfg.JWTSecret), accessTTL: cfg.AccessTTL, refreshTTL: cfg.RefreshTTL}
+}
+
+// Claims are the JWT claims of Kora access and refresh tokens.
+type Claims struct {
+	TokenType string `json:"typ"`
+	Version   int    `json:"ver"` // The user's TokenVersion when the token was issued
+	jwt.RegisteredClaims
+}
+
+// Revoked reports whether the user's tokens were revoked after this one was
+// issued, by a password change.
+func (c *Claims) Revoked(user *models.User) bool {
+	return c.Version != user.TokenVersion
+}
+
+// UserID returns the ID of the user the token was issued to.
+func (c *Claims) UserID() (uint, error) {
+	id, err := strconv.ParseUint(c.Subject, 10, 32)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	return uint(id), nil
+}
+
+// TokenPair is the result of a successful login or refresh.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"` // Access token lifetime in seconds
+}
+
+// Issue signs a new access and refresh token for the user.
+func (t *Tokens) Issue(user *models.User) (TokenPair, error) {
+	access, err := t.sign(user, AccessToken, t.accessTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refresh, err := t.sign(user, RefreshToken, t.refreshTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(t.accessTTL.Seconds()),
+	}, nil
+}
+
+// Parse verifies a token's signature, expiry and type and returns its claims.
+func (t *Tokens) Parse(tokenString, tokenType string) (*Claims, error) {
+	if len(t.secret) == 0 {
+		return nil, ErrNotConfigured
+	}
+
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (any, error) {
+		return t.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(issuer), jwt.WithExpirationRequired())
+	if err != nil || claims.TokenType != tokenType {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (t *Tokens) sign(user *models.User, tokenType string, ttl time.Duration) (string, error) {
+	if len(t.secret) == 0 {
+		return "", ErrNotConfigured
+	}
+
+	now := time.Now()
+	claims := Claims{
+		TokenType: tokenType,
+		Version:   user.TokenVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(t.secret)
+}
@@ -0,0 +1,226 @@
+// Package collectionio reads and writes collection items in bulk as CSV or JSON.
+//
+// Each row describes one item by a code that is stable across imports:
+//
+//	code,label,parent_code,parent_collection_id
+//	ZM-LUS,Lusaka,ZM,1
+//
+// parent_code names the item this one is related to. It is looked up in
+// parent_collection_id when given, otherwise in the collection being imported.
+// JSON documents are an array of objects with the same keys. Rows are numbered
+// from 1, not counting the CSV header.
+package collectionio
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"kora_1/internal/structs"
+)
+
+type Format string
+
+const (
+	CSV  Format = "csv"
+	JSON Format = "json"
+)
+
+// maxLength matches the size of the code and label columns.
+const maxLength = 50
+
+var columns = []string{"code", "label", "parent_code", "parent_collection_id"}
+
+// Row is a single collection item in an import or export.
+type Row struct {
+	Code               string `json:"code"`
+	Label              string `json:"label"`
+	ParentCode         string `json:"parent_code,omitempty"`
+	ParentCollectionID *uint  `json:"parent_collection_id,omitempty"`
+}
+
+// ParseFormat accepts "csv" or "json" in any case.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case CSV, JSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("unsupported format %q, expected csv or json", s)
+}
+
+// DetectFormat infers the format from a file name or content type.
+func DetectFormat(fileName, contentType string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return CSV, true
+	case ".json":
+		return JSON, true
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return CSV, true
+	case "application/json":
+		return JSON, true
+	}
+	return "", false
+}
+
+// Decode reads rows in the given format. Values that cannot be read are
+// reported per row; the error is reserved for documents that cannot be parsed.
+func Decode(r io.Reader, format Format) ([]Row, []structs.RowError, error) {
+	switch format {
+	case CSV:
+		return decodeCSV(r)
+	case JSON:
+		var rows []Row
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&rows); err != nil {
+			return nil, nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return rows, nil, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported format %q", format)
+}
+
+func decodeCSV(r io.Reader) ([]Row, []structs.RowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !isColumn(name) {
+			return nil, nil, fmt.Errorf("unknown CSV column %q, expected %s", name, strings.Join(columns, ", "))
+		}
+		index[name] = i
+	}
+	for _, required := range columns[:2] {
+		if _, ok := index[required]; !ok {
+			return nil, nil, fmt.Errorf("CSV header is missing the %q column", required)
+		}
+	}
+
+	var rows []Row
+	var rowErrors []structs.RowError
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		get := func(column string) string {
+			if i, ok := index[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		row := Row{Code: get("code"), Label: get("label"), ParentCode: get("parent_code")}
+		if raw := get("parent_collection_id"); raw != "" {
+			id, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil || id == 0 {
+				rowErrors = append(rowErrors, structs.RowError{Row: n, Code: row.Code, Message: fmt.Sprintf("Invalid parent_collection_id %q", raw)})
+			} else {
+				pid := uint(id)
+				row.ParentCollectionID = &pid
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, rowErrors, nil
+}
+
+func isColumn(name string) bool {
+	for _, c := range columns {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Encode writes rows in the given format.
+func Encode(w io.Writer, format Format, rows []Row) error {
+	switch format {
+	case CSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			parentCollection := ""
+			if row.ParentCollectionID != nil {
+				parentCollection = strconv.FormatUint(uint64(*row.ParentCollectionID), 10)
+			}
+			if err := writer.Write([]string{row.Code, row.Label, row.ParentCode, parentCollection}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	case JSON:
+		if rows == nil {
+			rows = []Row{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+	return fmt.Errorf("unsupported format %q", format)
+}
+
+// Check reports rows that cannot be imported whatever the database holds:
+// missing or overlong values, codes repeated in the file and items naming
+// themselves as parent.
+func Check(rows []Row) []structs.RowError {
+	var rowErrors []structs.RowError
+	seen := make(map[string]int, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		row.Code = strings.TrimSpace(row.Code)
+		row.Label = strings.TrimSpace(row.Label)
+		row.ParentCode = strings.TrimSpace(row.ParentCode)
+
+		n := i + 1
+		fail := func(format string, args ...any) {
+			rowErrors = append(rowErrors, structs.RowError{Row: n, Code: row.Code, Message: fmt.Sprintf(format, args...)})
+		}
+		switch {
+		case row.Code == "":
+			fail("Code is required")
+		case utf8.RuneCountInString(row.Code) > maxLength:
+			fail("Code must be at most %d characters", maxLength)
+		case seen[row.Code] > 0:
+			fail("Code is also used by row %d", seen[row.Code])
+		default:
+			seen[row.Code] = n
+		}
+		switch {
+		case row.Label == "":
+			fail("Label is required")
+		case utf8.RuneCountInString(row.Label) > maxLength:
+			fail("Label must be at most %d characters", maxLength)
+		}
+		if row.ParentCollectionID != nil && row.ParentCode == "" {
+			fail("parent_collection_id needs a parent_code")
+		}
+	}
+	return rowErrors
+}
@@ -0,0 +1,123 @@
+package collectionio
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func uintPtr(v uint) *uint { return &v }
+
+func TestDecodeCSV(t *testing.T) {
+	doc := "\ufeffLabel,code,parent_code,parent_collection_id\n" +
+		"Zambia,ZM,,\n" +
+		"Lusaka, ZM-LUS ,ZM,\n" +
+		"Ndola,ZM-NDO,CB,x\n" +
+		"Kitwe,ZM-KIT,CB,2\n"
+	rows, rowErrors, err := Decode(strings.NewReader(doc), CSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Row{
+		{Code: "ZM", Label: "Zambia"},
+		{Code: "ZM-LUS", Label: "Lusaka", ParentCode: "ZM"},
+		{Code: "ZM-NDO", Label: "Ndola", ParentCode: "CB"},
+		{Code: "ZM-KIT", Label: "Kitwe", ParentCode: "CB", ParentCollectionID: uintPtr(2)},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+	if len(rowErrors) != 1 || rowErrors[0].Row != 3 {
+		t.Errorf("row errors = %+v, want one for row 3", rowErrors)
+	}
+
+	for _, doc := range []string{"code,name\n", "code\nZM\n", "code,label\n\"ZM,Zambia\n"} {
+		if _, _, err := Decode(strings.NewReader(doc), CSV); err == nil {
+			t.Errorf("Decode(%q) succeeded, want an error", doc)
+		}
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	rows, _, err := Decode(strings.NewReader(`[{"code":"ZM","label":"Zambia"},{"code":"LUS","label":"Lusaka","parent_code":"ZM","parent_collection_id":1}]`), JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[1].ParentCollectionID == nil || *rows[1].ParentCollectionID != 1 {
+		t.Errorf("rows = %+v", rows)
+	}
+	if _, _, err := Decode(strings.NewReader(`[{"code":"ZM","name":"Zambia"}]`), JSON); err == nil {
+		t.Error("unknown JSON keys were accepted")
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	rows := []Row{
+		{Code: "ZM", Label: "Zambia, Republic of"},
+		{Code: "LUS", Label: "Lusaka", ParentCode: "ZM", ParentCollectionID: uintPtr(1)},
+	}
+	for _, format := range []Format{CSV, JSON} {
+		var buf bytes.Buffer
+		if err := Encode(&buf, format, rows); err != nil {
+			t.Fatal(err)
+		}
+		got, rowErrors, err := Decode(&buf, format)
+		if err != nil || len(rowErrors) > 0 {
+			t.Fatalf("%s: Decode: %v %v", format, err, rowErrors)
+		}
+		if !reflect.DeepEqual(got, rows) {
+			t.Errorf("%s: round trip = %+v, want %+v", format, got, rows)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rows := []Row{
+		{Code: " ZM ", Label: "Zambia"},
+		{Code: "ZM", Label: "Zambia again"},
+		{Code: "", Label: "No code"},
+		{Code: "LUS", Label: ""},
+		{Code: strings.Repeat("x", 51), Label: "Long"},
+		{Code: "KIT", Label: "Kitwe", ParentCollectionID: uintPtr(2)},
+	}
+	var got []int
+	for _, e := range Check(rows) {
+		got = append(got, e.Row)
+	}
+	if want := []int{2, 3, 4, 5, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("failing rows = %v, want %v", got, want)
+	}
+	if rows[0].Code != "ZM" {
+		t.Errorf("code not trimmed: %q", rows[0].Code)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name, contentType string
+		want              Format
+		ok                bool
+	}{
+		{"provinces.CSV", "", CSV, true},
+		{"provinces.json", "text/plain", JSON, true},
+		{"", "text/csv; charset=utf-8", CSV, true},
+		{"", "application/json", JSON, true},
+		{"provinces.txt", "text/plain", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := DetectFormat(tt.name, tt.contentType)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("DetectFormat(%q, %q) = %q, %v; want %q, %v", tt.name, tt.contentType, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLoops(t *testing.T) {
+	parentOf := map[uint]*uint{1: nil, 2: uintPtr(1), 3: uintPtr(4), 4: uintPtr(3), 5: uintPtr(99)}
+	for id, want := range map[uint]bool{1: false, 2: false, 3: true, 4: true, 5: false} {
+		if got := loops(parentOf, id); got != want {
+			t.Errorf("loops(%d) = %v, want %v", id, got, want)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package collectionio
+
+import (
+	"errors"
+	"fmt"
+
+	"kora_1/internal/models"
+	"kora_1/internal/structs"
+
+	"gorm.io/gorm"
+)
+
+var ErrCollectionNotFound = errors.New("collection not found")
+
+// errRejected rolls back an import that has row errors.
+var errRejected = errors.New("import rejected")
+
+// Result summarises an import. When Errors is non-empty nothing was written.
+type Result struct {
+	Created   int                `json:"created"`
+	Updated   int                `json:"updated"`
+	Unchanged int                `json:"unchanged"`
+	Errors    []structs.RowError `json:"errors,omitempty"`
+}
+
+// Import upserts rows into a collection by code in a single transaction.
+// Items missing from the rows are left as they are. If any row cannot be
+// applied the transaction is rolled back and the row errors are returned in
+// the Result; the error is reserved for database failures.
+func Import(db *gorm.DB, collectionID uint, rows []Row) (Result, error) {
+	result := Result{Errors: Check(rows)}
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := requireCollection(tx, collectionID); err != nil {
+			return err
+		}
+		existing, err := models.GetCollectionItems(tx, collectionID, nil)
+		if err != nil {
+			return err
+		}
+		byCode := make(map[string]*models.CollectionItem, len(existing)+len(rows))
+		parentOf := make(map[uint]*uint, len(existing)+len(rows))
+		for i := range existing {
+			byCode[existing[i].Code] = &existing[i]
+			parentOf[existing[i].ID] = existing[i].RelationCollectionItemsID
+		}
+
+		// Create new items first so rows may refer to parents further down the file.
+		created := make(map[string]bool)
+		for _, row := range rows {
+			if _, ok := byCode[row.Code]; ok {
+				continue
+			}
+			item := &models.CollectionItem{CollectionID: &collectionID, CollectionItem: row.Label, Code: row.Code}
+			if err := models.CreateCollectionItem(tx, item); err != nil {
+				return err
+			}
+			byCode[row.Code] = item
+			parentOf[item.ID] = nil
+			created[row.Code] = true
+		}
+
+		lookup := parentLookup{tx: tx, target: collectionID, own: byCode}
+		for i, row := range rows {
+			item := byCode[row.Code]
+			fail := func(format string, args ...any) {
+				result.Errors = append(result.Errors, structs.RowError{Row: i + 1, Code: row.Code, Message: fmt.Sprintf(format, args...)})
+			}
+
+			var parentID *uint
+			if row.ParentCode != "" {
+				id, msg, err := lookup.find(row)
+				if err != nil {
+					return err
+				}
+				if msg != "" {
+					fail("%s", msg)
+					continue
+				}
+				if id == item.ID {
+					fail("Item cannot be its own parent")
+					continue
+				}
+				parentID = &id
+			}
+			parentOf[item.ID] = parentID
+
+			if created[row.Code] {
+				result.Created++
+				if parentID == nil {
+					continue
+				}
+			} else if item.CollectionItem == row.Label && sameID(item.RelationCollectionItemsID, parentID) {
+				result.Unchanged++
+				continue
+			} else {
+				result.Updated++
+			}
+			item.CollectionItem = row.Label
+			item.RelationCollectionItemsID = parentID
+			if err := tx.Model(item).Updates(map[string]any{
+				"collection_item":              row.Label,
+				"relation_collection_items_id": parentID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		// Relations inside the collection must still form a tree.
+		for i, row := range rows {
+			if loops(parentOf, byCode[row.Code].ID) {
+				result.Errors = append(result.Errors, structs.RowError{Row: i + 1, Code: row.Code, Message: "Parent chain loops back to this item"})
+			}
+		}
+
+		if len(result.Errors) > 0 {
+			return errRejected
+		}
+		return nil
+	})
+	if errors.Is(err, errRejected) {
+		return Result{Errors: result.Errors}, nil
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	return result, nil
+}
+
+// parentLookup resolves parent codes, loading other collections once each.
+type parentLookup struct {
+	tx     *gorm.DB
+	target uint
+	own    map[string]*models.CollectionItem
+	others map[uint]map[string]uint
+}
+
+// find returns the parent item ID, or a message saying why it was not found.
+func (l *parentLookup) find(row Row) (uint, string, error) {
+	if row.ParentCollectionID == nil || *row.ParentCollectionID == l.target {
+		if parent, ok := l.own[row.ParentCode]; ok {
+			return parent.ID, "", nil
+		}
+		return 0, fmt.Sprintf("Parent %q not found in this collection", row.ParentCode), nil
+	}
+
+	collectionID := *row.ParentCollectionID
+	codes, ok := l.others[collectionID]
+	if !ok {
+		if _, err := models.GetCollection(l.tx, collectionID); errors.Is(err, gorm.ErrRecordNotFound) {
+			codes = nil
+		} else if err != nil {
+			return 0, "", err
+		} else {
+			items, err := models.GetCollectionItems(l.tx, collectionID, nil)
+			if err != nil {
+				return 0, "", err
+			}
+			codes = make(map[string]uint, len(items))
+			for _, item := range items {
+				codes[item.Code] = item.ID
+			}
+		}
+		if l.others == nil {
+			l.others = make(map[uint]map[string]uint)
+		}
+		l.others[collectionID] = codes
+	}
+	if codes == nil {
+		return 0, fmt.Sprintf("Parent collection %d not found", collectionID), nil
+	}
+	if id, ok := codes[row.ParentCode]; ok {
+		return id, "", nil
+	}
+	return 0, fmt.Sprintf("Parent %q not found in collection %d", row.ParentCode, collectionID), nil
+}
+
+// loops reports whether following parents from id within the collection leads back to id.
+func loops(parentOf map[uint]*uint, id uint) bool {
+	current := parentOf[id]
+	for steps := 0; current != nil && steps <= len(parentOf); steps++ {
+		if *current == id {
+			return true
+		}
+		next, inCollection := parentOf[*current]
+		if !inCollection {
+			return false
+		}
+		current = next
+	}
+	return false
+}
+
+func requireCollection(db *gorm.DB, collectionID uint) error {
+	_, err := models.GetCollection(db, collectionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrCollectionNotFound
+	}
+	return err
+}
+
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// Export returns every item of a collection as rows, in ID order.
+func Export(db *gorm.DB, collectionID uint) ([]Row, error) {
+	if err := requireCollection(db, collectionID); err != nil {
+		return nil, err
+	}
+	items, err := models.GetCollectionItems(db, collectionID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var parentIDs []uint
+	for _, item := range items {
+		if item.RelationCollectionItemsID != nil {
+			parentIDs = append(parentIDs, *item.RelationCollectionItemsID)
+		}
+	}
+	parents := make(map[uint]models.CollectionItem, len(parentIDs))
+	if len(parentIDs) > 0 {
+		found, err := models.GetCollectionItemsByIDs(db, parentIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range found {
+			parents[parent.ID] = parent
+		}
+	}
+
+	rows := make([]Row, 0, len(items))
+	for _, item := range items {
+		row := Row{Code: item.Code, Label: item.CollectionItem}
+		if item.RelationCollectionItemsID != nil {
+			if parent, ok := parents[*item.RelationCollectionItemsID]; ok {
+				row.ParentCode = parent.Code
+				if parent.CollectionID != nil && *parent.CollectionID != collectionID {
+					row.ParentCollectionID = parent.CollectionID
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
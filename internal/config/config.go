@@ -0,0 +1,408 @@
+// Package config loads the settings of the API server and the kora CLI.
+//
+// Settings start from defaults, are overridden by an optional YAML file and
+// then by environment variables. A .env file in the working directory is read
+// into the environment first, without replacing variables that are already
+// set. The YAML file is config.yaml in the working directory, or the file named
+// by KORA_CONFIG.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting. Field comments name the environment variable
+// that overrides the YAML key.
+type Config struct {
+	Port     int      `yaml:"port"` // PORT
+	DB       DB       `yaml:"db"`
+	Auth     Auth     `yaml:"auth"`
+	Storage  Storage  `yaml:"storage"`
+	CORS     CORS     `yaml:"cors"`
+	Timeouts Timeouts `yaml:"timeouts"`
+	Log      Log      `yaml:"log"`
+	Features Features `yaml:"features"`
+
+	Reservations Reservations `yaml:"reservations"`
+	Webhooks     Webhooks     `yaml:"webhooks"`
+	Mail         Mail         `yaml:"mail"`
+}
+
+// DB describes the Postgres connection and its pool. DSN, when set, is used
+// instead of the individual connection settings.
+type DB struct {
+	DSN      string `yaml:"dsn"`      // DATABASE_URL
+	Host     string `yaml:"host"`     // BLUEPRINT_DB_HOST
+	Port     string `yaml:"port"`     // BLUEPRINT_DB_PORT
+	Database string `yaml:"database"` // BLUEPRINT_DB_DATABASE
+	Username string `yaml:"username"` // BLUEPRINT_DB_USERNAME
+	Password string `yaml:"password"` // BLUEPRINT_DB_PASSWORD
+	Schema   string `yaml:"schema"`   // BLUEPRINT_DB_SCHEMA
+	SSLMode  string `yaml:"sslmode"`  // BLUEPRINT_DB_SSLMODE
+
+	MaxOpenConns    int           `yaml:"max_open_conns"`    // DB_MAX_OPEN_CONNS, 0 for no limit
+	MaxIdleConns    int           `yaml:"max_idle_conns"`    // DB_MAX_IDLE_CONNS
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"` // DB_CONN_MAX_LIFETIME, 0 to keep connections open
+
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold"` // DB_SLOW_QUERY_THRESHOLD, queries taking longer are logged as warnings; 0 disables
+}
+
+// ConnString returns DSN, or a URL built from the individual settings.
+func (db DB) ConnString() string {
+	if db.DSN != "" {
+		return db.DSN
+	}
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(db.Username, db.Password),
+		Host:   db.Host + ":" + db.Port,
+		Path:   "/" + db.Database,
+	}
+	q := url.Values{"sslmode": {db.SSLMode}}
+	if db.Schema != "" {
+		q.Set("search_path", db.Schema)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// Auth configures the tokens issued at login.
+type Auth struct {
+	JWTSecret  string        `yaml:"jwt_secret"`  // JWT_SECRET, signs access and refresh tokens; required
+	AccessTTL  time.Duration `yaml:"access_ttl"`  // JWT_ACCESS_TTL
+	RefreshTTL time.Duration `yaml:"refresh_ttl"` // JWT_REFRESH_TTL
+	AdminEmail string        `yaml:"admin_email"` // ADMIN_EMAIL, a registered user made administrator when migrating
+}
+
+// Storage selects where uploaded files are kept: a local directory or an
+// S3-compatible bucket.
+type Storage struct {
+	Driver    string `yaml:"driver"`     // STORAGE_DRIVER, local or s3
+	LocalPath string `yaml:"local_path"` // STORAGE_LOCAL_PATH, the directory of the local driver
+	S3        S3     `yaml:"s3"`
+}
+
+// S3 describes the bucket of the s3 storage driver.
+type S3 struct {
+	Endpoint        string `yaml:"endpoint"`          // S3_ENDPOINT, e.g. http://localhost:9000; empty for AWS in Region
+	Region          string `yaml:"region"`            // S3_REGION
+	Bucket          string `yaml:"bucket"`            // S3_BUCKET
+	AccessKeyID     string `yaml:"access_key_id"`     // S3_ACCESS_KEY_ID
+	SecretAccessKey string `yaml:"secret_access_key"` // S3_SECRET_ACCESS_KEY
+	PathStyle       bool   `yaml:"path_style"`        // S3_PATH_STYLE, address objects as endpoint/bucket/key, as MinIO expects
+}
+
+// CORS lists the browser origins allowed to call the API.
+type CORS struct {
+	AllowedOrigins []string `yaml:"allowed_origins"` // CORS_ALLOWED_ORIGINS, comma-separated
+}
+
+// Timeouts bound how long the HTTP server waits on clients.
+type Timeouts struct {
+	Read     time.Duration `yaml:"read"`     // HTTP_READ_TIMEOUT
+	Write    time.Duration `yaml:"write"`    // HTTP_WRITE_TIMEOUT
+	Idle     time.Duration `yaml:"idle"`     // HTTP_IDLE_TIMEOUT
+	Shutdown time.Duration `yaml:"shutdown"` // SHUTDOWN_TIMEOUT, for requests in flight on exit
+}
+
+// Log configures logging. Level is debug, info, warn or error; debug also
+// logs every query.
+type Log struct {
+	Level string `yaml:"level"` // LOG_LEVEL
+}
+
+// Reservations sets how long reserved names are held.
+type Reservations struct {
+	HoldPeriod     time.Duration `yaml:"hold_period"`     // RESERVATION_HOLD_PERIOD, how long a reservation or renewal holds a name
+	ExpiryInterval time.Duration `yaml:"expiry_interval"` // RESERVATION_EXPIRY_INTERVAL, how often the server expires lapsed holds; 0 disables
+}
+
+// Webhooks sets how service webhooks are delivered.
+type Webhooks struct {
+	DispatchInterval time.Duration `yaml:"dispatch_interval"` // WEBHOOK_DISPATCH_INTERVAL, how often the server sends pending deliveries; 0 disables
+	Timeout          time.Duration `yaml:"timeout"`           // WEBHOOK_TIMEOUT, how long a receiver has to answer
+	MaxAttempts      int           `yaml:"max_attempts"`      // WEBHOOK_MAX_ATTEMPTS, attempts before a delivery is marked failed
+}
+
+// Mail sets how notification emails are sent. Without a host they are
+// logged instead.
+type Mail struct {
+	Host     string `yaml:"host"`     // SMTP_HOST
+	Port     int    `yaml:"port"`     // SMTP_PORT
+	Username string `yaml:"username"` // SMTP_USERNAME, empty to send without authenticating
+	Password string `yaml:"password"` // SMTP_PASSWORD
+	From     string `yaml:"from"`     // MAIL_FROM, the sender address
+
+	SendInterval time.Duration `yaml:"send_interval"` // MAIL_SEND_INTERVAL, how often the server sends queued emails; 0 disables
+	Timeout      time.Duration `yaml:"timeout"`       // SMTP_TIMEOUT, for one email's whole conversation with the server
+	MaxAttempts  int           `yaml:"max_attempts"`  // MAIL_MAX_ATTEMPTS, attempts before an email is marked failed
+}
+
+// Features switches optional behaviour on or off.
+type Features struct {
+	MigrateOnStart bool `yaml:"migrate_on_start"` // MIGRATE_ON_START
+	Swagger        bool `yaml:"swagger"`          // SWAGGER_ENABLED, serves /swagger
+	Registration   bool `yaml:"registration"`     // REGISTRATION_ENABLED, lets anyone create an account
+}
+
+// Default returns the settings used where neither the file nor the
+// environment sets a value.
+func Default() Config {
+	return Config{
+		Port: 8080,
+		DB: DB{
+			Host:               "localhost",
+			Port:               "5432",
+			Schema:             "public",
+			SSLMode:            "disable",
+			MaxOpenConns:       25,
+			MaxIdleConns:       5,
+			SlowQueryThreshold: 200 * time.Millisecond,
+		},
+		Auth: Auth{
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: 7 * 24 * time.Hour,
+		},
+		Storage: Storage{
+			Driver:    "local",
+			LocalPath: "uploads",
+			S3:        S3{Region: "us-east-1", PathStyle: true},
+		},
+		CORS: CORS{AllowedOrigins: []string{"http://localhost:5173"}},
+		Timeouts: Timeouts{
+			Read:     10 * time.Second,
+			Write:    30 * time.Second,
+			Idle:     time.Minute,
+			Shutdown: 5 * time.Second,
+		},
+		Log:      Log{Level: "info"},
+		Features: Features{Swagger: true, Registration: true},
+		Reservations: Reservations{
+			HoldPeriod:     30 * 24 * time.Hour,
+			ExpiryInterval: 15 * time.Minute,
+		},
+		Webhooks: Webhooks{
+			DispatchInterval: 5 * time.Second,
+			Timeout:          10 * time.Second,
+			MaxAttempts:      8,
+		},
+		Mail: Mail{
+			Port:         587,
+			From:         "Kora <no-reply@localhost>",
+			SendInterval: 10 * time.Second,
+			Timeout:      30 * time.Second,
+			MaxAttempts:  5,
+		},
+	}
+}
+
+// Load reads the configuration and validates it.
+func Load() (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("config: reading .env: %w", err)
+	}
+
+	path, explicit := os.LookupEnv("KORA_CONFIG")
+	if !explicit {
+		path = "config.yaml"
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) && !explicit {
+		path = ""
+	}
+	return load(path, os.LookupEnv)
+}
+
+func load(path string, lookup func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		if err := readFile(path, &cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyEnv(&cfg, lookup); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func readFile(path string, cfg *Config) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config: %s: %w", path, err)
+	}
+	return nil
+}
+
+var (
+	logLevels      = []string{"debug", "info", "warn", "error"}
+	storageDrivers = []string{"local", "s3"}
+)
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: "+format, append([]any{key}, args...)...))
+	}
+
+	if c.Port < 1 || c.Port > 65535 {
+		invalid("port", "must be between 1 and 65535, got %d", c.Port)
+	}
+
+	if c.DB.DSN == "" {
+		for _, setting := range []struct{ key, value string }{
+			{"db.host", c.DB.Host},
+			{"db.port", c.DB.Port},
+			{"db.database", c.DB.Database},
+			{"db.username", c.DB.Username},
+		} {
+			if setting.value == "" {
+				invalid(setting.key, "is required unless db.dsn is set")
+			}
+		}
+	} else if u, err := url.Parse(c.DB.DSN); err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		invalid("db.dsn", "must be a postgres:// URL")
+	}
+	if c.DB.MaxOpenConns < 0 {
+		invalid("db.max_open_conns", "must not be negative")
+	}
+	if c.DB.MaxIdleConns < 0 {
+		invalid("db.max_idle_conns", "must not be negative")
+	}
+	if c.DB.MaxOpenConns > 0 && c.DB.MaxIdleConns > c.DB.MaxOpenConns {
+		invalid("db.max_idle_conns", "must not exceed db.max_open_conns (%d)", c.DB.MaxOpenConns)
+	}
+	if c.DB.ConnMaxLifetime < 0 {
+		invalid("db.conn_max_lifetime", "must not be negative")
+	}
+	if c.DB.SlowQueryThreshold < 0 {
+		invalid("db.slow_query_threshold", "must not be negative")
+	}
+
+	if c.Auth.JWTSecret == "" {
+		invalid("auth.jwt_secret", "is required")
+	}
+	if c.Auth.AccessTTL <= 0 {
+		invalid("auth.access_ttl", "must be positive")
+	}
+	if c.Auth.RefreshTTL <= 0 {
+		invalid("auth.refresh_ttl", "must be positive")
+	}
+	if c.Auth.AdminEmail != "" {
+		if _, err := mail.ParseAddress(c.Auth.AdminEmail); err != nil {
+			invalid("auth.admin_email", "%q is not an email address", c.Auth.AdminEmail)
+		}
+	}
+
+	switch c.Storage.Driver {
+	case "local":
+		if c.Storage.LocalPath == "" {
+			invalid("storage.local_path", "is required for the local driver")
+		}
+	case "s3":
+		for _, setting := range []struct{ key, value string }{
+			{"storage.s3.region", c.Storage.S3.Region},
+			{"storage.s3.bucket", c.Storage.S3.Bucket},
+			{"storage.s3.access_key_id", c.Storage.S3.AccessKeyID},
+			{"storage.s3.secret_access_key", c.Storage.S3.SecretAccessKey},
+		} {
+			if setting.value == "" {
+				invalid(setting.key, "is required for the s3 driver")
+			}
+		}
+		if endpoint := c.Storage.S3.Endpoint; endpoint != "" {
+			if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				invalid("storage.s3.endpoint", "%q is not a URL such as http://localhost:9000", endpoint)
+			}
+		}
+	default:
+		invalid("storage.driver", "must be one of %s, got %q", strings.Join(storageDrivers, ", "), c.Storage.Driver)
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" {
+			invalid("cors.allowed_origins", "%q is not an origin such as https://app.example.com", origin)
+		}
+	}
+
+	for _, timeout := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"timeouts.read", c.Timeouts.Read},
+		{"timeouts.write", c.Timeouts.Write},
+		{"timeouts.idle", c.Timeouts.Idle},
+		{"timeouts.shutdown", c.Timeouts.Shutdown},
+	} {
+		if timeout.value <= 0 {
+			invalid(timeout.key, "must be positive")
+		}
+	}
+
+	if c.Reservations.HoldPeriod <= 0 {
+		invalid("reservations.hold_period", "must be positive")
+	}
+	if c.Reservations.ExpiryInterval < 0 {
+		invalid("reservations.expiry_interval", "must not be negative")
+	}
+
+	if c.Webhooks.DispatchInterval < 0 {
+		invalid("webhooks.dispatch_interval", "must not be negative")
+	}
+	if c.Webhooks.Timeout <= 0 {
+		invalid("webhooks.timeout", "must be positive")
+	}
+	if c.Webhooks.MaxAttempts < 1 {
+		invalid("webhooks.max_attempts", "must be at least 1, got %d", c.Webhooks.MaxAttempts)
+	}
+
+	if c.Mail.Port < 1 || c.Mail.Port > 65535 {
+		invalid("mail.port", "must be between 1 and 65535, got %d", c.Mail.Port)
+	}
+	if _, err := mail.ParseAddress(c.Mail.From); err != nil {
+		invalid("mail.from", "%q is not an address such as Kora <no-reply@example.com>", c.Mail.From)
+	}
+	if c.Mail.SendInterval < 0 {
+		invalid("mail.send_interval", "must not be negative")
+	}
+	if c.Mail.Timeout <= 0 {
+		invalid("mail.timeout", "must be positive")
+	}
+	if c.Mail.MaxAttempts < 1 {
+		invalid("mail.max_attempts", "must be at least 1, got %d", c.Mail.MaxAttempts)
+	}
+
+	if !slices.Contains(logLevels, c.Log.Level) {
+		invalid("log.level", "must be one of %s, got %q", strings.Join(logLevels, ", "), c.Log.Level)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+}
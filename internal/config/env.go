@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// applyEnv overrides cfg with the environment variables that are set,
+// reporting every variable that does not parse.
+func applyEnv(cfg *Config, lookup func(string) (string, bool)) error {
+	e := envReader{lookup: lookup}
+
+	e.int("PORT", &cfg.Port)
+
+	e.string("DATABASE_URL", &cfg.DB.DSN)
+	e.string("BLUEPRINT_DB_HOST", &cfg.DB.Host)
+	e.string("BLUEPRINT_DB_PORT", &cfg.DB.Port)
+	e.string("BLUEPRINT_DB_DATABASE", &cfg.DB.Database)
+	e.string("BLUEPRINT_DB_USERNAME", &cfg.DB.Username)
+	e.string("BLUEPRINT_DB_PASSWORD", &cfg.DB.Password)
+	e.string("BLUEPRINT_DB_SCHEMA", &cfg.DB.Schema)
+	e.string("BLUEPRINT_DB_SSLMODE", &cfg.DB.SSLMode)
+	e.int("DB_MAX_OPEN_CONNS", &cfg.DB.MaxOpenConns)
+	e.int("DB_MAX_IDLE_CONNS", &cfg.DB.MaxIdleConns)
+	e.duration("DB_CONN_MAX_LIFETIME", &cfg.DB.ConnMaxLifetime)
+	e.duration("DB_SLOW_QUERY_THRESHOLD", &cfg.DB.SlowQueryThreshold)
+
+	e.string("JWT_SECRET", &cfg.Auth.JWTSecret)
+	e.duration("JWT_ACCESS_TTL", &cfg.Auth.AccessTTL)
+	e.duration("JWT_REFRESH_TTL", &cfg.Auth.RefreshTTL)
+	e.string("ADMIN_EMAIL", &cfg.Auth.AdminEmail)
+
+	e.string("STORAGE_DRIVER", &cfg.Storage.Driver)
+	e.string("STORAGE_LOCAL_PATH", &cfg.Storage.LocalPath)
+	e.string("S3_ENDPOINT", &cfg.Storage.S3.Endpoint)
+	e.string("S3_REGION", &cfg.Storage.S3.Region)
+	e.string("S3_BUCKET", &cfg.Storage.S3.Bucket)
+	e.string("S3_ACCESS_KEY_ID", &cfg.Storage.S3.AccessKeyID)
+	e.string("S3_SECRET_ACCESS_KEY", &cfg.Storage.S3.SecretAccessKey)
+	e.bool("S3_PATH_STYLE", &cfg.Storage.S3.PathStyle)
+
+	e.list("CORS_ALLOWED_ORIGINS", &cfg.CORS.AllowedOrigins)
+
+	e.duration("HTTP_READ_TIMEOUT", &cfg.Timeouts.Read)
+	e.duration("HTTP_WRITE_TIMEOUT", &cfg.Timeouts.Write)
+	e.duration("HTTP_IDLE_TIMEOUT", &cfg.Timeouts.Idle)
+	e.duration("SHUTDOWN_TIMEOUT", &cfg.Timeouts.Shutdown)
+
+	e.string("LOG_LEVEL", &cfg.Log.Level)
+
+	e.bool("MIGRATE_ON_START", &cfg.Features.MigrateOnStart)
+	e.bool("SWAGGER_ENABLED", &cfg.Features.Swagger)
+	e.bool("REGISTRATION_ENABLED", &cfg.Features.Registration)
+
+	e.duration("RESERVATION_HOLD_PERIOD", &cfg.Reservations.HoldPeriod)
+	e.duration("RESERVATION_EXPIRY_INTERVAL", &cfg.Reservations.ExpiryInterval)
+	e.duration("WEBHOOK_DISPATCH_INTERVAL", &cfg.Webhooks.DispatchInterval)
+	e.duration("WEBHOOK_TIMEOUT", &cfg.Webhooks.Timeout)
+	e.int("WEBHOOK_MAX_ATTEMPTS", &cfg.Webhooks.MaxAttempts)
+	e.string("SMTP_HOST", &cfg.Mail.Host)
+	e.int("SMTP_PORT", &cfg.Mail.Port)
+	e.string("SMTP_USERNAME", &cfg.Mail.Username)
+	e.string("SMTP_PASSWORD", &cfg.Mail.Password)
+	e.string("MAIL_FROM", &cfg.Mail.From)
+	e.duration("MAIL_SEND_INTERVAL", &cfg.Mail.SendInterval)
+	e.duration("SMTP_TIMEOUT", &cfg.Mail.Timeout)
+	e.int("MAIL_MAX_ATTEMPTS", &cfg.Mail.MaxAttempts)
+
+	if len(e.errs) > 0 {
+		return fmt.Errorf("invalid environment:\n%w", errors.Join(e.errs...))
+	}
+	return nil
+}
+
+// envReader sets values from non-empty variables, collecting parse errors.
+type envReader struct {
+	lookup func(string) (string, bool)
+	errs   []error
+}
+
+func (e *envReader) get(key string) (string, bool) {
+	value, ok := e.lookup(key)
+	value = strings.TrimSpace(value)
+	return value, ok && value != ""
+}
+
+func (e *envReader) string(key string, dst *string) {
+	if value, ok := e.get(key); ok {
+		*dst = value
+	}
+}
+
+func (e *envReader) int(key string, dst *int) {
+	if value, ok := e.get(key); ok {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: %q is not a whole number", key, value))
+			return
+		}
+		*dst = n
+	}
+}
+
+func (e *envReader) bool(key string, dst *bool) {
+	if value, ok := e.get(key); ok {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: %q is not true or false", key, value))
+			return
+		}
+		*dst = b
+	}
+}
+
+func (e *envReader) duration(key string, dst *time.Duration) {
+	if value, ok := e.get(key); ok {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: %q is not a duration such as 30s or 5m", key, value))
+			return
+		}
+		*dst = d
+	}
+}
+
+func (e *envReader) list(key string, dst *[]string) {
+	if value, ok := e.get(key); ok {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*dst = items
+	}
+}
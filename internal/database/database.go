@@ -3,15 +3,16 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
 	"strconv"
 	"time"
 
+	"kora_1/internal/config"
+	"kora_1/internal/logging"
+
 	"gorm.io/driver/postgres"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
-	_ "github.com/joho/godotenv/autoload"
 	"gorm.io/gorm"
 )
 
@@ -30,36 +31,29 @@ type service struct {
 	db *gorm.DB
 }
 
-var (
-	database   = os.Getenv("BLUEPRINT_DB_DATABASE")
-	password   = os.Getenv("BLUEPRINT_DB_PASSWORD")
-	username   = os.Getenv("BLUEPRINT_DB_USERNAME")
-	port       = os.Getenv("BLUEPRINT_DB_PORT")
-	host       = os.Getenv("BLUEPRINT_DB_HOST")
-	schema     = os.Getenv("BLUEPRINT_DB_SCHEMA")
-	dbInstance *service
-)
-
-var DB *gorm.DB
-
-func New() Service {
-	// Reuse Connection
-	if dbInstance != nil {
-		return dbInstance
-	}
-	connStr := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable&search_path=%s", username, password, host, port, database, schema)
-	var err error
-	DB, err = gorm.Open(postgres.Open(connStr), &gorm.Config{})
+// Open connects to the database described by cfg and sizes its connection pool.
+// The connection is passed to whatever needs it; there is no package-level handle.
+func Open(cfg config.DB) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(cfg.ConnString()), &gorm.Config{
+		Logger: logging.GormLogger{SlowThreshold: cfg.SlowQueryThreshold},
+	})
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	Migrate(DB)
-
-	dbInstance = &service{
-		db: DB,
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
 	}
-	return dbInstance
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	return db, nil
+}
+
+// New wraps an open connection for health checks and shutdown.
+func New(db *gorm.DB) Service {
+	return &service{db: db}
 }
 
 // Health checks the health of the database connection by pinging the database.
@@ -122,6 +116,6 @@ func (s *service) Close() error {
 		return err
 	}
 
-	log.Printf("Disconnected from database: %s", database)
+	slog.Info("disconnected from database")
 	return sqlDB.Close()
 }
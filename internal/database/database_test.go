@@ -2,19 +2,34 @@ package database
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
+	"kora_1/internal/auth"
+	"kora_1/internal/config"
+	"kora_1/internal/migrations"
+	"kora_1/internal/models"
+
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"gorm.io/gorm"
 )
 
-// Reset singleton between tests
-func resetDB() {
-	dbInstance = nil
+// testDB points at the container started by TestMain.
+var testDB config.DB
+
+func mustOpen(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := Open(testDB)
+	if err != nil {
+		t.Fatalf("Open() returned error: %v", err)
+	}
+	return db
 }
 
 // Start Postgres test container
@@ -51,13 +66,12 @@ func mustStartPostgresContainer() (func(context.Context, ...testcontainers.Termi
 		return container.Terminate, err
 	}
 
-	// Assign database globals
-	database = dbName
-	username = dbUser
-	password = dbPwd
-	host = hostIP
-	port = mappedPort.Port()
-	schema = "public"
+	testDB = config.Default().DB
+	testDB.Database = dbName
+	testDB.Username = dbUser
+	testDB.Password = dbPwd
+	testDB.Host = hostIP
+	testDB.Port = mappedPort.Port()
 
 	return container.Terminate, nil
 }
@@ -81,9 +95,7 @@ func TestMain(m *testing.M) {
 // ---------- Tests ----------
 
 func TestNew(t *testing.T) {
-	resetDB()
-
-	srv := New()
+	srv := New(mustOpen(t))
 
 	if srv == nil {
 		t.Fatal("New() returned nil")
@@ -91,9 +103,7 @@ func TestNew(t *testing.T) {
 }
 
 func TestHealth(t *testing.T) {
-	resetDB()
-
-	srv := New()
+	srv := New(mustOpen(t))
 
 	stats := srv.Health()
 
@@ -115,12 +125,236 @@ func TestHealth(t *testing.T) {
 }
 
 func TestClose(t *testing.T) {
-	resetDB()
-
-	srv := New()
+	srv := New(mustOpen(t))
 
 	err := srv.Close()
 	if err != nil {
 		t.Fatalf("Close() returned error: %v", err)
 	}
 }
+
+func TestMigrate(t *testing.T) {
+	db := mustOpen(t)
+	ctx := context.Background()
+
+	if err := Migrate(ctx, db, ""); err != nil {
+		t.Fatalf("Migrate() returned error: %v", err)
+	}
+	// A second run finds nothing to do.
+	if err := Migrate(ctx, db, ""); err != nil {
+		t.Fatalf("second Migrate() returned error: %v", err)
+	}
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending, err := migrator.Pending(ctx)
+	if err != nil || len(pending) != 0 {
+		t.Fatalf("Pending() = %v, %v; want none", pending, err)
+	}
+
+	if _, err := migrator.To(ctx, 0); err != nil {
+		t.Fatalf("reverting every migration: %v", err)
+	}
+	if db.Migrator().HasTable("users") {
+		t.Fatal("users table still exists after reverting the baseline")
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("re-applying migrations: %v", err)
+	}
+}
+
+// The models as they were before versioned migrations, for building the
+// schema AutoMigrate created.
+type (
+	legacyUser struct {
+		ID       uint   `gorm:"primaryKey;autoIncrement"`
+		Email    string `gorm:"size:250;unique"`
+		Password string `gorm:"size:250"`
+	}
+	legacyService struct {
+		ID          uint   `gorm:"primaryKey;autoIncrement"`
+		ServiceName string `gorm:"size:100;not null"`
+	}
+	legacyDataType struct {
+		ID       uint   `gorm:"primaryKey;autoIncrement"`
+		DataType string `gorm:"size:50"`
+	}
+	legacyCollection struct {
+		ID             uint   `gorm:"primaryKey;autoIncrement"`
+		CollectionName string `gorm:"size:50"`
+	}
+	legacyCollectionItem struct {
+		ID                        uint   `gorm:"primaryKey;autoIncrement"`
+		CollectionID              *uint  `gorm:"index"`
+		CollectionItem            string `gorm:"size:50"`
+		RelationCollectionItemsID *uint  `gorm:"index"`
+	}
+	legacyReservedName struct {
+		ID           uint   `gorm:"primaryKey;autoIncrement"`
+		ReservedName string `gorm:"size:50"`
+	}
+	legacyField struct {
+		ID         uint   `gorm:"primaryKey;autoIncrement"`
+		Label      string `gorm:"size:50;not null"`
+		DataTypeID uint   `gorm:"not null"`
+	}
+	legacyForm struct {
+		ID          uint   `gorm:"primaryKey"`
+		FormName    string `gorm:"size:50;not null"`
+		Description string `gorm:"size:250"`
+		DataTypeID  uint   `gorm:"not null"`
+		ServiceID   *uint  `gorm:"index"`
+	}
+	legacyFormField struct {
+		ID         uint   `gorm:"primaryKey;autoIncrement"`
+		FormID     uint   `gorm:"not null"`
+		FieldID    uint   `gorm:"not null"`
+		FieldName  string `gorm:"size:50"`
+		Validation string `gorm:"size:250"`
+	}
+	legacySubmission struct {
+		ID         uint      `gorm:"primaryKey;autoIncrement"`
+		ServicesID *uint     `gorm:"index"`
+		CreatedBy  *uint     `gorm:"index"`
+		CreatedOn  time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	}
+	legacyFormAnswer struct {
+		ID           uint   `gorm:"primaryKey;autoIncrement"`
+		FormFieldID  *uint  `gorm:"index"`
+		Answer       string `gorm:"size:250"`
+		SubmissionID *uint  `gorm:"index"`
+	}
+)
+
+func (legacyUser) TableName() string           { return "users" }
+func (legacyService) TableName() string        { return "services" }
+func (legacyDataType) TableName() string       { return "data_types" }
+func (legacyCollection) TableName() string     { return "collections" }
+func (legacyCollectionItem) TableName() string { return "collection_items" }
+func (legacyReservedName) TableName() string   { return "reserved_names" }
+func (legacyField) TableName() string          { return "fields" }
+func (legacyForm) TableName() string           { return "forms" }
+func (legacyFormField) TableName() string      { return "form_fields" }
+func (legacySubmission) TableName() string     { return "submissions" }
+func (legacyFormAnswer) TableName() string     { return "form_answers" }
+
+// TestMigrateLegacySchema migrates a database that AutoMigrate created before
+// versioned migrations, with data in it.
+func TestMigrateLegacySchema(t *testing.T) {
+	ctx := context.Background()
+	if err := mustOpen(t).Exec("CREATE DATABASE legacy").Error; err != nil {
+		t.Fatal(err)
+	}
+	cfg := testDB
+	cfg.Database = "legacy"
+	db, err := Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.AutoMigrate(&legacyUser{}, &legacyService{}, &legacyDataType{}, &legacyCollection{}, &legacyCollectionItem{},
+		&legacyReservedName{}, &legacyField{}, &legacyForm{}, &legacyFormField{}, &legacySubmission{}, &legacyFormAnswer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &legacyUser{Email: "jane@example.com", Password: "plaintext"}
+	service := &legacyService{ServiceName: "Business names"}
+	dataType := &legacyDataType{DataType: "text"}
+	collection := &legacyCollection{CollectionName: "Provinces"}
+	for _, row := range []any{user, service, dataType, collection} {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	item := &legacyCollectionItem{CollectionID: &collection.ID, CollectionItem: "Lusaka"}
+	field := &legacyField{Label: "Name", DataTypeID: dataType.ID}
+	form := &legacyForm{FormName: "Registration", DataTypeID: dataType.ID, ServiceID: &service.ID}
+	submission := &legacySubmission{ServicesID: &service.ID, CreatedBy: &user.ID}
+	for _, row := range []any{item, field, form, submission, &legacyReservedName{ReservedName: "Acme Trading Ltd"}} {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	formField := &legacyFormField{FormID: form.ID, FieldID: field.ID, FieldName: "name"}
+	if err := db.Create(formField).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, answer := range []string{"Acme", "Acme Ltd"} {
+		if err := db.Create(&legacyFormAnswer{FormFieldID: &formField.ID, SubmissionID: &submission.ID, Answer: answer}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Migrate(ctx, db, ""); err != nil {
+		t.Fatalf("Migrate() returned error: %v", err)
+	}
+
+	var migrated models.Submission
+	if err := db.First(&migrated, submission.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if migrated.Status != "submitted" || migrated.FormVersionID == nil {
+		t.Errorf("submission = %+v, want it submitted against the backfilled version", migrated)
+	}
+	var version models.FormVersion
+	err = db.First(&version, *migrated.FormVersionID).Error
+	if err != nil || version.FormID != form.ID || version.Status != models.FormVersionPublished {
+		t.Errorf("version = %+v, %v; want form %d's published version", version, err, form.ID)
+	}
+	var fieldVersions []uint
+	db.Model(&models.FormFields{}).Where("id = ?", formField.ID).Pluck("form_version_id", &fieldVersions)
+	if len(fieldVersions) != 1 || fieldVersions[0] != *migrated.FormVersionID {
+		t.Errorf("form field versions = %v, want %d", fieldVersions, *migrated.FormVersionID)
+	}
+
+	var answers []string
+	db.Model(&models.FormAnswer{}).Where("submission_id = ?", submission.ID).Pluck("answer", &answers)
+	if len(answers) != 1 || answers[0] != "Acme Ltd" {
+		t.Errorf("answers = %q, want only the latest", answers)
+	}
+	if err := models.SaveFormAnswers(db, submission.ID, map[uint]string{formField.ID: "Acme Trading"}); err != nil {
+		t.Fatalf("SaveFormAnswers() returned error: %v", err)
+	}
+	var saved []string
+	db.Model(&models.FormAnswer{}).Where("submission_id = ?", submission.ID).Pluck("answer", &saved)
+	if len(saved) != 1 || saved[0] != "Acme Trading" {
+		t.Errorf("answers after saving = %q, want the answer replaced", saved)
+	}
+
+	// Version 1 is published, so the field it places is frozen but for its status.
+	catalogField, err := models.GetFields(db, field.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalogField.Label = "Company name"
+	if err := models.UpdateFields(db, catalogField); !errors.Is(err, models.ErrFormVersionPublished) {
+		t.Errorf("UpdateFields() renaming a published field returned %v, want ErrFormVersionPublished", err)
+	}
+	active := true
+	catalogField.Label = field.Label
+	catalogField.Status = &active
+	if err := models.UpdateFields(db, catalogField); err != nil {
+		t.Errorf("UpdateFields() changing the status returned %v", err)
+	}
+	if err := models.DeleteFields(db, field.ID); !errors.Is(err, models.ErrFormVersionPublished) {
+		t.Errorf("DeleteFields() returned %v, want ErrFormVersionPublished", err)
+	}
+
+	var migratedUser models.User
+	db.First(&migratedUser, user.ID)
+	if migratedUser.Role != models.RoleApplicant || !auth.IsHashed(migratedUser.Password) {
+		t.Errorf("user = %+v, want an applicant with a hashed password", migratedUser)
+	}
+	var codes []string
+	db.Model(&models.CollectionItem{}).Where("id = ?", item.ID).Pluck("code", &codes)
+	if len(codes) != 1 || codes[0] != strconv.FormatUint(uint64(item.ID), 10) {
+		t.Errorf("collection item codes = %q, want its ID", codes)
+	}
+	var keys []string
+	db.Model(&models.ReservedName{}).Pluck("normalized_name", &keys)
+	if len(keys) != 1 || keys[0] == "" {
+		t.Errorf("reserved name keys = %q, want the name keyed", keys)
+	}
+}
@@ -1,34 +1,61 @@
 package database
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
 
+	"kora_1/internal/migrations"
 	"kora_1/internal/models"
 
 	"gorm.io/gorm"
 )
 
-func Migrate(db *gorm.DB) {
+// Migrate applies every pending migration, then promotes the user with
+// adminEmail, if set, to administrator.
+func Migrate(ctx context.Context, db *gorm.DB, adminEmail string) error {
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return err
+	}
+	for _, m := range applied {
+		slog.InfoContext(ctx, "applied migration", "version", m.Version, "name", m.Name)
+	}
 
-	err := db.AutoMigrate(
-		&models.User{},
-		&models.Service{},
-		&models.DataType{},
-		&models.Group{},
-		&models.Collection{},
-		&models.CollectionItem{},
-		&models.FormGroup{},
-		&models.ReservedName{},
-		&models.Field{},
-		&models.Form{},
-		&models.FormFields{},
-		&models.Submission{},
-		&models.FormAnswer{},
-	)
+	// auth.admin_email bootstraps the first administrator from an already registered user.
+	if adminEmail != "" {
+		if err := db.WithContext(ctx).Model(&models.User{}).Where("email = ?", adminEmail).Update("role", models.RoleAdmin).Error; err != nil {
+			return fmt.Errorf("admin bootstrap failed: %w", err)
+		}
+	}
+	return nil
+}
 
-	if err != nil {
-		log.Fatal("Migration failed:", err)
+// EnsureSchema stops the server from starting against a database with
+// pending migrations. migrate, set by MIGRATE_ON_START, applies them instead;
+// it suits a single-instance deployment that does not run "kora migrate up".
+func EnsureSchema(ctx context.Context, db *gorm.DB, migrate bool, adminEmail string) error {
+	if migrate {
+		if err := Migrate(ctx, db, adminEmail); err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+		return nil
 	}
 
-	log.Println("Database migrated successfully")
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return fmt.Errorf("checking migrations failed: %w", err)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("database has %d pending migrations, run \"kora migrate up\" or set MIGRATE_ON_START=true", len(pending))
+	}
+	return nil
 }
@@ -0,0 +1,241 @@
+// Package datatypes maps the names stored in models.DataType onto the rules for
+// parsing, normalising and presenting answers of that type.
+//
+// Answers are persisted as text in FormAnswer.Answer; each Type defines the
+// canonical text form it is stored in and how that text is turned back into a
+// typed JSON value.
+package datatypes
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	Text       = "text"
+	Integer    = "integer"
+	Decimal    = "decimal"
+	Date       = "date"
+	Boolean    = "boolean"
+	Collection = "collection"
+	File       = "file"
+)
+
+const dateLayout = "2006-01-02"
+
+// Type parses, normalises and decodes answers of one data type.
+type Type interface {
+	// Name is the canonical name of the type.
+	Name() string
+	// Normalize parses a raw answer and returns the canonical text to store.
+	Normalize(raw string) (string, error)
+	// Decode converts a stored answer into the value returned in JSON.
+	Decode(stored string) (any, error)
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]Type{}
+)
+
+func init() {
+	Register(textType{}, "string", "textarea", "email", "phone")
+	Register(integerType{}, "int")
+	Register(decimalType{}, "number", "numeric", "float", "currency")
+	Register(dateType{})
+	Register(booleanType{}, "bool", "checkbox")
+	Register(collectionType{}, "select", "dropdown", "radio")
+	Register(fileType{}, "attachment", "upload")
+}
+
+// Register adds a type under its canonical name and any aliases.
+func Register(t Type, aliases ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry[key(t.Name())] = t
+	for _, alias := range aliases {
+		registry[key(alias)] = t
+	}
+}
+
+// Lookup returns the type registered for a models.DataType name.
+func Lookup(name string) (Type, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	t, ok := registry[key(name)]
+	return t, ok
+}
+
+// For returns the type registered for a models.DataType name, falling back to
+// free text for names the registry does not know.
+func For(name string) Type {
+	if t, ok := Lookup(name); ok {
+		return t
+	}
+	return textType{}
+}
+
+// Names returns the canonical name of every registered type, sorted.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	seen := make(map[string]bool)
+	var names []string
+	for _, t := range registry {
+		if !seen[t.Name()] {
+			seen[t.Name()] = true
+			names = append(names, t.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func key(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+type textType struct{}
+
+func (textType) Name() string { return Text }
+
+func (textType) Normalize(raw string) (string, error) { return raw, nil }
+
+func (textType) Decode(stored string) (any, error) { return stored, nil }
+
+type integerType struct{}
+
+func (integerType) Name() string { return Integer }
+
+func (integerType) Normalize(raw string) (string, error) {
+	n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil {
+		return "", errors.New("Must be a whole number")
+	}
+	return strconv.FormatInt(n, 10), nil
+}
+
+func (integerType) Decode(stored string) (any, error) {
+	return strconv.ParseInt(stored, 10, 64)
+}
+
+var decimalPattern = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+
+type decimalType struct{}
+
+func (decimalType) Name() string { return Decimal }
+
+// Normalize keeps the exact digits entered, stripping signs, leading zeros and
+// trailing fractional zeros, so "+007.50" is stored as "7.5".
+func (decimalType) Normalize(raw string) (string, error) {
+	s := strings.TrimSpace(raw)
+	if !decimalPattern.MatchString(s) {
+		return "", errors.New("Must be a decimal number")
+	}
+
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+	whole, frac, _ := strings.Cut(s, ".")
+	whole = strings.TrimLeft(whole, "0")
+	frac = strings.TrimRight(frac, "0")
+	if whole == "" {
+		whole = "0"
+	}
+
+	out := whole
+	if frac != "" {
+		out += "." + frac
+	}
+	if negative && out != "0" {
+		out = "-" + out
+	}
+	return out, nil
+}
+
+func (decimalType) Decode(stored string) (any, error) {
+	if !decimalPattern.MatchString(stored) {
+		return nil, errors.New("not a decimal number")
+	}
+	return json.Number(stored), nil
+}
+
+type dateType struct{}
+
+func (dateType) Name() string { return Date }
+
+// Normalize accepts YYYY-MM-DD or an RFC 3339 timestamp and stores the calendar day.
+func (dateType) Normalize(raw string) (string, error) {
+	s := strings.TrimSpace(raw)
+	if d, err := time.Parse(dateLayout, s); err == nil {
+		return d.Format(dateLayout), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.Format(dateLayout), nil
+	}
+	return "", errors.New("Must be a date in YYYY-MM-DD format")
+}
+
+func (dateType) Decode(stored string) (any, error) {
+	if _, err := time.Parse(dateLayout, stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+type booleanType struct{}
+
+func (booleanType) Name() string { return Boolean }
+
+func (booleanType) Normalize(raw string) (string, error) {
+	switch key(raw) {
+	case "true", "yes", "y", "1", "on":
+		return "true", nil
+	case "false", "no", "n", "0", "off":
+		return "false", nil
+	}
+	return "", errors.New("Must be true or false")
+}
+
+func (booleanType) Decode(stored string) (any, error) {
+	return strconv.ParseBool(stored)
+}
+
+// collectionType stores the ID of the chosen CollectionItem.
+type collectionType struct{}
+
+func (collectionType) Name() string { return Collection }
+
+func (collectionType) Normalize(raw string) (string, error) {
+	id, err := strconv.ParseUint(strings.TrimSpace(raw), 10, 32)
+	if err != nil || id == 0 {
+		return "", errors.New("Must be the ID of one of the available options")
+	}
+	return strconv.FormatUint(id, 10), nil
+}
+
+func (collectionType) Decode(stored string) (any, error) {
+	return strconv.ParseUint(stored, 10, 32)
+}
+
+// fileType stores the ID of an uploaded Attachment.
+type fileType struct{}
+
+func (fileType) Name() string { return File }
+
+func (fileType) Normalize(raw string) (string, error) {
+	id, err := strconv.ParseUint(strings.TrimSpace(raw), 10, 32)
+	if err != nil || id == 0 {
+		return "", errors.New("Must be the ID of an uploaded file")
+	}
+	return strconv.FormatUint(id, 10), nil
+}
+
+func (fileType) Decode(stored string) (any, error) {
+	return strconv.ParseUint(stored, 10, 32)
+}
@@ -0,0 +1,79 @@
+package datatypes
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		dataType string
+		raw      string
+		want     string
+		wantErr  bool
+	}{
+		{"Text", " as entered ", " as entered ", false},
+		{"Integer", " 042 ", "42", false},
+		{"Integer", "4.2", "", true},
+		{"Number", "+007.50", "7.5", false},
+		{"Decimal", "-0.00", "0", false},
+		{"Decimal", ".25", "0.25", false},
+		{"Decimal", "1e3", "", true},
+		{"Date", "2024-02-29", "2024-02-29", false},
+		{"Date", "2024-02-29T23:10:00Z", "2024-02-29", false},
+		{"Date", "29/02/2024", "", true},
+		{"Boolean", "Yes", "true", false},
+		{"Checkbox", "0", "false", false},
+		{"Boolean", "maybe", "", true},
+		{"Dropdown", "0012", "12", false},
+		{"Collection", "Lusaka", "", true},
+		{"Attachment", " 7 ", "7", false},
+		{"File", "scan.pdf", "", true},
+		{"Unknown Type", "kept", "kept", false},
+	}
+
+	for _, tt := range tests {
+		got, err := For(tt.dataType).Normalize(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s.Normalize(%q) error = %v, wantErr %v", tt.dataType, tt.raw, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s.Normalize(%q) = %q, want %q", tt.dataType, tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeProducesTypedJSON(t *testing.T) {
+	values := map[string]any{}
+	for name, stored := range map[string]string{
+		"integer":    "42",
+		"decimal":    "7.5",
+		"boolean":    "true",
+		"date":       "2024-02-29",
+		"collection": "12",
+	} {
+		v, err := For(name).Decode(stored)
+		if err != nil {
+			t.Fatalf("%s.Decode(%q) returned error: %v", name, stored, err)
+		}
+		values[name] = v
+	}
+
+	out, err := json.Marshal(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"boolean":true,"collection":12,"date":"2024-02-29","decimal":7.5,"integer":42}`
+	if string(out) != want {
+		t.Errorf("typed JSON = %s, want %s", out, want)
+	}
+}
+
+func TestNames(t *testing.T) {
+	want := []string{Boolean, Collection, Date, Decimal, File, Integer, Text}
+	if got := Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+}
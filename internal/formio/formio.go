@@ -0,0 +1,250 @@
+// Package formio exports a form version as a portable JSON document and
+// imports such a document as a new form, so a form designed in one
+// environment can be copied into another.
+//
+// Documents refer to data types, groups, collections and the service by name
+// rather than ID. On import data types and groups are matched by name and
+// created when missing, catalog fields are reused when one with the same
+// label, data type, group and collection exists, while collections and the
+// service must already exist.
+package formio
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"kora_1/internal/models"
+	"kora_1/internal/validation"
+
+	"gorm.io/gorm"
+)
+
+// FormatVersion is the version of the document layout written by Export.
+const FormatVersion = 1
+
+var ErrNoVersion = errors.New("form has no such version")
+
+// Document is a form version with everything it references spelled out by name.
+type Document struct {
+	Format      int     `json:"format"`
+	FormName    string  `json:"form_name"`
+	Description string  `json:"description,omitempty"`
+	DataType    string  `json:"data_type"`
+	Service     string  `json:"service,omitempty"`
+	Version     int     `json:"version,omitempty"` // The version exported, informational only
+	Groups      []Group `json:"groups,omitempty"`
+	Fields      []Field `json:"fields,omitempty"` // Fields that are not placed in a group
+}
+
+// Group is a form group with the fields placed in it.
+type Group struct {
+	Name   string  `json:"name"`
+	Span   int     `json:"span"`
+	Row    int     `json:"row"`
+	Fields []Field `json:"fields"`
+}
+
+// Field is a form field together with its catalog field.
+type Field struct {
+	Ref        uint   `json:"ref"` // Identifies the field within the document
+	FieldName  string `json:"field_name,omitempty"`
+	Label      string `json:"label"`
+	DataType   string `json:"data_type"`
+	Group      string `json:"group,omitempty"` // Catalog group of the field, not its form group
+	Collection string `json:"collection,omitempty"`
+	Validation string `json:"validation,omitempty"`
+	Span       int    `json:"span"`
+	Row        int    `json:"row"`
+	DependsOn  uint   `json:"depends_on,omitempty"` // Ref of the field this one depends on
+}
+
+// Export builds the document for a form version, or for the latest published
+// version (falling back to the draft) when version is zero.
+func Export(db *gorm.DB, formID uint, version int) (Document, error) {
+	form, err := models.GetForm(db, formID)
+	if err != nil {
+		return Document{}, err
+	}
+
+	var formVersion *models.FormVersion
+	if version > 0 {
+		formVersion, err = models.GetFormVersion(db, formID, version)
+	} else if formVersion, err = models.GetPublishedFormVersion(db, formID); errors.Is(err, gorm.ErrRecordNotFound) {
+		formVersion, err = models.GetLatestFormVersion(db, formID)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Document{}, ErrNoVersion
+	}
+	if err != nil {
+		return Document{}, err
+	}
+
+	doc := Document{
+		Format:      FormatVersion,
+		FormName:    formVersion.FormName,
+		Description: formVersion.Description,
+		Version:     formVersion.Version,
+	}
+	if form.DataType != nil {
+		doc.DataType = form.DataType.DataType
+	} else if dataType, err := models.GetDataType(db, form.DataTypeID); err == nil {
+		doc.DataType = dataType.DataType
+	} else {
+		return Document{}, err
+	}
+	if form.ServiceID != nil {
+		service, err := models.GetServiceByID(db, *form.ServiceID)
+		if err != nil {
+			return Document{}, err
+		}
+		doc.Service = service.ServiceName
+	}
+
+	layout, err := models.GetFormLayout(db, formVersion.ID)
+	if err != nil {
+		return Document{}, err
+	}
+
+	names := nameCache{db: db}
+	groups := make(map[uint]*Group)
+	var groupOrder []uint
+	for _, ff := range layout {
+		field := Field{
+			Ref:        ff.ID,
+			FieldName:  ff.FieldName,
+			Label:      ff.Field.Label,
+			DataType:   ff.Field.DataType.DataType,
+			Validation: ff.Validation,
+			Span:       ff.FieldSpan,
+			Row:        ff.FieldRow,
+		}
+		if ff.DependsOnFormFieldID != nil {
+			field.DependsOn = *ff.DependsOnFormFieldID
+		}
+		if field.Group, err = names.group(ff.Field.GroupID); err != nil {
+			return Document{}, err
+		}
+		if field.Collection, err = names.collection(ff.Field.CollectionID); err != nil {
+			return Document{}, err
+		}
+
+		if ff.FormGroup == nil {
+			doc.Fields = append(doc.Fields, field)
+			continue
+		}
+		group, ok := groups[ff.FormGroup.ID]
+		if !ok {
+			group = &Group{Name: ff.FormGroup.GroupName, Span: ff.FormGroup.GroupSpan, Row: ff.FormGroup.GroupRow}
+			groups[ff.FormGroup.ID] = group
+			groupOrder = append(groupOrder, ff.FormGroup.ID)
+		}
+		group.Fields = append(group.Fields, field)
+	}
+
+	sort.SliceStable(groupOrder, func(i, j int) bool {
+		a, b := groups[groupOrder[i]], groups[groupOrder[j]]
+		if a.Row != b.Row {
+			return a.Row < b.Row
+		}
+		return groupOrder[i] < groupOrder[j]
+	})
+	for _, id := range groupOrder {
+		doc.Groups = append(doc.Groups, *groups[id])
+	}
+	return doc, nil
+}
+
+// nameCache looks up group and collection names once each.
+type nameCache struct {
+	db          *gorm.DB
+	groups      map[uint]string
+	collections map[uint]string
+}
+
+func (n *nameCache) group(id *uint) (string, error) {
+	if id == nil {
+		return "", nil
+	}
+	if name, ok := n.groups[*id]; ok {
+		return name, nil
+	}
+	group, err := models.GetGroupByID(n.db, *id)
+	if err != nil {
+		return "", err
+	}
+	if n.groups == nil {
+		n.groups = make(map[uint]string)
+	}
+	n.groups[*id] = group.GroupName
+	return group.GroupName, nil
+}
+
+func (n *nameCache) collection(id *uint) (string, error) {
+	if id == nil {
+		return "", nil
+	}
+	if name, ok := n.collections[*id]; ok {
+		return name, nil
+	}
+	collection, err := models.GetCollection(n.db, *id)
+	if err != nil {
+		return "", err
+	}
+	if n.collections == nil {
+		n.collections = make(map[uint]string)
+	}
+	n.collections[*id] = collection.CollectionName
+	return collection.CollectionName, nil
+}
+
+// AllFields returns every field of the document, grouped fields first.
+func (d Document) AllFields() []Field {
+	var fields []Field
+	for _, g := range d.Groups {
+		fields = append(fields, g.Fields...)
+	}
+	return append(fields, d.Fields...)
+}
+
+// Check reports problems with a document that do not depend on the database.
+func (d Document) Check() error {
+	if d.Format != FormatVersion {
+		return fmt.Errorf("unsupported document format %d, expected %d", d.Format, FormatVersion)
+	}
+	if d.FormName == "" {
+		return errors.New("form_name is required")
+	}
+	if d.DataType == "" {
+		return errors.New("data_type is required")
+	}
+
+	refs := make(map[uint]bool)
+	for _, f := range d.AllFields() {
+		if f.Ref == 0 {
+			return fmt.Errorf("field %q has no ref", f.Label)
+		}
+		if refs[f.Ref] {
+			return fmt.Errorf("ref %d is used by more than one field", f.Ref)
+		}
+		refs[f.Ref] = true
+		if f.Label == "" || f.DataType == "" {
+			return fmt.Errorf("field %d needs a label and a data_type", f.Ref)
+		}
+		if _, err := validation.Parse(f.Validation); err != nil {
+			return fmt.Errorf("field %d has invalid validation rules: %w", f.Ref, err)
+		}
+	}
+	for _, f := range d.AllFields() {
+		if f.DependsOn == 0 {
+			continue
+		}
+		if f.DependsOn == f.Ref || !refs[f.DependsOn] {
+			return fmt.Errorf("field %d depends on unknown field %d", f.Ref, f.DependsOn)
+		}
+		if f.Collection == "" {
+			return fmt.Errorf("field %d depends on another field but has no collection", f.Ref)
+		}
+	}
+	return nil
+}
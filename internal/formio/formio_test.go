@@ -0,0 +1,59 @@
+package formio
+
+import (
+	"strings"
+	"testing"
+)
+
+func validDocument() Document {
+	return Document{
+		Format:   FormatVersion,
+		FormName: "Business registration",
+		DataType: "text",
+		Groups: []Group{{
+			Name: "Location",
+			Fields: []Field{
+				{Ref: 1, Label: "Province", DataType: "collection", Collection: "Provinces"},
+				{Ref: 2, Label: "District", DataType: "collection", Collection: "Districts", DependsOn: 1},
+			},
+		}},
+		Fields: []Field{{Ref: 3, Label: "Business name", DataType: "text", Validation: "required|max_length:50"}},
+	}
+}
+
+func TestAllFields(t *testing.T) {
+	var refs []uint
+	for _, f := range validDocument().AllFields() {
+		refs = append(refs, f.Ref)
+	}
+	if len(refs) != 3 || refs[0] != 1 || refs[2] != 3 {
+		t.Errorf("AllFields refs = %v, want [1 2 3]", refs)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := validDocument().Check(); err != nil {
+		t.Fatalf("valid document: %v", err)
+	}
+
+	tests := map[string]struct {
+		change func(d *Document)
+		want   string
+	}{
+		"format":         {func(d *Document) { d.Format = 2 }, "unsupported document format"},
+		"form name":      {func(d *Document) { d.FormName = "" }, "form_name"},
+		"missing ref":    {func(d *Document) { d.Fields[0].Ref = 0 }, "has no ref"},
+		"duplicate ref":  {func(d *Document) { d.Fields[0].Ref = 1 }, "more than one field"},
+		"bad rules":      {func(d *Document) { d.Fields[0].Validation = "nonsense" }, "invalid validation rules"},
+		"unknown parent": {func(d *Document) { d.Groups[0].Fields[1].DependsOn = 9 }, "unknown field 9"},
+		"self parent":    {func(d *Document) { d.Groups[0].Fields[1].DependsOn = 2 }, "unknown field 2"},
+		"no collection":  {func(d *Document) { d.Groups[0].Fields[1].Collection = "" }, "has no collection"},
+	}
+	for name, tt := range tests {
+		doc := validDocument()
+		tt.change(&doc)
+		if err := doc.Check(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Check() = %v, want an error containing %q", name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package formio
+
+import (
+	"errors"
+	"fmt"
+
+	"kora_1/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ImportOptions adjusts how a document is imported.
+type ImportOptions struct {
+	ServiceID *uint // Overrides the service named in the document
+	Publish   bool  // Publish the imported version instead of leaving it as a draft
+}
+
+// Import creates a new form from a document in a single transaction and
+// returns it with its first version.
+func Import(db *gorm.DB, doc Document, opts ImportOptions) (*models.Form, *models.FormVersion, error) {
+	if err := doc.Check(); err != nil {
+		return nil, nil, err
+	}
+
+	var form *models.Form
+	var version *models.FormVersion
+	err := db.Transaction(func(tx *gorm.DB) error {
+		r := resolver{tx: tx}
+
+		formDataType, err := r.dataType(doc.DataType)
+		if err != nil {
+			return err
+		}
+		serviceID := opts.ServiceID
+		if serviceID == nil && doc.Service != "" {
+			service, err := models.GetServiceByName(tx, doc.Service)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("service %q does not exist, create it or choose another service", doc.Service)
+			}
+			if err != nil {
+				return err
+			}
+			serviceID = &service.ID
+		}
+
+		form, err = models.CreateForm(tx, &models.Form{
+			FormName:    doc.FormName,
+			Description: doc.Description,
+			DataTypeID:  formDataType,
+			ServiceID:   serviceID,
+		})
+		if err != nil {
+			return err
+		}
+		if version, err = models.GetOrCreateDraftVersion(tx, form); err != nil {
+			return err
+		}
+
+		created := make(map[uint]*models.FormFields)
+		place := func(f Field, formGroupID *uint) error {
+			fieldID, err := r.field(f)
+			if err != nil {
+				return err
+			}
+			ff := &models.FormFields{
+				FormID:        form.ID,
+				FormVersionID: &version.ID,
+				FieldID:       fieldID,
+				FieldName:     f.FieldName,
+				FormGroupID:   formGroupID,
+				Validation:    f.Validation,
+				FieldSpan:     f.Span,
+				FieldRow:      f.Row,
+			}
+			if err := models.CreateFormFields(tx, ff); err != nil {
+				return err
+			}
+			created[f.Ref] = ff
+			return nil
+		}
+
+		for _, g := range doc.Groups {
+			formGroup := &models.FormGroup{GroupName: g.Name, GroupSpan: g.Span, GroupRow: g.Row}
+			if err := models.CreateFormGroup(tx, formGroup); err != nil {
+				return err
+			}
+			for _, f := range g.Fields {
+				if err := place(f, &formGroup.ID); err != nil {
+					return err
+				}
+			}
+		}
+		for _, f := range doc.Fields {
+			if err := place(f, nil); err != nil {
+				return err
+			}
+		}
+
+		for _, f := range doc.AllFields() {
+			if f.DependsOn == 0 {
+				continue
+			}
+			ff := created[f.Ref]
+			ff.DependsOnFormFieldID = &created[f.DependsOn].ID
+			if err := tx.Model(ff).Update("depends_on_form_field_id", ff.DependsOnFormFieldID).Error; err != nil {
+				return err
+			}
+		}
+
+		if opts.Publish {
+			return models.PublishFormVersion(tx, version)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return form, version, nil
+}
+
+// resolver maps the names in a document onto rows of the target database.
+type resolver struct {
+	tx          *gorm.DB
+	dataTypes   map[string]uint
+	groups      map[string]uint
+	collections map[string]uint
+}
+
+func (r *resolver) dataType(name string) (uint, error) {
+	if id, ok := r.dataTypes[name]; ok {
+		return id, nil
+	}
+	dataType, err := models.GetDataTypeByName(r.tx, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		dataType = &models.DataType{DataType: name}
+		err = models.CreateDataType(r.tx, dataType)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if r.dataTypes == nil {
+		r.dataTypes = make(map[string]uint)
+	}
+	r.dataTypes[name] = dataType.ID
+	return dataType.ID, nil
+}
+
+func (r *resolver) group(name string) (*uint, error) {
+	if name == "" {
+		return nil, nil
+	}
+	if id, ok := r.groups[name]; ok {
+		return &id, nil
+	}
+	group, err := models.GetGroupByName(r.tx, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		group = &models.Group{GroupName: name}
+		err = models.CreateGroup(r.tx, group)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.groups == nil {
+		r.groups = make(map[string]uint)
+	}
+	r.groups[name] = group.ID
+	return &group.ID, nil
+}
+
+func (r *resolver) collection(name string) (*uint, error) {
+	if name == "" {
+		return nil, nil
+	}
+	if id, ok := r.collections[name]; ok {
+		return &id, nil
+	}
+	collection, err := models.GetCollectionByName(r.tx, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("collection %q does not exist, import it first", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.collections == nil {
+		r.collections = make(map[string]uint)
+	}
+	r.collections[name] = collection.ID
+	return &collection.ID, nil
+}
+
+// field returns a catalog field matching f, creating one when there is none.
+func (r *resolver) field(f Field) (uint, error) {
+	dataTypeID, err := r.dataType(f.DataType)
+	if err != nil {
+		return 0, err
+	}
+	groupID, err := r.group(f.Group)
+	if err != nil {
+		return 0, err
+	}
+	collectionID, err := r.collection(f.Collection)
+	if err != nil {
+		return 0, err
+	}
+
+	field, err := models.FindField(r.tx, f.Label, dataTypeID, groupID, collectionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		field = &models.Field{Label: f.Label, DataTypeID: dataTypeID, GroupID: groupID, CollectionID: collectionID}
+		err = models.CreateFields(r.tx, field)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return field.ID, nil
+}
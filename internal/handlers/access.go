@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+
+	"kora_1/internal/auth"
+	"kora_1/internal/models"
+	"kora_1/internal/repository"
+)
+
+// canAccessSubmission reports whether a user may read a submission: admins see
+// everything, reviewers see submissions of services they are assigned to and
+// applicants see only their own.
+func (h *Handler) canAccessSubmission(ctx context.Context, user *models.User, submission *models.Submission) (bool, error) {
+	if auth.Can(user.Role, auth.ManageSubmissions) {
+		return true, nil
+	}
+	if auth.Can(user.Role, auth.ReviewSubmissions) {
+		if submission.ServicesID == nil {
+			return false, nil
+		}
+		return h.Users.IsReviewer(ctx, user.ID, *submission.ServicesID)
+	}
+	if auth.Can(user.Role, auth.ViewSubmissions) {
+		return submission.CreatedBy != nil && *submission.CreatedBy == user.ID, nil
+	}
+	return false, nil
+}
+
+// canAccessService reports whether a user may read the submissions of a whole
+// service. Applicants may, but only ever see their own rows.
+func (h *Handler) canAccessService(ctx context.Context, user *models.User, serviceID uint) (bool, error) {
+	if auth.Can(user.Role, auth.ManageSubmissions) {
+		return true, nil
+	}
+	if auth.Can(user.Role, auth.ReviewSubmissions) {
+		return h.Users.IsReviewer(ctx, user.ID, serviceID)
+	}
+	return auth.Can(user.Role, auth.ViewSubmissions), nil
+}
+
+// submissionScope narrows a submission listing to the rows canAccessSubmission
+// would allow the user to read.
+func submissionScope(user *models.User) repository.SubmissionScope {
+	switch {
+	case auth.Can(user.Role, auth.ManageSubmissions):
+		return repository.SubmissionScope{}
+	case auth.Can(user.Role, auth.ReviewSubmissions):
+		return repository.SubmissionScope{ReviewerID: &user.ID}
+	case auth.Can(user.Role, auth.ViewSubmissions):
+		return repository.SubmissionScope{CreatedBy: &user.ID}
+	default:
+		return repository.SubmissionScope{None: true}
+	}
+}
+
+// canAccessUser reports whether a user may read or edit another user's profile.
+func canAccessUser(user *models.User, id uint) bool {
+	return user.ID == id || auth.Can(user.Role, auth.ManageUsers)
+}
@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"kora_1/internal/datatypes"
+	"kora_1/internal/helpers"
+	"kora_1/internal/middleware"
+	"kora_1/internal/models"
+	"kora_1/internal/storage"
+	"kora_1/internal/structs"
+	"kora_1/internal/validation"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxUploadSize caps every upload; fields may set a lower limit with max_size.
+const maxUploadSize = 20 << 20
+
+type AttachmentResponse struct {
+	ID           uint   `json:"id"`
+	FileName     string `json:"file_name"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	SHA256       string `json:"sha256"`
+	FormFieldID  *uint  `json:"form_field_id"`
+	SubmissionID *uint  `json:"submission_id"`
+	CreatedOn    string `json:"created_on"`
+}
+
+// UploadAttachmentHandler stores an uploaded file for a file field
+// @Summary      Upload a file
+// @Description  Upload a file for a file-type form field. The field's max_size and mime rules are checked against the file's size and detected content type. Use the returned ID as the field's answer.
+// @Tags         attachments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        form_field_id  formData  int   true  "Form field the file answers"
+// @Param        file           formData  file  true  "File to upload"
+// @Success      201  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Failure      413  {object}  structs.ErrorResponse  "The file exceeds the upload limit"
+// @Failure      422  {object}  structs.ErrorResponse  "The file breaks the field's max_size or mime rules"
+// @Router       /attachments [post]
+func (h *Handler) UploadAttachmentHandler(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadSize+1<<20)
+
+	formFieldID, err := strconv.ParseUint(c.PostForm("form_field_id"), 10, 32)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			helpers.WriteError(c, http.StatusRequestEntityTooLarge, helpers.NewError("File exceeds the upload limit", helpers.CodePayloadTooLarge))
+			return
+		}
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("form_field_id is required", helpers.CodeInvalidRequest))
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			helpers.WriteError(c, http.StatusRequestEntityTooLarge, helpers.NewError("File exceeds the upload limit", helpers.CodePayloadTooLarge))
+			return
+		}
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("file is required", helpers.CodeInvalidRequest))
+		return
+	}
+	if header.Size > maxUploadSize {
+		helpers.WriteError(c, http.StatusRequestEntityTooLarge, helpers.NewError("File exceeds the upload limit", helpers.CodePayloadTooLarge))
+		return
+	}
+
+	fields, err := h.Forms.FormFields(c.Request.Context(), []uint{uint(formFieldID)})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if len(fields) == 0 {
+		respondError(c, helpers.ErrFormFieldNotFound)
+		return
+	}
+	field := fields[0]
+	if datatypes.For(field.Field.DataType.DataType).Name() != datatypes.File {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Form field does not accept files", helpers.CodeInvalidRequest))
+		return
+	}
+
+	rules, err := validation.Parse(field.Validation)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer file.Close()
+
+	// Trust the bytes, not the client's Content-Type, for the mime rule.
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		respondError(c, err)
+		return
+	}
+	contentType := http.DetectContentType(sniff[:n])
+
+	if fileFailures := rules.ValidateFile(header.Size, contentType); len(fileFailures) > 0 {
+		details := make([]structs.FieldError, 0, len(fileFailures))
+		for _, f := range fileFailures {
+			details = append(details, structs.FieldError{FormFieldID: field.ID, Rule: f.Rule, Message: f.Message})
+		}
+		helpers.WriteError(c, http.StatusUnprocessableEntity, helpers.NewValidationError(details))
+		return
+	}
+
+	hash := sha256.New()
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		respondError(c, err)
+		return
+	}
+	if _, err := io.Copy(hash, file); err != nil {
+		respondError(c, err)
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	key, err := newStorageKey()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := h.Files.Put(c.Request.Context(), key, file, header.Size, contentType); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	attachment := &models.Attachment{
+		StorageKey:  key,
+		FileName:    cleanFileName(header.Filename),
+		ContentType: contentType,
+		Size:        header.Size,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		FormFieldID: &field.ID,
+		UploadedBy:  &user.ID,
+	}
+	if err := h.Submissions.CreateAttachment(c.Request.Context(), attachment); err != nil {
+		_ = h.Files.Delete(c.Request.Context(), key)
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, helpers.NewSuccess[AttachmentResponse](attachmentToResponse(attachment), "File uploaded successfully"))
+}
+
+// GetAttachmentHandler retrieves an uploaded file's details
+// @Summary      Get file details
+// @Description  Retrieve the name, type, size and hash of an uploaded file
+// @Tags         attachments
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Attachment ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /attachments/{id} [get]
+func (h *Handler) GetAttachmentHandler(c *gin.Context) {
+	attachment, ok := h.loadAccessibleAttachment(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[AttachmentResponse](attachmentToResponse(attachment), "File retrieved successfully"))
+}
+
+// ListSubmissionAttachmentsHandler lists the files linked to a submission
+// @Summary      List submission files
+// @Description  Retrieve the details of the files a submission's answers reference
+// @Tags         submissions
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Submission ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /submission/{id}/attachments [get]
+func (h *Handler) ListSubmissionAttachmentsHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	submission, ok := h.loadAccessibleSubmission(c, user, uint(id))
+	if !ok {
+		return
+	}
+
+	attachments, err := h.Submissions.Attachments(c.Request.Context(), submission.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]AttachmentResponse, 0, len(attachments))
+	for i := range attachments {
+		response = append(response, attachmentToResponse(&attachments[i]))
+	}
+	c.JSON(http.StatusOK, helpers.NewSuccess(response, "Files retrieved successfully"))
+}
+
+// DownloadAttachmentHandler streams an uploaded file
+// @Summary      Download a file
+// @Description  Download the contents of an uploaded file
+// @Tags         attachments
+// @Produce      octet-stream
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Attachment ID"
+// @Success      200  {file}    file
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /attachments/{id}/content [get]
+func (h *Handler) DownloadAttachmentHandler(c *gin.Context) {
+	attachment, ok := h.loadAccessibleAttachment(c)
+	if !ok {
+		return
+	}
+
+	reader, err := h.Files.Get(c.Request.Context(), attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		helpers.WriteError(c, http.StatusNotFound, helpers.NewError("File content is missing", helpers.CodeFileNotFound))
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, reader, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// loadAccessibleAttachment loads the attachment named by the id parameter if
+// the user uploaded it or may see the submission it belongs to.
+func (h *Handler) loadAccessibleAttachment(c *gin.Context) (*models.Attachment, bool) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return nil, false
+	}
+
+	attachment, err := h.Submissions.Attachment(c.Request.Context(), uint(id))
+	if err != nil {
+		respondMissing(c, err, helpers.ErrFileNotFound)
+		return nil, false
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	if attachment.UploadedBy != nil && *attachment.UploadedBy == user.ID {
+		return attachment, true
+	}
+	if attachment.SubmissionID != nil {
+		submission, err := h.Submissions.Get(c.Request.Context(), *attachment.SubmissionID)
+		if err == nil {
+			ok, err := h.canAccessSubmission(c.Request.Context(), user, submission)
+			if err != nil {
+				respondError(c, err)
+				return nil, false
+			}
+			if ok {
+				return attachment, true
+			}
+		}
+	}
+
+	respondError(c, helpers.ErrFileNotFound)
+	return nil, false
+}
+
+// newStorageKey returns a random key grouped by upload month.
+func newStorageKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "attachments/" + time.Now().UTC().Format("2006/01") + "/" + hex.EncodeToString(b), nil
+}
+
+// cleanFileName keeps the base name of an uploaded file, without any client path.
+func cleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		return "upload"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
+
+func attachmentToResponse(attachment *models.Attachment) AttachmentResponse {
+	return AttachmentResponse{
+		ID:           attachment.ID,
+		FileName:     attachment.FileName,
+		ContentType:  attachment.ContentType,
+		Size:         attachment.Size,
+		SHA256:       attachment.SHA256,
+		FormFieldID:  attachment.FormFieldID,
+		SubmissionID: attachment.SubmissionID,
+		CreatedOn:    attachment.CreatedOn.Format(time.RFC3339),
+	}
+}
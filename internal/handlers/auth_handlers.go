@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"kora_1/internal/auth"
+	"kora_1/internal/helpers"
+	"kora_1/internal/middleware"
+	"kora_1/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LoginHandler exchanges an email and password for tokens
+// @Summary      Log in
+// @Description  Verify a user's email and password and issue an access and refresh token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      LoginRequest  true  "Login Request"
+// @Success      200      {object}  map[string]interface{}
+// @Failure      400,401,500  {object}  structs.ErrorResponse
+// @Router       /auth/login [post]
+func (h *Handler) LoginHandler(c *gin.Context) {
+	var request LoginRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	user, err := h.authenticate(c.Request.Context(), request.Email, request.Password)
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		helpers.WriteError(c, http.StatusUnauthorized, helpers.NewError("Invalid email or password", helpers.CodeInvalidCredentials))
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	tokens, err := h.Tokens.Issue(user)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[auth.TokenPair](tokens, "Logged in successfully"))
+}
+
+// RefreshHandler issues new tokens from a refresh token
+// @Summary      Refresh tokens
+// @Description  Exchange a valid refresh token for a new access and refresh token. Changing a user's password revokes the refresh tokens issued before.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      RefreshRequest  true  "Refresh Request"
+// @Success      200      {object}  map[string]interface{}
+// @Failure      400,401,500  {object}  structs.ErrorResponse
+// @Router       /auth/refresh [post]
+func (h *Handler) RefreshHandler(c *gin.Context) {
+	var request RefreshRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	claims, err := h.Tokens.Parse(request.RefreshToken, auth.RefreshToken)
+	if errors.Is(err, auth.ErrNotConfigured) {
+		respondError(c, err)
+		return
+	}
+	if err != nil {
+		helpers.WriteError(c, http.StatusUnauthorized, helpers.NewError("Invalid or expired refresh token", helpers.CodeInvalidToken))
+		return
+	}
+
+	userID, err := claims.UserID()
+	if err != nil {
+		helpers.WriteError(c, http.StatusUnauthorized, helpers.NewError("Invalid or expired refresh token", helpers.CodeInvalidToken))
+		return
+	}
+	user, err := h.Users.Get(c.Request.Context(), userID)
+	if err != nil {
+		helpers.WriteError(c, http.StatusUnauthorized, helpers.NewError("User no longer exists", helpers.CodeUnauthorized))
+		return
+	}
+	if claims.Revoked(user) {
+		helpers.WriteError(c, http.StatusUnauthorized, helpers.NewError("Refresh token was revoked by a password change", helpers.CodeInvalidToken))
+		return
+	}
+
+	tokens, err := h.Tokens.Issue(user)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[auth.TokenPair](tokens, "Tokens refreshed successfully"))
+}
+
+// MeHandler returns the authenticated user
+// @Summary      Current user
+// @Description  Retrieve the user the access token was issued to
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  structs.ErrorResponse
+// @Router       /auth/me [get]
+func (h *Handler) MeHandler(c *gin.Context) {
+	user, ok := middleware.CurrentUser(c)
+	if !ok {
+		helpers.WriteError(c, http.StatusUnauthorized, helpers.NewError("Not authenticated", helpers.CodeUnauthorized))
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[UserResponse](userToResponse(user), "User retrieved successfully"))
+}
+
+// authenticate checks a user's password. A password still stored in plaintext
+// is accepted once and replaced with its hash. Errors other than a wrong email
+// or password are returned as they are.
+func (h *Handler) authenticate(ctx context.Context, email, password string) (*models.User, error) {
+	user, err := h.Users.GetByEmail(ctx, email)
+	if helpers.IsNotFound(err) {
+		auth.RejectPassword(password)
+		return nil, auth.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Password == "" {
+		auth.RejectPassword(password)
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	if auth.IsHashed(user.Password) {
+		if !auth.CheckPassword(user.Password, password) {
+			return nil, auth.ErrInvalidCredentials
+		}
+		return user, nil
+	}
+
+	if subtle.ConstantTimeCompare([]byte(user.Password), []byte(password)) != 1 {
+		return nil, auth.ErrInvalidCredentials
+	}
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	user.SetPassword(hash)
+	if err := h.Users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
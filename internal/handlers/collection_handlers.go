@@ -1,11 +1,16 @@
 package handlers
 
 import (
-	"kora_1/internal/database"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"kora_1/internal/collectionio"
 	"kora_1/internal/helpers"
-	"kora_1/internal/models" // Required for Swagger
+	"kora_1/internal/models"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,20 +32,21 @@ type CollectionResponse struct {
 // @Tags         collections
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        request  body      CollectionRequest  true  "Collection Request"
 // @Success      201      {object}  map[string]interface{}
 // @Failure      400,500  {object}  structs.ErrorResponse
 // @Router       /collections [post]
-func CreateCollectionHandler(c *gin.Context) {
+func (h *Handler) CreateCollectionHandler(c *gin.Context) {
 	var request CollectionRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
 	collection := &models.Collection{CollectionName: request.CollectionName}
-	if err := models.CreateCollection(database.DB, collection); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.Collections.Create(c.Request.Context(), collection); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -56,21 +62,22 @@ func CreateCollectionHandler(c *gin.Context) {
 // @Tags         collections
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Collection ID"
 // @Success      200  {object}  map[string]interface{}
 // @Failure      400,404  {object}  structs.ErrorResponse
 // @Router       /collections/{id} [get]
-func GetCollectionHandler(c *gin.Context) {
+func (h *Handler) GetCollectionHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	collection, err := models.GetCollection(database.DB, uint(id))
+	collection, err := h.Collections.Get(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Collection not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrCollectionNotFound)
 		return
 	}
 
@@ -80,30 +87,43 @@ func GetCollectionHandler(c *gin.Context) {
 	}, "Collection retrieved successfully"))
 }
 
+var collectionsSpec = byName("collection_name")
+
 // GetAllCollectionsHandler retrieves all collections
 // @Summary      Get all collections
 // @Description  Retrieve all collections
 // @Tags         collections
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Param        limit   query     int     false  "Page size (default 50, max 200)"
+// @Param        offset  query     int     false  "Rows to skip"
+// @Param        cursor  query     string  false  "meta.next_cursor of the previous page"
+// @Param        sort    query     string  false  "Comma-separated sort fields, prefix - for descending (id, name)"
+// @Param        name    query     string  false  "Only names containing this text"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      500  {object}  structs.ErrorResponse
+// @Failure      400,500  {object}  structs.ErrorResponse
 // @Router       /collections [get]
-func GetAllCollectionsHandler(c *gin.Context) {
-	collections, err := models.GetAllCollections(database.DB)
+func (h *Handler) GetAllCollectionsHandler(c *gin.Context) {
+	params, ok := listParams(c, collectionsSpec)
+	if !ok {
+		return
+	}
+
+	collections, meta, err := h.Collections.List(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+		respondError(c, err)
 		return
 	}
 
-	var response []CollectionResponse
+	response := make([]CollectionResponse, 0, len(collections))
 	for _, col := range collections {
 		response = append(response, CollectionResponse{
 			ID:             col.ID,
 			CollectionName: col.CollectionName,
 		})
 	}
-	c.JSON(http.StatusOK, helpers.NewSuccess[[]CollectionResponse](response, "Collections retrieved successfully"))
+	c.JSON(http.StatusOK, helpers.NewPage(response, meta, "Collections retrieved successfully"))
 }
 
 // UpdateCollectionHandler updates a collection
@@ -112,34 +132,35 @@ func GetAllCollectionsHandler(c *gin.Context) {
 // @Tags         collections
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id       path      int                true  "Collection ID"
 // @Param        request  body      CollectionRequest  true  "Collection Request"
 // @Success      200      {object}  map[string]interface{}
 // @Failure      400,404,500  {object}  structs.ErrorResponse
 // @Router       /collections/{id} [put]
-func UpdateCollectionHandler(c *gin.Context) {
+func (h *Handler) UpdateCollectionHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
 	var request CollectionRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
-	collection, err := models.GetCollection(database.DB, uint(id))
+	collection, err := h.Collections.Get(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Collection not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrCollectionNotFound)
 		return
 	}
 
 	collection.CollectionName = request.CollectionName
-	if err := database.DB.Save(collection).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.Collections.Update(c.Request.Context(), collection); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -155,20 +176,21 @@ func UpdateCollectionHandler(c *gin.Context) {
 // @Tags         collections
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Collection ID"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      400,500  {object}  structs.ErrorResponse
+// @Failure      400,409,500  {object}  structs.ErrorResponse
 // @Router       /collections/{id} [delete]
-func DeleteCollectionHandler(c *gin.Context) {
+func (h *Handler) DeleteCollectionHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	if err := database.DB.Delete(&models.Collection{}, id).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.Collections.Delete(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -181,6 +203,7 @@ type CollectionItemRequest struct {
 	CollectionID              *uint  `json:"collection_id"`
 	CollectionItem            string `json:"collection_item"`
 	RelationCollectionItemsID *uint  `json:"relation_collection_items_id"`
+	Code                      string `json:"code" binding:"max=50"` // Defaults to the item ID; kept as is on update when blank
 }
 
 type CollectionItemResponse struct {
@@ -188,6 +211,7 @@ type CollectionItemResponse struct {
 	CollectionID              *uint  `json:"collection_id"`
 	CollectionItem            string `json:"collection_item"`
 	RelationCollectionItemsID *uint  `json:"relation_collection_items_id"`
+	Code                      string `json:"code"`
 }
 
 // CreateCollectionItemHandler creates a new collection item
@@ -196,14 +220,15 @@ type CollectionItemResponse struct {
 // @Tags         collection-items
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        request  body      CollectionItemRequest  true  "Collection Item Request"
 // @Success      201      {object}  map[string]interface{}
-// @Failure      400,500  {object}  structs.ErrorResponse
+// @Failure      400,409,500  {object}  structs.ErrorResponse
 // @Router       /collection_items [post]
-func CreateCollectionItemHandler(c *gin.Context) {
+func (h *Handler) CreateCollectionItemHandler(c *gin.Context) {
 	var request CollectionItemRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
@@ -211,10 +236,15 @@ func CreateCollectionItemHandler(c *gin.Context) {
 		CollectionID:              request.CollectionID,
 		CollectionItem:            request.CollectionItem,
 		RelationCollectionItemsID: request.RelationCollectionItemsID,
+		Code:                      strings.TrimSpace(request.Code),
+	}
+
+	if !h.collectionItemCodeAvailable(c, item) {
+		return
 	}
 
-	if err := models.CreateCollectionItem(database.DB, item); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.Collections.CreateItem(c.Request.Context(), item); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -223,6 +253,7 @@ func CreateCollectionItemHandler(c *gin.Context) {
 		CollectionID:              item.CollectionID,
 		CollectionItem:            item.CollectionItem,
 		RelationCollectionItemsID: item.RelationCollectionItemsID,
+		Code:                      item.Code,
 	}, "Collection item created successfully"))
 }
 
@@ -232,21 +263,22 @@ func CreateCollectionItemHandler(c *gin.Context) {
 // @Tags         collection-items
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Collection Item ID"
 // @Success      200  {object}  map[string]interface{}
 // @Failure      400,404  {object}  structs.ErrorResponse
 // @Router       /collection_items/{id} [get]
-func GetCollectionItemHandler(c *gin.Context) {
+func (h *Handler) GetCollectionItemHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	item, err := models.GetCollectionItem(database.DB, uint(id))
+	item, err := h.Collections.GetItem(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Collection item not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrCollectionItemNotFound)
 		return
 	}
 
@@ -255,6 +287,7 @@ func GetCollectionItemHandler(c *gin.Context) {
 		CollectionID:              item.CollectionID,
 		CollectionItem:            item.CollectionItem,
 		RelationCollectionItemsID: item.RelationCollectionItemsID,
+		Code:                      item.Code,
 	}, "Collection item retrieved successfully"))
 }
 
@@ -264,37 +297,45 @@ func GetCollectionItemHandler(c *gin.Context) {
 // @Tags         collection-items
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id       path      int                    true  "Collection Item ID"
 // @Param        request  body      CollectionItemRequest  true  "Collection Item Request"
 // @Success      200      {object}  map[string]interface{}
-// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Failure      400,404,409,500  {object}  structs.ErrorResponse
 // @Router       /collection_items/{id} [put]
-func UpdateCollectionItemHandler(c *gin.Context) {
+func (h *Handler) UpdateCollectionItemHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
 	var request CollectionItemRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
-	item, err := models.GetCollectionItem(database.DB, uint(id))
+	item, err := h.Collections.GetItem(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Collection item not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrCollectionItemNotFound)
 		return
 	}
 
 	item.CollectionID = request.CollectionID
 	item.CollectionItem = request.CollectionItem
 	item.RelationCollectionItemsID = request.RelationCollectionItemsID
+	if code := strings.TrimSpace(request.Code); code != "" {
+		item.Code = code
+	}
 
-	if err := database.DB.Save(item).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if !h.collectionItemCodeAvailable(c, item) {
+		return
+	}
+
+	if err := h.Collections.UpdateItem(c.Request.Context(), item); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -303,31 +344,344 @@ func UpdateCollectionItemHandler(c *gin.Context) {
 		CollectionID:              item.CollectionID,
 		CollectionItem:            item.CollectionItem,
 		RelationCollectionItemsID: item.RelationCollectionItemsID,
+		Code:                      item.Code,
 	}, "Collection item updated successfully"))
 }
 
+// collectionItemCodeAvailable responds with 409 when another item of the collection already has the code.
+func (h *Handler) collectionItemCodeAvailable(c *gin.Context, item *models.CollectionItem) bool {
+	if item.Code == "" || item.CollectionID == nil {
+		return true
+	}
+	taken, err := h.Collections.ItemCodeTaken(c.Request.Context(), *item.CollectionID, item.Code, item.ID)
+	if err != nil {
+		respondError(c, err)
+		return false
+	}
+	if taken {
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Code is already used in this collection", helpers.CodeDuplicateCode))
+		return false
+	}
+	return true
+}
+
 // DeleteCollectionItemHandler deletes a collection item
 // @Summary      Delete collection item
 // @Description  Delete a collection item by its ID
 // @Tags         collection-items
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Collection Item ID"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      400,500  {object}  structs.ErrorResponse
+// @Failure      400,409,500  {object}  structs.ErrorResponse
 // @Router       /collection_items/{id} [delete]
-func DeleteCollectionItemHandler(c *gin.Context) {
+func (h *Handler) DeleteCollectionItemHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	if err := database.DB.Delete(&models.CollectionItem{}, id).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.Collections.DeleteItem(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, helpers.NewSuccess[any](nil, "Collection item deleted successfully"))
 }
+
+// maxCollectionTreeDepth bounds how many levels of related items a tree export follows.
+const maxCollectionTreeDepth = 10
+
+type CollectionTreeNode struct {
+	ID             uint                 `json:"id"`
+	CollectionID   *uint                `json:"collection_id"`
+	CollectionItem string               `json:"collection_item"`
+	Code           string               `json:"code"`
+	Children       []CollectionTreeNode `json:"children"`
+}
+
+// GetCollectionItemsHandler lists a collection's items
+// @Summary      List collection items
+// @Description  Retrieve the items of a collection. Pass parent_id to get only the items related to that parent item, e.g. the districts of a province.
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      int  true   "Collection ID"
+// @Param        parent_id  query     int  false  "Parent collection item ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /collections/{id}/items [get]
+func (h *Handler) GetCollectionItemsHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	var parentID *uint
+	if raw := c.Query("parent_id"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid parent_id", helpers.CodeInvalidID))
+			return
+		}
+		pid := uint(parsed)
+		parentID = &pid
+	}
+
+	if _, err := h.Collections.Get(c.Request.Context(), uint(id)); err != nil {
+		respondMissing(c, err, helpers.ErrCollectionNotFound)
+		return
+	}
+
+	items, err := h.Collections.Items(c.Request.Context(), uint(id), parentID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]CollectionItemResponse, 0, len(items))
+	for _, item := range items {
+		response = append(response, CollectionItemResponse{
+			ID:                        item.ID,
+			CollectionID:              item.CollectionID,
+			CollectionItem:            item.CollectionItem,
+			RelationCollectionItemsID: item.RelationCollectionItemsID,
+			Code:                      item.Code,
+		})
+	}
+	c.JSON(http.StatusOK, helpers.NewSuccess[[]CollectionItemResponse](response, "Collection items retrieved successfully"))
+}
+
+// GetCollectionTreeHandler exports a collection as a tree
+// @Summary      Get collection tree
+// @Description  Retrieve a collection's top-level items with every related child item nested below them, following relations into other collections (e.g. provinces, their districts and their constituencies).
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Collection ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /collections/{id}/tree [get]
+func (h *Handler) GetCollectionTreeHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	if _, err := h.Collections.Get(c.Request.Context(), uint(id)); err != nil {
+		respondMissing(c, err, helpers.ErrCollectionNotFound)
+		return
+	}
+
+	items, err := h.Collections.Items(c.Request.Context(), uint(id), nil)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Items whose parent is in the same collection are nested under it rather than listed as roots.
+	inCollection := make(map[uint]bool, len(items))
+	for _, item := range items {
+		inCollection[item.ID] = true
+	}
+	var roots []models.CollectionItem
+	for _, item := range items {
+		if item.RelationCollectionItemsID == nil || !inCollection[*item.RelationCollectionItemsID] {
+			roots = append(roots, item)
+		}
+	}
+
+	children := make(map[uint][]models.CollectionItem)
+	visited := make(map[uint]bool)
+	level := make([]uint, 0, len(roots))
+	for _, item := range roots {
+		visited[item.ID] = true
+		level = append(level, item.ID)
+	}
+	for depth := 0; depth < maxCollectionTreeDepth && len(level) > 0; depth++ {
+		next, err := h.Collections.Children(c.Request.Context(), level)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		level = level[:0]
+		for _, item := range next {
+			if visited[item.ID] {
+				continue
+			}
+			visited[item.ID] = true
+			children[*item.RelationCollectionItemsID] = append(children[*item.RelationCollectionItemsID], item)
+			level = append(level, item.ID)
+		}
+	}
+
+	var build func(item models.CollectionItem) CollectionTreeNode
+	build = func(item models.CollectionItem) CollectionTreeNode {
+		node := CollectionTreeNode{
+			ID:             item.ID,
+			CollectionID:   item.CollectionID,
+			CollectionItem: item.CollectionItem,
+			Code:           item.Code,
+			Children:       make([]CollectionTreeNode, 0, len(children[item.ID])),
+		}
+		for _, child := range children[item.ID] {
+			node.Children = append(node.Children, build(child))
+		}
+		return node
+	}
+
+	tree := make([]CollectionTreeNode, 0, len(roots))
+	for _, item := range roots {
+		tree = append(tree, build(item))
+	}
+	c.JSON(http.StatusOK, helpers.NewSuccess[[]CollectionTreeNode](tree, "Collection tree retrieved successfully"))
+}
+
+// maxCollectionImportSize caps the size of an uploaded import document.
+const maxCollectionImportSize = 10 << 20
+
+// ImportCollectionHandler bulk loads items into a collection
+// @Summary      Import collection items
+// @Description  Upsert a collection's items by code from a CSV or JSON document with the columns code, label, parent_code and parent_collection_id. Send the document as the "file" field of a multipart form or as the raw request body. The whole import is rolled back if any row fails, and the failing rows are listed.
+// @Tags         collections
+// @Accept       mpfd,json,plain
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int     true   "Collection ID"
+// @Param        format  query     string  false  "csv or json, detected from the file name or content type when omitted"
+// @Param        file    formData  file    false  "CSV or JSON document"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,413,422,500  {object}  structs.ErrorResponse
+// @Router       /collections/{id}/import [post]
+func (h *Handler) ImportCollectionHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	if _, err := h.Collections.Get(c.Request.Context(), uint(id)); err != nil {
+		respondMissing(c, err, helpers.ErrCollectionNotFound)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCollectionImportSize)
+
+	var body io.Reader = c.Request.Body
+	fileName, contentType := "", c.ContentType()
+	if strings.HasPrefix(contentType, "multipart/") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			respondImportReadError(c, err)
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		defer file.Close()
+		body, fileName, contentType = file, header.Filename, header.Header.Get("Content-Type")
+	}
+
+	format, ok := collectionio.DetectFormat(fileName, contentType)
+	if raw := c.Query("format"); raw != "" {
+		format, err = collectionio.ParseFormat(raw)
+		ok = err == nil
+	}
+	if !ok {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Cannot tell the import format, pass format=csv or format=json", helpers.CodeInvalidRequest))
+		return
+	}
+
+	rows, rowErrors, err := collectionio.Decode(body, format)
+	if err != nil {
+		respondImportReadError(c, err)
+		return
+	}
+	if len(rowErrors) > 0 {
+		helpers.WriteError(c, http.StatusUnprocessableEntity, helpers.NewImportError(rowErrors))
+		return
+	}
+
+	result, err := h.Collections.Import(c.Request.Context(), uint(id), rows)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if len(result.Errors) > 0 {
+		helpers.WriteError(c, http.StatusUnprocessableEntity, helpers.NewImportError(result.Errors))
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[collectionio.Result](result, "Collection imported successfully"))
+}
+
+func respondImportReadError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		helpers.WriteError(c, http.StatusRequestEntityTooLarge, helpers.NewError("Import must be at most 10MB", helpers.CodePayloadTooLarge))
+		return
+	}
+	helpers.WriteError(c, http.StatusBadRequest, helpers.NewError(err.Error(), helpers.CodeInvalidRequest))
+}
+
+// ExportCollectionHandler downloads a collection's items
+// @Summary      Export collection items
+// @Description  Download a collection's items as CSV or JSON in the format accepted by the import endpoint
+// @Tags         collections
+// @Produce      json,plain
+// @Security     BearerAuth
+// @Param        id      path      int     true   "Collection ID"
+// @Param        format  query     string  false  "csv (default) or json"
+// @Success      200  {file}    file
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /collections/{id}/export [get]
+func (h *Handler) ExportCollectionHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	format, err := collectionio.ParseFormat(c.DefaultQuery("format", string(collectionio.CSV)))
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError(err.Error(), helpers.CodeInvalidRequest))
+		return
+	}
+
+	if _, err := h.Collections.Get(c.Request.Context(), uint(id)); err != nil {
+		respondMissing(c, err, helpers.ErrCollectionNotFound)
+		return
+	}
+
+	rows, err := h.Collections.Export(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := collectionio.Encode(&buf, format, rows); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == collectionio.JSON {
+		contentType = "application/json"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="collection-%d.%s"`, id, format))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"kora_1/internal/database"
 	"kora_1/internal/helpers"
 	"kora_1/internal/models"
 	"net/http"
@@ -25,20 +24,21 @@ type DataTypeResponse struct {
 // @Tags         data-types
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        request  body      DataTypeRequest  true  "Data Type Request"
 // @Success      201      {object}  map[string]interface{}
 // @Failure      400,500  {object}  structs.ErrorResponse
 // @Router       /data_types [post]
-func CreateDataTypeHandler(c *gin.Context) {
+func (h *Handler) CreateDataTypeHandler(c *gin.Context) {
 	var request DataTypeRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
 	dt := &models.DataType{DataType: request.DataType}
-	if err := models.CreateDataType(database.DB, dt); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.DataTypes.Create(c.Request.Context(), dt); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -54,21 +54,22 @@ func CreateDataTypeHandler(c *gin.Context) {
 // @Tags         data-types
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Data Type ID"
 // @Success      200  {object}  map[string]interface{}
 // @Failure      400,404  {object}  structs.ErrorResponse
 // @Router       /data_types/{id} [get]
-func GetDataTypeHandler(c *gin.Context) {
+func (h *Handler) GetDataTypeHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	dt, err := models.GetDataType(database.DB, uint(id))
+	dt, err := h.DataTypes.Get(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Data type not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrDataTypeNotFound)
 		return
 	}
 
@@ -78,23 +79,36 @@ func GetDataTypeHandler(c *gin.Context) {
 	}, "Data type retrieved successfully"))
 }
 
+var dataTypesSpec = byName("data_type")
+
 // GetAllDataTypesHandler retrieves all data types
 // @Summary      Get all data types
 // @Description  Retrieve all data types
 // @Tags         data-types
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Param        limit   query     int     false  "Page size (default 50, max 200)"
+// @Param        offset  query     int     false  "Rows to skip"
+// @Param        cursor  query     string  false  "meta.next_cursor of the previous page"
+// @Param        sort    query     string  false  "Comma-separated sort fields, prefix - for descending (id, name)"
+// @Param        name    query     string  false  "Only names containing this text"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      500  {object}  structs.ErrorResponse
+// @Failure      400,500  {object}  structs.ErrorResponse
 // @Router       /data_types [get]
-func GetAllDataTypesHandler(c *gin.Context) {
-	dts, err := models.GetAllDataTypes(database.DB)
+func (h *Handler) GetAllDataTypesHandler(c *gin.Context) {
+	params, ok := listParams(c, dataTypesSpec)
+	if !ok {
+		return
+	}
+
+	dts, meta, err := h.DataTypes.List(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+		respondError(c, err)
 		return
 	}
 
-	var response []DataTypeResponse
+	response := make([]DataTypeResponse, 0, len(dts))
 	for _, dt := range dts {
 		response = append(response, DataTypeResponse{
 			ID:       dt.ID,
@@ -102,7 +116,7 @@ func GetAllDataTypesHandler(c *gin.Context) {
 		})
 	}
 
-	c.JSON(http.StatusOK, helpers.NewSuccess[[]DataTypeResponse](response, "Data types retrieved successfully"))
+	c.JSON(http.StatusOK, helpers.NewPage(response, meta, "Data types retrieved successfully"))
 }
 
 // UpdateDataTypeHandler updates a data type
@@ -111,34 +125,35 @@ func GetAllDataTypesHandler(c *gin.Context) {
 // @Tags         data-types
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id       path      int              true  "Data Type ID"
 // @Param        request  body      DataTypeRequest  true  "Data Type Request"
 // @Success      200      {object}  map[string]interface{}
 // @Failure      400,404,500  {object}  structs.ErrorResponse
 // @Router       /data_types/{id} [put]
-func UpdateDataTypeHandler(c *gin.Context) {
+func (h *Handler) UpdateDataTypeHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
 	var request DataTypeRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
-	dt, err := models.GetDataType(database.DB, uint(id))
+	dt, err := h.DataTypes.Get(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Data type not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrDataTypeNotFound)
 		return
 	}
 
 	dt.DataType = request.DataType
-	if err := database.DB.Save(dt).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.DataTypes.Update(c.Request.Context(), dt); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -154,20 +169,21 @@ func UpdateDataTypeHandler(c *gin.Context) {
 // @Tags         data-types
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Data Type ID"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      400,500  {object}  structs.ErrorResponse
+// @Failure      400,409,500  {object}  structs.ErrorResponse
 // @Router       /data_types/{id} [delete]
-func DeleteDataTypeHandler(c *gin.Context) {
+func (h *Handler) DeleteDataTypeHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	if err := database.DB.Delete(&models.DataType{}, id).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.DataTypes.Delete(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"errors"
+	"kora_1/internal/helpers"
+	"kora_1/internal/middleware"
+	"kora_1/internal/models"
+	"kora_1/internal/repository"
+	"kora_1/internal/workflow"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DraftRequest struct {
+	FormID     *uint           `json:"form_id"`
+	ServicesID *uint           `json:"services_id"` // Optional; defaults to, and must match, the form's service
+	Answers    []AnswerRequest `json:"answers" binding:"dive"`
+}
+
+type DraftAnswersRequest struct {
+	Answers []AnswerRequest `json:"answers" binding:"required,min=1,dive"` // A blank answer clears the field
+}
+
+// CreateDraftHandler starts a draft submission
+// @Summary      Save a draft
+// @Description  Start a draft submission against a published form version. Answers are type-checked and validated, but required fields may be left blank until the draft is submitted.
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      DraftRequest  true  "Draft Request"
+// @Success      201      {object}  map[string]interface{}
+// @Failure      400,500  {object}  structs.ErrorResponse
+// @Failure      409      {object}  structs.ErrorResponse  "The form has no published version"
+// @Failure      422      {object}  structs.ErrorResponse  "Answers failed type coercion or validation, or services_id is not the form's service"
+// @Router       /submission/draft [post]
+func (h *Handler) CreateDraftHandler(c *gin.Context) {
+	var request DraftRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	version, err := resolveFormVersion(ctx, h.Repositories, request.FormID, request.Answers)
+	switch {
+	case errors.Is(err, errSubmissionFormNeeded):
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("form_id is required when no answers are given", helpers.CodeInvalidRequest))
+		return
+	case errors.Is(err, errNoPublishedVersion):
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Form has no published version", helpers.CodeNoPublishedVersion))
+		return
+	case errors.Is(err, errVersionNotPublished):
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Answers reference a form version that is not published", helpers.CodeVersionNotPublished))
+		return
+	case err != nil:
+		respondError(c, err)
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	checked, failures, err := checkAnswers(ctx, h.Repositories, version, request.Answers, answerCheck{uploaderID: user.ID})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if len(failures) > 0 {
+		helpers.WriteError(c, http.StatusUnprocessableEntity, helpers.NewValidationError(failures))
+		return
+	}
+
+	serviceID, err := submissionService(ctx, h.Repositories, version, request.ServicesID)
+	if errors.Is(err, errServiceMismatch) {
+		helpers.WriteError(c, http.StatusUnprocessableEntity, helpers.NewError("services_id does not match the form's service", helpers.CodeInvalidReference))
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	submission := &models.Submission{
+		ServicesID:    serviceID,
+		FormVersionID: &version.ID,
+		CreatedBy:     &user.ID,
+		Status:        workflow.Draft,
+	}
+
+	err = h.Transaction(ctx, func(tx repository.Repositories) error {
+		if err := tx.Submissions.Create(ctx, submission); err != nil {
+			return err
+		}
+		if err := tx.Submissions.SaveAnswers(ctx, submission.ID, checked.values); err != nil {
+			return err
+		}
+		if err := tx.Submissions.LinkAttachments(ctx, submission.ID, checked.attachments); err != nil {
+			return err
+		}
+		return tx.Submissions.AddHistory(ctx, &models.SubmissionStatusHistory{
+			SubmissionID: submission.ID,
+			ToStatus:     submission.Status,
+			ChangedBy:    &user.ID,
+		})
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	submission.FormVersion = version
+	h.respondWithSubmission(c, http.StatusCreated, submission, "Draft saved successfully")
+}
+
+// UpdateDraftAnswersHandler saves some answers of a draft
+// @Summary      Update draft answers
+// @Description  Save or replace individual answers of a draft, or of a submission returned for correction. A blank answer clears the field. Required fields are not enforced until submit.
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                  true  "Submission ID"
+// @Param        request  body      DraftAnswersRequest  true  "Draft Answers Request"
+// @Success      200      {object}  map[string]interface{}
+// @Failure      400,403,404,500  {object}  structs.ErrorResponse
+// @Failure      409      {object}  structs.ErrorResponse  "The submission can no longer be edited"
+// @Failure      422      {object}  structs.ErrorResponse  "Answers failed type coercion or validation"
+// @Router       /submission/{id}/answers [patch]
+func (h *Handler) UpdateDraftAnswersHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	var request DraftAnswersRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	submission, ok := h.loadEditableSubmission(c, user, uint(id))
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	checked, failures, err := checkAnswers(ctx, h.Repositories, submission.FormVersion, request.Answers, answerCheck{
+		uploaderID:   user.ID,
+		submissionID: submission.ID,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if len(failures) > 0 {
+		helpers.WriteError(c, http.StatusUnprocessableEntity, helpers.NewValidationError(failures))
+		return
+	}
+
+	err = h.Transaction(ctx, func(tx repository.Repositories) error {
+		if err := tx.Submissions.SaveAnswers(ctx, submission.ID, checked.values); err != nil {
+			return err
+		}
+		if err := tx.Submissions.LinkAttachments(ctx, submission.ID, checked.attachments); err != nil {
+			return err
+		}
+		return tx.Submissions.Touch(ctx, submission)
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.respondWithSubmission(c, http.StatusOK, submission, "Draft updated successfully")
+}
+
+// ListDraftsHandler lists the current user's drafts
+// @Summary      List my drafts
+// @Description  Retrieve the current user's unsubmitted drafts with their saved answers, most recently updated first
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}
+// @Failure      500  {object}  structs.ErrorResponse
+// @Router       /submission/drafts [get]
+func (h *Handler) ListDraftsHandler(c *gin.Context) {
+	user, _ := middleware.CurrentUser(c)
+
+	drafts, err := h.Submissions.ByStatus(c.Request.Context(), user.ID, workflow.Draft)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ids := make([]uint, len(drafts))
+	for i, d := range drafts {
+		ids[i] = d.ID
+	}
+	answers, err := h.Submissions.Answers(c.Request.Context(), ids)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]SubmissionResponse, 0, len(drafts))
+	for i := range drafts {
+		response = append(response, submissionToResponse(&drafts[i], answers[drafts[i].ID]))
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[[]SubmissionResponse](response, "Drafts retrieved successfully"))
+}
+
+// SubmitDraftHandler submits a draft
+// @Summary      Submit a draft
+// @Description  Validate every answer of a draft, or of a submission returned for correction, including required fields, and move it to submitted, or to the status its service's workflow lets the applicant submit to.
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Submission ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,403,404,500  {object}  structs.ErrorResponse
+// @Failure      409  {object}  structs.ErrorResponse  "The submission can no longer be edited, or the service's workflow does not let applicants submit it"
+// @Failure      422  {object}  structs.ErrorResponse  "Answers failed validation"
+// @Router       /submission/{id}/submit [post]
+func (h *Handler) SubmitDraftHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	submission, ok := h.loadEditableSubmission(c, user, uint(id))
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	stored, err := h.submissionAnswers(ctx, submission.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	answers := make([]AnswerRequest, 0, len(stored))
+	for _, ans := range stored {
+		if ans.FormFieldID != nil {
+			answers = append(answers, AnswerRequest{FormFieldID: *ans.FormFieldID, Answer: ans.Answer})
+		}
+	}
+
+	checked, failures, err := checkAnswers(ctx, h.Repositories, submission.FormVersion, answers, answerCheck{
+		final:        true,
+		uploaderID:   user.ID,
+		submissionID: submission.ID,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if len(failures) > 0 {
+		helpers.WriteError(c, http.StatusUnprocessableEntity, helpers.NewValidationError(failures))
+		return
+	}
+
+	err = h.Transaction(ctx, func(tx repository.Repositories) error {
+		if err := tx.Submissions.SaveAnswers(ctx, submission.ID, checked.values); err != nil {
+			return err
+		}
+		if err := tx.Submissions.LinkAttachments(ctx, submission.ID, checked.attachments); err != nil {
+			return err
+		}
+		machine, err := serviceWorkflow(ctx, tx, submission.ServicesID)
+		if err != nil {
+			return err
+		}
+		to, err := machine.Submit(submission.Status)
+		if err != nil {
+			return err
+		}
+		return transitionSubmission(ctx, tx, user, submission, to, "")
+	})
+	if !respondTransitionError(c, err) {
+		return
+	}
+
+	h.respondWithSubmission(c, http.StatusOK, submission, "Draft submitted successfully")
+}
+
+// loadEditableSubmission loads a submission whose answers the user may change:
+// their own, still a draft or returned for correction, and tied to a form version.
+func (h *Handler) loadEditableSubmission(c *gin.Context, user *models.User, id uint) (*models.Submission, bool) {
+	submission, ok := h.loadAccessibleSubmission(c, user, id)
+	if !ok {
+		return nil, false
+	}
+
+	if submission.CreatedBy == nil || *submission.CreatedBy != user.ID {
+		helpers.WriteError(c, http.StatusForbidden, helpers.NewError("Only the applicant can edit a submission's answers", helpers.CodeForbidden))
+		return nil, false
+	}
+	if submission.Status != workflow.Draft && submission.Status != workflow.Returned {
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Only drafts and submissions returned for correction can be edited", helpers.CodeNotEditable))
+		return nil, false
+	}
+	if submission.FormVersion == nil {
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Submission is not linked to a form version", helpers.CodeConflict))
+		return nil, false
+	}
+	return submission, true
+}
+
+func (h *Handler) respondWithSubmission(c *gin.Context, status int, submission *models.Submission, message string) {
+	answers, err := h.submissionAnswers(c.Request.Context(), submission.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(status, helpers.NewSuccess[SubmissionResponse](submissionToResponse(submission, answers), message))
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kora_1/internal/helpers"
+	"kora_1/internal/structs"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report request body fields by their JSON names.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// respondError answers with the status and code err maps to. Unexpected
+// errors are logged and answered without their message.
+func respondError(c *gin.Context, err error) {
+	status, response, internal := helpers.ErrorFor(err)
+	if internal {
+		slog.ErrorContext(c.Request.Context(), "request failed", "route", c.FullPath(), "error", err.Error())
+	}
+	helpers.WriteError(c, status, response)
+}
+
+// respondMissing answers with missing when err reports that the record does
+// not exist, and as respondError otherwise.
+func respondMissing(c *gin.Context, err error, missing *helpers.Error) {
+	if helpers.IsNotFound(err) {
+		err = missing
+	}
+	respondError(c, err)
+}
+
+// respondBindError answers a request body or query that failed to bind,
+// listing the fields that failed validation.
+func respondBindError(c *gin.Context, err error) {
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		message := "Invalid request"
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			message = "Request body is not valid JSON"
+		case errors.As(err, &typeErr):
+			message = typeErr.Field + " must be a " + typeErr.Type.String()
+		}
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError(message, helpers.CodeInvalidRequest))
+		return
+	}
+
+	details := make([]structs.FieldError, 0, len(invalid))
+	for _, fe := range invalid {
+		details = append(details, structs.FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Message: ruleMessage(fe),
+		})
+	}
+	helpers.WriteError(c, http.StatusBadRequest, helpers.NewValidationError(details))
+}
+
+// fieldPath is the JSON path of the field, without the request type's name.
+func fieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return path
+}
+
+func ruleMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "Is required"
+	case "email":
+		return "Must be an email address"
+	case "max", "min":
+		bound := "at most "
+		if fe.Tag() == "min" {
+			bound = "at least "
+		}
+		if fe.Kind() == reflect.String {
+			return "Must be " + bound + fe.Param() + " characters"
+		}
+		return "Must be " + bound + fe.Param()
+	case "oneof":
+		return "Must be one of " + fe.Param()
+	case "http_url":
+		return "Must be an http or https URL"
+	default:
+		return "Failed the " + fe.Tag() + " rule"
+	}
+}
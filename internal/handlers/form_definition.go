@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"kora_1/internal/datatypes"
+	"kora_1/internal/models"
+	"kora_1/internal/repository"
+	"kora_1/internal/validation"
+	"sort"
+)
+
+// FormDefinitionResponse is the complete form tree a renderer needs: the form
+// header, its groups in layout order and every field with its options.
+type FormDefinitionResponse struct {
+	FormResponse
+	FormVersionID uint                  `json:"form_version_id"`
+	Version       int                   `json:"version"`
+	VersionStatus string                `json:"version_status"`
+	Groups        []FormGroupDefinition `json:"groups"`
+	Fields        []FormFieldDefinition `json:"fields"` // Fields that are not placed in a group
+}
+
+type FormGroupDefinition struct {
+	ID        uint                  `json:"id"`
+	GroupName string                `json:"group_name"`
+	GroupSpan int                   `json:"group_span"`
+	GroupRow  int                   `json:"group_row"`
+	Fields    []FormFieldDefinition `json:"fields"`
+}
+
+type FormFieldDefinition struct {
+	ID           uint                     `json:"id"`
+	FieldID      uint                     `json:"field_id"`
+	FieldName    string                   `json:"field_name"`
+	Label        string                   `json:"label"`
+	DataTypeID   uint                     `json:"data_type_id"`
+	DataType     string                   `json:"data_type"`
+	Type         string                   `json:"type"` // Canonical type from the data type registry
+	Required     bool                     `json:"required"`
+	Validation   string                   `json:"validation"`
+	Rules        []ValidationRuleResponse `json:"rules"`
+	FieldSpan    int                      `json:"field_span"`
+	FieldRow     int                      `json:"field_row"`
+	CollectionID *uint                    `json:"collection_id"`
+	Options      []FieldOptionResponse    `json:"options,omitempty"`
+
+	// DependsOnFormFieldID is the field whose chosen item filters Options to those with that ParentID.
+	DependsOnFormFieldID *uint `json:"depends_on_form_field_id,omitempty"`
+}
+
+type ValidationRuleResponse struct {
+	Name string `json:"name"`
+	Arg  string `json:"arg,omitempty"`
+}
+
+type FieldOptionResponse struct {
+	ID       uint   `json:"id"`
+	Label    string `json:"label"`
+	ParentID *uint  `json:"parent_id,omitempty"`
+}
+
+// buildFormDefinition loads the fields, groups and collection options of a form
+// version and arranges them by GroupRow and FieldRow.
+func buildFormDefinition(ctx context.Context, repos repository.Repositories, form *models.Form, version *models.FormVersion) (FormDefinitionResponse, error) {
+	definition := FormDefinitionResponse{
+		FormResponse:  formToResponse(form),
+		FormVersionID: version.ID,
+		Version:       version.Version,
+		VersionStatus: version.Status,
+		Groups:        []FormGroupDefinition{},
+		Fields:        []FormFieldDefinition{},
+	}
+	definition.FormName = version.FormName
+	definition.Description = version.Description
+
+	formFields, err := repos.Forms.Layout(ctx, version.ID)
+	if err != nil {
+		return definition, err
+	}
+
+	options, err := loadFieldOptions(ctx, repos, formFields)
+	if err != nil {
+		return definition, err
+	}
+
+	groups := make(map[uint]*FormGroupDefinition)
+	var order []uint
+	for _, ff := range formFields {
+		field, err := formFieldToDefinition(ff, options)
+		if err != nil {
+			return definition, err
+		}
+
+		if ff.FormGroup == nil {
+			definition.Fields = append(definition.Fields, field)
+			continue
+		}
+
+		group, ok := groups[ff.FormGroup.ID]
+		if !ok {
+			group = &FormGroupDefinition{
+				ID:        ff.FormGroup.ID,
+				GroupName: ff.FormGroup.GroupName,
+				GroupSpan: ff.FormGroup.GroupSpan,
+				GroupRow:  ff.FormGroup.GroupRow,
+				Fields:    []FormFieldDefinition{},
+			}
+			groups[ff.FormGroup.ID] = group
+			order = append(order, ff.FormGroup.ID)
+		}
+		group.Fields = append(group.Fields, field)
+	}
+
+	for _, id := range order {
+		definition.Groups = append(definition.Groups, *groups[id])
+	}
+	sort.SliceStable(definition.Groups, func(i, j int) bool {
+		if definition.Groups[i].GroupRow != definition.Groups[j].GroupRow {
+			return definition.Groups[i].GroupRow < definition.Groups[j].GroupRow
+		}
+		return definition.Groups[i].ID < definition.Groups[j].ID
+	})
+
+	return definition, nil
+}
+
+// loadFieldOptions returns the collection items of every collection-backed field, keyed by collection ID.
+func loadFieldOptions(ctx context.Context, repos repository.Repositories, formFields []models.FormFields) (map[uint][]FieldOptionResponse, error) {
+	var collectionIDs []uint
+	seen := make(map[uint]bool)
+	for _, ff := range formFields {
+		if id := ff.Field.CollectionID; id != nil && !seen[*id] {
+			seen[*id] = true
+			collectionIDs = append(collectionIDs, *id)
+		}
+	}
+
+	options := make(map[uint][]FieldOptionResponse, len(collectionIDs))
+	if len(collectionIDs) == 0 {
+		return options, nil
+	}
+
+	items, err := repos.Collections.ItemsIn(ctx, collectionIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		options[*item.CollectionID] = append(options[*item.CollectionID], FieldOptionResponse{
+			ID:       item.ID,
+			Label:    item.CollectionItem,
+			ParentID: item.RelationCollectionItemsID,
+		})
+	}
+	return options, nil
+}
+
+func formFieldToDefinition(ff models.FormFields, options map[uint][]FieldOptionResponse) (FormFieldDefinition, error) {
+	rules, err := validation.Parse(ff.Validation)
+	if err != nil {
+		return FormFieldDefinition{}, fmt.Errorf("form field %d has invalid validation rules: %w", ff.ID, err)
+	}
+
+	field := FormFieldDefinition{
+		ID:           ff.ID,
+		FieldID:      ff.FieldID,
+		FieldName:    ff.FieldName,
+		Label:        ff.Field.Label,
+		DataTypeID:   ff.Field.DataTypeID,
+		DataType:     ff.Field.DataType.DataType,
+		Type:         datatypes.For(ff.Field.DataType.DataType).Name(),
+		Required:     rules.Has(validation.Required),
+		Validation:   ff.Validation,
+		Rules:        make([]ValidationRuleResponse, 0, len(rules)),
+		FieldSpan:    ff.FieldSpan,
+		FieldRow:     ff.FieldRow,
+		CollectionID: ff.Field.CollectionID,
+
+		DependsOnFormFieldID: ff.DependsOnFormFieldID,
+	}
+	for _, rule := range rules {
+		field.Rules = append(field.Rules, ValidationRuleResponse{Name: rule.Name, Arg: rule.Arg})
+	}
+	if ff.Field.CollectionID != nil {
+		field.Options = options[*ff.Field.CollectionID]
+	}
+	return field, nil
+}
@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"kora_1/internal/database"
+	"errors"
 	"kora_1/internal/helpers"
 	"kora_1/internal/models"
+	"kora_1/internal/query"
 	"net/http"
 	"strconv"
 
@@ -29,14 +30,15 @@ type FormGroupResponse struct {
 // @Tags         form-groups
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        request  body      FormGroupRequest  true  "Form Group Request"
 // @Success      201      {object}  map[string]interface{}
 // @Failure      400,500  {object}  structs.ErrorResponse
 // @Router       /form_groups [post]
-func CreateFormGroupHandler(c *gin.Context) {
+func (h *Handler) CreateFormGroupHandler(c *gin.Context) {
 	var request FormGroupRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
@@ -46,8 +48,8 @@ func CreateFormGroupHandler(c *gin.Context) {
 		GroupRow:  request.GroupRow,
 	}
 
-	if err := models.CreateFormGroup(database.DB, fg); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.FormGroups.Create(c.Request.Context(), fg); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -65,21 +67,22 @@ func CreateFormGroupHandler(c *gin.Context) {
 // @Tags         form-groups
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Form Group ID"
 // @Success      200  {object}  map[string]interface{}
 // @Failure      400,404  {object}  structs.ErrorResponse
 // @Router       /form_groups/{id} [get]
-func GetFormGroupHandler(c *gin.Context) {
+func (h *Handler) GetFormGroupHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	fg, err := models.GetFormGroup(database.DB, uint(id))
+	fg, err := h.FormGroups.Get(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Form group not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrFormGroupNotFound)
 		return
 	}
 
@@ -91,23 +94,40 @@ func GetFormGroupHandler(c *gin.Context) {
 	}, "Form group retrieved successfully"))
 }
 
+var formGroupsSpec = func() query.Spec {
+	spec := byName("group_name")
+	spec.Sorts["row"] = "group_row"
+	return spec
+}()
+
 // GetAllFormGroupsHandler retrieves all form groups
 // @Summary      Get all form groups
 // @Description  Retrieve all form groups
 // @Tags         form-groups
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Param        limit   query     int     false  "Page size (default 50, max 200)"
+// @Param        offset  query     int     false  "Rows to skip"
+// @Param        cursor  query     string  false  "meta.next_cursor of the previous page"
+// @Param        sort    query     string  false  "Comma-separated sort fields, prefix - for descending (id, name, row)"
+// @Param        name    query     string  false  "Only names containing this text"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      500  {object}  structs.ErrorResponse
+// @Failure      400,500  {object}  structs.ErrorResponse
 // @Router       /form_groups [get]
-func GetAllFormGroupsHandler(c *gin.Context) {
-	fgs, err := models.GetAllFormGroups(database.DB)
+func (h *Handler) GetAllFormGroupsHandler(c *gin.Context) {
+	params, ok := listParams(c, formGroupsSpec)
+	if !ok {
+		return
+	}
+
+	fgs, meta, err := h.FormGroups.List(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+		respondError(c, err)
 		return
 	}
 
-	var response []FormGroupResponse
+	response := make([]FormGroupResponse, 0, len(fgs))
 	for _, fg := range fgs {
 		response = append(response, FormGroupResponse{
 			ID:        fg.ID,
@@ -116,37 +136,39 @@ func GetAllFormGroupsHandler(c *gin.Context) {
 			GroupRow:  fg.GroupRow,
 		})
 	}
-	c.JSON(http.StatusOK, helpers.NewSuccess[[]FormGroupResponse](response, "Form groups retrieved successfully"))
+	c.JSON(http.StatusOK, helpers.NewPage(response, meta, "Form groups retrieved successfully"))
 }
 
 // UpdateFormGroupHandler updates a form group
 // @Summary      Update form group
-// @Description  Update an existing form group by its ID
+// @Description  Update an existing form group by its ID, unless a published form version lays fields out in it
 // @Tags         form-groups
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id       path      int               true  "Form Group ID"
 // @Param        request  body      FormGroupRequest  true  "Form Group Request"
 // @Success      200      {object}  map[string]interface{}
 // @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Failure      409      {object}  structs.ErrorResponse  "A published form version uses the form group"
 // @Router       /form_groups/{id} [put]
-func UpdateFormGroupHandler(c *gin.Context) {
+func (h *Handler) UpdateFormGroupHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
 	var request FormGroupRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
-	fg, err := models.GetFormGroup(database.DB, uint(id))
+	fg, err := h.FormGroups.Get(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Form group not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrFormGroupNotFound)
 		return
 	}
 
@@ -154,8 +176,13 @@ func UpdateFormGroupHandler(c *gin.Context) {
 	fg.GroupSpan = request.GroupSpan
 	fg.GroupRow = request.GroupRow
 
-	if err := database.DB.Save(fg).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	err = h.FormGroups.Update(c.Request.Context(), fg)
+	if errors.Is(err, models.ErrFormVersionPublished) {
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Form group lays out a published form version; create a new form group instead", helpers.CodeVersionPublished))
+		return
+	}
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -169,24 +196,30 @@ func UpdateFormGroupHandler(c *gin.Context) {
 
 // DeleteFormGroupHandler deletes a form group
 // @Summary      Delete form group
-// @Description  Delete a form group by its ID
+// @Description  Delete a form group by its ID, unless a published form version lays fields out in it
 // @Tags         form-groups
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Form Group ID"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      400,500  {object}  structs.ErrorResponse
+// @Failure      400,409,500  {object}  structs.ErrorResponse
 // @Router       /form_groups/{id} [delete]
-func DeleteFormGroupHandler(c *gin.Context) {
+func (h *Handler) DeleteFormGroupHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	if err := database.DB.Delete(&models.FormGroup{}, id).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	err = h.FormGroups.Delete(c.Request.Context(), uint(id))
+	if errors.Is(err, models.ErrFormVersionPublished) {
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Form group lays out a published form version", helpers.CodeVersionPublished))
+		return
+	}
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
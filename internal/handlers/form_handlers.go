@@ -1,15 +1,22 @@
 package handlers
 
 import (
-	"kora_1/internal/database"
+	"context"
+	"errors"
+	"fmt"
 	"kora_1/internal/helpers"
 	"kora_1/internal/models"
+	"kora_1/internal/query"
+	"kora_1/internal/repository"
+	"kora_1/internal/validation"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
+var errInvalidDependency = errors.New("invalid field dependency")
+
 type FormRequest struct {
 	FormName    string               `json:"form_name" binding:"required"`
 	Description string               `json:"description"`
@@ -37,21 +44,29 @@ type FormResponse struct {
 
 // FormHandler creates a new form
 // @Summary      Create a new form
-// @Description  Create a new form with fields
+// @Description  Create a new form with fields. The form and its fields are saved in a single transaction as draft version 1; publish it before accepting submissions.
 // @Tags         form
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        request  body      FormRequest  true  "Form Request"
 // @Success      201      {object}  map[string]interface{}
 // @Failure      400,500  {object}  structs.ErrorResponse
 // @Router       /form [post]
-func FormHandler(c *gin.Context) {
+func (h *Handler) FormHandler(c *gin.Context) {
 	var request FormRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
+	for _, field := range request.Fields {
+		if _, err := validation.Parse(field.Validations); err != nil {
+			helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid validation rules: "+err.Error(), helpers.CodeInvalidRequest))
+			return
+		}
+	}
+
 	newForm := &models.Form{
 		FormName:    request.FormName,
 		Description: request.Description,
@@ -60,118 +75,270 @@ func FormHandler(c *gin.Context) {
 		Status:      request.Status,
 	}
 
-	createdForm, err := models.CreateForm(database.DB, newForm)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
-		return
-	}
+	ctx := c.Request.Context()
+	err := h.Transaction(ctx, func(tx repository.Repositories) error {
+		if err := tx.Forms.Create(ctx, newForm); err != nil {
+			return err
+		}
+
+		draft, err := tx.Forms.OpenDraft(ctx, newForm)
+		if err != nil {
+			return err
+		}
 
-	if len(request.Fields) > 0 {
 		for _, field := range request.Fields {
-			models.CreateFormFields(database.DB, &models.FormFields{
-				FormID:     createdForm.ID,
-				FieldID:    field.FieldID,
-				Validation: field.Validations,
-				FieldSpan:  field.FieldSpan,
-				FieldRow:   field.FieldRow,
-			})
+			if err := tx.Forms.CreateFormField(ctx, &models.FormFields{
+				FormID:        newForm.ID,
+				FormVersionID: &draft.ID,
+				FieldID:       field.FieldID,
+				Validation:    field.Validations,
+				FieldSpan:     field.FieldSpan,
+				FieldRow:      field.FieldRow,
+			}); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, helpers.NewSuccess[FormResponse](FormResponse{
-		ID:          createdForm.ID,
-		FormName:    createdForm.FormName,
-		Description: createdForm.Description,
-		DataTypeID:  createdForm.DataTypeID,
-		ServiceID:   createdForm.ServiceID,
-		Status:      createdForm.Status,
-	}, "Form created successfully"))
+	c.JSON(http.StatusCreated, helpers.NewSuccess[FormResponse](formToResponse(newForm), "Form created successfully"))
 }
 
-// GetFormWithFieldsHandler retrieves a form by ID
-// @Summary      Get form by ID
-// @Description  Retrieve a form by its ID
+// GetFormWithFieldsHandler retrieves the full definition of a form
+// @Summary      Get form definition by ID
+// @Description  Retrieve a form version with its groups, fields, data types, validation rules and collection options in layout order.
+// @Description  Without a version the latest published version is returned, or the draft when the form was never published.
 // @Tags         form
 // @Accept       json
 // @Produce      json
-// @Param        id   path      int  true  "Form ID"
+// @Security     BearerAuth
+// @Param        id       path      int     true   "Form ID"
+// @Param        version  query     string  false  "Version number, or \"draft\""
 // @Success      200  {object}  map[string]interface{}
-// @Failure      400,404  {object}  structs.ErrorResponse
+// @Failure      400,404,500  {object}  structs.ErrorResponse
 // @Router       /form/{id} [get]
-func GetFormWithFieldsHandler(c *gin.Context) {
+func (h *Handler) GetFormWithFieldsHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	form, err := models.GetForm(database.DB, uint(id))
+	form, err := h.Forms.Get(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError(err.Error(), http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrFormNotFound)
 		return
 	}
 
-	c.JSON(http.StatusOK, helpers.NewSuccess[FormResponse](FormResponse{
-		ID:          form.ID,
-		FormName:    form.FormName,
-		Description: form.Description,
-		DataTypeID:  form.DataTypeID,
-		ServiceID:   form.ServiceID,
-		Status:      form.Status,
-	}, "Form retrieved successfully"))
+	version, ok := h.requestedFormVersion(c, form.ID)
+	if !ok {
+		return
+	}
+
+	definition, err := buildFormDefinition(c.Request.Context(), h.Repositories, form, version)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[FormDefinitionResponse](definition, "Form retrieved successfully"))
+}
+
+// requestedFormVersion resolves the ?version= parameter: a version number,
+// "draft", or by default the latest published version, falling back to the
+// draft when the form was never published.
+func (h *Handler) requestedFormVersion(c *gin.Context, formID uint) (*models.FormVersion, bool) {
+	ctx := c.Request.Context()
+	var version *models.FormVersion
+	var err error
+	switch requested := c.Query("version"); requested {
+	case "":
+		version, err = h.Forms.PublishedVersion(ctx, formID)
+		if errors.Is(err, repository.ErrNotFound) {
+			version, err = h.Forms.DraftVersion(ctx, formID)
+		}
+	case models.FormVersionDraft:
+		version, err = h.Forms.DraftVersion(ctx, formID)
+	default:
+		number, convErr := strconv.Atoi(requested)
+		if convErr != nil {
+			helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid version", helpers.CodeInvalidRequest))
+			return nil, false
+		}
+		version, err = h.Forms.Version(ctx, formID, number)
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		respondError(c, helpers.ErrFormVersionNotFound)
+		return nil, false
+	}
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	return version, true
+}
+
+var formsSpec = query.Spec{
+	Sorts:       map[string]string{"id": "id", "name": "form_name"},
+	DefaultSort: "id",
+	Filters: map[string]query.Filter{
+		"name":         {Column: "form_name", Op: query.Contains},
+		"service_id":   {Column: "service_id", Op: query.Equal, Kind: query.Int},
+		"data_type_id": {Column: "data_type_id", Op: query.Equal, Kind: query.Int},
+		"status":       {Column: "status", Op: query.Equal, Kind: query.Bool},
+	},
+}
+
+// ListFormsHandler lists forms
+// @Summary      List forms
+// @Description  Retrieve a page of forms, optionally only those of one service
+// @Tags         form
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit         query     int     false  "Page size (default 50, max 200)"
+// @Param        offset        query     int     false  "Rows to skip"
+// @Param        cursor        query     string  false  "meta.next_cursor of the previous page"
+// @Param        sort          query     string  false  "Comma-separated sort fields, prefix - for descending (id, name)"
+// @Param        name          query     string  false  "Only names containing this text"
+// @Param        service_id    query     string  false  "Comma-separated service IDs"
+// @Param        data_type_id  query     string  false  "Comma-separated data type IDs"
+// @Param        status        query     bool    false  "Form status"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,500  {object}  structs.ErrorResponse
+// @Router       /form [get]
+func (h *Handler) ListFormsHandler(c *gin.Context) {
+	params, ok := listParams(c, formsSpec)
+	if !ok {
+		return
+	}
+
+	forms, meta, err := h.Forms.List(c.Request.Context(), params)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]FormResponse, 0, len(forms))
+	for i := range forms {
+		response = append(response, formToResponse(&forms[i]))
+	}
+	c.JSON(http.StatusOK, helpers.NewPage(response, meta, "Forms retrieved successfully"))
+}
+
+// ListFormFieldsHandler lists the fields placed on a form version
+// @Summary      List form fields
+// @Description  Retrieve the form fields of a form version in layout order, without the nested definition returned by GET /form/{id}.
+// @Description  Without a version the latest published version is used, or the draft when the form was never published.
+// @Tags         form
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int     true   "Form ID"
+// @Param        version  query     string  false  "Version number, or \"draft\""
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /form/{id}/fields [get]
+func (h *Handler) ListFormFieldsHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	if _, err := h.Forms.Get(c.Request.Context(), uint(id)); err != nil {
+		respondMissing(c, err, helpers.ErrFormNotFound)
+		return
+	}
+	version, ok := h.requestedFormVersion(c, uint(id))
+	if !ok {
+		return
+	}
+
+	formFields, err := h.Forms.Layout(c.Request.Context(), version.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]FormFieldResponse, 0, len(formFields))
+	for i := range formFields {
+		response = append(response, formFieldToResponse(&formFields[i]))
+	}
+	c.JSON(http.StatusOK, helpers.NewSuccess(response, "Form fields retrieved successfully"))
 }
 
 // UpdateFormHandler updates a form
 // @Summary      Update form
-// @Description  Update an existing form by its ID
+// @Description  Update a form by its ID. Name and description changes go to the form's draft version, which is created
+// @Description  from the latest version when none exists; published versions are never modified.
+// @Description  Once a version is published, the data type, service and status of the form can no longer change.
 // @Tags         form
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id       path      int          true  "Form ID"
 // @Param        request  body      FormRequest  true  "Form Request"
 // @Success      200      {object}  map[string]interface{}
 // @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Failure      409      {object}  structs.ErrorResponse  "The form has a published version and the data type, service or status changed"
 // @Router       /form/{id} [put]
-func UpdateFormHandler(c *gin.Context) {
+func (h *Handler) UpdateFormHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
 	var request FormRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
-	form, err := models.GetForm(database.DB, uint(id))
+	ctx := c.Request.Context()
+	form, err := h.Forms.Get(ctx, uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Form not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrFormNotFound)
 		return
 	}
 
-	form.FormName = request.FormName
-	form.Description = request.Description
-	form.DataTypeID = request.DataTypeID
-	form.ServiceID = request.ServiceID
-	form.Status = request.Status
+	var draft *models.FormVersion
+	err = h.Transaction(ctx, func(tx repository.Repositories) error {
+		var err error
+		draft, err = tx.Forms.OpenDraft(ctx, form)
+		if err != nil {
+			return err
+		}
+
+		draft.FormName = request.FormName
+		draft.Description = request.Description
+		if err := tx.Forms.UpdateVersion(ctx, draft); err != nil {
+			return err
+		}
 
-	if err := models.UpdateForm(database.DB, form); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+		form.DataTypeID = request.DataTypeID
+		form.ServiceID = request.ServiceID
+		form.Status = request.Status
+		form.Service = nil
+		return tx.Forms.Update(ctx, form)
+	})
+	if errors.Is(err, models.ErrFormVersionPublished) {
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Form has a published version; its data type, service and status can no longer change", helpers.CodeVersionPublished))
+		return
+	}
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, helpers.NewSuccess[FormResponse](FormResponse{
-		ID:          form.ID,
-		FormName:    form.FormName,
-		Description: form.Description,
-		DataTypeID:  form.DataTypeID,
-		ServiceID:   form.ServiceID,
-		Status:      form.Status,
-	}, "Form updated successfully"))
+	c.JSON(http.StatusOK, helpers.NewSuccess[FormVersionResponse](formVersionToResponse(draft), "Form draft updated successfully"))
 }
 
 // DeleteFormHandler deletes a form
@@ -180,133 +347,364 @@ func UpdateFormHandler(c *gin.Context) {
 // @Tags         form
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Form ID"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      400,500  {object}  structs.ErrorResponse
+// @Failure      400,409,500  {object}  structs.ErrorResponse
 // @Router       /form/{id} [delete]
-func DeleteFormHandler(c *gin.Context) {
+func (h *Handler) DeleteFormHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	if err := models.DeleteForm(database.DB, uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.Forms.Delete(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, helpers.NewSuccess[any](nil, "Form deleted successfully"))
 }
 
+func formToResponse(form *models.Form) FormResponse {
+	return FormResponse{
+		ID:          form.ID,
+		FormName:    form.FormName,
+		Description: form.Description,
+		DataTypeID:  form.DataTypeID,
+		ServiceID:   form.ServiceID,
+		Status:      form.Status,
+	}
+}
+
 // Form Field Handlers
 
 type FormFieldRequest struct {
-	FormID      uint   `json:"form_id" binding:"required"`
-	FieldID     uint   `json:"field_id" binding:"required"`
-	Validation  string `json:"validation"`
-	FieldSpan   int    `json:"field_span"`
-	FieldRow    int    `json:"field_row"`
-	FormGroupID *uint  `json:"form_group_id"`
+	FormID               uint   `json:"form_id" binding:"required"`
+	FieldID              uint   `json:"field_id" binding:"required"`
+	Validation           string `json:"validation"`
+	FieldSpan            int    `json:"field_span"`
+	FieldRow             int    `json:"field_row"`
+	FormGroupID          *uint  `json:"form_group_id"`
+	DependsOnFormFieldID *uint  `json:"depends_on_form_field_id"` // Collection field whose answer filters this field's options
+}
+
+type FormFieldDependencyRequest struct {
+	DependsOnFormFieldID *uint `json:"depends_on_form_field_id"` // Null removes the dependency
 }
 
 type FormFieldResponse struct {
-	ID          uint   `json:"id"`
-	FormID      uint   `json:"form_id"`
-	FieldID     uint   `json:"field_id"`
-	Validation  string `json:"validation"`
-	FieldSpan   int    `json:"field_span"`
-	FieldRow    int    `json:"field_row"`
-	FormGroupID *uint  `json:"form_group_id"`
+	ID                   uint   `json:"id"`
+	FormID               uint   `json:"form_id"`
+	FormVersionID        *uint  `json:"form_version_id"`
+	FieldID              uint   `json:"field_id"`
+	FieldName            string `json:"field_name"`
+	Validation           string `json:"validation"`
+	FieldSpan            int    `json:"field_span"`
+	FieldRow             int    `json:"field_row"`
+	FormGroupID          *uint  `json:"form_group_id"`
+	DependsOnFormFieldID *uint  `json:"depends_on_form_field_id"`
 }
 
 // CreateFormFieldsHandler creates a form field association
 // @Summary      Create form field
-// @Description  Create a form field association
+// @Description  Add a field to the form's draft version, creating the draft from the latest version when none exists
 // @Tags         form-fields
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        request  body      FormFieldRequest  true  "Form Field Request"
 // @Success      201      {object}  map[string]interface{}
-// @Failure      400,500  {object}  structs.ErrorResponse
+// @Failure      400,404,500  {object}  structs.ErrorResponse
 // @Router       /form_fields [post]
-func CreateFormFieldsHandler(c *gin.Context) {
+func (h *Handler) CreateFormFieldsHandler(c *gin.Context) {
 	var request FormFieldRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
-	ff := &models.FormFields{
-		FormID:      request.FormID,
-		FieldID:     request.FieldID,
-		Validation:  request.Validation,
-		FieldSpan:   request.FieldSpan,
-		FieldRow:    request.FieldRow,
-		FormGroupID: request.FormGroupID,
+	if _, err := validation.Parse(request.Validation); err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid validation rules: "+err.Error(), helpers.CodeInvalidRequest))
+		return
 	}
 
-	if err := models.CreateFormFields(database.DB, ff); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	ctx := c.Request.Context()
+	var ff *models.FormFields
+	err := h.Transaction(ctx, func(tx repository.Repositories) error {
+		var err error
+		ff, err = createDraftFormField(ctx, tx, request, map[uint]*models.FormVersion{})
+		return err
+	})
+	if errors.Is(err, errInvalidDependency) {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError(err.Error(), helpers.CodeInvalidRequest))
+		return
+	}
+	if helpers.IsNotFound(err) {
+		respondError(c, helpers.ErrFormNotFound)
+		return
+	}
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, helpers.NewSuccess[FormFieldResponse](FormFieldResponse{
-		ID:          ff.ID,
-		FormID:      ff.FormID,
-		FieldID:     ff.FieldID,
-		Validation:  ff.Validation,
-		FieldSpan:   ff.FieldSpan,
-		FieldRow:    ff.FieldRow,
-		FormGroupID: ff.FormGroupID,
-	}, "Form field created successfully"))
+	c.JSON(http.StatusOK, helpers.NewSuccess[FormFieldResponse](formFieldToResponse(ff), "Form field created successfully"))
 }
 
 // CreateMultipleFormFieldsHandler creates multiple form field associations
 // @Summary      Create multiple form fields
-// @Description  Create multiple form field associations
+// @Description  Add fields to the draft versions of their forms. Either all fields are saved or none are.
 // @Tags         form-fields
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        request  body      []FormFieldRequest  true  "Form Field Requests"
 // @Success      200      {object}  map[string]interface{}
-// @Failure      400,500  {object}  structs.ErrorResponse
+// @Failure      400,404,500  {object}  structs.ErrorResponse
 // @Router       /form_fields/multiple [post]
-func CreateMultipleFormFieldsHandler(c *gin.Context) {
+func (h *Handler) CreateMultipleFormFieldsHandler(c *gin.Context) {
 	var requests []FormFieldRequest
 	if err := c.ShouldBindJSON(&requests); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
-	var responses []FormFieldResponse
 	for _, req := range requests {
-		ff := &models.FormFields{
-			FormID:      req.FormID,
-			FieldID:     req.FieldID,
-			Validation:  req.Validation,
-			FieldSpan:   req.FieldSpan,
-			FieldRow:    req.FieldRow,
-			FormGroupID: req.FormGroupID,
-		}
-		if err := models.CreateFormFields(database.DB, ff); err != nil {
-			c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+		if _, err := validation.Parse(req.Validation); err != nil {
+			helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid validation rules: "+err.Error(), helpers.CodeInvalidRequest))
 			return
 		}
-		responses = append(responses, FormFieldResponse{
-			ID:          ff.ID,
-			FormID:      ff.FormID,
-			FieldID:     ff.FieldID,
-			Validation:  ff.Validation,
-			FieldSpan:   ff.FieldSpan,
-			FieldRow:    ff.FieldRow,
-			FormGroupID: ff.FormGroupID,
-		})
+	}
+
+	ctx := c.Request.Context()
+	var responses []FormFieldResponse
+	err := h.Transaction(ctx, func(tx repository.Repositories) error {
+		drafts := make(map[uint]*models.FormVersion)
+		for _, req := range requests {
+			ff, err := createDraftFormField(ctx, tx, req, drafts)
+			if err != nil {
+				return err
+			}
+			responses = append(responses, formFieldToResponse(ff))
+		}
+		return nil
+	})
+	if errors.Is(err, errInvalidDependency) {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError(err.Error(), helpers.CodeInvalidRequest))
+		return
+	}
+	if helpers.IsNotFound(err) {
+		respondError(c, helpers.ErrFormNotFound)
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 	c.JSON(http.StatusOK, helpers.NewSuccess[[]FormFieldResponse](responses, "Multiple form fields created"))
 }
 
+// UpdateFormFieldDependencyHandler sets the field a form field's options depend on
+// @Summary      Set form field dependency
+// @Description  Make a collection-backed draft field cascade from another collection field of the same form version: its options are the children of the item chosen for that field. Send null to remove the dependency.
+// @Tags         form-fields
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                         true  "Form Field ID"
+// @Param        request  body      FormFieldDependencyRequest  true  "Dependency Request"
+// @Success      200      {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Failure      409      {object}  structs.ErrorResponse  "The field belongs to a published version"
+// @Router       /form_fields/{id}/dependency [put]
+func (h *Handler) UpdateFormFieldDependencyHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	var request FormFieldDependencyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	ff, err := h.Forms.FormField(ctx, uint(id))
+	if err != nil {
+		respondMissing(c, err, helpers.ErrFormFieldNotFound)
+		return
+	}
+
+	err = h.Transaction(ctx, func(tx repository.Repositories) error {
+		var parentID *uint
+		if request.DependsOnFormFieldID != nil {
+			resolved, err := resolveDependency(ctx, tx, ff, *request.DependsOnFormFieldID)
+			if err != nil {
+				return err
+			}
+			parentID = &resolved
+		}
+		return tx.Forms.SetDependency(ctx, ff, parentID)
+	})
+	switch {
+	case errors.Is(err, errInvalidDependency):
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError(err.Error(), helpers.CodeInvalidRequest))
+		return
+	case errors.Is(err, models.ErrFormVersionPublished):
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Field belongs to a published form version; edit the draft instead", helpers.CodeVersionPublished))
+		return
+	case err != nil:
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[FormFieldResponse](formFieldToResponse(ff), "Form field dependency updated successfully"))
+}
+
+// createDraftFormField adds a field to the draft version of its form. drafts
+// caches the draft per form ID so a batch reuses one draft per form.
+func createDraftFormField(ctx context.Context, tx repository.Repositories, req FormFieldRequest, drafts map[uint]*models.FormVersion) (*models.FormFields, error) {
+	draft, ok := drafts[req.FormID]
+	if !ok {
+		form, err := tx.Forms.Get(ctx, req.FormID)
+		if err != nil {
+			return nil, err
+		}
+		draft, err = tx.Forms.OpenDraft(ctx, form)
+		if err != nil {
+			return nil, err
+		}
+		drafts[req.FormID] = draft
+	}
+
+	ff := &models.FormFields{
+		FormID:        req.FormID,
+		FormVersionID: &draft.ID,
+		FieldID:       req.FieldID,
+		Validation:    req.Validation,
+		FieldSpan:     req.FieldSpan,
+		FieldRow:      req.FieldRow,
+		FormGroupID:   req.FormGroupID,
+	}
+	if req.DependsOnFormFieldID != nil {
+		field, err := tx.Forms.Field(ctx, req.FieldID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %d does not exist", errInvalidDependency, req.FieldID)
+		}
+		ff.Field = *field
+		parentID, err := resolveDependency(ctx, tx, ff, *req.DependsOnFormFieldID)
+		if err != nil {
+			return nil, err
+		}
+		ff.DependsOnFormFieldID = &parentID
+	}
+	if err := tx.Forms.CreateFormField(ctx, ff); err != nil {
+		return nil, err
+	}
+	return ff, nil
+}
+
+// resolveDependency checks that a draft field may take its options from the
+// answer to parentID and returns the parent ID to store. A parent from an
+// earlier version of the form is mapped onto its copy in the draft.
+func resolveDependency(ctx context.Context, tx repository.Repositories, child *models.FormFields, parentID uint) (uint, error) {
+	parent, err := tx.Forms.FormField(ctx, parentID)
+	if helpers.IsNotFound(err) {
+		return 0, fmt.Errorf("%w: form field %d does not exist", errInvalidDependency, parentID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if parent.FormID != child.FormID {
+		return 0, fmt.Errorf("%w: form field %d belongs to another form", errInvalidDependency, parentID)
+	}
+
+	if parent.FormVersionID == nil || child.FormVersionID == nil || *parent.FormVersionID != *child.FormVersionID {
+		var matches []models.FormFields
+		if child.FormVersionID != nil {
+			matches, err = tx.Forms.Placements(ctx, *child.FormVersionID, parent.FieldID)
+			if err != nil {
+				return 0, err
+			}
+		}
+		if len(matches) != 1 {
+			return 0, fmt.Errorf("%w: form field %d is not part of the draft", errInvalidDependency, parentID)
+		}
+		parent = &matches[0]
+	}
+
+	if parent.ID == child.ID {
+		return 0, fmt.Errorf("%w: a field cannot depend on itself", errInvalidDependency)
+	}
+	if parent.Field.CollectionID == nil || child.Field.CollectionID == nil {
+		return 0, fmt.Errorf("%w: both fields must take their options from a collection", errInvalidDependency)
+	}
+
+	// Walk up the parent's own dependencies to refuse cycles.
+	seen := map[uint]bool{child.ID: true, parent.ID: true}
+	for next := parent.DependsOnFormFieldID; next != nil; {
+		if seen[*next] {
+			return 0, fmt.Errorf("%w: dependencies would form a cycle", errInvalidDependency)
+		}
+		seen[*next] = true
+		ancestor, err := tx.Forms.FormField(ctx, *next)
+		if err != nil {
+			return 0, err
+		}
+		next = ancestor.DependsOnFormFieldID
+	}
+	return parent.ID, nil
+}
+
+func formFieldToResponse(ff *models.FormFields) FormFieldResponse {
+	return FormFieldResponse{
+		ID:            ff.ID,
+		FormID:        ff.FormID,
+		FormVersionID: ff.FormVersionID,
+		FieldID:       ff.FieldID,
+		FieldName:     ff.FieldName,
+		Validation:    ff.Validation,
+		FieldSpan:     ff.FieldSpan,
+		FieldRow:      ff.FieldRow,
+		FormGroupID:   ff.FormGroupID,
+
+		DependsOnFormFieldID: ff.DependsOnFormFieldID,
+	}
+}
+
+// GetFormFieldHandler retrieves a form field
+// @Summary      Get form field
+// @Description  Retrieve a field's placement on a form version by its ID
+// @Tags         form-fields
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Form Field ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404  {object}  structs.ErrorResponse
+// @Router       /form_fields/{id} [get]
+func (h *Handler) GetFormFieldHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	formField, err := h.Forms.FormField(c.Request.Context(), uint(id))
+	if err != nil {
+		respondMissing(c, err, helpers.ErrFormFieldNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[FormFieldResponse](formFieldToResponse(formField), "Form field retrieved successfully"))
+}
+
 // Field Handlers
 
 type FieldRequest struct {
@@ -332,14 +730,15 @@ type FieldResponse struct {
 // @Tags         fields
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        request  body      FieldRequest  true  "Field Request"
 // @Success      201      {object}  map[string]interface{}
 // @Failure      400,500  {object}  structs.ErrorResponse
 // @Router       /field [post]
-func CreateFieldHandler(c *gin.Context) {
+func (h *Handler) CreateFieldHandler(c *gin.Context) {
 	var request FieldRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
@@ -351,8 +750,8 @@ func CreateFieldHandler(c *gin.Context) {
 		Status:       request.Status,
 	}
 
-	if err := models.CreateFields(database.DB, field); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.Forms.CreateField(c.Request.Context(), field); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -372,21 +771,22 @@ func CreateFieldHandler(c *gin.Context) {
 // @Tags         fields
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Field ID"
 // @Success      200  {object}  map[string]interface{}
 // @Failure      400,404  {object}  structs.ErrorResponse
 // @Router       /field/{id} [get]
-func GetFieldHandler(c *gin.Context) {
+func (h *Handler) GetFieldHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	field, err := models.GetFields(database.DB, uint(id))
+	field, err := h.Forms.Field(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Field not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrFieldNotFound)
 		return
 	}
 
@@ -400,34 +800,93 @@ func GetFieldHandler(c *gin.Context) {
 	}, "Field retrieved successfully"))
 }
 
+var fieldsSpec = query.Spec{
+	Sorts:       map[string]string{"id": "id", "label": "label"},
+	DefaultSort: "id",
+	Filters: map[string]query.Filter{
+		"label":         {Column: "label", Op: query.Contains},
+		"data_type_id":  {Column: "data_type_id", Op: query.Equal, Kind: query.Int},
+		"group_id":      {Column: "group_id", Op: query.Equal, Kind: query.Int},
+		"collection_id": {Column: "collection_id", Op: query.Equal, Kind: query.Int},
+		"status":        {Column: "status", Op: query.Equal, Kind: query.Bool},
+	},
+}
+
+// ListFieldsHandler lists fields
+// @Summary      List fields
+// @Description  Retrieve a page of the reusable fields forms are built from
+// @Tags         fields
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit          query     int     false  "Page size (default 50, max 200)"
+// @Param        offset         query     int     false  "Rows to skip"
+// @Param        cursor         query     string  false  "meta.next_cursor of the previous page"
+// @Param        sort           query     string  false  "Comma-separated sort fields, prefix - for descending (id, label)"
+// @Param        label          query     string  false  "Only labels containing this text"
+// @Param        data_type_id   query     string  false  "Comma-separated data type IDs"
+// @Param        group_id       query     string  false  "Comma-separated group IDs"
+// @Param        collection_id  query     string  false  "Comma-separated collection IDs"
+// @Param        status         query     bool    false  "Field status"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,500  {object}  structs.ErrorResponse
+// @Router       /field [get]
+func (h *Handler) ListFieldsHandler(c *gin.Context) {
+	params, ok := listParams(c, fieldsSpec)
+	if !ok {
+		return
+	}
+
+	fields, meta, err := h.Forms.Fields(c.Request.Context(), params)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]FieldResponse, 0, len(fields))
+	for _, f := range fields {
+		response = append(response, FieldResponse{
+			ID:           f.ID,
+			Label:        f.Label,
+			DataTypeID:   f.DataTypeID,
+			GroupID:      f.GroupID,
+			CollectionID: f.CollectionID,
+			Status:       f.Status,
+		})
+	}
+	c.JSON(http.StatusOK, helpers.NewPage(response, meta, "Fields retrieved successfully"))
+}
+
 // UpdateFieldHandler updates a field
 // @Summary      Update field
-// @Description  Update an existing field by its ID
+// @Description  Update an existing field by its ID. Once a published form version places the field only its status can change; create a new field and place it on the form's draft instead.
 // @Tags         fields
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id       path      int           true  "Field ID"
 // @Param        request  body      FieldRequest  true  "Field Request"
 // @Success      200      {object}  map[string]interface{}
 // @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Failure      409      {object}  structs.ErrorResponse  "A published form version places the field"
 // @Router       /field/{id} [put]
-func UpdateFieldHandler(c *gin.Context) {
+func (h *Handler) UpdateFieldHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
 	var request FieldRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
-	field, err := models.GetFields(database.DB, uint(id))
+	field, err := h.Forms.Field(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Field not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrFieldNotFound)
 		return
 	}
 
@@ -437,8 +896,13 @@ func UpdateFieldHandler(c *gin.Context) {
 	field.CollectionID = request.CollectionID
 	field.Status = request.Status
 
-	if err := models.UpdateFields(database.DB, field); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	err = h.Forms.UpdateField(c.Request.Context(), field)
+	if errors.Is(err, models.ErrFormVersionPublished) {
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Field is placed on a published form version; only its status can change", helpers.CodeVersionPublished))
+		return
+	}
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -454,24 +918,30 @@ func UpdateFieldHandler(c *gin.Context) {
 
 // DeleteFieldHandler deletes a field
 // @Summary      Delete field
-// @Description  Delete a field by its ID
+// @Description  Delete a field by its ID. Fields placed on a published form version cannot be deleted.
 // @Tags         fields
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Field ID"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      400,500  {object}  structs.ErrorResponse
+// @Failure      400,409,500  {object}  structs.ErrorResponse
 // @Router       /field/{id} [delete]
-func DeleteFieldHandler(c *gin.Context) {
+func (h *Handler) DeleteFieldHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	if err := models.DeleteFields(database.DB, uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	err = h.Forms.DeleteField(c.Request.Context(), uint(id))
+	if errors.Is(err, models.ErrFormVersionPublished) {
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Field is placed on a published form version", helpers.CodeVersionPublished))
+		return
+	}
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -495,20 +965,21 @@ type GroupResponse struct {
 // @Tags         groups
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        request  body      GroupRequest  true  "Group Request"
 // @Success      201      {object}  map[string]interface{}
 // @Failure      400,500  {object}  structs.ErrorResponse
 // @Router       /groups [post]
-func CreateGroupHandler(c *gin.Context) {
+func (h *Handler) CreateGroupHandler(c *gin.Context) {
 	var request GroupRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
 	group := &models.Group{GroupName: request.GroupName}
-	if err := models.CreateGroup(database.DB, group); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.Groups.Create(c.Request.Context(), group); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -524,21 +995,22 @@ func CreateGroupHandler(c *gin.Context) {
 // @Tags         groups
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Group ID"
 // @Success      200  {object}  map[string]interface{}
 // @Failure      400,404  {object}  structs.ErrorResponse
 // @Router       /groups/{id} [get]
-func GetGroupByIDHandler(c *gin.Context) {
+func (h *Handler) GetGroupByIDHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	group, err := models.GetGroupByID(database.DB, uint(id))
+	group, err := h.Groups.Get(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Group not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrGroupNotFound)
 		return
 	}
 
@@ -548,27 +1020,40 @@ func GetGroupByIDHandler(c *gin.Context) {
 	}, "Group retrieved successfully"))
 }
 
+var groupsSpec = byName("group_name")
+
 // GetAllGroupsHandler retrieves all groups
 // @Summary      Get all groups
 // @Description  Retrieve all groups
 // @Tags         groups
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Param        limit   query     int     false  "Page size (default 50, max 200)"
+// @Param        offset  query     int     false  "Rows to skip"
+// @Param        cursor  query     string  false  "meta.next_cursor of the previous page"
+// @Param        sort    query     string  false  "Comma-separated sort fields, prefix - for descending (id, name)"
+// @Param        name    query     string  false  "Only names containing this text"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      500  {object}  structs.ErrorResponse
+// @Failure      400,500  {object}  structs.ErrorResponse
 // @Router       /groups [get]
-func GetAllGroupsHandler(c *gin.Context) {
-	groups, err := models.GetAllGroups(database.DB)
+func (h *Handler) GetAllGroupsHandler(c *gin.Context) {
+	params, ok := listParams(c, groupsSpec)
+	if !ok {
+		return
+	}
+
+	groups, meta, err := h.Groups.List(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+		respondError(c, err)
 		return
 	}
 
-	var response []GroupResponse
+	response := make([]GroupResponse, 0, len(groups))
 	for _, g := range groups {
 		response = append(response, GroupResponse{ID: g.ID, GroupName: g.GroupName})
 	}
-	c.JSON(http.StatusOK, helpers.NewSuccess[[]GroupResponse](response, "Groups retrieved successfully"))
+	c.JSON(http.StatusOK, helpers.NewPage(response, meta, "Groups retrieved successfully"))
 }
 
 // UpdateGroupHandler updates a group
@@ -577,34 +1062,35 @@ func GetAllGroupsHandler(c *gin.Context) {
 // @Tags         groups
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id       path      int           true  "Group ID"
 // @Param        request  body      GroupRequest  true  "Group Request"
 // @Success      200      {object}  map[string]interface{}
 // @Failure      400,404,500  {object}  structs.ErrorResponse
 // @Router       /groups/{id} [put]
-func UpdateGroupHandler(c *gin.Context) {
+func (h *Handler) UpdateGroupHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
 	var request GroupRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
-	group, err := models.GetGroupByID(database.DB, uint(id))
+	group, err := h.Groups.Get(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Group not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrGroupNotFound)
 		return
 	}
 
 	group.GroupName = request.GroupName
-	if err := models.UpdateGroup(database.DB, group); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.Groups.Update(c.Request.Context(), group); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -620,20 +1106,21 @@ func UpdateGroupHandler(c *gin.Context) {
 // @Tags         groups
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Group ID"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      400,500  {object}  structs.ErrorResponse
+// @Failure      400,409,500  {object}  structs.ErrorResponse
 // @Router       /groups/{id} [delete]
-func DeleteGroupHandler(c *gin.Context) {
+func (h *Handler) DeleteGroupHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	if err := models.DeleteGroup(database.DB, uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.Groups.Delete(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"kora_1/internal/helpers"
+	"kora_1/internal/models"
+	"kora_1/internal/repository"
+	"kora_1/internal/webhooks"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FormVersionResponse struct {
+	ID          uint    `json:"id"`
+	FormID      uint    `json:"form_id"`
+	Version     int     `json:"version"`
+	Status      string  `json:"status"`
+	FormName    string  `json:"form_name"`
+	Description string  `json:"description"`
+	CreatedOn   string  `json:"created_on"`
+	PublishedOn *string `json:"published_on"`
+}
+
+// PublishFormHandler publishes the draft version of a form
+// @Summary      Publish form
+// @Description  Freeze the form's draft version so it accepts submissions. Published versions can no longer be edited.
+// @Tags         form
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Form ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,409,500  {object}  structs.ErrorResponse
+// @Router       /form/{id}/publish [post]
+func (h *Handler) PublishFormHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.Forms.Get(ctx, uint(id)); err != nil {
+		respondMissing(c, err, helpers.ErrFormNotFound)
+		return
+	}
+
+	var version *models.FormVersion
+	err = h.Transaction(ctx, func(tx repository.Repositories) error {
+		var err error
+		version, err = tx.Forms.DraftVersion(ctx, uint(id))
+		if err != nil {
+			return err
+		}
+		if err := tx.Forms.Publish(ctx, version); err != nil {
+			return err
+		}
+
+		form, err := tx.Forms.Get(ctx, version.FormID)
+		if err != nil {
+			return err
+		}
+		return webhooks.Enqueue(ctx, tx.Webhooks, webhooks.FormPublished, form.ServiceID, webhooks.FormData{
+			FormID:        form.ID,
+			FormVersionID: version.ID,
+			Version:       version.Version,
+		})
+	})
+	if helpers.IsNotFound(err) {
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Form has no draft to publish", helpers.CodeNoDraft))
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[FormVersionResponse](formVersionToResponse(version), "Form published successfully"))
+}
+
+// ListFormVersionsHandler lists the versions of a form
+// @Summary      List form versions
+// @Description  Retrieve every draft and published version of a form, oldest first
+// @Tags         form
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Form ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /form/{id}/versions [get]
+func (h *Handler) ListFormVersionsHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	if _, err := h.Forms.Get(c.Request.Context(), uint(id)); err != nil {
+		respondMissing(c, err, helpers.ErrFormNotFound)
+		return
+	}
+
+	versions, err := h.Forms.Versions(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]FormVersionResponse, 0, len(versions))
+	for i := range versions {
+		response = append(response, formVersionToResponse(&versions[i]))
+	}
+	c.JSON(http.StatusOK, helpers.NewSuccess[[]FormVersionResponse](response, "Form versions retrieved successfully"))
+}
+
+func formVersionToResponse(version *models.FormVersion) FormVersionResponse {
+	response := FormVersionResponse{
+		ID:          version.ID,
+		FormID:      version.FormID,
+		Version:     version.Version,
+		Status:      version.Status,
+		FormName:    version.FormName,
+		Description: version.Description,
+		CreatedOn:   version.CreatedOn.Format(time.RFC3339),
+	}
+	if version.PublishedOn != nil {
+		publishedOn := version.PublishedOn.Format(time.RFC3339)
+		response.PublishedOn = &publishedOn
+	}
+	return response
+}
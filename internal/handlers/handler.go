@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"kora_1/internal/auth"
+	"kora_1/internal/config"
+	"kora_1/internal/repository"
+	"kora_1/internal/storage"
+
+	"gorm.io/gorm"
+)
+
+// Handler serves the HTTP API. Every read and write goes through the
+// repositories, so tests can swap in the fakes of package repository/memory;
+// flows that write several tables run inside h.Transaction.
+type Handler struct {
+	repository.Repositories
+
+	Tokens *auth.Tokens
+	Files  storage.Storage // Uploaded files
+
+	Reservations config.Reservations
+}
+
+// New returns a Handler whose repositories are backed by db and whose uploads
+// are kept in files.
+func New(cfg *config.Config, db *gorm.DB, files storage.Storage) *Handler {
+	return &Handler{
+		Repositories: repository.NewGorm(db),
+		Tokens:       auth.NewTokens(cfg.Auth),
+		Files:        files,
+		Reservations: cfg.Reservations,
+	}
+}
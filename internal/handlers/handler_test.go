@@ -0,0 +1,1312 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"kora_1/internal/auth"
+	"kora_1/internal/config"
+	"kora_1/internal/datatypes"
+	"kora_1/internal/helpers"
+	"kora_1/internal/middleware"
+	"kora_1/internal/models"
+	"kora_1/internal/names"
+	"kora_1/internal/query"
+	"kora_1/internal/repository"
+	"kora_1/internal/repository/memory"
+	"kora_1/internal/structs"
+	"kora_1/internal/webhooks"
+	"kora_1/internal/workflow"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serve runs one request against a handler backed by store, as user.
+func serve(store *memory.Store, user *models.User, method, path, body string, route func(*gin.Engine, *Handler)) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return serveRequest(&Handler{Repositories: store.Repositories()}, user, req, route)
+}
+
+// serveRequest runs req against h as user, for tests that need headers or
+// repositories of their own.
+func serveRequest(h *Handler, user *models.User, req *http.Request, route func(*gin.Engine, *Handler)) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		middleware.SetCurrentUser(c, user)
+	})
+	route(r, h)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var body struct {
+		Data T `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	return body.Data
+}
+
+func TestServiceHandlers(t *testing.T) {
+	store := memory.New()
+	admin := &models.User{Role: models.RoleAdmin}
+	store.Add(admin)
+	routes := func(r *gin.Engine, h *Handler) {
+		r.GET("/services", h.ListServicesHandler)
+		r.POST("/services", h.AddServiceHandler)
+		r.PUT("/services/:id", h.UpdateServiceHandler)
+		r.GET("/services/:id", h.GetServiceHandler)
+	}
+
+	rec := serve(store, admin, http.MethodPost, "/services", `{"service_name":"Business names"}`, routes)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: got %d: %s", rec.Code, rec.Body)
+	}
+	created := decode[ServiceResponse](t, rec)
+
+	path := "/services/" + jsonNumber(created.ID)
+	rec = serve(store, admin, http.MethodPut, path, `{"service_name":"Company names"}`, routes)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update: got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = serve(store, admin, http.MethodGet, path, "", routes)
+	if got := decode[ServiceResponse](t, rec); got.ServiceName != "Company names" {
+		t.Errorf("get: got %q, want the updated name", got.ServiceName)
+	}
+
+	rec = serve(store, admin, http.MethodGet, "/services?limit=10", "", routes)
+	if got := decode[[]ServiceResponse](t, rec); len(got) != 1 {
+		t.Errorf("list: got %d services, want 1", len(got))
+	}
+
+	rec = serve(store, admin, http.MethodGet, "/services/999", "", routes)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("get missing: got %d, want 404", rec.Code)
+	}
+}
+
+// brokenUsers is a Users repository whose lookups by email fail like a lost connection.
+type brokenUsers struct{ repository.Users }
+
+func (brokenUsers) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestLoginHandler(t *testing.T) {
+	store := memory.New()
+	hash, err := auth.HashPassword("s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := &models.User{Email: "alice@example.com", Password: hash, Role: models.RoleApplicant}
+	store.Add(alice)
+
+	cfg := config.Default().Auth
+	cfg.JWTSecret = "test-secret"
+	routes := func(r *gin.Engine, h *Handler) {
+		r.POST("/auth/login", h.LoginHandler)
+	}
+	login := func(repos repository.Repositories, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return serveRequest(&Handler{Repositories: repos, Tokens: auth.NewTokens(cfg)}, nil, req, routes)
+	}
+
+	if rec := login(store.Repositories(), `{"email":"alice@example.com","password":"s3cret"}`); rec.Code != http.StatusOK {
+		t.Errorf("right password: got %d %s, want 200", rec.Code, rec.Body)
+	}
+	for name, body := range map[string]string{
+		"wrong password": `{"email":"alice@example.com","password":"guess"}`,
+		"unknown email":  `{"email":"bob@example.com","password":"guess"}`,
+	} {
+		rec := login(store.Repositories(), body)
+		if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), helpers.CodeInvalidCredentials) {
+			t.Errorf("%s: got %d %s, want 401 %s", name, rec.Code, rec.Body, helpers.CodeInvalidCredentials)
+		}
+	}
+
+	repos := store.Repositories()
+	repos.Users = brokenUsers{repos.Users}
+	if rec := login(repos, `{"email":"alice@example.com","password":"s3cret"}`); rec.Code != http.StatusInternalServerError {
+		t.Errorf("user lookup failing: got %d %s, want 500", rec.Code, rec.Body)
+	}
+}
+
+func TestRefreshHandlerRevokedByPasswordChange(t *testing.T) {
+	store := memory.New()
+	alice := &models.User{Email: "alice@example.com", Role: models.RoleApplicant}
+	store.Add(alice)
+
+	cfg := config.Default().Auth
+	cfg.JWTSecret = "test-secret"
+	h := &Handler{Repositories: store.Repositories(), Tokens: auth.NewTokens(cfg)}
+	routes := func(r *gin.Engine, h *Handler) {
+		r.POST("/auth/refresh", h.RefreshHandler)
+	}
+	refresh := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(`{"refresh_token":"`+token+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		return serveRequest(h, nil, req, routes)
+	}
+
+	tokens, err := h.Tokens.Issue(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := refresh(tokens.RefreshToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("refresh: got %d %s, want 200", rec.Code, rec.Body)
+	}
+	renewed := decode[auth.TokenPair](t, rec)
+
+	hash, err := auth.HashPassword("n3w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice.SetPassword(hash)
+	if err := h.Users.Update(t.Context(), alice); err != nil {
+		t.Fatal(err)
+	}
+	for name, token := range map[string]string{"first": tokens.RefreshToken, "renewed": renewed.RefreshToken} {
+		if rec := refresh(token); rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), helpers.CodeInvalidToken) {
+			t.Errorf("%s refresh token after a password change: got %d %s, want 401", name, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestGetUserHandlerForbidsOtherProfiles(t *testing.T) {
+	store := memory.New()
+	alice := &models.User{Email: "alice@example.com", Role: models.RoleApplicant}
+	bob := &models.User{Email: "bob@example.com", Role: models.RoleApplicant}
+	store.Add(alice, bob)
+	routes := func(r *gin.Engine, h *Handler) {
+		r.GET("/users/:id", h.GetUserHandler)
+	}
+
+	if rec := serve(store, alice, http.MethodGet, "/users/"+jsonNumber(alice.ID), "", routes); rec.Code != http.StatusOK {
+		t.Errorf("own profile: got %d, want 200", rec.Code)
+	}
+	if rec := serve(store, alice, http.MethodGet, "/users/"+jsonNumber(bob.ID), "", routes); rec.Code != http.StatusForbidden {
+		t.Errorf("other profile: got %d, want 403", rec.Code)
+	}
+}
+
+func TestSubmissionHandlersScopeToCaller(t *testing.T) {
+	store := memory.New()
+	names := &models.Service{ServiceName: "Business names"}
+	permits := &models.Service{ServiceName: "Permits"}
+	alice := &models.User{Email: "alice@example.com", Role: models.RoleApplicant}
+	bob := &models.User{Email: "bob@example.com", Role: models.RoleApplicant}
+	reviewer := &models.User{Email: "rita@example.com", Role: models.RoleReviewer}
+	store.Add(names, permits, alice, bob, reviewer)
+
+	aliceNames := &models.Submission{ServicesID: &names.ID, CreatedBy: &alice.ID, Status: workflow.Submitted}
+	bobPermits := &models.Submission{ServicesID: &permits.ID, CreatedBy: &bob.ID, Status: workflow.Submitted}
+	store.Add(aliceNames, bobPermits)
+
+	h := &Handler{Repositories: store.Repositories()}
+	if err := h.Users.AssignReviewer(t.Context(), reviewer.ID, permits.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	routes := func(r *gin.Engine, h *Handler) {
+		r.GET("/submission", h.ListSubmissionsHandler)
+		r.GET("/submission/:id", h.GetSubmissionHandler)
+	}
+
+	for _, tc := range []struct {
+		name string
+		user *models.User
+		want uint
+	}{
+		{"applicant", alice, aliceNames.ID},
+		{"reviewer", reviewer, bobPermits.ID},
+	} {
+		rec := serve(store, tc.user, http.MethodGet, "/submission", "", routes)
+		got := decode[[]SubmissionResponse](t, rec)
+		if len(got) != 1 || got[0].ID != tc.want {
+			t.Errorf("%s list: got %+v, want only submission %d", tc.name, got, tc.want)
+		}
+	}
+
+	if rec := serve(store, alice, http.MethodGet, "/submission/"+jsonNumber(bobPermits.ID), "", routes); rec.Code != http.StatusNotFound {
+		t.Errorf("applicant reading another's submission: got %d, want 404", rec.Code)
+	}
+	if rec := serve(store, reviewer, http.MethodGet, "/submission/"+jsonNumber(aliceNames.ID), "", routes); rec.Code != http.StatusNotFound {
+		t.Errorf("reviewer reading an unassigned service's submission: got %d, want 404", rec.Code)
+	}
+}
+
+func TestTransitionCannotSubmitADraft(t *testing.T) {
+	store := memory.New()
+	alice := &models.User{Email: "alice@example.com", Role: models.RoleApplicant}
+	store.Add(alice)
+	draft := &models.Submission{CreatedBy: &alice.ID, Status: workflow.Draft}
+	returned := &models.Submission{CreatedBy: &alice.ID, Status: workflow.Returned}
+	store.Add(draft, returned)
+
+	routes := func(r *gin.Engine, h *Handler) {
+		r.POST("/submission/:id/transition", h.TransitionSubmissionHandler)
+	}
+	rec := serve(store, alice, http.MethodPost, "/submission/"+jsonNumber(draft.ID)+"/transition", `{"status":"submitted"}`, routes)
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), helpers.CodeTransitionNotAllowed) {
+		t.Errorf("submitting a draft by transition: got %d %s, want 409", rec.Code, rec.Body)
+	}
+
+	// Other edges into submitted are the workflow's to allow.
+	rec = serve(store, alice, http.MethodPost, "/submission/"+jsonNumber(returned.ID)+"/transition", `{"status":"submitted"}`, routes)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("resubmitting a returned submission: got %d %s, want 200", rec.Code, rec.Body)
+	}
+	if got := decode[SubmissionResponse](t, rec); got.Status != workflow.Submitted {
+		t.Errorf("status: got %q, want %q", got.Status, workflow.Submitted)
+	}
+}
+
+// publishedForm adds a service and a form published with one text field, and
+// returns them with the field's placement.
+func publishedForm(store *memory.Store) (*models.Service, *models.Form, *models.FormFields) {
+	service := &models.Service{ServiceName: "Business names"}
+	text := &models.DataType{DataType: "text"}
+	store.Add(service, text)
+	field := &models.Field{Label: "Business name", DataTypeID: text.ID}
+	form := &models.Form{FormName: "Registration", DataTypeID: text.ID, ServiceID: &service.ID}
+	store.Add(field, form)
+	version := &models.FormVersion{FormID: form.ID, Version: 1, Status: models.FormVersionPublished, FormName: form.FormName}
+	store.Add(version)
+	placement := &models.FormFields{FormID: form.ID, FormVersionID: &version.ID, FieldID: field.ID, FieldName: "business_name", Validation: "required"}
+	store.Add(placement)
+	return service, form, placement
+}
+
+func TestGetFormDefinitionHandler(t *testing.T) {
+	store := memory.New()
+	designer := &models.User{Role: models.RoleDesigner}
+	text := &models.DataType{DataType: "text"}
+	choice := &models.DataType{DataType: "collection"}
+	provinces := &models.Collection{CollectionName: "Provinces"}
+	districts := &models.Collection{CollectionName: "Districts"}
+	store.Add(designer, text, choice, provinces, districts)
+	north := &models.CollectionItem{CollectionID: &provinces.ID, CollectionItem: "North"}
+	south := &models.CollectionItem{CollectionID: &provinces.ID, CollectionItem: "South"}
+	store.Add(north, south)
+	harbour := &models.CollectionItem{CollectionID: &districts.ID, CollectionItem: "Harbour", RelationCollectionItemsID: &north.ID}
+	store.Add(harbour)
+
+	name := &models.Field{Label: "Business name", DataTypeID: text.ID}
+	notes := &models.Field{Label: "Notes", DataTypeID: text.ID}
+	province := &models.Field{Label: "Province", DataTypeID: choice.ID, CollectionID: &provinces.ID}
+	district := &models.Field{Label: "District", DataTypeID: choice.ID, CollectionID: &districts.ID}
+	owner := &models.FormGroup{GroupName: "Owner", GroupSpan: 12, GroupRow: 2}
+	business := &models.FormGroup{GroupName: "Business", GroupSpan: 6, GroupRow: 1}
+	form := &models.Form{FormName: "Registration", DataTypeID: text.ID}
+	store.Add(name, notes, province, district, owner, business, form)
+	version := &models.FormVersion{FormID: form.ID, Version: 1, Status: models.FormVersionPublished, FormName: "Registration", Description: "Register a business"}
+	store.Add(version)
+
+	// Added out of layout order, so the response has to sort them.
+	districtFF := &models.FormFields{FormID: form.ID, FormVersionID: &version.ID, FieldID: district.ID, FieldName: "district", FormGroupID: &business.ID, FieldRow: 3}
+	store.Add(districtFF)
+	provinceFF := &models.FormFields{FormID: form.ID, FormVersionID: &version.ID, FieldID: province.ID, FieldName: "province", FormGroupID: &business.ID, FieldRow: 2, Validation: "required"}
+	nameFF := &models.FormFields{FormID: form.ID, FormVersionID: &version.ID, FieldID: name.ID, FieldName: "owner_name", FormGroupID: &owner.ID, FieldRow: 1, Validation: "required|max_length:50"}
+	notesFF := &models.FormFields{FormID: form.ID, FormVersionID: &version.ID, FieldID: notes.ID, FieldName: "notes", FieldRow: 4}
+	store.Add(provinceFF, nameFF, notesFF)
+	districtFF.DependsOnFormFieldID = &provinceFF.ID
+	store.Add(districtFF)
+
+	routes := func(r *gin.Engine, h *Handler) {
+		r.GET("/form/:id", h.GetFormWithFieldsHandler)
+	}
+	rec := serve(store, designer, http.MethodGet, "/form/"+jsonNumber(form.ID), "", routes)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get: got %d: %s", rec.Code, rec.Body)
+	}
+
+	got := decode[FormDefinitionResponse](t, rec)
+	if got.FormVersionID != version.ID || got.Version != 1 || got.VersionStatus != models.FormVersionPublished || got.Description != "Register a business" {
+		t.Errorf("header: got %+v, want published version 1", got.FormResponse)
+	}
+	if len(got.Groups) != 2 || got.Groups[0].GroupName != "Business" || got.Groups[1].GroupName != "Owner" {
+		t.Fatalf("groups: got %+v, want Business then Owner by group row", got.Groups)
+	}
+	if fields := got.Groups[0].Fields; len(fields) != 2 || fields[0].FieldName != "province" || fields[1].FieldName != "district" {
+		t.Fatalf("business fields: got %+v, want province then district by field row", fields)
+	}
+	if len(got.Fields) != 1 || got.Fields[0].FieldName != "notes" {
+		t.Errorf("ungrouped fields: got %+v, want notes", got.Fields)
+	}
+
+	owned := got.Groups[1].Fields[0]
+	if !owned.Required || owned.Type != datatypes.Text || len(owned.Rules) != 2 || owned.Rules[1] != (ValidationRuleResponse{Name: "max_length", Arg: "50"}) {
+		t.Errorf("owner name: got %+v, want a required text field with its rules", owned)
+	}
+
+	provinceDef, districtDef := got.Groups[0].Fields[0], got.Groups[0].Fields[1]
+	if provinceDef.Type != datatypes.Collection || len(provinceDef.Options) != 2 || provinceDef.Options[0].Label != "North" || provinceDef.Options[1].Label != "South" {
+		t.Errorf("province options: got %+v, want North and South", provinceDef.Options)
+	}
+	if districtDef.DependsOnFormFieldID == nil || *districtDef.DependsOnFormFieldID != provinceFF.ID {
+		t.Errorf("district dependency: got %v, want the province field %d", districtDef.DependsOnFormFieldID, provinceFF.ID)
+	}
+	if len(districtDef.Options) != 1 || districtDef.Options[0].ParentID == nil || *districtDef.Options[0].ParentID != north.ID {
+		t.Errorf("district options: got %+v, want Harbour under North", districtDef.Options)
+	}
+
+	// The JSON keys are the renderer's contract.
+	var raw struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &raw); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"form_version_id", "version", "version_status", "groups", "fields"} {
+		if _, ok := raw.Data[key]; !ok {
+			t.Errorf("definition has no %q: %s", key, rec.Body)
+		}
+	}
+	group := raw.Data["groups"].([]any)[0].(map[string]any)
+	field := group["fields"].([]any)[1].(map[string]any)
+	option := field["options"].([]any)[0].(map[string]any)
+	if _, ok := field["depends_on_form_field_id"]; !ok {
+		t.Errorf("dependent field has no depends_on_form_field_id: %v", field)
+	}
+	if _, ok := option["parent_id"]; !ok {
+		t.Errorf("dependent option has no parent_id: %v", option)
+	}
+	if _, ok := raw.Data["fields"].([]any)[0].(map[string]any)["options"]; ok {
+		t.Error("a field without a collection has options")
+	}
+}
+
+func TestFormVersioning(t *testing.T) {
+	store := memory.New()
+	designer := &models.User{Role: models.RoleDesigner}
+	choice := &models.DataType{DataType: "collection"}
+	service := &models.Service{ServiceName: "Business names"}
+	other := &models.Service{ServiceName: "Permits"}
+	provinces := &models.Collection{CollectionName: "Provinces"}
+	districts := &models.Collection{CollectionName: "Districts"}
+	store.Add(designer, choice, service, other, provinces, districts)
+	province := &models.Field{Label: "Province", DataTypeID: choice.ID, CollectionID: &provinces.ID}
+	district := &models.Field{Label: "District", DataTypeID: choice.ID, CollectionID: &districts.ID}
+	store.Add(province, district)
+
+	routes := func(r *gin.Engine, h *Handler) {
+		r.POST("/form", h.FormHandler)
+		r.GET("/form/:id", h.GetFormWithFieldsHandler)
+		r.PUT("/form/:id", h.UpdateFormHandler)
+		r.POST("/form/:id/publish", h.PublishFormHandler)
+		r.PUT("/form_fields/:id/dependency", h.UpdateFormFieldDependencyHandler)
+		r.PUT("/field/:id", h.UpdateFieldHandler)
+	}
+
+	body := `{"form_name":"Registration","data_type_id":` + jsonNumber(choice.ID) + `,"service_id":` + jsonNumber(service.ID) +
+		`,"fields":[{"fields_id":` + jsonNumber(province.ID) + `,"field_row":1},{"fields_id":` + jsonNumber(district.ID) + `,"field_row":2}]}`
+	rec := serve(store, designer, http.MethodPost, "/form", body, routes)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: got %d: %s", rec.Code, rec.Body)
+	}
+	path := "/form/" + jsonNumber(decode[FormResponse](t, rec).ID)
+
+	v1 := decode[FormDefinitionResponse](t, serve(store, designer, http.MethodGet, path+"?version=draft", "", routes))
+	if v1.Version != 1 || v1.VersionStatus != models.FormVersionDraft || len(v1.Fields) != 2 {
+		t.Fatalf("new form: got version %d %s with %d fields, want draft 1 with 2", v1.Version, v1.VersionStatus, len(v1.Fields))
+	}
+	parent, child := v1.Fields[0].ID, v1.Fields[1].ID
+	rec = serve(store, designer, http.MethodPut, "/form_fields/"+jsonNumber(child)+"/dependency", `{"depends_on_form_field_id":`+jsonNumber(parent)+`}`, routes)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("dependency: got %d: %s", rec.Code, rec.Body)
+	}
+
+	if rec := serve(store, designer, http.MethodPost, path+"/publish", "", routes); rec.Code != http.StatusOK {
+		t.Fatalf("publish: got %d: %s", rec.Code, rec.Body)
+	}
+
+	// Publishing freezes the fields the version places.
+	rec = serve(store, designer, http.MethodPut, "/field/"+jsonNumber(province.ID), `{"label":"Region","data_type_id":`+jsonNumber(choice.ID)+`}`, routes)
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), helpers.CodeVersionPublished) {
+		t.Errorf("relabelling a published field: got %d %s, want 409", rec.Code, rec.Body)
+	}
+	rec = serve(store, designer, http.MethodPut, "/form_fields/"+jsonNumber(child)+"/dependency", `{"depends_on_form_field_id":null}`, routes)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("changing a published dependency: got %d %s, want 409", rec.Code, rec.Body)
+	}
+
+	// Editing after publishing opens draft 2, and editing again keeps to it.
+	update := `{"form_name":"Registration 2025","data_type_id":` + jsonNumber(choice.ID) + `,"service_id":` + jsonNumber(service.ID) + `}`
+	for range 2 {
+		rec = serve(store, designer, http.MethodPut, path, update, routes)
+		if got := decode[FormVersionResponse](t, rec); rec.Code != http.StatusOK || got.Version != 2 || got.Status != models.FormVersionDraft {
+			t.Fatalf("edit after publish: got %d %+v, want draft 2", rec.Code, got)
+		}
+	}
+
+	v2 := decode[FormDefinitionResponse](t, serve(store, designer, http.MethodGet, path+"?version=draft", "", routes))
+	if v2.Version != 2 || v2.FormName != "Registration 2025" || len(v2.Fields) != 2 {
+		t.Fatalf("draft 2: got version %d %q with %d fields", v2.Version, v2.FormName, len(v2.Fields))
+	}
+	clonedParent, clonedChild := v2.Fields[0], v2.Fields[1]
+	if clonedParent.ID == parent || clonedChild.ID == child {
+		t.Errorf("draft 2 shares form fields with version 1: %d %d", clonedParent.ID, clonedChild.ID)
+	}
+	if clonedChild.DependsOnFormFieldID == nil || *clonedChild.DependsOnFormFieldID != clonedParent.ID {
+		t.Errorf("cloned dependency: got %v, want the cloned parent %d", clonedChild.DependsOnFormFieldID, clonedParent.ID)
+	}
+
+	published := decode[FormDefinitionResponse](t, serve(store, designer, http.MethodGet, path, "", routes))
+	if published.Version != 1 || published.FormName != "Registration" {
+		t.Errorf("published: got version %d %q, want version 1 unchanged", published.Version, published.FormName)
+	}
+	if dep := published.Fields[1].DependsOnFormFieldID; dep == nil || *dep != parent {
+		t.Errorf("published dependency: got %v, want %d", dep, parent)
+	}
+
+	// Submissions to version 1 were filed under its service and data type.
+	moved := `{"form_name":"Registration 2025","data_type_id":` + jsonNumber(choice.ID) + `,"service_id":` + jsonNumber(other.ID) + `}`
+	rec = serve(store, designer, http.MethodPut, path, moved, routes)
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), helpers.CodeVersionPublished) {
+		t.Errorf("moving a published form to another service: got %d %s, want 409", rec.Code, rec.Body)
+	}
+	if got := decode[FormDefinitionResponse](t, serve(store, designer, http.MethodGet, path, "", routes)); got.ServiceID == nil || *got.ServiceID != service.ID {
+		t.Errorf("service after the refused move: got %v, want %d", got.ServiceID, service.ID)
+	}
+}
+
+func TestFormFieldDependencies(t *testing.T) {
+	store := memory.New()
+	designer := &models.User{Role: models.RoleDesigner}
+	choice := &models.DataType{DataType: "collection"}
+	provinces := &models.Collection{CollectionName: "Provinces"}
+	districts := &models.Collection{CollectionName: "Districts"}
+	wards := &models.Collection{CollectionName: "Wards"}
+	store.Add(designer, choice, provinces, districts, wards)
+	province := &models.Field{Label: "Province", DataTypeID: choice.ID, CollectionID: &provinces.ID}
+	district := &models.Field{Label: "District", DataTypeID: choice.ID, CollectionID: &districts.ID}
+	ward := &models.Field{Label: "Ward", DataTypeID: choice.ID, CollectionID: &wards.ID}
+	form := &models.Form{FormName: "Registration", DataTypeID: choice.ID}
+	other := &models.Form{FormName: "Permit", DataTypeID: choice.ID}
+	store.Add(province, district, ward, form, other)
+	v1 := &models.FormVersion{FormID: form.ID, Version: 1, Status: models.FormVersionPublished, FormName: form.FormName}
+	draft := &models.FormVersion{FormID: form.ID, Version: 2, Status: models.FormVersionDraft, FormName: form.FormName}
+	otherDraft := &models.FormVersion{FormID: other.ID, Version: 1, Status: models.FormVersionDraft, FormName: other.FormName}
+	store.Add(v1, draft, otherDraft)
+	place := func(version *models.FormVersion, field *models.Field) *models.FormFields {
+		ff := &models.FormFields{FormID: version.FormID, FormVersionID: &version.ID, FieldID: field.ID}
+		store.Add(ff)
+		return ff
+	}
+	publishedProvince, publishedWard := place(v1, province), place(v1, ward)
+	draftProvince, draftDistrict := place(draft, province), place(draft, district)
+	otherProvince := place(otherDraft, province)
+
+	routes := func(r *gin.Engine, h *Handler) {
+		r.PUT("/form_fields/:id/dependency", h.UpdateFormFieldDependencyHandler)
+	}
+	depend := func(child *models.FormFields, parent uint) *httptest.ResponseRecorder {
+		return serve(store, designer, http.MethodPut, "/form_fields/"+jsonNumber(child.ID)+"/dependency", `{"depends_on_form_field_id":`+jsonNumber(parent)+`}`, routes)
+	}
+
+	// A parent from the published version is mapped onto its copy in the draft.
+	rec := depend(draftDistrict, publishedProvince.ID)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("parent from the published version: got %d: %s", rec.Code, rec.Body)
+	}
+	if got := decode[FormFieldResponse](t, rec).DependsOnFormFieldID; got == nil || *got != draftProvince.ID {
+		t.Errorf("parent from the published version: got %v, want the draft's copy %d", got, draftProvince.ID)
+	}
+
+	refused := []struct {
+		name   string
+		child  *models.FormFields
+		parent uint
+	}{
+		{"parent from another form", draftDistrict, otherProvince.ID},
+		{"parent missing from the draft", draftDistrict, publishedWard.ID},
+		{"parent that does not exist", draftDistrict, 9999},
+		{"dependency on itself", draftProvince, draftProvince.ID},
+		{"dependency cycle", draftProvince, draftDistrict.ID},
+	}
+	for _, tt := range refused {
+		rec := depend(tt.child, tt.parent)
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), helpers.CodeInvalidRequest) {
+			t.Errorf("%s: got %d %s, want 400", tt.name, rec.Code, rec.Body)
+		}
+	}
+	if ff, err := store.Repositories().Forms.FormField(t.Context(), draftProvince.ID); err != nil || ff.DependsOnFormFieldID != nil {
+		t.Errorf("province after the refused cycle: got %+v, %v, want no dependency", ff, err)
+	}
+}
+
+func TestDependentAnswers(t *testing.T) {
+	store := memory.New()
+	alice := &models.User{Email: "alice@example.com", Role: models.RoleApplicant}
+	choice := &models.DataType{DataType: "collection"}
+	provinces := &models.Collection{CollectionName: "Provinces"}
+	districts := &models.Collection{CollectionName: "Districts"}
+	store.Add(alice, choice, provinces, districts)
+	lusaka := &models.CollectionItem{CollectionID: &provinces.ID, CollectionItem: "Lusaka"}
+	central := &models.CollectionItem{CollectionID: &provinces.ID, CollectionItem: "Central"}
+	store.Add(lusaka, central)
+	chongwe := &models.CollectionItem{CollectionID: &districts.ID, CollectionItem: "Chongwe", RelationCollectionItemsID: &lusaka.ID}
+	kabwe := &models.CollectionItem{CollectionID: &districts.ID, CollectionItem: "Kabwe", RelationCollectionItemsID: &central.ID}
+	province := &models.Field{Label: "Province", DataTypeID: choice.ID, CollectionID: &provinces.ID}
+	district := &models.Field{Label: "District", DataTypeID: choice.ID, CollectionID: &districts.ID}
+	form := &models.Form{FormName: "Registration", DataTypeID: choice.ID}
+	store.Add(chongwe, kabwe, province, district, form)
+	version := &models.FormVersion{FormID: form.ID, Version: 1, Status: models.FormVersionPublished, FormName: form.FormName}
+	store.Add(version)
+	parent := &models.FormFields{FormID: form.ID, FormVersionID: &version.ID, FieldID: province.ID, FieldName: "province"}
+	store.Add(parent)
+	child := &models.FormFields{FormID: form.ID, FormVersionID: &version.ID, FieldID: district.ID, FieldName: "district", Validation: "required", DependsOnFormFieldID: &parent.ID}
+	store.Add(child)
+
+	routes := func(r *gin.Engine, h *Handler) {
+		r.POST("/submission", h.SubmitFormHandler)
+		r.POST("/submission/draft", h.CreateDraftHandler)
+	}
+	body := func(provinceID, districtID uint) string {
+		var answers []string
+		if provinceID != 0 {
+			answers = append(answers, `{"form_field_id":`+jsonNumber(parent.ID)+`,"answer":"`+jsonNumber(provinceID)+`"}`)
+		}
+		if districtID != 0 {
+			answers = append(answers, `{"form_field_id":`+jsonNumber(child.ID)+`,"answer":"`+jsonNumber(districtID)+`"}`)
+		}
+		return `{"form_id":` + jsonNumber(form.ID) + `,"answers":[` + strings.Join(answers, ",") + `]}`
+	}
+	childFailure := func(rec *httptest.ResponseRecorder) string {
+		var response structs.ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("decoding %s: %v", rec.Body, err)
+		}
+		for _, failure := range response.Details {
+			if failure.FormFieldID == child.ID {
+				return failure.Rule
+			}
+		}
+		return ""
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		province uint
+		district uint
+		wantRule string // Rule the district fails, empty when the request succeeds
+	}{
+		{"unanswered province hides the required district", "/submission", 0, 0, ""},
+		{"answered province requires the district", "/submission", lusaka.ID, 0, "required"},
+		{"district of another province", "/submission", lusaka.ID, kabwe.ID, "depends_on"},
+		{"district without its province", "/submission", 0, chongwe.ID, "depends_on"},
+		{"draft skips a district without its province", "/submission/draft", 0, chongwe.ID, ""},
+		{"district of the chosen province", "/submission", lusaka.ID, chongwe.ID, ""},
+	}
+	for _, tt := range tests {
+		rec := serve(store, alice, http.MethodPost, tt.path, body(tt.province, tt.district), routes)
+		if tt.wantRule == "" {
+			if rec.Code != http.StatusCreated {
+				t.Errorf("%s: got %d %s, want 201", tt.name, rec.Code, rec.Body)
+			}
+			continue
+		}
+		if rec.Code != http.StatusUnprocessableEntity || childFailure(rec) != tt.wantRule {
+			t.Errorf("%s: got %d %s, want 422 failing %s", tt.name, rec.Code, rec.Body, tt.wantRule)
+		}
+	}
+}
+
+func TestSubmitFormHandler(t *testing.T) {
+	store := memory.New()
+	service, form, placement := publishedForm(store)
+	other := &models.Service{ServiceName: "Permits"}
+	alice := &models.User{Email: "alice@example.com", Role: models.RoleApplicant}
+	reviewer := &models.User{Email: "rita@example.com", Role: models.RoleReviewer}
+	store.Add(other, alice, reviewer)
+
+	h := &Handler{Repositories: store.Repositories()}
+	if err := h.Users.AssignReviewer(t.Context(), reviewer.ID, service.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	routes := func(r *gin.Engine, h *Handler) {
+		r.POST("/submission", h.SubmitFormHandler)
+	}
+	body := `{"form_id":` + jsonNumber(form.ID) + `,"answers":[{"form_field_id":` + jsonNumber(placement.ID) + `,"answer":"Acme"}]}`
+	submit := func(body, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/submission", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set(idempotencyKeyHeader, key)
+		}
+		return serveRequest(h, alice, req, routes)
+	}
+
+	rec := submit(body, "attempt-1")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("submit: got %d: %s", rec.Code, rec.Body)
+	}
+	created := decode[SubmissionResponse](t, rec)
+	if created.Status != workflow.Submitted || created.ServicesID == nil || *created.ServicesID != service.ID {
+		t.Errorf("submit: got %+v, want a submitted submission of the form's service", created)
+	}
+	if len(created.Answers) != 1 || created.Answers[0].Answer != "Acme" {
+		t.Errorf("submit answers: got %+v, want the one answer", created.Answers)
+	}
+
+	history, err := h.Submissions.History(t.Context(), created.ID)
+	if err != nil || len(history) != 1 || history[0].ToStatus != workflow.Submitted {
+		t.Errorf("history: got %+v, %v, want one entry to submitted", history, err)
+	}
+	if events := store.Events(); len(events) != 1 || events[0].Event != webhooks.SubmissionCreated {
+		t.Errorf("events: got %+v, want one %s", events, webhooks.SubmissionCreated)
+	}
+	kinds := map[string]uint{}
+	for _, n := range store.Notifications() {
+		kinds[n.Kind] = n.UserID
+	}
+	if kinds[models.NotifySubmissionReceipt] != alice.ID || kinds[models.NotifyReviewRequested] != reviewer.ID {
+		t.Errorf("notifications: got %v, want a receipt for the applicant and a review request for the reviewer", kinds)
+	}
+
+	rec = submit(body, "attempt-1")
+	if rec.Code != http.StatusOK || rec.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("replay: got %d %s, want 200 with Idempotent-Replayed", rec.Code, rec.Body)
+	}
+	if got := decode[SubmissionResponse](t, rec); got.ID != created.ID {
+		t.Errorf("replay: got submission %d, want %d", got.ID, created.ID)
+	}
+	if events := store.Events(); len(events) != 1 {
+		t.Errorf("replay: got %d events, want no new one", len(events))
+	}
+
+	mismatched := `{"form_id":` + jsonNumber(form.ID) + `,"services_id":` + jsonNumber(other.ID) + `,"answers":[{"form_field_id":` + jsonNumber(placement.ID) + `,"answer":"Acme"}]}`
+	rec = submit(mismatched, "")
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), helpers.CodeInvalidReference) {
+		t.Errorf("services_id of another service: got %d %s, want 422", rec.Code, rec.Body)
+	}
+
+	rec = submit(`{"form_id":`+jsonNumber(form.ID)+`,"answers":[{"form_field_id":`+jsonNumber(placement.ID)+`,"answer":""}]}`, "")
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("blank required answer: got %d %s, want 422", rec.Code, rec.Body)
+	}
+	if got := len(store.Events()); got != 1 {
+		t.Errorf("rejected submits: got %d events, want still 1", got)
+	}
+}
+
+func TestSubmitFollowsServiceWorkflow(t *testing.T) {
+	store := memory.New()
+	service, form, placement := publishedForm(store)
+	alice := &models.User{Email: "alice@example.com", Role: models.RoleApplicant}
+	store.Add(alice)
+
+	h := &Handler{Repositories: store.Repositories()}
+	routes := func(r *gin.Engine, h *Handler) {
+		r.POST("/submission", h.SubmitFormHandler)
+	}
+	body := `{"form_id":` + jsonNumber(form.ID) + `,"answers":[{"form_field_id":` + jsonNumber(placement.ID) + `,"answer":"Acme"}]}`
+
+	if err := h.Services.SetWorkflow(t.Context(), service.ID, []models.WorkflowTransition{
+		{FromStatus: workflow.Draft, ToStatus: workflow.UnderReview, Actor: workflow.Applicant},
+		{FromStatus: workflow.UnderReview, ToStatus: workflow.Approved, Actor: workflow.Reviewer},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	rec := serve(store, alice, http.MethodPost, "/submission", body, routes)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("submit: got %d: %s", rec.Code, rec.Body)
+	}
+	created := decode[SubmissionResponse](t, rec)
+	if created.Status != workflow.UnderReview {
+		t.Errorf("status: got %q, want the workflow's %q", created.Status, workflow.UnderReview)
+	}
+	history, err := h.Submissions.History(t.Context(), created.ID)
+	if err != nil || len(history) != 1 || history[0].ToStatus != workflow.UnderReview {
+		t.Errorf("history: got %+v, %v, want one entry to %s", history, err, workflow.UnderReview)
+	}
+
+	if err := h.Services.SetWorkflow(t.Context(), service.ID, []models.WorkflowTransition{
+		{FromStatus: workflow.Submitted, ToStatus: workflow.Approved, Actor: workflow.Reviewer},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	rec = serve(store, alice, http.MethodPost, "/submission", body, routes)
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), helpers.CodeTransitionNotAllowed) {
+		t.Errorf("workflow without an applicant edge out of draft: got %d %s, want 409", rec.Code, rec.Body)
+	}
+	if got, _, err := h.Submissions.List(t.Context(), repository.SubmissionScope{}, query.Params{Limit: 10}); err != nil || len(got) != 1 {
+		t.Errorf("submissions: got %+v, %v, want only the first", got, err)
+	}
+}
+
+func TestDraftHandlers(t *testing.T) {
+	store := memory.New()
+	_, form, placement := publishedForm(store)
+	alice := &models.User{Email: "alice@example.com", Role: models.RoleApplicant}
+	store.Add(alice)
+
+	routes := func(r *gin.Engine, h *Handler) {
+		r.POST("/submission/draft", h.CreateDraftHandler)
+		r.PATCH("/submission/:id/answers", h.UpdateDraftAnswersHandler)
+		r.POST("/submission/:id/submit", h.SubmitDraftHandler)
+	}
+	answer := func(value string) string {
+		return `{"answers":[{"form_field_id":` + jsonNumber(placement.ID) + `,"answer":"` + value + `"}]}`
+	}
+
+	rec := serve(store, alice, http.MethodPost, "/submission/draft", `{"form_id":`+jsonNumber(form.ID)+`}`, routes)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create draft: got %d: %s", rec.Code, rec.Body)
+	}
+	draft := decode[SubmissionResponse](t, rec)
+	if draft.Status != workflow.Draft || len(draft.Answers) != 0 {
+		t.Errorf("create draft: got %+v, want an empty draft", draft)
+	}
+	path := "/submission/" + jsonNumber(draft.ID)
+
+	rec = serve(store, alice, http.MethodPost, path+"/submit", "", routes)
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), `"rule":"required"`) {
+		t.Errorf("submit without the required answer: got %d %s, want 422 for the required field", rec.Code, rec.Body)
+	}
+
+	for _, value := range []string{"Acme", "Globex"} {
+		rec = serve(store, alice, http.MethodPatch, path+"/answers", answer(value), routes)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("save %q: got %d: %s", value, rec.Code, rec.Body)
+		}
+		got := decode[SubmissionResponse](t, rec)
+		if len(got.Answers) != 1 || got.Answers[0].Answer != value {
+			t.Errorf("save %q: got answers %+v, want the one answer replaced", value, got.Answers)
+		}
+	}
+
+	rec = serve(store, alice, http.MethodPost, path+"/submit", "", routes)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("submit: got %d: %s", rec.Code, rec.Body)
+	}
+	if got := decode[SubmissionResponse](t, rec); got.Status != workflow.Submitted || len(got.Answers) != 1 || got.Answers[0].Answer != "Globex" {
+		t.Errorf("submit: got %+v, want submitted with the saved answer", got)
+	}
+
+	rec = serve(store, alice, http.MethodPost, path+"/submit", "", routes)
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), helpers.CodeNotEditable) {
+		t.Errorf("submitting twice: got %d %s, want 409 %s", rec.Code, rec.Body, helpers.CodeNotEditable)
+	}
+	rec = serve(store, alice, http.MethodPatch, path+"/answers", answer("Initech"), routes)
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), helpers.CodeNotEditable) {
+		t.Errorf("editing a submitted submission: got %d %s, want 409 %s", rec.Code, rec.Body, helpers.CodeNotEditable)
+	}
+	if events := store.Events(); len(events) != 1 || events[0].Event != webhooks.SubmissionStatusChanged {
+		t.Errorf("events: got %+v, want one %s", events, webhooks.SubmissionStatusChanged)
+	}
+}
+
+// failingAnswers is a Submissions repository whose answer inserts fail.
+type failingAnswers struct{ repository.Submissions }
+
+func (failingAnswers) CreateAnswer(ctx context.Context, answer *models.FormAnswer) error {
+	return errors.New("answer insert failed")
+}
+
+// failingAnswersUnitOfWork runs transactions whose answer inserts fail.
+type failingAnswersUnitOfWork struct{ repository.UnitOfWork }
+
+func (u failingAnswersUnitOfWork) Transaction(ctx context.Context, fn func(tx repository.Repositories) error) error {
+	return u.UnitOfWork.Transaction(ctx, func(tx repository.Repositories) error {
+		tx.Submissions = failingAnswers{tx.Submissions}
+		return fn(tx)
+	})
+}
+
+func TestSubmitRollsBackWhenAnAnswerFails(t *testing.T) {
+	store := memory.New()
+	_, form, placement := publishedForm(store)
+	alice := &models.User{Email: "alice@example.com", Role: models.RoleApplicant}
+	store.Add(alice)
+
+	repos := store.Repositories()
+	repos.UnitOfWork = failingAnswersUnitOfWork{repos.UnitOfWork}
+	routes := func(r *gin.Engine, h *Handler) {
+		r.POST("/submission", h.SubmitFormHandler)
+	}
+	body := `{"form_id":` + jsonNumber(form.ID) + `,"answers":[{"form_field_id":` + jsonNumber(placement.ID) + `,"answer":"Acme"}]}`
+	submit := func(h *Handler) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/submission", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(idempotencyKeyHeader, "attempt-1")
+		return serveRequest(h, alice, req, routes)
+	}
+
+	if rec := submit(&Handler{Repositories: repos}); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("failed answer insert: got %d %s, want 500", rec.Code, rec.Body)
+	}
+	h := &Handler{Repositories: store.Repositories()}
+	if _, err := h.Submissions.ByIdempotencyKey(t.Context(), "attempt-1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("submission after the failed insert: got %v, want it rolled back", err)
+	}
+	if got, _, err := h.Submissions.List(t.Context(), repository.SubmissionScope{}, query.Params{Limit: 10}); err != nil || len(got) != 0 {
+		t.Errorf("submissions after the failed insert: got %+v, %v, want none", got, err)
+	}
+	if events := store.Events(); len(events) != 0 {
+		t.Errorf("events after the failed insert: got %+v, want none", events)
+	}
+
+	// The key was not used up by the failed attempt.
+	if rec := submit(h); rec.Code != http.StatusCreated {
+		t.Errorf("retry: got %d %s, want 201", rec.Code, rec.Body)
+	}
+}
+
+func TestSubmitRefusesIdempotencyKeyForAnotherRequest(t *testing.T) {
+	store := memory.New()
+	_, form, placement := publishedForm(store)
+	alice := &models.User{Email: "alice@example.com", Role: models.RoleApplicant}
+	store.Add(alice)
+
+	h := &Handler{Repositories: store.Repositories()}
+	routes := func(r *gin.Engine, h *Handler) {
+		r.POST("/submission", h.SubmitFormHandler)
+	}
+	submit := func(answer string) *httptest.ResponseRecorder {
+		body := `{"form_id":` + jsonNumber(form.ID) + `,"answers":[{"form_field_id":` + jsonNumber(placement.ID) + `,"answer":"` + answer + `"}]}`
+		req := httptest.NewRequest(http.MethodPost, "/submission", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(idempotencyKeyHeader, "attempt-1")
+		return serveRequest(h, alice, req, routes)
+	}
+
+	if rec := submit("Acme"); rec.Code != http.StatusCreated {
+		t.Fatalf("submit: got %d %s", rec.Code, rec.Body)
+	}
+	rec := submit("Globex")
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), helpers.CodeIdempotencyKeyReused) {
+		t.Errorf("same key, other answers: got %d %s, want 422 %s", rec.Code, rec.Body, helpers.CodeIdempotencyKeyReused)
+	}
+	if rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Error("same key, other answers: replayed the original submission")
+	}
+}
+
+func TestTransitionSubmissionHandler(t *testing.T) {
+	store := memory.New()
+	service, _, _ := publishedForm(store)
+	alice := &models.User{Email: "alice@example.com", Role: models.RoleApplicant}
+	reviewer := &models.User{Email: "rita@example.com", Role: models.RoleReviewer}
+	store.Add(alice, reviewer)
+	submission := &models.Submission{ServicesID: &service.ID, CreatedBy: &alice.ID, Status: workflow.Submitted}
+	store.Add(submission)
+
+	h := &Handler{Repositories: store.Repositories()}
+	if err := h.Users.AssignReviewer(t.Context(), reviewer.ID, service.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	routes := func(r *gin.Engine, h *Handler) {
+		r.POST("/submission/:id/transition", h.TransitionSubmissionHandler)
+	}
+	path := "/submission/" + jsonNumber(submission.ID) + "/transition"
+
+	rec := serve(store, alice, http.MethodPost, path, `{"status":"under_review"}`, routes)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("applicant starting review: got %d %s, want 409", rec.Code, rec.Body)
+	}
+
+	rec = serve(store, reviewer, http.MethodPost, path, `{"status":"under_review","comment":"Looking into it"}`, routes)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("reviewer starting review: got %d: %s", rec.Code, rec.Body)
+	}
+	if got := decode[SubmissionResponse](t, rec); got.Status != workflow.UnderReview {
+		t.Errorf("status: got %q, want %q", got.Status, workflow.UnderReview)
+	}
+
+	history, err := h.Submissions.History(t.Context(), submission.ID)
+	if err != nil || len(history) != 1 {
+		t.Fatalf("history: got %+v, %v, want one entry", history, err)
+	}
+	if entry := history[0]; entry.FromStatus != workflow.Submitted || entry.ToStatus != workflow.UnderReview || entry.Comment != "Looking into it" {
+		t.Errorf("history: got %+v, want submitted to under_review with the comment", entry)
+	}
+	if events := store.Events(); len(events) != 1 || events[0].Event != webhooks.SubmissionStatusChanged {
+		t.Errorf("events: got %+v, want one %s", events, webhooks.SubmissionStatusChanged)
+	}
+	if sent := store.Notifications(); len(sent) != 1 || sent[0].Kind != models.NotifyStatusChange || sent[0].UserID != alice.ID {
+		t.Errorf("notifications: got %+v, want a status change for the applicant", sent)
+	}
+}
+
+func TestCollectionItemHandlers(t *testing.T) {
+	store := memory.New()
+	designer := &models.User{Role: models.RoleDesigner}
+	provinces := &models.Collection{CollectionName: "Provinces"}
+	store.Add(designer, provinces)
+	lusaka := &models.CollectionItem{CollectionID: &provinces.ID, CollectionItem: "Lusaka", Code: "LSK"}
+	store.Add(lusaka)
+	routes := func(r *gin.Engine, h *Handler) {
+		r.POST("/collection_items", h.CreateCollectionItemHandler)
+		r.GET("/collections/:id/items", h.GetCollectionItemsHandler)
+	}
+
+	body := `{"collection_id":` + jsonNumber(provinces.ID) + `,"collection_item":"Lusaka West","code":"LSK"}`
+	if rec := serve(store, designer, http.MethodPost, "/collection_items", body, routes); rec.Code != http.StatusConflict {
+		t.Errorf("duplicate code: got %d, want 409: %s", rec.Code, rec.Body)
+	}
+
+	body = `{"collection_id":` + jsonNumber(provinces.ID) + `,"collection_item":"Copperbelt"}`
+	if rec := serve(store, designer, http.MethodPost, "/collection_items", body, routes); rec.Code != http.StatusCreated {
+		t.Fatalf("create: got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec := serve(store, designer, http.MethodGet, "/collections/"+jsonNumber(provinces.ID)+"/items", "", routes)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("items: got %d: %s", rec.Code, rec.Body)
+	}
+	if got := decode[[]json.RawMessage](t, rec); len(got) != 2 {
+		t.Errorf("items: got %d, want 2", len(got))
+	}
+}
+
+func jsonNumber(id uint) string {
+	out, _ := json.Marshal(id)
+	return string(out)
+}
+
+func TestBindErrorsListFields(t *testing.T) {
+	store := memory.New()
+	admin := &models.User{Role: models.RoleAdmin}
+	store.Add(admin)
+	routes := func(r *gin.Engine, h *Handler) {
+		r.POST("/services", h.AddServiceHandler)
+	}
+
+	rec := serve(store, admin, http.MethodPost, "/services", `{}`, routes)
+	var body structs.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusBadRequest || body.Code != helpers.CodeValidationFailed {
+		t.Fatalf("got %d %s, want 400 %s", rec.Code, body.Code, helpers.CodeValidationFailed)
+	}
+	if len(body.Details) != 1 || body.Details[0].Field != "service_name" || body.Details[0].Rule != "required" {
+		t.Errorf("got details %+v, want service_name required", body.Details)
+	}
+}
+
+func TestGetReservedNameHandlerJudgesAvailability(t *testing.T) {
+	store := memory.New()
+	admin := &models.User{Role: models.RoleAdmin}
+	store.Add(admin, models.NewReservedName("ACME Limited"), models.NewReservedName("Akme Traders"), models.NewReservedName("Zambezi Breweries"))
+	routes := func(r *gin.Engine, h *Handler) {
+		r.GET("/reserved-name/:name", h.GetReservedNameHandler)
+	}
+
+	rec := serve(store, admin, http.MethodGet, "/reserved-name/Acme%20Ltd", "", routes)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body)
+	}
+	got := decode[NameAvailabilityResponse](t, rec)
+	if got.Available || got.Verdict != names.Unavailable || got.NormalizedName != "acme" {
+		t.Errorf("got %+v, want acme unavailable", got)
+	}
+	if len(got.Conflicts) != 1 || got.Conflicts[0].ReservedName != "ACME Limited" || got.Conflicts[0].Score != 1 {
+		t.Errorf("got conflicts %+v, want only ACME Limited scoring 1", got.Conflicts)
+	}
+
+	rec = serve(store, admin, http.MethodGet, "/reserved-name/Acme%20Traders", "", routes)
+	got = decode[NameAvailabilityResponse](t, rec)
+	if got.Verdict != names.Review || len(got.Conflicts) != 2 || got.Conflicts[0].ReservedName != "Akme Traders" {
+		t.Errorf("got %+v, want review with Akme Traders ranked first", got)
+	}
+
+	rec = serve(store, admin, http.MethodGet, "/reserved-name/Kafue%20Fisheries", "", routes)
+	if got := decode[NameAvailabilityResponse](t, rec); !got.Available || len(got.Conflicts) != 0 {
+		t.Errorf("got %+v, want available without conflicts", got)
+	}
+
+	if rec := serve(store, admin, http.MethodGet, "/reserved-name/...", "", routes); rec.Code != http.StatusBadRequest {
+		t.Errorf("punctuation only: got %d, want 400", rec.Code)
+	}
+}
+
+func TestReservationLifecycle(t *testing.T) {
+	store := memory.New()
+	alice := &models.User{Email: "alice@example.com", Role: models.RoleApplicant}
+	bob := &models.User{Email: "bob@example.com", Role: models.RoleApplicant}
+	admin := &models.User{Email: "admin@example.com", Role: models.RoleAdmin}
+	service := &models.Service{ServiceName: "Business names"}
+	store.Add(alice, bob, admin, service)
+	routes := func(r *gin.Engine, h *Handler) {
+		h.Reservations.HoldPeriod = time.Hour
+		r.GET("/reserved-name", h.ListReservedNamesHandler)
+		r.POST("/reserved-name", h.CreateReservedNameHandler)
+		r.POST("/reserved-name/:id/renew", h.RenewReservedNameHandler)
+		r.POST("/reserved-name/:id/release", h.ReleaseReservedNameHandler)
+	}
+	reserve := `{"reserved_name":"Acme Ltd","service_id":` + jsonNumber(service.ID) + `}`
+
+	rec := serve(store, alice, http.MethodPost, "/reserved-name", reserve, routes)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("reserve: got %d: %s", rec.Code, rec.Body)
+	}
+	held := decode[ReservedNameResponse](t, rec)
+	if held.Status != models.ReservationHeld || held.UserID == nil || *held.UserID != alice.ID || held.ExpiresOn == nil {
+		t.Fatalf("reserve: got %+v, want held by alice with an expiry", held)
+	}
+
+	var body structs.ErrorResponse
+	rec = serve(store, bob, http.MethodPost, "/reserved-name", `{"reserved_name":"ACME Limited","service_id":`+jsonNumber(service.ID)+`}`, routes)
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusConflict || body.Code != helpers.CodeNameTaken {
+		t.Errorf("same normalised name: got %d %s, want 409 %s", rec.Code, body.Code, helpers.CodeNameTaken)
+	}
+
+	path := "/reserved-name/" + jsonNumber(held.ID)
+	if rec := serve(store, bob, http.MethodPost, path+"/renew", "", routes); rec.Code != http.StatusForbidden {
+		t.Errorf("renew by another user: got %d, want 403", rec.Code)
+	}
+	rec = serve(store, alice, http.MethodPost, path+"/renew", "", routes)
+	if renewed := decode[ReservedNameResponse](t, rec); rec.Code != http.StatusOK || !renewed.ExpiresOn.After(*held.ExpiresOn) {
+		t.Errorf("renew: got %d %+v, want a later expiry than %s", rec.Code, renewed, held.ExpiresOn)
+	}
+
+	rec = serve(store, alice, http.MethodPost, path+"/release", "", routes)
+	if released := decode[ReservedNameResponse](t, rec); released.Status != models.ReservationReleased || released.ReleasedOn == nil {
+		t.Errorf("release: got %d %+v", rec.Code, released)
+	}
+	if rec := serve(store, alice, http.MethodPost, path+"/renew", "", routes); rec.Code != http.StatusConflict {
+		t.Errorf("renew after release: got %d, want 409", rec.Code)
+	}
+
+	if rec := serve(store, bob, http.MethodPost, "/reserved-name", reserve, routes); rec.Code != http.StatusCreated {
+		t.Errorf("reserve a released name: got %d: %s", rec.Code, rec.Body)
+	}
+
+	// Applicants only list their own reservations; names managers list all.
+	for _, tt := range []struct {
+		user *models.User
+		want int
+	}{{alice, 1}, {bob, 1}, {admin, 2}} {
+		rec := serve(store, tt.user, http.MethodGet, "/reserved-name", "", routes)
+		listed := decode[[]ReservedNameResponse](t, rec)
+		if rec.Code != http.StatusOK || len(listed) != tt.want {
+			t.Errorf("list as %s: got %d %+v, want %d reservations", tt.user.Role, rec.Code, listed, tt.want)
+		}
+		for _, rn := range listed {
+			if tt.user != admin && (rn.UserID == nil || *rn.UserID != tt.user.ID) {
+				t.Errorf("list as %s: got a reservation held by %v", tt.user.Email, rn.UserID)
+			}
+		}
+	}
+}
+
+func TestReservingChecksResemblingNames(t *testing.T) {
+	store := memory.New()
+	alice := &models.User{Email: "alice@example.com", Role: models.RoleApplicant}
+	bob := &models.User{Email: "bob@example.com", Role: models.RoleApplicant}
+	service := &models.Service{ServiceName: "Business names"}
+	store.Add(alice, bob, service)
+	routes := func(r *gin.Engine, h *Handler) {
+		h.Reservations.HoldPeriod = time.Hour
+		r.GET("/reserved-name/:name", h.GetReservedNameHandler)
+		r.POST("/reserved-name", h.CreateReservedNameHandler)
+	}
+	reserve := func(user *models.User, name string) *httptest.ResponseRecorder {
+		return serve(store, user, http.MethodPost, "/reserved-name", `{"reserved_name":"`+name+`","service_id":`+jsonNumber(service.ID)+`}`, routes)
+	}
+
+	if rec := reserve(alice, "Zambezi Breweries"); rec.Code != http.StatusCreated {
+		t.Fatalf("reserve: got %d: %s", rec.Code, rec.Body)
+	}
+
+	// A near-duplicate normalises differently, so only the check stops it.
+	checked := decode[NameAvailabilityResponse](t, serve(store, bob, http.MethodGet, "/reserved-name/Zambezi%20Breweries%201", "", routes))
+	if checked.Verdict != names.Unavailable {
+		t.Fatalf("check near-duplicate: got %+v, want unavailable", checked)
+	}
+	var body structs.ErrorResponse
+	rec := reserve(bob, "Zambezi Breweries 1")
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusConflict || body.Code != helpers.CodeNameTaken || len(body.Details) != 1 {
+		t.Errorf("reserve near-duplicate: got %d %+v, want 409 %s naming the held name", rec.Code, body, helpers.CodeNameTaken)
+	}
+
+	rec = reserve(bob, "Zambesi Breweries")
+	if reserved := decode[ReservedNameResponse](t, rec); rec.Code != http.StatusCreated || !reserved.NeedsReview {
+		t.Errorf("reserve a name that sounds alike: got %d %+v, want it reserved for review", rec.Code, reserved)
+	}
+
+	all, _, err := store.Repositories().Names.List(t.Context(), nil, query.Params{Limit: 10})
+	if err != nil || len(all) != 2 {
+		t.Errorf("reservations: got %+v, %v, want the first and the one for review", all, err)
+	}
+}
+
+func TestRestrictedTermsAreChecked(t *testing.T) {
+	store := memory.New()
+	admin := &models.User{Role: models.RoleAdmin}
+	service := &models.Service{ServiceName: "Business names"}
+	store.Add(admin, service)
+	routes := func(r *gin.Engine, h *Handler) {
+		h.Reservations.HoldPeriod = time.Hour
+		r.POST("/restricted-terms", h.CreateRestrictedTermHandler)
+		r.GET("/reserved-name/:name", h.GetReservedNameHandler)
+		r.POST("/reserved-name", h.CreateReservedNameHandler)
+	}
+	for _, term := range []string{
+		`{"term":"Bank","severity":"prohibited","reason":"Only licensed banks"}`,
+		`{"term":"Insurance","severity":"restricted","reason":"Needs PIA approval"}`,
+	} {
+		if rec := serve(store, admin, http.MethodPost, "/restricted-terms", term, routes); rec.Code != http.StatusCreated {
+			t.Fatalf("create term: got %d: %s", rec.Code, rec.Body)
+		}
+	}
+	if rec := serve(store, admin, http.MethodPost, "/restricted-terms", `{"term":"Bank","severity":"forbidden"}`, routes); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown severity: got %d, want 400", rec.Code)
+	}
+
+	rec := serve(store, admin, http.MethodGet, "/reserved-name/Acme%20Banking%20Ltd", "", routes)
+	got := decode[NameAvailabilityResponse](t, rec)
+	if got.Verdict != names.Unavailable || len(got.Terms) != 1 || got.Terms[0].Term != "Bank" || got.Terms[0].Severity != names.Prohibited {
+		t.Errorf("search: got %+v, want unavailable for the prohibited term Bank", got)
+	}
+
+	var body structs.ErrorResponse
+	rec = serve(store, admin, http.MethodPost, "/reserved-name", `{"reserved_name":"Acme Bank","service_id":`+jsonNumber(service.ID)+`}`, routes)
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusUnprocessableEntity || body.Code != helpers.CodeNameProhibited || len(body.Details) != 1 {
+		t.Errorf("reserve prohibited: got %d %+v, want 422 %s naming the term", rec.Code, body, helpers.CodeNameProhibited)
+	}
+
+	rec = serve(store, admin, http.MethodPost, "/reserved-name", `{"reserved_name":"Acme Insurance","service_id":`+jsonNumber(service.ID)+`}`, routes)
+	if reserved := decode[ReservedNameResponse](t, rec); rec.Code != http.StatusCreated || !reserved.NeedsReview || len(reserved.Terms) != 1 {
+		t.Errorf("reserve restricted: got %d %+v, want it reserved for review", rec.Code, reserved)
+	}
+}
+
+func TestWebhookHandlers(t *testing.T) {
+	store := memory.New()
+	admin := &models.User{Role: models.RoleAdmin}
+	service := &models.Service{ServiceName: "Business names"}
+	store.Add(admin, service)
+	routes := func(r *gin.Engine, h *Handler) {
+		r.GET("/services/:id/webhooks", h.ListServiceWebhooksHandler)
+		r.POST("/services/:id/webhooks", h.CreateServiceWebhookHandler)
+		r.GET("/webhooks/:id", h.GetWebhookHandler)
+		r.PUT("/webhooks/:id", h.UpdateWebhookHandler)
+		r.GET("/webhooks/:id/deliveries", h.ListWebhookDeliveriesHandler)
+		r.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", h.RedeliverWebhookDeliveryHandler)
+	}
+	base := "/services/" + jsonNumber(service.ID) + "/webhooks"
+
+	for _, body := range []string{
+		`{"url":"ftp://payments.example.com"}`,
+		`{"url":"https://payments.example.com","events":["submission.deleted"]}`,
+	} {
+		if rec := serve(store, admin, http.MethodPost, base, body, routes); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want 400", body, rec.Code)
+		}
+	}
+
+	rec := serve(store, admin, http.MethodPost, base, `{"url":"https://payments.example.com/hooks","events":["submission.created"]}`, routes)
+	created := decode[WebhookResponse](t, rec)
+	if rec.Code != http.StatusCreated || len(created.Secret) != 64 || !created.Active || len(created.Events) != 1 {
+		t.Fatalf("create: got %d %+v, want an active webhook with a generated secret", rec.Code, created)
+	}
+	path := "/webhooks/" + jsonNumber(created.ID)
+
+	rec = serve(store, admin, http.MethodPut, path, `{"url":"https://payments.example.com/v2","active":false}`, routes)
+	if updated := decode[WebhookResponse](t, rec); rec.Code != http.StatusOK || updated.Active || len(updated.Events) != 0 || updated.Secret != "" {
+		t.Errorf("update: got %d %+v, want it inactive for every event, secret hidden", rec.Code, updated)
+	}
+	if stored, _ := store.Repositories().Webhooks.Get(context.Background(), created.ID); stored.Secret != created.Secret {
+		t.Error("update without a secret replaced it")
+	}
+
+	rec = serve(store, admin, http.MethodGet, base, "", routes)
+	if list := decode[[]WebhookResponse](t, rec); len(list) != 1 || list[0].Secret != "" {
+		t.Errorf("list: got %+v", list)
+	}
+
+	event := &models.WebhookEvent{Event: "submission.created", ServiceID: &service.ID, Payload: `{}`}
+	store.Add(event)
+	delivery := &models.WebhookDelivery{WebhookID: created.ID, EventID: event.ID, Status: models.DeliveryFailed, Attempts: 8}
+	other := &models.WebhookDelivery{WebhookID: created.ID + 100, EventID: event.ID, Status: models.DeliveryFailed}
+	store.Add(delivery, other)
+
+	rec = serve(store, admin, http.MethodGet, path+"/deliveries", "", routes)
+	if list := decode[[]WebhookDeliveryResponse](t, rec); len(list) != 1 || list[0].Event != "submission.created" || list[0].NextAttemptOn != nil {
+		t.Errorf("deliveries: got %+v", list)
+	}
+
+	rec = serve(store, admin, http.MethodPost, path+"/deliveries/"+jsonNumber(other.ID)+"/redeliver", "", routes)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("redeliver another webhook's delivery: got %d, want 404", rec.Code)
+	}
+	rec = serve(store, admin, http.MethodPost, path+"/deliveries/"+jsonNumber(delivery.ID)+"/redeliver", "", routes)
+	if got := decode[WebhookDeliveryResponse](t, rec); rec.Code != http.StatusAccepted || got.Status != models.DeliveryPending || got.Attempts != 0 || got.NextAttemptOn == nil {
+		t.Errorf("redeliver: got %d %+v, want it pending again", rec.Code, got)
+	}
+}
+
+func TestNotificationTemplateHandlers(t *testing.T) {
+	store := memory.New()
+	admin := &models.User{Role: models.RoleAdmin}
+	service := &models.Service{ServiceName: "Business names"}
+	store.Add(admin, service)
+	routes := func(r *gin.Engine, h *Handler) {
+		r.GET("/services/:id/notification-templates", h.ListNotificationTemplatesHandler)
+		r.PUT("/services/:id/notification-templates/:kind", h.SaveNotificationTemplateHandler)
+		r.DELETE("/services/:id/notification-templates/:kind", h.DeleteNotificationTemplateHandler)
+	}
+	base := "/services/" + jsonNumber(service.ID) + "/notification-templates"
+
+	rec := serve(store, admin, http.MethodPut, base+"/newsletter", `{"subject":"x","body":"x"}`, routes)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown kind: got %d, want 404", rec.Code)
+	}
+	rec = serve(store, admin, http.MethodPut, base+"/status_change", `{"subject":"x","body":"{{.Reservation.Name}}"}`, routes)
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), helpers.CodeInvalidTemplate) {
+		t.Errorf("template using another kind's data: got %d %s, want 422", rec.Code, rec.Body)
+	}
+
+	rec = serve(store, admin, http.MethodPut, base+"/status_change", `{"subject":"#{{.Submission.ID}} is {{humanize .Submission.Status}}","body":"Hello {{.Recipient.Name}}"}`, routes)
+	if saved := decode[NotificationTemplateResponse](t, rec); rec.Code != http.StatusOK || !saved.Custom {
+		t.Fatalf("save: got %d %+v", rec.Code, saved)
+	}
+	rec = serve(store, admin, http.MethodPut, base+"/status_change", `{"subject":"#{{.Submission.ID}} changed","body":"Hello {{.Recipient.Name}}"}`, routes)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("replace: got %d", rec.Code)
+	}
+
+	rec = serve(store, admin, http.MethodGet, base, "", routes)
+	list := decode[[]NotificationTemplateResponse](t, rec)
+	if len(list) != len(models.NotificationKinds) {
+		t.Fatalf("list: got %+v, want every kind", list)
+	}
+	for _, template := range list {
+		if custom := template.Kind == models.NotifyStatusChange; template.Custom != custom || custom && template.Subject != "#{{.Submission.ID}} changed" {
+			t.Errorf("list: got %+v", template)
+		}
+	}
+
+	serve(store, admin, http.MethodDelete, base+"/status_change", "", routes)
+	rec = serve(store, admin, http.MethodGet, base, "", routes)
+	for _, template := range decode[[]NotificationTemplateResponse](t, rec) {
+		if template.Custom {
+			t.Errorf("after delete: got %+v, want the default", template)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"kora_1/internal/helpers"
+	"kora_1/internal/query"
+
+	"github.com/gin-gonic/gin"
+)
+
+// byName lets a list be sorted by ID or by its name column and searched by
+// name, which is all the reference-data lists need.
+func byName(column string) query.Spec {
+	return query.Spec{
+		Sorts:       map[string]string{"id": "id", "name": column},
+		DefaultSort: "id",
+		Filters:     map[string]query.Filter{"name": {Column: column, Op: query.Contains}},
+	}
+}
+
+// listParams parses the pagination, sort and filter parameters of a list
+// request, answering 400 when they are invalid.
+func listParams(c *gin.Context, spec query.Spec) (query.Params, bool) {
+	params, err := query.Parse(c.Request.URL.Query(), spec)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError(err.Error(), helpers.CodeInvalidRequest))
+		return params, false
+	}
+	return params, true
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"kora_1/internal/helpers"
+	"kora_1/internal/models"
+	"kora_1/internal/notify"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationTemplateRequest struct {
+	Subject string `json:"subject" binding:"required,max=255" example:"Submission #{{.Submission.ID}} received"`
+	Body    string `json:"body" binding:"required,max=20000" example:"Hello {{.Recipient.Name}},\n\nWe received your submission."`
+}
+
+type NotificationTemplateResponse struct {
+	Kind      string     `json:"kind"`
+	Subject   string     `json:"subject"`
+	Body      string     `json:"body"`
+	Custom    bool       `json:"custom"`     // False while the service uses the default
+	UpdatedOn *time.Time `json:"updated_on"` // Only for custom templates
+}
+
+func templateToResponse(template *models.NotificationTemplate) NotificationTemplateResponse {
+	return NotificationTemplateResponse{
+		Kind:      template.Kind,
+		Subject:   template.Subject,
+		Body:      template.Body,
+		Custom:    true,
+		UpdatedOn: &template.UpdatedOn,
+	}
+}
+
+// templateParams answers 400 or 404 unless the :id parameter names a service
+// and :kind a notification kind.
+func (h *Handler) templateParams(c *gin.Context) (uint, string, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return 0, "", false
+	}
+	kind := c.Param("kind")
+	if _, ok := notify.Default(kind); !ok {
+		helpers.WriteError(c, http.StatusNotFound, helpers.NewError("Unknown notification kind", helpers.CodeNotFound))
+		return 0, "", false
+	}
+	if _, err := h.Services.Get(c.Request.Context(), uint(id)); err != nil {
+		respondMissing(c, err, helpers.ErrServiceNotFound)
+		return 0, "", false
+	}
+	return uint(id), kind, true
+}
+
+// ListNotificationTemplatesHandler lists the email templates of a service
+// @Summary      List notification templates
+// @Description  Retrieve the subject and body of every email a service sends: submission_receipt, review_requested, status_change and reservation_expiry. Kinds the service has not customised show the default.
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Service ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /services/{id}/notification-templates [get]
+func (h *Handler) ListNotificationTemplatesHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	if _, err := h.Services.Get(c.Request.Context(), uint(id)); err != nil {
+		respondMissing(c, err, helpers.ErrServiceNotFound)
+		return
+	}
+	rows, err := h.Templates.List(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	custom := make(map[string]*models.NotificationTemplate, len(rows))
+	for i := range rows {
+		custom[rows[i].Kind] = &rows[i]
+	}
+	response := make([]NotificationTemplateResponse, 0, len(models.NotificationKinds))
+	for _, kind := range models.NotificationKinds {
+		if template, ok := custom[kind]; ok {
+			response = append(response, templateToResponse(template))
+			continue
+		}
+		source, _ := notify.Default(kind)
+		response = append(response, NotificationTemplateResponse{Kind: kind, Subject: source.Subject, Body: source.Body})
+	}
+	c.JSON(http.StatusOK, helpers.NewSuccess(response, "Notification templates retrieved successfully"))
+}
+
+// SaveNotificationTemplateHandler replaces the default email of a kind for a service
+// @Summary      Save notification template
+// @Description  Set the subject and body a service's emails of the kind are rendered from. Both are Go text templates executed with .Recipient.Name, .Recipient.Email, .Service,
+// @Description  .Submission (ID, FormName, Status, FromStatus, Comment) for submission emails and .Reservation (ID, Name, ExpiresOn) for reservation_expiry; humanize turns under_review into "under review".
+// @Description  A template that does not parse, or uses data its kind does not have, is rejected with INVALID_TEMPLATE.
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                          true  "Service ID"
+// @Param        kind     path      string                       true  "Notification kind"
+// @Param        request  body      NotificationTemplateRequest  true  "Notification Template Request"
+// @Success      200      {object}  map[string]interface{}
+// @Failure      400,404,422,500  {object}  structs.ErrorResponse
+// @Router       /services/{id}/notification-templates/{kind} [put]
+func (h *Handler) SaveNotificationTemplateHandler(c *gin.Context) {
+	serviceID, kind, ok := h.templateParams(c)
+	if !ok {
+		return
+	}
+
+	var request NotificationTemplateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if _, err := notify.Parse(kind, notify.Source{Subject: request.Subject, Body: request.Body}); err != nil {
+		helpers.WriteError(c, http.StatusUnprocessableEntity, helpers.NewError(err.Error(), helpers.CodeInvalidTemplate))
+		return
+	}
+
+	template := &models.NotificationTemplate{
+		ServiceID: serviceID,
+		Kind:      kind,
+		Subject:   request.Subject,
+		Body:      request.Body,
+		UpdatedOn: time.Now(),
+	}
+	if err := h.Templates.Save(c.Request.Context(), template); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess(templateToResponse(template), "Notification template saved successfully"))
+}
+
+// DeleteNotificationTemplateHandler reverts a service's email of a kind to the default
+// @Summary      Delete notification template
+// @Description  Remove a service's template of the kind, so that its emails use the default again
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int     true  "Service ID"
+// @Param        kind  path      string  true  "Notification kind"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /services/{id}/notification-templates/{kind} [delete]
+func (h *Handler) DeleteNotificationTemplateHandler(c *gin.Context) {
+	serviceID, kind, ok := h.templateParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Templates.Delete(c.Request.Context(), serviceID, kind); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[any](nil, "Notification template deleted successfully"))
+}
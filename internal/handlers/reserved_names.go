@@ -1,101 +1,443 @@
 package handlers
 
 import (
-	"kora_1/internal/database"
+	"context"
+	"errors"
+	"fmt"
+	"kora_1/internal/auth"
 	"kora_1/internal/helpers"
+	"kora_1/internal/middleware"
 	"kora_1/internal/models"
+	"kora_1/internal/names"
+	"kora_1/internal/query"
+	"kora_1/internal/repository"
+	"kora_1/internal/structs"
 	"net/http"
+	"sort"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ReservedNameRequest struct {
-	ReservedName string `json:"reserved_name" binding:"required"`
+	ReservedName string `json:"reserved_name" binding:"required,max=50"`
+	ServiceID    uint   `json:"service_id" binding:"required"`
 }
 
 type ReservedNameResponse struct {
-	ID           uint   `json:"id"`
-	ReservedName string `json:"reserved_name"`
+	ID             uint       `json:"id"`
+	ReservedName   string     `json:"reserved_name"`
+	NormalizedName string     `json:"normalized_name"`
+	UserID         *uint      `json:"user_id"`
+	ServiceID      *uint      `json:"service_id"`
+	Status         string     `json:"status"`
+	ReservedOn     time.Time  `json:"reserved_on"`
+	ExpiresOn      *time.Time `json:"expires_on"`
+	ReleasedOn     *time.Time `json:"released_on,omitempty"`
+	NeedsReview    bool       `json:"needs_review"`
+
+	// Terms lists the restricted terms the name uses, when it was just reserved.
+	Terms []TermMatch `json:"terms,omitempty"`
+}
+
+func reservedNameToResponse(rn *models.ReservedName) ReservedNameResponse {
+	return ReservedNameResponse{
+		ID:             rn.ID,
+		ReservedName:   rn.ReservedName,
+		NormalizedName: rn.NormalizedName,
+		UserID:         rn.UserID,
+		ServiceID:      rn.ServiceID,
+		Status:         rn.Status,
+		ReservedOn:     rn.ReservedOn,
+		ExpiresOn:      rn.ExpiresOn,
+		ReleasedOn:     rn.ReleasedOn,
+		NeedsReview:    rn.NeedsReview,
+	}
+}
+
+// NameConflict is a reserved name that resembles the name asked about.
+type NameConflict struct {
+	ID           uint           `json:"id"`
+	ReservedName string         `json:"reserved_name"`
+	Score        float64        `json:"score"`
+	Reasons      []names.Reason `json:"reasons"`
 }
 
-// GetReservedNameHandler retrieves reserved names similar to the provided name
-// @Summary      Get reserved names
-// @Description  Retrieve reserved names that match or are similar to the provided name parameter
+// NameAvailabilityResponse is the verdict on whether a name can be reserved.
+type NameAvailabilityResponse struct {
+	Name           string         `json:"name"`
+	NormalizedName string         `json:"normalized_name"`
+	Available      bool           `json:"available"`
+	Verdict        names.Verdict  `json:"verdict"`
+	Conflicts      []NameConflict `json:"conflicts"`
+	Terms          []TermMatch    `json:"terms"`
+}
+
+// nameCandidates caps how many similar reserved names are scored.
+const nameCandidates = 50
+
+var (
+	errNameProhibited = errors.New("name uses a prohibited term")
+	errNameTaken      = errors.New("name is, or is all but, a reserved name")
+)
+
+// GetReservedNameHandler checks whether a name can be reserved
+// @Summary      Check name availability
+// @Description  Compare a name with the names currently held, ignoring case, punctuation and legal suffixes such as Ltd or PLC, by trigram similarity and by sound, and check it against the restricted terms. The verdict is unavailable when a reserved name is the same or nearly so or the name uses a prohibited term, review when a reserved name resembles it or it uses a restricted term, and available otherwise. Conflicts lists the resembling names, most similar first, and terms the restricted terms used.
 // @Tags         reserved-name
 // @Accept       json
 // @Produce      json
-// @Param        name  path      string  true  "Name to search for"
+// @Security     BearerAuth
+// @Param        name  path      string  true  "Name to check"
 // @Success      200   {object}  map[string]interface{}
 // @Failure      400,500   {object}  structs.ErrorResponse
 // @Router       /reserved-name/{name} [get]
-func GetReservedNameHandler(c *gin.Context) {
+func (h *Handler) GetReservedNameHandler(c *gin.Context) {
 	name := c.Param("name")
-	if name == "" {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Name parameter is required", http.StatusBadRequest))
+	if names.KeyOf(name).Normalized == "" {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Name must contain letters or digits", helpers.CodeInvalidRequest))
 		return
 	}
 
-	reservedNames, err := models.GetSimilarNames(database.DB, name)
+	check, err := checkName(c.Request.Context(), h.Repositories, name, time.Now())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+		respondError(c, err)
 		return
 	}
 
-	// Helper to convert to response struct if needed, or return directly
-	c.JSON(http.StatusOK, helpers.NewSuccess[[]models.ReservedName](reservedNames, "Reserved names retrieved successfully"))
+	verdict := check.verdict()
+	c.JSON(http.StatusOK, helpers.NewSuccess(NameAvailabilityResponse{
+		Name:           name,
+		NormalizedName: check.key.Normalized,
+		Available:      verdict == names.Available,
+		Verdict:        verdict,
+		Conflicts:      check.conflicts,
+		Terms:          check.terms,
+	}, "Name checked successfully"))
+}
+
+// nameCheck is the verdict on a name and what it rests on.
+type nameCheck struct {
+	key         names.Key
+	conflicts   []NameConflict // Most similar first
+	terms       []TermMatch
+	nameVerdict names.Verdict // From the reserved names it resembles
+	termVerdict names.Verdict // From the restricted terms it uses
+}
+
+func (nc nameCheck) verdict() names.Verdict {
+	return names.Worse(nc.nameVerdict, nc.termVerdict)
+}
+
+// checkName compares a name with the names held at now and the restricted
+// terms. Checking a name and reserving it both go through it, so a name is
+// reserved only when a check would have let it through.
+func checkName(ctx context.Context, repos repository.Repositories, name string, now time.Time) (nameCheck, error) {
+	check := nameCheck{key: names.KeyOf(name)}
+	candidates, err := repos.Names.Similar(ctx, check.key, now, nameCandidates)
+	if err != nil {
+		return nameCheck{}, err
+	}
+
+	check.conflicts = make([]NameConflict, 0, len(candidates))
+	matches := make([]names.Match, 0, len(candidates))
+	for _, candidate := range candidates {
+		match := names.Compare(check.key, candidate.Key())
+		if !match.Conflict() {
+			continue
+		}
+		matches = append(matches, match)
+		check.conflicts = append(check.conflicts, NameConflict{
+			ID:           candidate.ID,
+			ReservedName: candidate.ReservedName,
+			Score:        match.Score,
+			Reasons:      match.Reasons,
+		})
+	}
+	sort.SliceStable(check.conflicts, func(i, j int) bool { return check.conflicts[i].Score > check.conflicts[j].Score })
+	check.nameVerdict = names.Judge(matches)
+
+	check.terms, check.termVerdict, err = checkTerms(ctx, repos, name)
+	if err != nil {
+		return nameCheck{}, err
+	}
+	return check, nil
 }
 
-// CreateReservedNameHandler creates a new reserved name
-// @Summary      Create reserved name
-// @Description  Create a new reserved name
+var reservedNamesSpec = query.Spec{
+	Sorts: map[string]string{
+		"id":          "id",
+		"name":        "reserved_name",
+		"reserved_on": "reserved_on",
+		"expires_on":  "expires_on",
+	},
+	DefaultSort: "id",
+	Filters: map[string]query.Filter{
+		"name":         {Column: "reserved_name", Op: query.Contains},
+		"status":       {Column: "status", Op: query.Equal},
+		"user_id":      {Column: "user_id", Op: query.Equal, Kind: query.Int},
+		"service_id":   {Column: "service_id", Op: query.Equal, Kind: query.Int},
+		"needs_review": {Column: "needs_review", Op: query.Equal, Kind: query.Bool},
+	},
+}
+
+// ListReservedNamesHandler lists reserved names
+// @Summary      List reserved names
+// @Description  Retrieve a page of reservations, including expired and released ones unless filtered by status. Names managers see every reservation and other users only their own; use GET /reserved-name/{name} to check whether a name is free. Unlike that check the name filter is a plain substring match.
 // @Tags         reserved-name
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Param        limit         query     int     false  "Page size (default 50, max 200)"
+// @Param        offset        query     int     false  "Rows to skip"
+// @Param        cursor        query     string  false  "meta.next_cursor of the previous page"
+// @Param        sort          query     string  false  "Comma-separated sort fields, prefix - for descending (id, name, reserved_on, expires_on)"
+// @Param        name          query     string  false  "Only names containing this text"
+// @Param        status        query     string  false  "Only reservations in this status (held, expired, released)"
+// @Param        user_id       query     int     false  "Only reservations held by this user (names managers)"
+// @Param        service_id    query     int     false  "Only reservations for this service"
+// @Param        needs_review  query     bool    false  "Only reservations that do, or do not, need review"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,500  {object}  structs.ErrorResponse
+// @Router       /reserved-name [get]
+func (h *Handler) ListReservedNamesHandler(c *gin.Context) {
+	params, ok := listParams(c, reservedNamesSpec)
+	if !ok {
+		return
+	}
+
+	// Only names managers see who holds other reservations.
+	user, _ := middleware.CurrentUser(c)
+	var holderID *uint
+	if !auth.Can(user.Role, auth.ManageReservedNames) {
+		holderID = &user.ID
+	}
+
+	reserved, meta, err := h.Names.List(c.Request.Context(), holderID, params)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]ReservedNameResponse, 0, len(reserved))
+	for i := range reserved {
+		response = append(response, reservedNameToResponse(&reserved[i]))
+	}
+	c.JSON(http.StatusOK, helpers.NewPage(response, meta, "Reserved names retrieved successfully"))
+}
+
+// CreateReservedNameHandler reserves a name
+// @Summary      Reserve a name
+// @Description  Hold a name for the current user and a service for the configured hold period, after which the reservation expires unless renewed. The name is checked as GET /reserved-name/{name} checks it: one the check finds unavailable because a held name is the same or nearly so answers 409 NAME_TAKEN, and one using a prohibited term 422 NAME_PROHIBITED. A name the check sends for review, because it resembles a held name or uses a restricted term, is reserved with needs_review set, and terms lists the restricted terms it uses.
+// @Tags         reserved-name
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
 // @Param        request  body      ReservedNameRequest  true  "Reserved Name Request"
 // @Success      201      {object}  map[string]interface{}
-// @Failure      400,500      {object}  structs.ErrorResponse
+// @Failure      400,404,409,422,500      {object}  structs.ErrorResponse
 // @Router       /reserved-name [post]
-func CreateReservedNameHandler(c *gin.Context) {
+func (h *Handler) CreateReservedNameHandler(c *gin.Context) {
 	var request ReservedNameRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
-	rn := &models.ReservedName{ReservedName: request.ReservedName}
-	err := models.CreateReservedName(database.DB, rn)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	rn := models.NewReservedName(request.ReservedName)
+	if rn.NormalizedName == "" {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Name must contain letters or digits", helpers.CodeInvalidRequest))
+		return
+	}
+	if _, err := h.Services.Get(c.Request.Context(), request.ServiceID); err != nil {
+		respondMissing(c, err, helpers.ErrServiceNotFound)
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	now := time.Now()
+	expiresOn := now.Add(h.Reservations.HoldPeriod)
+	rn.UserID = &user.ID
+	rn.ServiceID = &request.ServiceID
+	rn.ReservedOn = now
+	rn.ExpiresOn = &expiresOn
+
+	// The check and the reservation share a transaction holding the names
+	// lock, so a resembling name reserved meanwhile cannot slip past the check.
+	ctx := c.Request.Context()
+	var check nameCheck
+	err := h.Transaction(ctx, func(tx repository.Repositories) error {
+		if err := tx.Names.Lock(ctx); err != nil {
+			return err
+		}
+		var err error
+		check, err = checkName(ctx, tx, request.ReservedName, now)
+		if err != nil {
+			return err
+		}
+		if check.termVerdict == names.Unavailable {
+			return errNameProhibited
+		}
+		if check.nameVerdict == names.Unavailable {
+			return errNameTaken
+		}
+		rn.NeedsReview = check.verdict() == names.Review
+		return tx.Names.Reserve(ctx, rn)
+	})
+	switch {
+	case errors.Is(err, errNameProhibited):
+		response := helpers.NewError("The name uses a prohibited term", helpers.CodeNameProhibited)
+		for _, term := range check.terms {
+			if term.Severity == names.Prohibited {
+				response.Details = append(response.Details, structs.FieldError{Field: "reserved_name", Rule: "prohibited_term", Message: prohibitedMessage(term)})
+			}
+		}
+		helpers.WriteError(c, http.StatusUnprocessableEntity, response)
+		return
+	case errors.Is(err, errNameTaken):
+		response := helpers.NewError("The name is already reserved, or all but", helpers.CodeNameTaken)
+		for _, conflict := range check.conflicts {
+			if conflict.Score >= names.UnavailableScore {
+				response.Details = append(response.Details, structs.FieldError{Field: "reserved_name", Rule: "similar_name", Message: fmt.Sprintf("Resembles the reserved name %q", conflict.ReservedName)})
+			}
+		}
+		helpers.WriteError(c, http.StatusConflict, response)
+		return
+	case err != nil:
+		respondError(c, err)
+		return
+	}
+
+	response := reservedNameToResponse(rn)
+	response.Terms = check.terms
+	c.JSON(http.StatusCreated, helpers.NewSuccess(response, "Name reserved successfully"))
+}
+
+func prohibitedMessage(term TermMatch) string {
+	message := fmt.Sprintf("Uses the prohibited term %q", term.Term)
+	if term.Reason != "" {
+		message += ": " + term.Reason
+	}
+	return message
+}
+
+// RenewReservedNameHandler extends a reservation
+// @Summary      Renew a reservation
+// @Description  Hold a reserved name for another hold period from now. Only the holder or a names manager may renew, and only while the reservation is held and has not lapsed.
+// @Tags         reserved-name
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Reserved Name ID"
+// @Success      200      {object}  map[string]interface{}
+// @Failure      400,403,404,409,500  {object}  structs.ErrorResponse
+// @Router       /reserved-name/{id}/renew [post]
+func (h *Handler) RenewReservedNameHandler(c *gin.Context) {
+	rn, ok := h.loadOwnReservation(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	err := h.Names.Renew(c.Request.Context(), rn.ID, now, now.Add(h.Reservations.HoldPeriod))
+	if !respondReservationError(c, err) {
 		return
 	}
+	h.respondReservation(c, rn.ID, "Reservation renewed successfully")
+}
 
-	c.JSON(http.StatusCreated, helpers.NewSuccess[ReservedNameResponse](ReservedNameResponse{
-		ID:           rn.ID,
-		ReservedName: rn.ReservedName,
-	}, "Reserved name created successfully"))
+// ReleaseReservedNameHandler gives up a reservation
+// @Summary      Release a reservation
+// @Description  Give up a held name so others can reserve it. Only the holder or a names manager may release it.
+// @Tags         reserved-name
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Reserved Name ID"
+// @Success      200      {object}  map[string]interface{}
+// @Failure      400,403,404,409,500  {object}  structs.ErrorResponse
+// @Router       /reserved-name/{id}/release [post]
+func (h *Handler) ReleaseReservedNameHandler(c *gin.Context) {
+	rn, ok := h.loadOwnReservation(c)
+	if !ok {
+		return
+	}
+
+	err := h.Names.Release(c.Request.Context(), rn.ID, time.Now())
+	if !respondReservationError(c, err) {
+		return
+	}
+	h.respondReservation(c, rn.ID, "Reservation released successfully")
+}
+
+// loadOwnReservation loads the reservation named by the id parameter,
+// answering 403 unless the current user holds it or manages reserved names.
+func (h *Handler) loadOwnReservation(c *gin.Context) (*models.ReservedName, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return nil, false
+	}
+
+	rn, err := h.Names.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		respondMissing(c, err, helpers.ErrReservedNameNotFound)
+		return nil, false
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	if (rn.UserID == nil || *rn.UserID != user.ID) && !auth.Can(user.Role, auth.ManageReservedNames) {
+		helpers.WriteError(c, http.StatusForbidden, helpers.NewError("Only the holder can change this reservation", helpers.CodeForbidden))
+		return nil, false
+	}
+	return rn, true
+}
+
+// respondReservationError writes the response for a failed renewal or
+// release and reports whether it succeeded.
+func respondReservationError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, models.ErrReservationNotHeld):
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("The reservation has expired or been released", helpers.CodeReservationNotHeld))
+	default:
+		respondError(c, err)
+	}
+	return false
+}
+
+func (h *Handler) respondReservation(c *gin.Context, id uint, message string) {
+	rn, err := h.Names.Get(c.Request.Context(), id)
+	if err != nil {
+		respondMissing(c, err, helpers.ErrReservedNameNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, helpers.NewSuccess(reservedNameToResponse(rn), message))
 }
 
 // DeleteReservedNameHandler deletes a reserved name
 // @Summary      Delete reserved name
-// @Description  Delete a reserved name by its ID
+// @Description  Delete a reservation by its ID. To free a name and keep the record, release it instead.
 // @Tags         reserved-name
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Reserved Name ID"
 // @Success      200      {object}  map[string]interface{}
-// @Failure      400,500      {object}  structs.ErrorResponse
+// @Failure      400,409,500  {object}  structs.ErrorResponse
 // @Router       /reserved-name/{id} [delete]
-func DeleteReservedNameHandler(c *gin.Context) {
+func (h *Handler) DeleteReservedNameHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	if err := database.DB.Delete(&models.ReservedName{}, id).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.Names.Delete(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"kora_1/internal/helpers"
+	"kora_1/internal/models"
+	"kora_1/internal/names"
+	"kora_1/internal/query"
+	"kora_1/internal/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RestrictedTermRequest struct {
+	Term     string `json:"term" binding:"required,max=100"`
+	Severity string `json:"severity" binding:"required,oneof=prohibited restricted"`
+	Reason   string `json:"reason" binding:"max=255"`
+}
+
+type RestrictedTermResponse struct {
+	ID       uint   `json:"id"`
+	Term     string `json:"term"`
+	Severity string `json:"severity"`
+	Reason   string `json:"reason"`
+}
+
+func restrictedTermToResponse(term *models.RestrictedTerm) RestrictedTermResponse {
+	return RestrictedTermResponse{ID: term.ID, Term: term.Term, Severity: term.Severity, Reason: term.Reason}
+}
+
+// TermMatch is a restricted term a name uses.
+type TermMatch struct {
+	ID       uint           `json:"id"`
+	Term     string         `json:"term"`
+	Severity names.Severity `json:"severity"`
+	Reason   string         `json:"reason,omitempty"`
+}
+
+// checkTerms returns the restricted terms name uses and the verdict they
+// impose on it.
+func checkTerms(ctx context.Context, repos repository.Repositories, name string) ([]TermMatch, names.Verdict, error) {
+	terms, err := repos.Terms.All(ctx)
+	if err != nil {
+		return nil, names.Available, err
+	}
+
+	matches := []TermMatch{}
+	verdict := names.Available
+	for _, term := range terms {
+		if !names.Uses(name, term.Term) {
+			continue
+		}
+		severity := names.Severity(term.Severity)
+		matches = append(matches, TermMatch{ID: term.ID, Term: term.Term, Severity: severity, Reason: term.Reason})
+		verdict = names.Worse(verdict, names.VerdictFor(severity))
+	}
+	return matches, verdict, nil
+}
+
+// GetRestrictedTermHandler retrieves a restricted term by ID
+// @Summary      Get restricted term
+// @Description  Retrieve a prohibited or restricted term by its ID
+// @Tags         restricted-terms
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Restricted Term ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /restricted-terms/{id} [get]
+func (h *Handler) GetRestrictedTermHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	term, err := h.Terms.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		respondMissing(c, err, helpers.ErrRestrictedTermNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess(restrictedTermToResponse(term), "Restricted term retrieved successfully"))
+}
+
+var restrictedTermsSpec = query.Spec{
+	Sorts:       map[string]string{"id": "id", "term": "term", "severity": "severity"},
+	DefaultSort: "id",
+	Filters: map[string]query.Filter{
+		"term":     {Column: "term", Op: query.Contains},
+		"severity": {Column: "severity", Op: query.Equal},
+	},
+}
+
+// ListRestrictedTermsHandler lists restricted terms
+// @Summary      List restricted terms
+// @Description  Retrieve a page of the terms names may not use (prohibited) or may use only with approval (restricted)
+// @Tags         restricted-terms
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit     query     int     false  "Page size (default 50, max 200)"
+// @Param        offset    query     int     false  "Rows to skip"
+// @Param        cursor    query     string  false  "meta.next_cursor of the previous page"
+// @Param        sort      query     string  false  "Comma-separated sort fields, prefix - for descending (id, term, severity)"
+// @Param        term      query     string  false  "Only terms containing this text"
+// @Param        severity  query     string  false  "Only prohibited or only restricted terms"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,500  {object}  structs.ErrorResponse
+// @Router       /restricted-terms [get]
+func (h *Handler) ListRestrictedTermsHandler(c *gin.Context) {
+	params, ok := listParams(c, restrictedTermsSpec)
+	if !ok {
+		return
+	}
+
+	terms, meta, err := h.Terms.List(c.Request.Context(), params)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]RestrictedTermResponse, 0, len(terms))
+	for i := range terms {
+		response = append(response, restrictedTermToResponse(&terms[i]))
+	}
+	c.JSON(http.StatusOK, helpers.NewPage(response, meta, "Restricted terms retrieved successfully"))
+}
+
+// CreateRestrictedTermHandler adds a restricted term
+// @Summary      Create restricted term
+// @Description  Add a term that names may not use (prohibited) or may use only with approval (restricted). Terms match whole words, ignoring case, accents and punctuation, and the last word also matches inflections such as a plural.
+// @Tags         restricted-terms
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      RestrictedTermRequest  true  "Restricted Term Request"
+// @Success      201      {object}  map[string]interface{}
+// @Failure      400,409,500  {object}  structs.ErrorResponse
+// @Router       /restricted-terms [post]
+func (h *Handler) CreateRestrictedTermHandler(c *gin.Context) {
+	var request RestrictedTermRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	term := &models.RestrictedTerm{}
+	if !applyRestrictedTermRequest(c, term, request) {
+		return
+	}
+	if err := h.Terms.Create(c.Request.Context(), term); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, helpers.NewSuccess(restrictedTermToResponse(term), "Restricted term created successfully"))
+}
+
+// UpdateRestrictedTermHandler updates a restricted term
+// @Summary      Update restricted term
+// @Description  Change a restricted term, its severity or its reason
+// @Tags         restricted-terms
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                    true  "Restricted Term ID"
+// @Param        request  body      RestrictedTermRequest  true  "Restricted Term Request"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,409,500  {object}  structs.ErrorResponse
+// @Router       /restricted-terms/{id} [put]
+func (h *Handler) UpdateRestrictedTermHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	var request RestrictedTermRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	term, err := h.Terms.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		respondMissing(c, err, helpers.ErrRestrictedTermNotFound)
+		return
+	}
+
+	if !applyRestrictedTermRequest(c, term, request) {
+		return
+	}
+	if err := h.Terms.Update(c.Request.Context(), term); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess(restrictedTermToResponse(term), "Restricted term updated successfully"))
+}
+
+// applyRestrictedTermRequest copies request onto term, answering 400 when the
+// term has no words to match.
+func applyRestrictedTermRequest(c *gin.Context, term *models.RestrictedTerm, request RestrictedTermRequest) bool {
+	term.Term = request.Term
+	term.Severity = request.Severity
+	term.Reason = request.Reason
+	term.SetNormalizedTerm()
+	if term.NormalizedTerm == "" {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Term must contain letters or digits", helpers.CodeInvalidRequest))
+		return false
+	}
+	return true
+}
+
+// DeleteRestrictedTermHandler deletes a restricted term
+// @Summary      Delete restricted term
+// @Description  Delete a restricted term by its ID. Reservations already flagged for review stay flagged.
+// @Tags         restricted-terms
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Restricted Term ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,500  {object}  structs.ErrorResponse
+// @Router       /restricted-terms/{id} [delete]
+func (h *Handler) DeleteRestrictedTermHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	if err := h.Terms.Delete(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[any](nil, "Restricted term deleted successfully"))
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"kora_1/internal/database"
 	"kora_1/internal/helpers"
 	"kora_1/internal/models"
 	"net/http"
@@ -25,21 +24,22 @@ type ServiceRequest struct {
 // @Tags         services
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Service ID"
 // @Success      200  {object}  map[string]interface{}
 // @Failure      400,404  {object}  structs.ErrorResponse
 // @Router       /services/{id} [get]
-func GetServiceHandler(c *gin.Context) {
+func (h *Handler) GetServiceHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	service, err := models.GetServiceByID(database.DB, uint(id))
+	service, err := h.Services.Get(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Service not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrServiceNotFound)
 		return
 	}
 
@@ -49,23 +49,36 @@ func GetServiceHandler(c *gin.Context) {
 	}, "Service retrieved successfully"))
 }
 
+var servicesSpec = byName("service_name")
+
 // ListServicesHandler retrieves all services
 // @Summary      Get all services
 // @Description  Retrieve all services
 // @Tags         services
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Param        limit   query     int     false  "Page size (default 50, max 200)"
+// @Param        offset  query     int     false  "Rows to skip"
+// @Param        cursor  query     string  false  "meta.next_cursor of the previous page"
+// @Param        sort    query     string  false  "Comma-separated sort fields, prefix - for descending (id, name)"
+// @Param        name    query     string  false  "Only names containing this text"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      500  {object}  structs.ErrorResponse
+// @Failure      400,500  {object}  structs.ErrorResponse
 // @Router       /services [get]
-func ListServicesHandler(c *gin.Context) {
-	services, err := models.ListAllServices(database.DB)
+func (h *Handler) ListServicesHandler(c *gin.Context) {
+	params, ok := listParams(c, servicesSpec)
+	if !ok {
+		return
+	}
+
+	services, meta, err := h.Services.List(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError("Failed to retrieve services", http.StatusInternalServerError))
+		respondError(c, err)
 		return
 	}
 
-	var response []ServiceResponse
+	response := make([]ServiceResponse, 0, len(services))
 	for _, s := range services {
 		response = append(response, ServiceResponse{
 			ID:          s.ID,
@@ -73,7 +86,7 @@ func ListServicesHandler(c *gin.Context) {
 		})
 	}
 
-	c.JSON(http.StatusOK, helpers.NewSuccess[[]ServiceResponse](response, "Services retrieved successfully"))
+	c.JSON(http.StatusOK, helpers.NewPage(response, meta, "Services retrieved successfully"))
 }
 
 // AddServiceHandler creates a new service
@@ -82,20 +95,21 @@ func ListServicesHandler(c *gin.Context) {
 // @Tags         services
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        request  body      ServiceRequest  true  "Service Request"
 // @Success      201  {object}  map[string]interface{}
 // @Failure      400,500  {object}  structs.ErrorResponse
 // @Router       /services [post]
-func AddServiceHandler(c *gin.Context) {
+func (h *Handler) AddServiceHandler(c *gin.Context) {
 	var request ServiceRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
-	service, err := models.CreateService(database.DB, request.ServiceName)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	service := &models.Service{ServiceName: request.ServiceName}
+	if err := h.Services.Create(c.Request.Context(), service); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -111,34 +125,35 @@ func AddServiceHandler(c *gin.Context) {
 // @Tags         services
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id       path      int             true  "Service ID"
 // @Param        request  body      ServiceRequest  true  "Service Request"
 // @Success      200  {object}  map[string]interface{}
 // @Failure      400,404,500  {object}  structs.ErrorResponse
 // @Router       /services/{id} [put]
-func UpdateServiceHandler(c *gin.Context) {
+func (h *Handler) UpdateServiceHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
 	var request ServiceRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
-	service, err := models.GetServiceByID(database.DB, uint(id))
+	service, err := h.Services.Get(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Service not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrServiceNotFound)
 		return
 	}
 
 	service.ServiceName = request.ServiceName
-	if err := models.UpdateService(database.DB, service); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError("Failed to update service", http.StatusInternalServerError))
+	if err := h.Services.Update(c.Request.Context(), service); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -154,20 +169,21 @@ func UpdateServiceHandler(c *gin.Context) {
 // @Tags         services
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Service ID"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      400,500  {object}  structs.ErrorResponse
+// @Failure      400,409,500  {object}  structs.ErrorResponse
 // @Router       /services/{id} [delete]
-func DeleteServiceHandler(c *gin.Context) {
+func (h *Handler) DeleteServiceHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	if err := models.DeleteService(database.DB, uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError("Failed to delete service", http.StatusInternalServerError))
+	if err := h.Services.Delete(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kora_1/internal/datatypes"
+	"kora_1/internal/helpers"
+	"kora_1/internal/models"
+	"kora_1/internal/repository"
+	"kora_1/internal/structs"
+	"kora_1/internal/validation"
+	"strconv"
+	"strings"
+)
+
+var (
+	errNoPublishedVersion   = errors.New("form has no published version")
+	errVersionNotPublished  = errors.New("answers reference a form version that is not published")
+	errSubmissionFormNeeded = errors.New("form_id is required when no answers are given")
+	errServiceMismatch      = errors.New("services_id does not match the form's service")
+)
+
+// submissionService returns the service a submission against version is filed
+// under, which is always its form's; a services_id given in the request must
+// name that service.
+func submissionService(ctx context.Context, repos repository.Repositories, version *models.FormVersion, requested *uint) (*uint, error) {
+	form, err := repos.Forms.Get(ctx, version.FormID)
+	if err != nil {
+		return nil, err
+	}
+	if requested != nil && (form.ServiceID == nil || *form.ServiceID != *requested) {
+		return nil, errServiceMismatch
+	}
+	return form.ServiceID, nil
+}
+
+// resolveFormVersion picks the published form version a submission is filled
+// against: the version of the first answered field, so applicants who loaded an
+// older published layout are recorded against it, or else the form's latest
+// published version.
+func resolveFormVersion(ctx context.Context, repos repository.Repositories, formID *uint, answers []AnswerRequest) (*models.FormVersion, error) {
+	for _, ans := range answers {
+		ff, err := repos.Forms.FormField(ctx, ans.FormFieldID)
+		if helpers.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ff.FormVersionID == nil || (formID != nil && ff.FormID != *formID) {
+			continue
+		}
+
+		version, err := repos.Forms.VersionByID(ctx, *ff.FormVersionID)
+		if err != nil {
+			return nil, err
+		}
+		if !version.IsPublished() {
+			return nil, errVersionNotPublished
+		}
+		return version, nil
+	}
+
+	if formID == nil {
+		if len(answers) == 0 {
+			return nil, errSubmissionFormNeeded
+		}
+		return nil, nil
+	}
+
+	version, err := repos.Forms.PublishedVersion(ctx, *formID)
+	if helpers.IsNotFound(err) {
+		return nil, errNoPublishedVersion
+	}
+	return version, err
+}
+
+// answerCheck says how strictly checkAnswers treats a set of answers.
+type answerCheck struct {
+	final        bool // Enforce required fields, as on final submit; drafts skip them
+	uploaderID   uint // File answers must reference uploads made by this user
+	submissionID uint // The submission being edited, zero for a new one
+}
+
+// checkedAnswers is the outcome of a successful checkAnswers.
+type checkedAnswers struct {
+	values      map[uint]string // Canonical answer text keyed by form field ID
+	attachments []uint          // Attachments referenced by file answers
+}
+
+// checkAnswers coerces each answer to its field's data type and evaluates the
+// Validation rules of every field in the form version. It returns the canonical
+// answers together with the failing fields, or an error when the rules could
+// not be evaluated. A nil version means no answer matched a known field.
+func checkAnswers(ctx context.Context, repos repository.Repositories, version *models.FormVersion, answers []AnswerRequest, check answerCheck) (checkedAnswers, []structs.FieldError, error) {
+	var failures []structs.FieldError
+
+	ids := make([]uint, 0, len(answers))
+	for _, ans := range answers {
+		ids = append(ids, ans.FormFieldID)
+	}
+
+	answered, err := repos.Forms.FormFields(ctx, ids)
+	if err != nil {
+		return checkedAnswers{}, nil, err
+	}
+	known := make(map[uint]models.FormFields, len(answered))
+	for _, ff := range answered {
+		known[ff.ID] = ff
+	}
+
+	values := make(map[uint]string, len(answers))
+	for _, ans := range answers {
+		ff, ok := known[ans.FormFieldID]
+		if !ok {
+			failures = append(failures, structs.FieldError{FormFieldID: ans.FormFieldID, Rule: "form_field", Message: "Unknown form field"})
+			continue
+		}
+		if version == nil || ff.FormVersionID == nil || *ff.FormVersionID != version.ID {
+			failures = append(failures, structs.FieldError{FormFieldID: ans.FormFieldID, Rule: "form_field", Message: "Field does not belong to this form version"})
+			continue
+		}
+		if _, dup := values[ans.FormFieldID]; dup {
+			failures = append(failures, structs.FieldError{FormFieldID: ans.FormFieldID, Rule: "form_field", Message: "Field answered more than once"})
+			continue
+		}
+		values[ans.FormFieldID] = ans.Answer
+	}
+
+	checked := checkedAnswers{values: values}
+	if version == nil {
+		return checked, failures, nil
+	}
+
+	fields, err := repos.Forms.VersionFields(ctx, version.ID)
+	if err != nil {
+		return checkedAnswers{}, nil, err
+	}
+
+	inCollection := func(collectionID uint, value string) (bool, error) {
+		item, err := collectionItem(ctx, repos, value)
+		if item == nil || err != nil {
+			return false, err
+		}
+		return item.CollectionID != nil && *item.CollectionID == collectionID, nil
+	}
+
+	// typed holds the fields whose non-blank answer passed its data type checks;
+	// only those take part in the dependency checks below.
+	typed := make(map[uint]bool, len(values))
+	for _, ff := range fields {
+		value, answeredField := values[ff.ID]
+		if answeredField && strings.TrimSpace(value) != "" {
+			dt := datatypes.For(ff.Field.DataType.DataType)
+			canonical, err := dt.Normalize(value)
+			if err != nil {
+				failures = append(failures, structs.FieldError{FormFieldID: ff.ID, Rule: "type", Message: err.Error()})
+				continue
+			}
+			if dt.Name() == datatypes.Collection && ff.Field.CollectionID != nil {
+				ok, err := inCollection(*ff.Field.CollectionID, canonical)
+				if err != nil {
+					return checkedAnswers{}, nil, err
+				}
+				if !ok {
+					failures = append(failures, structs.FieldError{FormFieldID: ff.ID, Rule: "type", Message: "Must be one of the available options"})
+					continue
+				}
+			}
+			if dt.Name() == datatypes.File {
+				attachmentID, _ := strconv.ParseUint(canonical, 10, 32)
+				ok, err := repos.Submissions.AttachmentUsable(ctx, uint(attachmentID), ff.ID, check.uploaderID, check.submissionID)
+				if err != nil {
+					return checkedAnswers{}, nil, err
+				}
+				if !ok {
+					failures = append(failures, structs.FieldError{FormFieldID: ff.ID, Rule: "type", Message: "Must be a file you uploaded for this field"})
+					continue
+				}
+				checked.attachments = append(checked.attachments, uint(attachmentID))
+			}
+			value = canonical
+			values[ff.ID] = canonical
+			typed[ff.ID] = true
+		}
+
+		rules, err := validation.Parse(ff.Validation)
+		if err != nil {
+			return checkedAnswers{}, nil, fmt.Errorf("form field %d has invalid validation rules: %w", ff.ID, err)
+		}
+		if !check.final {
+			rules = rules.Without(validation.Required)
+		}
+		// A dependent field has no options until the field it depends on is
+		// answered, so it is only required once that answer is given.
+		if ff.DependsOnFormFieldID != nil && strings.TrimSpace(values[*ff.DependsOnFormFieldID]) == "" {
+			rules = rules.Without(validation.Required)
+		}
+
+		fieldFailures, err := rules.Validate(value, validation.Env{
+			CollectionID: ff.Field.CollectionID,
+			InCollection: inCollection,
+		})
+		if err != nil {
+			return checkedAnswers{}, nil, fmt.Errorf("form field %d: %w", ff.ID, err)
+		}
+		for _, f := range fieldFailures {
+			failures = append(failures, structs.FieldError{FormFieldID: ff.ID, Rule: f.Rule, Message: f.Message})
+		}
+	}
+
+	dependencyFailures, err := checkDependencies(ctx, repos, fields, values, typed, check)
+	if err != nil {
+		return checkedAnswers{}, nil, err
+	}
+	failures = append(failures, dependencyFailures...)
+
+	return checked, failures, nil
+}
+
+// checkDependencies makes sure each answered dependent field holds a child of
+// the item chosen for the field it depends on. A missing parent answer only
+// fails a final check; drafts may be filled in any order.
+func checkDependencies(ctx context.Context, repos repository.Repositories, fields []models.FormFields, values map[uint]string, typed map[uint]bool, check answerCheck) ([]structs.FieldError, error) {
+	var failures []structs.FieldError
+	for _, ff := range fields {
+		if ff.DependsOnFormFieldID == nil || !typed[ff.ID] {
+			continue
+		}
+		parentID := *ff.DependsOnFormFieldID
+		parentValue := values[parentID]
+		if strings.TrimSpace(parentValue) == "" {
+			if check.final {
+				failures = append(failures, structs.FieldError{FormFieldID: ff.ID, Rule: "depends_on", Message: "Answer the field this one depends on first"})
+			}
+			continue
+		}
+		if !typed[parentID] {
+			// The parent answer already failed its own checks.
+			continue
+		}
+
+		parentItemID, err := strconv.ParseUint(parentValue, 10, 32)
+		if err != nil {
+			continue
+		}
+		item, err := collectionItem(ctx, repos, values[ff.ID])
+		if err != nil {
+			return nil, err
+		}
+		if item == nil || item.RelationCollectionItemsID == nil || *item.RelationCollectionItemsID != uint(parentItemID) {
+			failures = append(failures, structs.FieldError{FormFieldID: ff.ID, Rule: "depends_on", Message: "Must belong to the option chosen for the field it depends on"})
+		}
+	}
+	return failures, nil
+}
+
+// collectionItem returns the collection item whose ID is value, or nil when
+// value is not an item ID.
+func collectionItem(ctx context.Context, repos repository.Repositories, value string) (*models.CollectionItem, error) {
+	itemID, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil, nil
+	}
+	item, err := repos.Collections.GetItem(ctx, uint(itemID))
+	if helpers.IsNotFound(err) {
+		return nil, nil
+	}
+	return item, err
+}
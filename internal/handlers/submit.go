@@ -1,66 +1,264 @@
 package handlers
 
 import (
-	"kora_1/internal/database"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kora_1/internal/auth"
+	"kora_1/internal/datatypes"
 	"kora_1/internal/helpers"
+	"kora_1/internal/middleware"
 	"kora_1/internal/models"
+	"kora_1/internal/notify"
+	"kora_1/internal/query"
+	"kora_1/internal/repository"
+	"kora_1/internal/webhooks"
+	"kora_1/internal/workflow"
+	"maps"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	idempotencyKeyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLength = 100
+)
+
 type SubmitFormRequest struct {
-	ServicesID *uint               `json:"services_id"`
-	CreatedBy  *uint               `json:"created_by"`
-	Answers    []models.FormAnswer `json:"answers" binding:"required"`
+	FormID     *uint           `json:"form_id"`
+	ServicesID *uint           `json:"services_id"` // Optional; defaults to, and must match, the form's service
+	CreatedBy  *uint           `json:"created_by"`  // Admins only; everyone else submits as themselves
+	Answers    []AnswerRequest `json:"answers" binding:"required,dive"`
+}
+
+type AnswerRequest struct {
+	FormFieldID uint   `json:"form_field_id" binding:"required"`
+	Answer      string `json:"answer"`
 }
 
 type SubmissionResponse struct {
-	ID         uint                `json:"id"`
-	ServicesID *uint               `json:"services_id"`
-	CreatedBy  *uint               `json:"created_by"`
-	CreatedOn  string              `json:"created_on"`
-	Answers    []models.FormAnswer `json:"answers"`
+	ID            uint                       `json:"id"`
+	ServicesID    *uint                      `json:"services_id"`
+	FormID        *uint                      `json:"form_id"`
+	FormVersionID *uint                      `json:"form_version_id"`
+	FormVersion   *int                       `json:"form_version"` // Render answers with GET /form/{form_id}?version={form_version}
+	CreatedBy     *uint                      `json:"created_by"`
+	CreatedOn     string                     `json:"created_on"`
+	UpdatedOn     string                     `json:"updated_on"`
+	Status        string                     `json:"status"`
+	Answers       []SubmissionAnswerResponse `json:"answers"`
+}
+
+// SubmissionAnswerResponse carries the stored answer text and its value typed by the field's data type
+type SubmissionAnswerResponse struct {
+	ID          uint   `json:"id"`
+	FormFieldID *uint  `json:"form_field_id"`
+	DataType    string `json:"data_type"`
+	Answer      string `json:"answer"`
+	Value       any    `json:"value"`
 }
 
 // SubmitFormHandler creates a new form submission
 // @Summary      Submit a form
-// @Description  Create a new form submission
+// @Description  Create a new form submission against a published form version. The submission and its answers are saved in a single transaction.
+// @Description  Send an Idempotency-Key header to make retries safe: a repeated key returns the original submission with 200, and reusing it for a different request is refused with 422.
 // @Tags         submissions
 // @Accept       json
 // @Produce      json
-// @Param        request  body      SubmitFormRequest  true  "Submission Request"
-// @Success      201      {object}  map[string]interface{}
-// @Failure      400,500  {object}  structs.ErrorResponse
+// @Security     BearerAuth
+// @Param        Idempotency-Key  header    string             false  "Client-generated key identifying this submit attempt"
+// @Param        request          body      SubmitFormRequest  true   "Submission Request"
+// @Success      200,201          {object}  map[string]interface{}
+// @Failure      400,500          {object}  structs.ErrorResponse
+// @Failure      409              {object}  structs.ErrorResponse  "The form has no published version, the service's workflow does not let applicants submit, or the Idempotency-Key belongs to another user"
+// @Failure      422              {object}  structs.ErrorResponse  "Answers failed type coercion or validation, services_id is not the form's service, or the Idempotency-Key was sent with a different request"
 // @Router       /submission [post]
-func SubmitFormHandler(c *gin.Context) {
+func (h *Handler) SubmitFormHandler(c *gin.Context) {
+	user, _ := middleware.CurrentUser(c)
+
+	idempotencyKey := strings.TrimSpace(c.GetHeader(idempotencyKeyHeader))
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Idempotency-Key must be at most 100 characters", helpers.CodeInvalidRequest))
+		return
+	}
+
 	var request SubmitFormRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
+	fingerprint := requestFingerprint(request)
 
-	submission := &models.Submission{
-		ServicesID: request.ServicesID,
-		CreatedBy:  request.CreatedBy,
+	ctx := c.Request.Context()
+	if idempotencyKey != "" {
+		existing, err := h.Submissions.ByIdempotencyKey(ctx, idempotencyKey)
+		if err == nil {
+			h.replaySubmission(c, user, existing, fingerprint)
+			return
+		}
+		if !helpers.IsNotFound(err) {
+			respondError(c, err)
+			return
+		}
 	}
 
-	if err := models.CreateSubmission(database.DB, submission); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	version, err := resolveFormVersion(ctx, h.Repositories, request.FormID, request.Answers)
+	switch {
+	case errors.Is(err, errSubmissionFormNeeded):
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("form_id is required when no answers are given", helpers.CodeInvalidRequest))
+		return
+	case errors.Is(err, errNoPublishedVersion):
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Form has no published version", helpers.CodeNoPublishedVersion))
+		return
+	case errors.Is(err, errVersionNotPublished):
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Answers reference a form version that is not published", helpers.CodeVersionNotPublished))
+		return
+	case err != nil:
+		respondError(c, err)
 		return
 	}
 
-	// Create answers
-	for _, ans := range request.Answers {
-		ans.SubmissionID = &submission.ID // Link to created submission
-		if err := models.CreateFormAnswer(database.DB, &ans); err != nil {
-			c.JSON(http.StatusInternalServerError, helpers.NewError("Failed to save answers: "+err.Error(), http.StatusInternalServerError))
-			return
+	checked, failures, err := checkAnswers(ctx, h.Repositories, version, request.Answers, answerCheck{final: true, uploaderID: user.ID})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if len(failures) > 0 {
+		helpers.WriteError(c, http.StatusUnprocessableEntity, helpers.NewValidationError(failures))
+		return
+	}
+
+	serviceID, err := submissionService(ctx, h.Repositories, version, request.ServicesID)
+	if errors.Is(err, errServiceMismatch) {
+		helpers.WriteError(c, http.StatusUnprocessableEntity, helpers.NewError("services_id does not match the form's service", helpers.CodeInvalidReference))
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Submissions are filed as the caller; only admins may file on someone else's behalf.
+	createdBy := &user.ID
+	if request.CreatedBy != nil && auth.Can(user.Role, auth.ManageSubmissions) {
+		createdBy = request.CreatedBy
+	}
+
+	submission := &models.Submission{
+		ServicesID:    serviceID,
+		FormVersionID: &version.ID,
+		CreatedBy:     createdBy,
+	}
+	if idempotencyKey != "" {
+		submission.IdempotencyKey = &idempotencyKey
+		submission.IdempotencyFingerprint = &fingerprint
+	}
+
+	err = h.Transaction(ctx, func(tx repository.Repositories) error {
+		// A new submission takes the edge its service's lifecycle gives
+		// the applicant out of draft.
+		machine, err := serviceWorkflow(ctx, tx, submission.ServicesID)
+		if err != nil {
+			return err
+		}
+		if submission.Status, err = machine.Submit(workflow.Draft); err != nil {
+			return err
+		}
+
+		if err := tx.Submissions.Create(ctx, submission); err != nil {
+			return err
+		}
+
+		// Create answers
+		for _, ans := range request.Answers {
+			answer := &models.FormAnswer{
+				FormFieldID:  &ans.FormFieldID,
+				Answer:       checked.values[ans.FormFieldID],
+				SubmissionID: &submission.ID, // Link to created submission
+			}
+			if err := tx.Submissions.CreateAnswer(ctx, answer); err != nil {
+				return fmt.Errorf("failed to save answers: %w", err)
+			}
+		}
+		if err := tx.Submissions.LinkAttachments(ctx, submission.ID, checked.attachments); err != nil {
+			return err
 		}
+
+		if err := tx.Submissions.AddHistory(ctx, &models.SubmissionStatusHistory{
+			SubmissionID: submission.ID,
+			ToStatus:     submission.Status,
+			ChangedBy:    &user.ID,
+		}); err != nil {
+			return err
+		}
+
+		if err := webhooks.Enqueue(ctx, tx.Webhooks, webhooks.SubmissionCreated, submission.ServicesID, webhooks.SubmissionData{
+			SubmissionID:  submission.ID,
+			ServiceID:     submission.ServicesID,
+			FormVersionID: submission.FormVersionID,
+			Status:        submission.Status,
+			CreatedBy:     submission.CreatedBy,
+		}); err != nil {
+			return err
+		}
+		return notify.SubmissionChanged(ctx, tx.Notifications, submission, version.FormName, "", "", user.ID)
+	})
+	if err != nil {
+		// A concurrent retry with the same key may have committed first.
+		if idempotencyKey != "" {
+			if existing, lookupErr := h.Submissions.ByIdempotencyKey(ctx, idempotencyKey); lookupErr == nil {
+				h.replaySubmission(c, user, existing, fingerprint)
+				return
+			}
+		}
+		respondTransitionError(c, err)
+		return
+	}
+
+	answers, err := h.submissionAnswers(ctx, submission.ID)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusCreated, helpers.NewSuccess[models.Submission](*submission, "Form submitted successfully"))
+	submission.FormVersion = version
+	c.JSON(http.StatusCreated, helpers.NewSuccess[SubmissionResponse](submissionToResponse(submission, answers), "Form submitted successfully"))
+}
+
+// requestFingerprint hashes a submit request, so a retry can be told apart from another request sent with the same Idempotency-Key.
+func requestFingerprint(request SubmitFormRequest) string {
+	body, _ := json.Marshal(request)
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// replaySubmission answers a retried submit with the submission its Idempotency-Key already created,
+// provided the retry is the request that created it.
+func (h *Handler) replaySubmission(c *gin.Context, user *models.User, submission *models.Submission, fingerprint string) {
+	if ok, err := h.canAccessSubmission(c.Request.Context(), user, submission); err != nil || !ok {
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Idempotency-Key has already been used", helpers.CodeIdempotencyKeyReused))
+		return
+	}
+	if submission.IdempotencyFingerprint != nil && *submission.IdempotencyFingerprint != fingerprint {
+		helpers.WriteError(c, http.StatusUnprocessableEntity, helpers.NewError("Idempotency-Key has already been used with a different request", helpers.CodeIdempotencyKeyReused))
+		return
+	}
+
+	answers, err := h.submissionAnswers(c.Request.Context(), submission.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	c.JSON(http.StatusOK, helpers.NewSuccess[SubmissionResponse](submissionToResponse(submission, answers), "Form already submitted"))
 }
 
 // GetSubmissionHandler retrieves a submission by ID
@@ -69,55 +267,263 @@ func SubmitFormHandler(c *gin.Context) {
 // @Tags         submissions
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "Submission ID"
 // @Success      200  {object}  map[string]interface{}
 // @Failure      400,404,500  {object}  structs.ErrorResponse
 // @Router       /submission/{id} [get]
-func GetSubmissionHandler(c *gin.Context) {
+func (h *Handler) GetSubmissionHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	submission, err := models.GetSubmission(database.DB, uint(id))
+	user, _ := middleware.CurrentUser(c)
+	submission, ok := h.loadAccessibleSubmission(c, user, uint(id))
+	if !ok {
+		return
+	}
+
+	answers, err := h.submissionAnswers(c.Request.Context(), submission.ID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("Submission not found", http.StatusNotFound))
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, helpers.NewSuccess(submission, "Submission retrieved successfully"))
+	c.JSON(http.StatusOK, helpers.NewSuccess[SubmissionResponse](submissionToResponse(submission, answers), "Submission retrieved successfully"))
+}
+
+var submissionsSpec = query.Spec{
+	Sorts: map[string]string{
+		"id":         "id",
+		"created_on": "created_on",
+		"updated_on": "updated_on",
+		"status":     "status",
+	},
+	DefaultSort: "-created_on",
+	Filters: map[string]query.Filter{
+		"status":          {Column: "status", Op: query.Equal},
+		"created_by":      {Column: "created_by", Op: query.Equal, Kind: query.Int},
+		"form_version_id": {Column: "form_version_id", Op: query.Equal, Kind: query.Int},
+		"created_from":    {Column: "created_on", Op: query.From, Kind: query.Time},
+		"created_to":      {Column: "created_on", Op: query.To, Kind: query.Time},
+		"updated_from":    {Column: "updated_on", Op: query.From, Kind: query.Time},
+		"updated_to":      {Column: "updated_on", Op: query.To, Kind: query.Time},
+	},
 }
 
 // GetSubmissionsByFormIDHandler retrieves all submissions by service ID (formerly by form ID)
 // @Summary      Get submissions by Service ID
-// @Description  Retrieve all submissions for a specific service by its Service ID
+// @Description  Retrieve a page of submissions for a specific service by its Service ID, newest first. Applicants only see their own submissions.
 // @Tags         submissions
 // @Accept       json
 // @Produce      json
-// @Param        service_id   path      int  true  "Service ID"
+// @Security     BearerAuth
+// @Param        service_id       path      int     true   "Service ID"
+// @Param        limit            query     int     false  "Page size (default 50, max 200)"
+// @Param        offset           query     int     false  "Rows to skip"
+// @Param        cursor           query     string  false  "meta.next_cursor of the previous page"
+// @Param        sort             query     string  false  "Comma-separated sort fields, prefix - for descending (id, created_on, updated_on, status)"
+// @Param        status           query     string  false  "Comma-separated statuses"
+// @Param        created_by       query     string  false  "Comma-separated user IDs"
+// @Param        form_version_id  query     string  false  "Comma-separated form version IDs"
+// @Param        created_from     query     string  false  "Created on or after, YYYY-MM-DD or RFC 3339"
+// @Param        created_to       query     string  false  "Created on or before, YYYY-MM-DD or RFC 3339"
+// @Param        updated_from     query     string  false  "Updated on or after, YYYY-MM-DD or RFC 3339"
+// @Param        updated_to       query     string  false  "Updated on or before, YYYY-MM-DD or RFC 3339"
 // @Success      200       {object}  map[string]interface{}
 // @Failure      400,500   {object}  structs.ErrorResponse
+// @Failure      403       {object}  structs.ErrorResponse  "Reviewer is not assigned to the service"
 // @Router       /submission/service/{service_id} [get]
-func GetSubmissionsByFormIDHandler(c *gin.Context) {
+func (h *Handler) GetSubmissionsByFormIDHandler(c *gin.Context) {
 	serviceIDStr := c.Param("service_id")
 	if serviceIDStr == "" {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Service ID parameter is required", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Service ID parameter is required", helpers.CodeInvalidRequest))
 		return
 	}
 
 	serviceID, err := strconv.ParseUint(serviceIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	ok, err := h.canAccessService(c.Request.Context(), user, uint(serviceID))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if !ok {
+		helpers.WriteError(c, http.StatusForbidden, helpers.NewError("You are not assigned to this service", helpers.CodeForbidden))
+		return
+	}
+
+	params, ok := listParams(c, submissionsSpec)
+	if !ok {
+		return
+	}
+
+	scope := submissionScope(user)
+	service := uint(serviceID)
+	scope.ServiceID = &service
+	h.respondWithSubmissions(c, scope, params)
+}
+
+// submissionsAcrossServicesSpec adds a service filter to submissionsSpec.
+var submissionsAcrossServicesSpec = func() query.Spec {
+	spec := submissionsSpec
+	spec.Filters = maps.Clone(submissionsSpec.Filters)
+	spec.Filters["service_id"] = query.Filter{Column: "services_id", Op: query.Equal, Kind: query.Int}
+	return spec
+}()
+
+// ListSubmissionsHandler lists the submissions the caller may read
+// @Summary      List submissions
+// @Description  Retrieve a page of submissions across services, newest first. Admins see all, reviewers those of their assigned services and applicants their own.
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit            query     int     false  "Page size (default 50, max 200)"
+// @Param        offset           query     int     false  "Rows to skip"
+// @Param        cursor           query     string  false  "meta.next_cursor of the previous page"
+// @Param        sort             query     string  false  "Comma-separated sort fields, prefix - for descending (id, created_on, updated_on, status)"
+// @Param        service_id       query     string  false  "Comma-separated service IDs"
+// @Param        status           query     string  false  "Comma-separated statuses"
+// @Param        created_by       query     string  false  "Comma-separated user IDs"
+// @Param        form_version_id  query     string  false  "Comma-separated form version IDs"
+// @Param        created_from     query     string  false  "Created on or after, YYYY-MM-DD or RFC 3339"
+// @Param        created_to       query     string  false  "Created on or before, YYYY-MM-DD or RFC 3339"
+// @Param        updated_from     query     string  false  "Updated on or after, YYYY-MM-DD or RFC 3339"
+// @Param        updated_to       query     string  false  "Updated on or before, YYYY-MM-DD or RFC 3339"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,500  {object}  structs.ErrorResponse
+// @Router       /submission [get]
+func (h *Handler) ListSubmissionsHandler(c *gin.Context) {
+	params, ok := listParams(c, submissionsAcrossServicesSpec)
+	if !ok {
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	h.respondWithSubmissions(c, submissionScope(user), params)
+}
+
+// respondWithSubmissions answers with a page of the submissions in scope,
+// each with its form version and typed answers.
+func (h *Handler) respondWithSubmissions(c *gin.Context, scope repository.SubmissionScope, params query.Params) {
+	submissions, meta, err := h.Submissions.List(c.Request.Context(), scope, params)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ids := make([]uint, len(submissions))
+	for i, s := range submissions {
+		ids[i] = s.ID
+	}
+	answers, err := h.Submissions.Answers(c.Request.Context(), ids)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]SubmissionResponse, 0, len(submissions))
+	for i := range submissions {
+		response = append(response, submissionToResponse(&submissions[i], answers[submissions[i].ID]))
+	}
+	c.JSON(http.StatusOK, helpers.NewPage(response, meta, "Submissions retrieved successfully"))
+}
+
+// submissionAnswers loads the typed answers of one submission.
+func (h *Handler) submissionAnswers(ctx context.Context, submissionID uint) ([]models.FormAnswer, error) {
+	answers, err := h.Submissions.Answers(ctx, []uint{submissionID})
+	return answers[submissionID], err
+}
+
+// ListSubmissionAnswersHandler lists the answers of a submission
+// @Summary      List submission answers
+// @Description  Retrieve the typed answers of a submission the caller may read
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Submission ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /submission/{id}/answers [get]
+func (h *Handler) ListSubmissionAnswersHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	submission, ok := h.loadAccessibleSubmission(c, user, uint(id))
+	if !ok {
 		return
 	}
 
-	var submissions []models.Submission
-	if err := database.DB.Preload("Answers").Where("services_id = ?", serviceID).Find(&submissions).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	answers, err := h.submissionAnswers(c.Request.Context(), submission.ID)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, helpers.NewSuccess[[]models.Submission](submissions, "Submissions retrieved successfully"))
+	response := make([]SubmissionAnswerResponse, 0, len(answers))
+	for _, ans := range answers {
+		response = append(response, answerToResponse(ans))
+	}
+	c.JSON(http.StatusOK, helpers.NewSuccess(response, "Answers retrieved successfully"))
+}
+
+func submissionToResponse(submission *models.Submission, answers []models.FormAnswer) SubmissionResponse {
+	response := SubmissionResponse{
+		ID:            submission.ID,
+		ServicesID:    submission.ServicesID,
+		FormVersionID: submission.FormVersionID,
+		CreatedBy:     submission.CreatedBy,
+		CreatedOn:     submission.CreatedOn.Format(time.RFC3339),
+		UpdatedOn:     submission.UpdatedOn.Format(time.RFC3339),
+		Status:        submission.Status,
+		Answers:       make([]SubmissionAnswerResponse, 0, len(answers)),
+	}
+	if submission.FormVersion != nil {
+		response.FormID = &submission.FormVersion.FormID
+		response.FormVersion = &submission.FormVersion.Version
+	}
+	for _, ans := range answers {
+		response.Answers = append(response.Answers, answerToResponse(ans))
+	}
+	return response
+}
+
+// answerToResponse decodes the stored answer by its field's data type. Answers
+// that predate type coercion and no longer decode are returned as text.
+func answerToResponse(ans models.FormAnswer) SubmissionAnswerResponse {
+	dt := datatypes.For("")
+	if ans.FormField != nil {
+		dt = datatypes.For(ans.FormField.Field.DataType.DataType)
+	}
+
+	var value any
+	if ans.Answer != "" {
+		decoded, err := dt.Decode(ans.Answer)
+		if err != nil {
+			decoded = ans.Answer
+		}
+		value = decoded
+	}
+
+	return SubmissionAnswerResponse{
+		ID:          ans.ID,
+		FormFieldID: ans.FormFieldID,
+		DataType:    dt.Name(),
+		Answer:      ans.Answer,
+		Value:       value,
+	}
 }
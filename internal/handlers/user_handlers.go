@@ -1,9 +1,12 @@
 package handlers
 
 import (
-	"kora_1/internal/database"
+	"kora_1/internal/auth"
 	"kora_1/internal/helpers"
+	"kora_1/internal/middleware"
 	"kora_1/internal/models"
+	"kora_1/internal/query"
+	"kora_1/internal/structs"
 	"net/http"
 	"strconv"
 	"time"
@@ -27,22 +30,23 @@ type UserResponse struct {
 	Surname    string `json:"surname"`
 	Dob        string `json:"dob"`
 	Email      string `json:"email"`
+	Role       string `json:"role"`
 }
 
 // CreateUserHandler creates a new user
 // @Summary      Create user
-// @Description  Create a new user
+// @Description  Register a new user. New users always start with the applicant role.
 // @Tags         users
 // @Accept       json
 // @Produce      json
 // @Param        request  body      UserRequest  true  "User Request"
 // @Success      201      {object}  map[string]interface{}
-// @Failure      400,500  {object}  structs.ErrorResponse
+// @Failure      400,409,500  {object}  structs.ErrorResponse
 // @Router       /users [post]
-func CreateUserHandler(c *gin.Context) {
+func (h *Handler) CreateUserHandler(c *gin.Context) {
 	var request UserRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
@@ -51,7 +55,9 @@ func CreateUserHandler(c *gin.Context) {
 	if request.Dob != "" {
 		dob, err = time.Parse("2006-01-02", request.Dob)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, helpers.NewError("Invalid Dob format. Use YYYY-MM-DD", http.StatusBadRequest))
+			helpers.WriteError(c, http.StatusBadRequest, helpers.NewValidationError([]structs.FieldError{
+				{Field: "dob", Rule: "date", Message: "Must be a date in YYYY-MM-DD format"},
+			}))
 			return
 		}
 	}
@@ -62,11 +68,18 @@ func CreateUserHandler(c *gin.Context) {
 		Surname:    request.Surname,
 		Dob:        dob,
 		Email:      request.Email,
-		Password:   request.Password,
+		Role:       models.RoleApplicant,
+	}
+	if request.Password != "" {
+		user.Password, err = auth.HashPassword(request.Password)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
 	}
 
-	if err := models.CreateUser(database.DB, user); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.Users.Create(c.Request.Context(), user); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -79,56 +92,119 @@ func CreateUserHandler(c *gin.Context) {
 // @Tags         users
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "User ID"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      400,404  {object}  structs.ErrorResponse
+// @Failure      400,403,404  {object}  structs.ErrorResponse
 // @Router       /users/{id} [get]
-func GetUserHandler(c *gin.Context) {
+func (h *Handler) GetUserHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	if current, _ := middleware.CurrentUser(c); !canAccessUser(current, uint(id)) {
+		helpers.WriteError(c, http.StatusForbidden, helpers.NewError("You may only access your own profile", helpers.CodeForbidden))
 		return
 	}
 
-	user, err := models.GetUser(database.DB, uint(id))
+	user, err := h.Users.Get(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("User not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrUserNotFound)
 		return
 	}
 
 	c.JSON(http.StatusOK, helpers.NewSuccess[UserResponse](userToResponse(user), "User retrieved successfully"))
 }
 
+var usersSpec = query.Spec{
+	Sorts: map[string]string{
+		"id":         "id",
+		"email":      "email",
+		"first_name": "first_name",
+		"surname":    "surname",
+	},
+	DefaultSort: "id",
+	Filters: map[string]query.Filter{
+		"search": {Column: "concat_ws(' ', first_name, middle_name, surname, email)", Op: query.Contains},
+		"email":  {Column: "email", Op: query.Contains},
+		"role":   {Column: "role", Op: query.Equal},
+	},
+}
+
+// ListUsersHandler lists and searches users
+// @Summary      List users
+// @Description  Retrieve a page of users. search matches any part of a user's names or email.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit   query     int     false  "Page size (default 50, max 200)"
+// @Param        offset  query     int     false  "Rows to skip"
+// @Param        cursor  query     string  false  "meta.next_cursor of the previous page"
+// @Param        sort    query     string  false  "Comma-separated sort fields, prefix - for descending (id, email, first_name, surname)"
+// @Param        search  query     string  false  "Text to find in names or email"
+// @Param        email   query     string  false  "Only emails containing this text"
+// @Param        role    query     string  false  "Comma-separated roles"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,403,500  {object}  structs.ErrorResponse
+// @Router       /users [get]
+func (h *Handler) ListUsersHandler(c *gin.Context) {
+	params, ok := listParams(c, usersSpec)
+	if !ok {
+		return
+	}
+
+	users, meta, err := h.Users.List(c.Request.Context(), params)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]UserResponse, 0, len(users))
+	for i := range users {
+		response = append(response, userToResponse(&users[i]))
+	}
+	c.JSON(http.StatusOK, helpers.NewPage(response, meta, "Users retrieved successfully"))
+}
+
 // UpdateUserHandler updates a user
 // @Summary      Update user
 // @Description  Update an existing user by its ID
 // @Tags         users
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id       path      int          true  "User ID"
 // @Param        request  body      UserRequest  true  "User Request"
 // @Success      200      {object}  map[string]interface{}
-// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Failure      400,403,404,409,500  {object}  structs.ErrorResponse
 // @Router       /users/{id} [put]
-func UpdateUserHandler(c *gin.Context) {
+func (h *Handler) UpdateUserHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	if current, _ := middleware.CurrentUser(c); !canAccessUser(current, uint(id)) {
+		helpers.WriteError(c, http.StatusForbidden, helpers.NewError("You may only access your own profile", helpers.CodeForbidden))
 		return
 	}
 
 	// For updates, we might want partial updates. For now assuming full update or using logic to check fields
 	var request UserRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError(err.Error(), http.StatusBadRequest))
+		respondBindError(c, err)
 		return
 	}
 
-	user, err := models.GetUser(database.DB, uint(id))
+	user, err := h.Users.Get(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, helpers.NewError("User not found", http.StatusNotFound))
+		respondMissing(c, err, helpers.ErrUserNotFound)
 		return
 	}
 
@@ -144,11 +220,16 @@ func UpdateUserHandler(c *gin.Context) {
 	user.Surname = request.Surname
 	user.Email = request.Email
 	if request.Password != "" {
-		user.Password = request.Password
+		hash, err := auth.HashPassword(request.Password)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		user.SetPassword(hash)
 	}
 
-	if err := models.UpdateUser(database.DB, user); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.Users.Update(c.Request.Context(), user); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -161,20 +242,21 @@ func UpdateUserHandler(c *gin.Context) {
 // @Tags         users
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
 // @Param        id   path      int  true  "User ID"
 // @Success      200  {object}  map[string]interface{}
-// @Failure      400,500  {object}  structs.ErrorResponse
+// @Failure      400,403,409,500  {object}  structs.ErrorResponse
 // @Router       /users/{id} [delete]
-func DeleteUserHandler(c *gin.Context) {
+func (h *Handler) DeleteUserHandler(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, helpers.NewError("Invalid ID", http.StatusBadRequest))
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
 		return
 	}
 
-	if err := models.DeleteUser(database.DB, uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, helpers.NewError(err.Error(), http.StatusInternalServerError))
+	if err := h.Users.Delete(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -193,5 +275,6 @@ func userToResponse(user *models.User) UserResponse {
 		Surname:    user.Surname,
 		Dob:        dobStr,
 		Email:      user.Email,
+		Role:       user.Role,
 	}
 }
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"kora_1/internal/helpers"
+	"kora_1/internal/middleware"
+	"kora_1/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserRoleRequest struct {
+	Role string `json:"role" binding:"required" example:"reviewer"`
+}
+
+type ReviewerServiceRequest struct {
+	ServiceID uint `json:"service_id" binding:"required"`
+}
+
+// UpdateUserRoleHandler changes a user's role
+// @Summary      Set user role
+// @Description  Assign one of the roles admin, designer, reviewer or applicant to a user. Admins cannot change their own role.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int              true  "User ID"
+// @Param        request  body      UserRoleRequest  true  "Role Request"
+// @Success      200      {object}  map[string]interface{}
+// @Failure      400,403,404,500  {object}  structs.ErrorResponse
+// @Router       /users/{id}/role [put]
+func (h *Handler) UpdateUserRoleHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	var request UserRoleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !models.IsValidRole(request.Role) {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Role must be one of admin, designer, reviewer or applicant", helpers.CodeInvalidRequest))
+		return
+	}
+
+	if current, _ := middleware.CurrentUser(c); current.ID == uint(id) {
+		helpers.WriteError(c, http.StatusForbidden, helpers.NewError("You cannot change your own role", helpers.CodeForbidden))
+		return
+	}
+
+	user, err := h.Users.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		respondMissing(c, err, helpers.ErrUserNotFound)
+		return
+	}
+
+	if err := h.Users.UpdateRole(c.Request.Context(), user.ID, request.Role); err != nil {
+		respondError(c, err)
+		return
+	}
+	user.Role = request.Role
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[UserResponse](userToResponse(user), "User role updated successfully"))
+}
+
+// ListReviewerServicesHandler lists the services a reviewer is assigned to
+// @Summary      List reviewer services
+// @Description  Retrieve the services whose submissions a reviewer may review
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /users/{id}/services [get]
+func (h *Handler) ListReviewerServicesHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	if _, err := h.Users.Get(c.Request.Context(), uint(id)); err != nil {
+		respondMissing(c, err, helpers.ErrUserNotFound)
+		return
+	}
+
+	services, err := h.Users.ReviewerServices(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[[]models.Service](services, "Reviewer services retrieved successfully"))
+}
+
+// AssignReviewerServiceHandler scopes a reviewer to a service
+// @Summary      Assign reviewer to service
+// @Description  Allow a reviewer to review the submissions of a service
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                     true  "User ID"
+// @Param        request  body      ReviewerServiceRequest  true  "Reviewer Service Request"
+// @Success      201      {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /users/{id}/services [post]
+func (h *Handler) AssignReviewerServiceHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	var request ReviewerServiceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	user, err := h.Users.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		respondMissing(c, err, helpers.ErrUserNotFound)
+		return
+	}
+	if user.Role != models.RoleReviewer {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Only reviewers can be assigned to services", helpers.CodeInvalidRequest))
+		return
+	}
+	if _, err := h.Services.Get(c.Request.Context(), request.ServiceID); err != nil {
+		respondMissing(c, err, helpers.ErrServiceNotFound)
+		return
+	}
+
+	if err := h.Users.AssignReviewer(c.Request.Context(), user.ID, request.ServiceID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, helpers.NewSuccess[any](nil, "Reviewer assigned to service successfully"))
+}
+
+// RemoveReviewerServiceHandler removes a reviewer from a service
+// @Summary      Remove reviewer from service
+// @Description  Stop a reviewer from reviewing the submissions of a service
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id          path      int  true  "User ID"
+// @Param        service_id  path      int  true  "Service ID"
+// @Success      200         {object}  map[string]interface{}
+// @Failure      400,500     {object}  structs.ErrorResponse
+// @Router       /users/{id}/services/{service_id} [delete]
+func (h *Handler) RemoveReviewerServiceHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+	serviceID, err := strconv.ParseUint(c.Param("service_id"), 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid service ID", helpers.CodeInvalidID))
+		return
+	}
+
+	if err := h.Users.RemoveReviewer(c.Request.Context(), uint(id), uint(serviceID)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[any](nil, "Reviewer removed from service successfully"))
+}
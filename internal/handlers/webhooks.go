@@ -0,0 +1,383 @@
+package handlers
+
+import (
+	"kora_1/internal/helpers"
+	"kora_1/internal/models"
+	"kora_1/internal/query"
+	"kora_1/internal/webhooks"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookRequest struct {
+	URL    string   `json:"url" binding:"required,http_url,max=500" example:"https://payments.example.com/hooks/kora"`
+	Events []string `json:"events" binding:"dive,oneof=submission.created submission.status_changed form.published"` // Empty subscribes to every event
+	Active *bool    `json:"active"`                                                                                  // Defaults to true
+	Secret string   `json:"secret" binding:"omitempty,min=16,max=100"`                                               // Generated when omitted on create; replaced when set on update
+}
+
+type WebhookResponse struct {
+	ID        uint      `json:"id"`
+	ServiceID uint      `json:"service_id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	Secret    string    `json:"secret,omitempty"` // Only in the response to create
+	CreatedOn time.Time `json:"created_on"`
+}
+
+func webhookToResponse(webhook *models.Webhook) WebhookResponse {
+	events := webhook.EventList()
+	if events == nil {
+		events = []string{}
+	}
+	return WebhookResponse{
+		ID:        webhook.ID,
+		ServiceID: webhook.ServiceID,
+		URL:       webhook.URL,
+		Events:    events,
+		Active:    webhook.Active,
+		CreatedOn: webhook.CreatedOn,
+	}
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uint       `json:"id"`
+	WebhookID      uint       `json:"webhook_id"`
+	EventID        uint       `json:"event_id"`
+	Event          string     `json:"event"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptOn  *time.Time `json:"next_attempt_on"` // Only while pending
+	LastAttemptOn  *time.Time `json:"last_attempt_on"`
+	ResponseStatus *int       `json:"response_status"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredOn    *time.Time `json:"delivered_on"`
+	CreatedOn      time.Time  `json:"created_on"`
+}
+
+func deliveryToResponse(delivery *models.WebhookDelivery) WebhookDeliveryResponse {
+	response := WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventID:        delivery.EventID,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastAttemptOn:  delivery.LastAttemptOn,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		DeliveredOn:    delivery.DeliveredOn,
+		CreatedOn:      delivery.CreatedOn,
+	}
+	if delivery.Event != nil {
+		response.Event = delivery.Event.Event
+	}
+	if delivery.Status == models.DeliveryPending {
+		response.NextAttemptOn = &delivery.NextAttemptOn
+	}
+	return response
+}
+
+// loadWebhook answers 400 or 404 unless the :id parameter names a webhook.
+func (h *Handler) loadWebhook(c *gin.Context) (*models.Webhook, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return nil, false
+	}
+	webhook, err := h.Webhooks.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		respondMissing(c, err, helpers.ErrWebhookNotFound)
+		return nil, false
+	}
+	return webhook, true
+}
+
+var webhooksSpec = query.Spec{
+	Sorts:       map[string]string{"id": "id", "url": "url", "created_on": "created_on"},
+	DefaultSort: "id",
+	Filters: map[string]query.Filter{
+		"active": {Column: "active", Op: query.Equal, Kind: query.Bool},
+	},
+}
+
+// ListServiceWebhooksHandler lists the webhooks of a service
+// @Summary      List service webhooks
+// @Description  Retrieve a page of the webhooks that receive the events of a service. Secrets are not returned.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int     true   "Service ID"
+// @Param        limit   query     int     false  "Page size (default 50, max 200)"
+// @Param        offset  query     int     false  "Rows to skip"
+// @Param        cursor  query     string  false  "meta.next_cursor of the previous page"
+// @Param        sort    query     string  false  "Comma-separated sort fields, prefix - for descending (id, url, created_on)"
+// @Param        active  query     bool    false  "Only active or only inactive webhooks"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /services/{id}/webhooks [get]
+func (h *Handler) ListServiceWebhooksHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+	params, ok := listParams(c, webhooksSpec)
+	if !ok {
+		return
+	}
+
+	if _, err := h.Services.Get(c.Request.Context(), uint(id)); err != nil {
+		respondMissing(c, err, helpers.ErrServiceNotFound)
+		return
+	}
+	rows, meta, err := h.Webhooks.List(c.Request.Context(), uint(id), params)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]WebhookResponse, 0, len(rows))
+	for i := range rows {
+		response = append(response, webhookToResponse(&rows[i]))
+	}
+	c.JSON(http.StatusOK, helpers.NewPage(response, meta, "Webhooks retrieved successfully"))
+}
+
+// CreateServiceWebhookHandler subscribes a URL to the events of a service
+// @Summary      Create service webhook
+// @Description  Subscribe a URL to the events of a service: submission.created, submission.status_changed and form.published, or every event when events is empty.
+// @Description  Each delivery is a JSON POST signed with the secret: X-Kora-Signature is sha256= and the hex HMAC-SHA256 of X-Kora-Timestamp, a dot and the body. The secret is returned only here.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int             true  "Service ID"
+// @Param        request  body      WebhookRequest  true  "Webhook Request"
+// @Success      201      {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /services/{id}/webhooks [post]
+func (h *Handler) CreateServiceWebhookHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	var request WebhookRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if _, err := h.Services.Get(c.Request.Context(), uint(id)); err != nil {
+		respondMissing(c, err, helpers.ErrServiceNotFound)
+		return
+	}
+
+	if request.Secret == "" {
+		if request.Secret, err = webhooks.NewSecret(); err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+	webhook := &models.Webhook{ServiceID: uint(id), Active: true}
+	applyWebhookRequest(webhook, request)
+	if err := h.Webhooks.Create(c.Request.Context(), webhook); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := webhookToResponse(webhook)
+	response.Secret = webhook.Secret
+	c.JSON(http.StatusCreated, helpers.NewSuccess(response, "Webhook created successfully"))
+}
+
+// GetWebhookHandler retrieves a webhook by ID
+// @Summary      Get webhook
+// @Description  Retrieve a webhook by its ID, without its secret
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Webhook ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /webhooks/{id} [get]
+func (h *Handler) GetWebhookHandler(c *gin.Context) {
+	webhook, ok := h.loadWebhook(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, helpers.NewSuccess(webhookToResponse(webhook), "Webhook retrieved successfully"))
+}
+
+// UpdateWebhookHandler updates a webhook
+// @Summary      Update webhook
+// @Description  Change a webhook's URL, events or active flag, and replace its secret when one is sent. Pending deliveries of an inactive webhook wait until it is active again.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int             true  "Webhook ID"
+// @Param        request  body      WebhookRequest  true  "Webhook Request"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /webhooks/{id} [put]
+func (h *Handler) UpdateWebhookHandler(c *gin.Context) {
+	webhook, ok := h.loadWebhook(c)
+	if !ok {
+		return
+	}
+
+	var request WebhookRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	applyWebhookRequest(webhook, request)
+	if err := h.Webhooks.Update(c.Request.Context(), webhook); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess(webhookToResponse(webhook), "Webhook updated successfully"))
+}
+
+// applyWebhookRequest copies request onto webhook, keeping the secret and the
+// active flag when the request leaves them out.
+func applyWebhookRequest(webhook *models.Webhook, request WebhookRequest) {
+	webhook.URL = request.URL
+	webhook.SetEventList(request.Events)
+	if request.Active != nil {
+		webhook.Active = *request.Active
+	}
+	if request.Secret != "" {
+		webhook.Secret = request.Secret
+	}
+}
+
+// DeleteWebhookHandler deletes a webhook
+// @Summary      Delete webhook
+// @Description  Delete a webhook and its delivery log
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Webhook ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,500  {object}  structs.ErrorResponse
+// @Router       /webhooks/{id} [delete]
+func (h *Handler) DeleteWebhookHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	if err := h.Webhooks.Delete(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[any](nil, "Webhook deleted successfully"))
+}
+
+var deliveriesSpec = query.Spec{
+	Sorts:       map[string]string{"id": "id", "created_on": "created_on", "status": "status"},
+	DefaultSort: "-id",
+	Filters: map[string]query.Filter{
+		"status": {Column: "status", Op: query.Equal},
+	},
+}
+
+// ListWebhookDeliveriesHandler lists the delivery log of a webhook
+// @Summary      List webhook deliveries
+// @Description  Retrieve a page of a webhook's deliveries, newest first: their status, attempts, the receiver's last answer and when the next attempt is due.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int     true   "Webhook ID"
+// @Param        limit   query     int     false  "Page size (default 50, max 200)"
+// @Param        offset  query     int     false  "Rows to skip"
+// @Param        cursor  query     string  false  "meta.next_cursor of the previous page"
+// @Param        sort    query     string  false  "Comma-separated sort fields, prefix - for descending (id, created_on, status)"
+// @Param        status  query     string  false  "Only pending, succeeded or failed deliveries"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /webhooks/{id}/deliveries [get]
+func (h *Handler) ListWebhookDeliveriesHandler(c *gin.Context) {
+	webhook, ok := h.loadWebhook(c)
+	if !ok {
+		return
+	}
+	params, ok := listParams(c, deliveriesSpec)
+	if !ok {
+		return
+	}
+
+	rows, meta, err := h.Webhooks.Deliveries(c.Request.Context(), webhook.ID, params)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]WebhookDeliveryResponse, 0, len(rows))
+	for i := range rows {
+		response = append(response, deliveryToResponse(&rows[i]))
+	}
+	c.JSON(http.StatusOK, helpers.NewPage(response, meta, "Deliveries retrieved successfully"))
+}
+
+// RedeliverWebhookDeliveryHandler sends a delivery again
+// @Summary      Redeliver webhook delivery
+// @Description  Queue a delivery to be sent again as soon as possible with a fresh set of attempts, whether it failed, succeeded or is still pending. The body and X-Kora-Delivery stay the same.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id           path      int  true  "Webhook ID"
+// @Param        delivery_id  path      int  true  "Delivery ID"
+// @Success      202  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *Handler) RedeliverWebhookDeliveryHandler(c *gin.Context) {
+	webhook, ok := h.loadWebhook(c)
+	if !ok {
+		return
+	}
+	deliveryID, err := strconv.ParseUint(c.Param("delivery_id"), 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid delivery ID", helpers.CodeInvalidID))
+		return
+	}
+
+	ctx := c.Request.Context()
+	delivery, err := h.Webhooks.GetDelivery(ctx, uint(deliveryID))
+	if err != nil {
+		respondMissing(c, err, helpers.ErrDeliveryNotFound)
+		return
+	}
+	if delivery.WebhookID != webhook.ID {
+		respondError(c, helpers.ErrDeliveryNotFound)
+		return
+	}
+
+	if err := h.Webhooks.Redeliver(ctx, delivery.ID, time.Now()); err != nil {
+		respondMissing(c, err, helpers.ErrDeliveryNotFound)
+		return
+	}
+	delivery, err = h.Webhooks.GetDelivery(ctx, delivery.ID)
+	if err != nil {
+		respondMissing(c, err, helpers.ErrDeliveryNotFound)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, helpers.NewSuccess(deliveryToResponse(delivery), "Delivery queued for redelivery"))
+}
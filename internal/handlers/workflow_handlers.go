@@ -0,0 +1,410 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"kora_1/internal/auth"
+	"kora_1/internal/helpers"
+	"kora_1/internal/middleware"
+	"kora_1/internal/models"
+	"kora_1/internal/notify"
+	"kora_1/internal/repository"
+	"kora_1/internal/webhooks"
+	"kora_1/internal/workflow"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errCommentRequired = errors.New("a comment is required for this transition")
+
+type TransitionRequest struct {
+	Status  string `json:"status" binding:"required" example:"under_review"`
+	Comment string `json:"comment" binding:"max=1000"`
+}
+
+type StatusHistoryResponse struct {
+	ID         uint   `json:"id"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Comment    string `json:"comment"`
+	ChangedBy  *uint  `json:"changed_by"`
+	ChangedOn  string `json:"changed_on"`
+}
+
+type WorkflowRequest struct {
+	Transitions []workflow.Transition `json:"transitions"` // An empty list restores the default lifecycle
+}
+
+type WorkflowResponse struct {
+	ServiceID   uint                  `json:"service_id"`
+	IsDefault   bool                  `json:"is_default"`
+	Statuses    []string              `json:"statuses"`
+	Transitions []workflow.Transition `json:"transitions"`
+}
+
+// TransitionSubmissionHandler moves a submission to a new status
+// @Summary      Transition submission
+// @Description  Move a submission along its service's lifecycle. Applicants may move their own submissions and reviewers those of their services, each only along transitions granted to them.
+// @Description  Drafts are submitted with POST /submission/{id}/submit, which checks every answer first.
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                true  "Submission ID"
+// @Param        request  body      TransitionRequest  true  "Transition Request"
+// @Success      200      {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Failure      409      {object}  structs.ErrorResponse  "The transition is not allowed from the current status"
+// @Router       /submission/{id}/transition [post]
+func (h *Handler) TransitionSubmissionHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	var request TransitionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !workflow.IsStatus(request.Status) {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Unknown status", helpers.CodeInvalidRequest))
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	submission, ok := h.loadAccessibleSubmission(c, user, uint(id))
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	// A draft's answers are only checked in full, required fields included,
+	// on submit; every other edge is the workflow's to allow.
+	if submission.Status == workflow.Draft {
+		machine, err := serviceWorkflow(ctx, h.Repositories, submission.ServicesID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		if to, err := machine.Submit(workflow.Draft); err == nil && to == request.Status {
+			helpers.WriteError(c, http.StatusConflict, helpers.NewError("Submit with POST /submission/{id}/submit, which checks every answer", helpers.CodeTransitionNotAllowed))
+			return
+		}
+	}
+
+	err = h.Transaction(ctx, func(tx repository.Repositories) error {
+		return transitionSubmission(ctx, tx, user, submission, request.Status, request.Comment)
+	})
+	if !respondTransitionError(c, err) {
+		return
+	}
+
+	answers, err := h.submissionAnswers(c.Request.Context(), submission.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, helpers.NewSuccess[SubmissionResponse](submissionToResponse(submission, answers), "Submission status updated successfully"))
+}
+
+// GetSubmissionHistoryHandler lists a submission's status changes
+// @Summary      Get submission history
+// @Description  Retrieve every status change of a submission, oldest first, with who made it and their comment
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Submission ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /submission/{id}/history [get]
+func (h *Handler) GetSubmissionHistoryHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	user, _ := middleware.CurrentUser(c)
+	submission, ok := h.loadAccessibleSubmission(c, user, uint(id))
+	if !ok {
+		return
+	}
+
+	history, err := h.Submissions.History(c.Request.Context(), submission.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := make([]StatusHistoryResponse, 0, len(history))
+	for _, entry := range history {
+		response = append(response, StatusHistoryResponse{
+			ID:         entry.ID,
+			FromStatus: entry.FromStatus,
+			ToStatus:   entry.ToStatus,
+			Comment:    entry.Comment,
+			ChangedBy:  entry.ChangedBy,
+			ChangedOn:  entry.ChangedOn.Format(time.RFC3339),
+		})
+	}
+	c.JSON(http.StatusOK, helpers.NewSuccess[[]StatusHistoryResponse](response, "Submission history retrieved successfully"))
+}
+
+// GetServiceWorkflowHandler retrieves a service's submission lifecycle
+// @Summary      Get service workflow
+// @Description  Retrieve the transitions a service's submissions may take. Services without their own configuration use the default lifecycle.
+// @Tags         services
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Service ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /services/{id}/workflow [get]
+func (h *Handler) GetServiceWorkflowHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	if _, err := h.Services.Get(c.Request.Context(), uint(id)); err != nil {
+		respondMissing(c, err, helpers.ErrServiceNotFound)
+		return
+	}
+
+	response, err := workflowToResponse(c.Request.Context(), h.Repositories, uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, helpers.NewSuccess[WorkflowResponse](response, "Service workflow retrieved successfully"))
+}
+
+// UpdateServiceWorkflowHandler replaces a service's submission lifecycle
+// @Summary      Update service workflow
+// @Description  Replace the transitions a service's submissions may take. Send an empty list to go back to the default lifecycle.
+// @Tags         services
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int              true  "Service ID"
+// @Param        request  body      WorkflowRequest  true  "Workflow Request"
+// @Success      200      {object}  map[string]interface{}
+// @Failure      400,404,500  {object}  structs.ErrorResponse
+// @Router       /services/{id}/workflow [put]
+func (h *Handler) UpdateServiceWorkflowHandler(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("Invalid ID", helpers.CodeInvalidID))
+		return
+	}
+
+	var request WorkflowRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if _, err := workflow.New(request.Transitions); err != nil {
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError(err.Error(), helpers.CodeInvalidRequest))
+		return
+	}
+
+	if _, err := h.Services.Get(c.Request.Context(), uint(id)); err != nil {
+		respondMissing(c, err, helpers.ErrServiceNotFound)
+		return
+	}
+
+	rows := make([]models.WorkflowTransition, 0, len(request.Transitions))
+	for _, t := range request.Transitions {
+		rows = append(rows, models.WorkflowTransition{
+			FromStatus:      t.From,
+			ToStatus:        t.To,
+			Actor:           t.Actor,
+			CommentRequired: t.CommentRequired,
+		})
+	}
+
+	ctx := c.Request.Context()
+	var response WorkflowResponse
+	err = h.Transaction(ctx, func(tx repository.Repositories) error {
+		if err := tx.Services.SetWorkflow(ctx, uint(id), rows); err != nil {
+			return err
+		}
+		var err error
+		response, err = workflowToResponse(ctx, tx, uint(id))
+		return err
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, helpers.NewSuccess[WorkflowResponse](response, "Service workflow updated successfully"))
+}
+
+// loadAccessibleSubmission loads a submission the user may see, responding with
+// 404 when it does not exist or is out of their scope, so IDs cannot be probed.
+func (h *Handler) loadAccessibleSubmission(c *gin.Context, user *models.User, id uint) (*models.Submission, bool) {
+	submission, err := h.Submissions.Get(c.Request.Context(), id)
+	if err != nil {
+		respondMissing(c, err, helpers.ErrSubmissionNotFound)
+		return nil, false
+	}
+
+	ok, err := h.canAccessSubmission(c.Request.Context(), user, submission)
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	if !ok {
+		respondError(c, helpers.ErrSubmissionNotFound)
+		return nil, false
+	}
+	return submission, true
+}
+
+// serviceWorkflow returns the lifecycle configured for a service, or the default one.
+func serviceWorkflow(ctx context.Context, repos repository.Repositories, serviceID *uint) (*workflow.Machine, error) {
+	if serviceID == nil {
+		return workflow.DefaultMachine(), nil
+	}
+
+	rows, err := repos.Services.Workflow(ctx, *serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return workflow.DefaultMachine(), nil
+	}
+
+	transitions := make([]workflow.Transition, 0, len(rows))
+	for _, row := range rows {
+		transitions = append(transitions, workflow.Transition{
+			From:            row.FromStatus,
+			To:              row.ToStatus,
+			Actor:           row.Actor,
+			CommentRequired: row.CommentRequired,
+		})
+	}
+	return workflow.New(transitions)
+}
+
+// submissionActors lists the workflow actors the user acts as on a submission.
+func submissionActors(ctx context.Context, repos repository.Repositories, user *models.User, submission *models.Submission) ([]string, error) {
+	var actors []string
+	if submission.CreatedBy != nil && *submission.CreatedBy == user.ID {
+		actors = append(actors, workflow.Applicant)
+	}
+
+	reviewer := auth.Can(user.Role, auth.ManageSubmissions)
+	if !reviewer && auth.Can(user.Role, auth.ReviewSubmissions) && submission.ServicesID != nil {
+		var err error
+		reviewer, err = repos.Users.IsReviewer(ctx, user.ID, *submission.ServicesID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if reviewer {
+		actors = append(actors, workflow.Reviewer)
+	}
+	return actors, nil
+}
+
+// transitionSubmission checks a status change against the service's lifecycle,
+// applies it, records it in the submission's history and queues the webhook
+// event and emails it causes. Pass the repositories of a transaction.
+func transitionSubmission(ctx context.Context, tx repository.Repositories, user *models.User, submission *models.Submission, to, comment string) error {
+	machine, err := serviceWorkflow(ctx, tx, submission.ServicesID)
+	if err != nil {
+		return err
+	}
+	actors, err := submissionActors(ctx, tx, user, submission)
+	if err != nil {
+		return err
+	}
+
+	transition, err := machine.Find(submission.Status, to, actors...)
+	if err != nil {
+		return err
+	}
+	comment = strings.TrimSpace(comment)
+	if transition.CommentRequired && comment == "" {
+		return errCommentRequired
+	}
+
+	from := submission.Status
+	if err := tx.Submissions.UpdateStatus(ctx, submission, to); err != nil {
+		return err
+	}
+	if err := tx.Submissions.AddHistory(ctx, &models.SubmissionStatusHistory{
+		SubmissionID: submission.ID,
+		FromStatus:   from,
+		ToStatus:     to,
+		Comment:      comment,
+		ChangedBy:    &user.ID,
+	}); err != nil {
+		return err
+	}
+	if err := webhooks.Enqueue(ctx, tx.Webhooks, webhooks.SubmissionStatusChanged, submission.ServicesID, webhooks.StatusChangeData{
+		SubmissionID: submission.ID,
+		ServiceID:    submission.ServicesID,
+		FromStatus:   from,
+		ToStatus:     to,
+		Comment:      comment,
+		ChangedBy:    user.ID,
+	}); err != nil {
+		return err
+	}
+
+	var formName string
+	if submission.FormVersion != nil {
+		formName = submission.FormVersion.FormName
+	}
+	return notify.SubmissionChanged(ctx, tx.Notifications, submission, formName, from, comment, user.ID)
+}
+
+// respondTransitionError writes the response for a failed transition and
+// reports whether the transition succeeded.
+func respondTransitionError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, workflow.ErrIllegalTransition):
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Transition is not allowed from the current status", helpers.CodeTransitionNotAllowed))
+	case errors.Is(err, models.ErrSubmissionStatusChanged):
+		helpers.WriteError(c, http.StatusConflict, helpers.NewError("Submission status was changed by someone else; reload and try again", helpers.CodeStatusChanged))
+	case errors.Is(err, errCommentRequired):
+		helpers.WriteError(c, http.StatusBadRequest, helpers.NewError("A comment is required for this transition", helpers.CodeInvalidRequest))
+	default:
+		respondError(c, err)
+	}
+	return false
+}
+
+func workflowToResponse(ctx context.Context, repos repository.Repositories, serviceID uint) (WorkflowResponse, error) {
+	rows, err := repos.Services.Workflow(ctx, serviceID)
+	if err != nil {
+		return WorkflowResponse{}, err
+	}
+	machine, err := serviceWorkflow(ctx, repos, &serviceID)
+	if err != nil {
+		return WorkflowResponse{}, err
+	}
+	return WorkflowResponse{
+		ServiceID:   serviceID,
+		IsDefault:   len(rows) == 0,
+		Statuses:    workflow.Statuses,
+		Transitions: machine.Transitions(),
+	}, nil
+}
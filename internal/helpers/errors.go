@@ -0,0 +1,162 @@
+package helpers
+
+import (
+	"errors"
+	"kora_1/internal/repository"
+	"kora_1/internal/structs"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// Error codes returned in ErrorResponse.Code. They are part of the API:
+// rename one only together with the clients that check for it.
+const (
+	CodeInvalidRequest   = "INVALID_REQUEST"
+	CodeInvalidID        = "INVALID_ID"
+	CodeValidationFailed = "VALIDATION_FAILED"
+	CodeImportFailed     = "IMPORT_FAILED"
+	CodePayloadTooLarge  = "PAYLOAD_TOO_LARGE"
+
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeInvalidCredentials = "INVALID_CREDENTIALS"
+	CodeInvalidToken       = "INVALID_TOKEN"
+	CodeForbidden          = "FORBIDDEN"
+
+	CodeNotFound               = "NOT_FOUND"
+	CodeServiceNotFound        = "SERVICE_NOT_FOUND"
+	CodeUserNotFound           = "USER_NOT_FOUND"
+	CodeFormNotFound           = "FORM_NOT_FOUND"
+	CodeFormVersionNotFound    = "FORM_VERSION_NOT_FOUND"
+	CodeFormFieldNotFound      = "FORM_FIELD_NOT_FOUND"
+	CodeFieldNotFound          = "FIELD_NOT_FOUND"
+	CodeGroupNotFound          = "GROUP_NOT_FOUND"
+	CodeFormGroupNotFound      = "FORM_GROUP_NOT_FOUND"
+	CodeDataTypeNotFound       = "DATA_TYPE_NOT_FOUND"
+	CodeCollectionNotFound     = "COLLECTION_NOT_FOUND"
+	CodeCollectionItemNotFound = "COLLECTION_ITEM_NOT_FOUND"
+	CodeSubmissionNotFound     = "SUBMISSION_NOT_FOUND"
+	CodeFileNotFound           = "FILE_NOT_FOUND"
+	CodeReservedNameNotFound   = "RESERVED_NAME_NOT_FOUND"
+	CodeRestrictedTermNotFound = "RESTRICTED_TERM_NOT_FOUND"
+	CodeWebhookNotFound        = "WEBHOOK_NOT_FOUND"
+	CodeDeliveryNotFound       = "DELIVERY_NOT_FOUND"
+
+	CodeConflict             = "CONFLICT"
+	CodeDuplicate            = "DUPLICATE"
+	CodeDuplicateEmail       = "DUPLICATE_EMAIL"
+	CodeDuplicateCode        = "DUPLICATE_CODE"
+	CodeInUse                = "IN_USE"
+	CodeInvalidReference     = "INVALID_REFERENCE"
+	CodeNoPublishedVersion   = "NO_PUBLISHED_VERSION"
+	CodeVersionNotPublished  = "VERSION_NOT_PUBLISHED"
+	CodeVersionPublished     = "VERSION_PUBLISHED"
+	CodeNoDraft              = "NO_DRAFT"
+	CodeIdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+	CodeTransitionNotAllowed = "TRANSITION_NOT_ALLOWED"
+	CodeStatusChanged        = "STATUS_CHANGED"
+	CodeNotEditable          = "NOT_EDITABLE"
+	CodeNameTaken            = "NAME_TAKEN"
+	CodeReservationNotHeld   = "RESERVATION_NOT_HELD"
+	CodeNameProhibited       = "NAME_PROHIBITED"
+	CodeInvalidTemplate      = "INVALID_TEMPLATE"
+
+	CodeInternal = "INTERNAL_ERROR"
+)
+
+// Error is an error reported to clients as is: its status, code and message.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Errors for records a request names that do not exist.
+var (
+	ErrServiceNotFound        = &Error{http.StatusNotFound, CodeServiceNotFound, "Service not found"}
+	ErrUserNotFound           = &Error{http.StatusNotFound, CodeUserNotFound, "User not found"}
+	ErrFormNotFound           = &Error{http.StatusNotFound, CodeFormNotFound, "Form not found"}
+	ErrFormVersionNotFound    = &Error{http.StatusNotFound, CodeFormVersionNotFound, "Form version not found"}
+	ErrFormFieldNotFound      = &Error{http.StatusNotFound, CodeFormFieldNotFound, "Form field not found"}
+	ErrFieldNotFound          = &Error{http.StatusNotFound, CodeFieldNotFound, "Field not found"}
+	ErrGroupNotFound          = &Error{http.StatusNotFound, CodeGroupNotFound, "Group not found"}
+	ErrFormGroupNotFound      = &Error{http.StatusNotFound, CodeFormGroupNotFound, "Form group not found"}
+	ErrDataTypeNotFound       = &Error{http.StatusNotFound, CodeDataTypeNotFound, "Data type not found"}
+	ErrCollectionNotFound     = &Error{http.StatusNotFound, CodeCollectionNotFound, "Collection not found"}
+	ErrCollectionItemNotFound = &Error{http.StatusNotFound, CodeCollectionItemNotFound, "Collection item not found"}
+	ErrSubmissionNotFound     = &Error{http.StatusNotFound, CodeSubmissionNotFound, "Submission not found"}
+	ErrFileNotFound           = &Error{http.StatusNotFound, CodeFileNotFound, "File not found"}
+	ErrReservedNameNotFound   = &Error{http.StatusNotFound, CodeReservedNameNotFound, "Reserved name not found"}
+	ErrRestrictedTermNotFound = &Error{http.StatusNotFound, CodeRestrictedTermNotFound, "Restricted term not found"}
+	ErrWebhookNotFound        = &Error{http.StatusNotFound, CodeWebhookNotFound, "Webhook not found"}
+	ErrDeliveryNotFound       = &Error{http.StatusNotFound, CodeDeliveryNotFound, "Delivery not found"}
+)
+
+// duplicateCodes names the code for a unique constraint whose violation
+// means more to clients than DUPLICATE.
+var duplicateCodes = map[string]string{
+	"uni_users_email":                 CodeDuplicateEmail,
+	"idx_users_email":                 CodeDuplicateEmail,
+	"idx_collection_item_code":        CodeDuplicateCode,
+	"idx_submissions_idempotency_key": CodeIdempotencyKeyReused,
+
+	"idx_reserved_names_held_normalized_name": CodeNameTaken,
+}
+
+// Postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgNotNullViolation    = "23502"
+	pgCheckViolation      = "23514"
+)
+
+// IsNotFound reports whether err means a record does not exist.
+func IsNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, repository.ErrNotFound)
+}
+
+// ErrorFor maps err to the status and response to answer with. Messages of
+// database and other unexpected errors are never passed on; they answer 500
+// with a generic message, and internal reports whether err should be logged.
+func ErrorFor(err error) (status int, response structs.ErrorResponse, internal bool) {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Status, NewError(apiErr.Message, apiErr.Code), false
+	}
+	if IsNotFound(err) {
+		return http.StatusNotFound, NewError("Record not found", CodeNotFound), false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			code, ok := duplicateCodes[pgErr.ConstraintName]
+			if !ok {
+				code = CodeDuplicate
+			}
+			return http.StatusConflict, NewError("A record with the same value already exists", code), false
+		case pgForeignKeyViolation:
+			// Postgres reports deleting a referenced row as "update or delete on table ...".
+			if strings.HasPrefix(pgErr.Message, "update or delete") {
+				return http.StatusConflict, NewError("The record is still in use by other records", CodeInUse), false
+			}
+			return http.StatusUnprocessableEntity, NewError("A referenced record does not exist", CodeInvalidReference), false
+		case pgNotNullViolation:
+			return http.StatusUnprocessableEntity, NewValidationError([]structs.FieldError{
+				{Field: pgErr.ColumnName, Rule: "required", Message: "Is required"},
+			}), false
+		case pgCheckViolation:
+			return http.StatusUnprocessableEntity, NewError("Validation failed", CodeValidationFailed), false
+		}
+	}
+
+	return http.StatusInternalServerError, NewError("Internal server error", CodeInternal), true
+}
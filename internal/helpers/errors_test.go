@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"kora_1/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func TestErrorFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		status   int
+		code     string
+		internal bool
+	}{
+		{"api error", fmt.Errorf("loading: %w", ErrFormNotFound), http.StatusNotFound, CodeFormNotFound, false},
+		{"gorm not found", gorm.ErrRecordNotFound, http.StatusNotFound, CodeNotFound, false},
+		{"repository not found", repository.ErrNotFound, http.StatusNotFound, CodeNotFound, false},
+		{"duplicate email", &pgconn.PgError{Code: "23505", ConstraintName: "uni_users_email"}, http.StatusConflict, CodeDuplicateEmail, false},
+		{"other duplicate", &pgconn.PgError{Code: "23505", ConstraintName: "idx_other"}, http.StatusConflict, CodeDuplicate, false},
+		{"missing reference", &pgconn.PgError{Code: "23503", Message: `insert or update on table "forms" violates foreign key constraint "fk_forms_service"`}, http.StatusUnprocessableEntity, CodeInvalidReference, false},
+		{"still referenced", &pgconn.PgError{Code: "23503", Message: `update or delete on table "services" violates foreign key constraint "fk_forms_service" on table "forms"`}, http.StatusConflict, CodeInUse, false},
+		{"not null", &pgconn.PgError{Code: "23502", ColumnName: "form_name"}, http.StatusUnprocessableEntity, CodeValidationFailed, false},
+		{"unexpected", errors.New(`pq: relation "forms" does not exist`), http.StatusInternalServerError, CodeInternal, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			status, response, internal := ErrorFor(tc.err)
+			if status != tc.status || response.Code != tc.code || internal != tc.internal {
+				t.Errorf("got %d %s internal=%v, want %d %s internal=%v", status, response.Code, internal, tc.status, tc.code, tc.internal)
+			}
+		})
+	}
+}
+
+func TestErrorForHidesDatabaseMessages(t *testing.T) {
+	err := &pgconn.PgError{Code: "23505", Message: `duplicate key value violates unique constraint "uni_users_email"`, ConstraintName: "uni_users_email"}
+	_, response, _ := ErrorFor(fmt.Errorf("creating user: %w", err))
+	if response.Error != "A record with the same value already exists" {
+		t.Errorf("got message %q", response.Error)
+	}
+
+	_, response, _ = ErrorFor(errors.New("ERROR: syntax error at or near \"SELECT\" (SQLSTATE 42601)"))
+	if response.Error != "Internal server error" {
+		t.Errorf("got message %q", response.Error)
+	}
+}
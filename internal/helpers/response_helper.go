@@ -1,6 +1,11 @@
 package helpers
 
-import "kora_1/internal/structs"
+import (
+	"kora_1/internal/logging"
+	"kora_1/internal/structs"
+
+	"github.com/gin-gonic/gin"
+)
 
 func NewSuccess[T any](data T, message string) structs.SuccessResponse[T] {
 	return structs.SuccessResponse[T]{
@@ -10,10 +15,45 @@ func NewSuccess[T any](data T, message string) structs.SuccessResponse[T] {
 	}
 }
 
-func NewError(err string, code int) structs.ErrorResponse {
+func NewPage[T any](data T, meta structs.Meta, message string) structs.SuccessResponse[T] {
+	return structs.SuccessResponse[T]{
+		Status:  true,
+		Message: message,
+		Data:    data,
+		Meta:    &meta,
+	}
+}
+
+// NewError builds an error response; code is one of the Code constants.
+func NewError(err string, code string) structs.ErrorResponse {
 	return structs.ErrorResponse{
 		Status: false,
 		Error:  err,
 		Code:   code,
 	}
 }
+
+func NewValidationError(details []structs.FieldError) structs.ErrorResponse {
+	return structs.ErrorResponse{
+		Status:  false,
+		Error:   "Validation failed",
+		Code:    CodeValidationFailed,
+		Details: details,
+	}
+}
+
+func NewImportError(rows []structs.RowError) structs.ErrorResponse {
+	return structs.ErrorResponse{
+		Status: false,
+		Error:  "Import failed",
+		Code:   CodeImportFailed,
+		Rows:   rows,
+	}
+}
+
+// WriteError answers with response, tagged with the request ID, and stops the
+// remaining handlers.
+func WriteError(c *gin.Context, status int, response structs.ErrorResponse) {
+	response.RequestID = logging.RequestID(c.Request.Context())
+	c.AbortWithStatusJSON(status, response)
+}
@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func lines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("line is not JSON: %s", line)
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func TestLoggerAddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "info").With("component", "test")
+
+	logger.InfoContext(WithRequestID(context.Background(), "req-1"), "with id")
+	logger.InfoContext(context.Background(), "without id")
+	logger.Debug("below level")
+
+	got := lines(t, &buf)
+	if len(got) != 2 {
+		t.Fatalf("got %d lines, want 2: %v", len(got), got)
+	}
+	if got[0]["request_id"] != "req-1" || got[0]["component"] != "test" {
+		t.Errorf("got %v, want request_id and component", got[0])
+	}
+	if _, ok := got[1]["request_id"]; ok {
+		t.Errorf("got request_id on a line logged without one: %v", got[1])
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for level, want := range map[string]slog.Level{
+		"debug": slog.LevelDebug,
+		"warn":  slog.LevelWarn,
+		"error": slog.LevelError,
+		"":      slog.LevelInfo,
+		"loud":  slog.LevelInfo,
+	} {
+		if got := ParseLevel(level); got != want {
+			t.Errorf("ParseLevel(%q) = %s, want %s", level, got, want)
+		}
+	}
+}
+
+func TestGormLoggerTrace(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-2")
+	query := func() (string, int64) { return "SELECT 1", 1 }
+
+	tests := []struct {
+		name    string
+		level   string
+		elapsed time.Duration
+		err     error
+		want    string // "" when nothing should be logged
+	}{
+		{"fast query at info", "info", time.Millisecond, nil, ""},
+		{"fast query at debug", "debug", time.Millisecond, nil, "query"},
+		{"slow query", "info", time.Second, nil, "slow query"},
+		{"failed query", "info", time.Millisecond, errors.New("boom"), "query failed"},
+		{"missing record", "info", time.Millisecond, gorm.ErrRecordNotFound, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := GormLogger{Logger: New(&buf, tc.level), SlowThreshold: 100 * time.Millisecond}
+			l.Trace(ctx, time.Now().Add(-tc.elapsed), query, tc.err)
+
+			got := lines(t, &buf)
+			if tc.want == "" {
+				if len(got) != 0 {
+					t.Errorf("got %v, want nothing logged", got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0]["msg"] != tc.want || got[0]["request_id"] != "req-2" || got[0]["sql"] != "SELECT 1" {
+				t.Errorf("got %v, want one %q line with the SQL and request ID", got, tc.want)
+			}
+		})
+	}
+}
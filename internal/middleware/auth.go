@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"kora_1/internal/auth"
+	"kora_1/internal/helpers"
+	"kora_1/internal/models"
+	"kora_1/internal/repository"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const currentUserKey = "currentUser"
+
+// RequireAuth rejects requests without a valid Bearer access token issued by
+// tokens and stores the authenticated user, loaded from users, on the context
+// for CurrentUser.
+func RequireAuth(users repository.Users, tokens *auth.Tokens) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			helpers.WriteError(c, http.StatusUnauthorized, helpers.NewError("Missing bearer token", helpers.CodeUnauthorized))
+			return
+		}
+
+		claims, err := tokens.Parse(strings.TrimSpace(token), auth.AccessToken)
+		if err != nil {
+			helpers.WriteError(c, http.StatusUnauthorized, helpers.NewError("Invalid or expired token", helpers.CodeInvalidToken))
+			return
+		}
+
+		userID, err := claims.UserID()
+		if err != nil {
+			helpers.WriteError(c, http.StatusUnauthorized, helpers.NewError("Invalid or expired token", helpers.CodeInvalidToken))
+			return
+		}
+
+		user, err := users.Get(c.Request.Context(), userID)
+		if err != nil {
+			helpers.WriteError(c, http.StatusUnauthorized, helpers.NewError("User no longer exists", helpers.CodeUnauthorized))
+			return
+		}
+		if claims.Revoked(user) {
+			helpers.WriteError(c, http.StatusUnauthorized, helpers.NewError("Invalid or expired token", helpers.CodeInvalidToken))
+			return
+		}
+
+		SetCurrentUser(c, user)
+		c.Next()
+	}
+}
+
+// SetCurrentUser stores user as the authenticated user of the request.
+func SetCurrentUser(c *gin.Context, user *models.User) {
+	c.Set(currentUserKey, user)
+}
+
+// CurrentUser returns the user authenticated by RequireAuth, if any.
+func CurrentUser(c *gin.Context) (*models.User, bool) {
+	value, ok := c.Get(currentUserKey)
+	if !ok {
+		return nil, false
+	}
+	user, ok := value.(*models.User)
+	return user, ok
+}
+
+// RequirePermission rejects requests whose user's role does not grant the
+// permission. It must run after RequireAuth.
+func RequirePermission(permission auth.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := CurrentUser(c)
+		if !ok {
+			helpers.WriteError(c, http.StatusUnauthorized, helpers.NewError("Not authenticated", helpers.CodeUnauthorized))
+			return
+		}
+		if !auth.Can(user.Role, permission) {
+			helpers.WriteError(c, http.StatusForbidden, helpers.NewError("You do not have permission to perform this action", helpers.CodeForbidden))
+			return
+		}
+		c.Next()
+	}
+}
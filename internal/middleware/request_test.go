@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"kora_1/internal/helpers"
+	"kora_1/internal/logging"
+	"kora_1/internal/structs"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID())
+	var seen string
+	r.GET("/", func(c *gin.Context) {
+		seen = logging.RequestID(c.Request.Context())
+		helpers.WriteError(c, http.StatusNotFound, helpers.NewError("Form not found", helpers.CodeFormNotFound))
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"propagates the caller's ID", "abc-123", true},
+		{"assigns one when missing", "", false},
+		{"replaces one with spaces", "abc 123", false},
+		{"replaces one that is too long", strings.Repeat("a", 129), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				req.Header.Set(RequestIDHeader, tc.header)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(RequestIDHeader)
+			if tc.keep && id != tc.header {
+				t.Errorf("got %q, want the caller's %q", id, tc.header)
+			}
+			if !tc.keep && (id == "" || id == tc.header) {
+				t.Errorf("got %q, want a new ID", id)
+			}
+			if seen != id {
+				t.Errorf("context carried %q, response header %q", seen, id)
+			}
+
+			var body structs.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.RequestID != id {
+				t.Errorf("error response carried %q, want %q", body.RequestID, id)
+			}
+		})
+	}
+}
+
+func TestRecoveryAnswersWithRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID(), Recovery())
+	r.GET("/", func(c *gin.Context) { panic("boom") })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "req-9")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	var body structs.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusInternalServerError || body.Code != helpers.CodeInternal || body.RequestID != "req-9" {
+		t.Errorf("got %d %+v", rec.Code, body)
+	}
+}
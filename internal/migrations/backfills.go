@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"log/slog"
+
+	"kora_1/internal/auth"
+	"kora_1/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	// Data fixes that used to run after AutoMigrate on every start, once
+	// migrations 2 to 8 have added the columns they fill. They find nothing to
+	// do on a new database.
+	register(Migration{
+		Version: 9,
+		Name:    "legacy_backfills",
+		Up: func(tx *gorm.DB) error {
+			if err := models.BackfillFormVersions(tx); err != nil {
+				return err
+			}
+			if err := models.BackfillCollectionItemCodes(tx); err != nil {
+				return err
+			}
+			rehashed, err := auth.RehashPlaintextPasswords(tx)
+			if err != nil {
+				return err
+			}
+			if rehashed > 0 {
+				slog.Info("rehashed plaintext passwords", "count", rehashed)
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error { return nil },
+	})
+
+	// Keys the reserved names stored before migration 10 added the key columns.
+	register(Migration{
+		Version: 11,
+		Name:    "reserved_name_keys",
+		Up:      models.BackfillReservedNameKeys,
+		Down:    func(tx *gorm.DB) error { return nil },
+	})
+}
@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAll(t *testing.T) {
+	all, err := All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) == 0 || all[0].Version != 1 || all[0].Name != "baseline" {
+		t.Fatalf("first migration = %+v, want the baseline", all[0])
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Version <= all[i-1].Version {
+			t.Errorf("migrations out of order at %d", all[i].Version)
+		}
+	}
+}
+
+func TestLoadSQL(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/0002_add_codes.up.sql":   {Data: []byte("ALTER TABLE x ADD code text")},
+		"sql/0002_add_codes.down.sql": {Data: []byte("ALTER TABLE x DROP code")},
+		"sql/0001_init.up.sql":        {Data: []byte("CREATE TABLE x ()")},
+	}
+	migrations, err := loadSQL(fsys, "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("got %d migrations, want 2", len(migrations))
+	}
+	for _, m := range migrations {
+		switch m.Version {
+		case 1:
+			if m.Name != "init" || m.Up == nil || m.Down != nil {
+				t.Errorf("migration 1 = %+v", m)
+			}
+		case 2:
+			if m.Name != "add_codes" || m.Up == nil || m.Down == nil {
+				t.Errorf("migration 2 = %+v", m)
+			}
+		default:
+			t.Errorf("unexpected migration %d", m.Version)
+		}
+	}
+
+	for name, want := range map[string]string{
+		"sql/init.up.sql":            "not named",
+		"sql/0001_init.sideways.sql": "not named",
+		"sql/0001_init.up.sql":       "used by both",
+	} {
+		bad := fstest.MapFS{
+			"sql/0001_other.up.sql": {Data: []byte("SELECT 1")},
+			name:                    {Data: []byte("SELECT 1")},
+		}
+		if _, err := loadSQL(bad, "sql"); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("loadSQL with %s: error %v, want one containing %q", name, err, want)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS form_answers;
+DROP TABLE IF EXISTS submissions;
+DROP TABLE IF EXISTS form_fields;
+DROP TABLE IF EXISTS forms;
+DROP TABLE IF EXISTS fields;
+DROP TABLE IF EXISTS reserved_names;
+DROP TABLE IF EXISTS form_groups;
+DROP TABLE IF EXISTS collection_items;
+DROP TABLE IF EXISTS collections;
+DROP TABLE IF EXISTS "groups";
+DROP TABLE IF EXISTS data_types;
+DROP TABLE IF EXISTS services;
+DROP TABLE IF EXISTS users;
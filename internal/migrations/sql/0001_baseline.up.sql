@@ -0,0 +1,106 @@
+-- Baseline schema, exactly what AutoMigrate created before versioned
+-- migrations. Every statement is guarded so databases that were migrated by
+-- AutoMigrate simply record this version; the migrations after it add what
+-- came later.
+
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial PRIMARY KEY,
+    first_name varchar(100),
+    middle_name varchar(100),
+    surname varchar(100),
+    dob date,
+    email varchar(250) CONSTRAINT uni_users_email UNIQUE,
+    password varchar(250)
+);
+
+CREATE TABLE IF NOT EXISTS services (
+    id bigserial PRIMARY KEY,
+    service_name varchar(100) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS data_types (
+    id bigserial PRIMARY KEY,
+    data_type varchar(50)
+);
+
+CREATE TABLE IF NOT EXISTS "groups" (
+    id bigserial PRIMARY KEY,
+    group_name varchar(50) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS collections (
+    id bigserial PRIMARY KEY,
+    collection_name varchar(50)
+);
+
+CREATE TABLE IF NOT EXISTS collection_items (
+    id bigserial PRIMARY KEY,
+    collection_id bigint CONSTRAINT fk_collection_items_collection REFERENCES collections (id),
+    collection_item varchar(50),
+    relation_collection_items_id bigint CONSTRAINT fk_collection_items_relation_collection_items REFERENCES collection_items (id)
+);
+CREATE INDEX IF NOT EXISTS idx_collection_items_collection_id ON collection_items (collection_id);
+CREATE INDEX IF NOT EXISTS idx_collection_items_relation_collection_items_id ON collection_items (relation_collection_items_id);
+
+CREATE TABLE IF NOT EXISTS form_groups (
+    id bigserial PRIMARY KEY,
+    group_name varchar(50),
+    group_span bigint,
+    group_row bigint
+);
+
+CREATE TABLE IF NOT EXISTS reserved_names (
+    id bigserial PRIMARY KEY,
+    reserved_name varchar(50)
+);
+
+CREATE TABLE IF NOT EXISTS fields (
+    id bigserial PRIMARY KEY,
+    label varchar(50) NOT NULL,
+    data_type_id bigint NOT NULL CONSTRAINT fk_fields_data_type REFERENCES data_types (id),
+    group_id bigint CONSTRAINT fk_fields_group REFERENCES "groups" (id),
+    collection_id bigint CONSTRAINT fk_fields_collection REFERENCES collections (id),
+    status boolean DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_fields_group_id ON fields (group_id);
+CREATE INDEX IF NOT EXISTS idx_fields_collection_id ON fields (collection_id);
+
+CREATE TABLE IF NOT EXISTS forms (
+    id bigserial PRIMARY KEY,
+    form_name varchar(50) NOT NULL,
+    description varchar(250),
+    data_type_id bigint NOT NULL CONSTRAINT fk_forms_data_type REFERENCES data_types (id),
+    service_id bigint CONSTRAINT fk_forms_service REFERENCES services (id),
+    status boolean DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_forms_service_id ON forms (service_id);
+
+CREATE TABLE IF NOT EXISTS form_fields (
+    id bigserial PRIMARY KEY,
+    form_id bigint NOT NULL CONSTRAINT fk_form_fields_form REFERENCES forms (id),
+    field_id bigint NOT NULL CONSTRAINT fk_form_fields_field REFERENCES fields (id),
+    field_name varchar(50),
+    form_group_id bigint CONSTRAINT fk_form_fields_form_group REFERENCES form_groups (id),
+    validation varchar(250),
+    field_span bigint,
+    field_row bigint
+);
+CREATE INDEX IF NOT EXISTS idx_form_fields_form_group_id ON form_fields (form_group_id);
+
+CREATE TABLE IF NOT EXISTS submissions (
+    id bigserial PRIMARY KEY,
+    services_id bigint CONSTRAINT fk_submissions_service REFERENCES services (id),
+    created_by bigint CONSTRAINT fk_submissions_user REFERENCES users (id),
+    created_on timestamptz DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_submissions_services_id ON submissions (services_id);
+CREATE INDEX IF NOT EXISTS idx_submissions_created_by ON submissions (created_by);
+
+CREATE TABLE IF NOT EXISTS form_answers (
+    id bigserial PRIMARY KEY,
+    form_field_id bigint CONSTRAINT fk_form_answers_form_field REFERENCES form_fields (id),
+    answer varchar(250),
+    submission_id bigint CONSTRAINT fk_form_answers_submission REFERENCES submissions (id)
+);
+CREATE INDEX IF NOT EXISTS idx_form_answers_form_field_id ON form_answers (form_field_id);
+CREATE INDEX IF NOT EXISTS idx_form_answers_submission_id ON form_answers (submission_id);
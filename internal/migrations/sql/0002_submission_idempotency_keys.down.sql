@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_submissions_idempotency_key;
+
+ALTER TABLE submissions
+    DROP COLUMN IF EXISTS idempotency_key;
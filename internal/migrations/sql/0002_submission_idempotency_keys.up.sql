@@ -0,0 +1,7 @@
+-- A client may send an Idempotency-Key with a submission; a retry with the
+-- same key returns the first submission instead of filing another.
+
+ALTER TABLE submissions
+    ADD COLUMN IF NOT EXISTS idempotency_key varchar(100);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_submissions_idempotency_key ON submissions (idempotency_key);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_submissions_form_version_id;
+ALTER TABLE submissions
+    DROP COLUMN IF EXISTS form_version_id;
+
+DROP INDEX IF EXISTS idx_form_fields_form_version_id;
+ALTER TABLE form_fields
+    DROP COLUMN IF EXISTS form_version_id;
+
+DROP TABLE IF EXISTS form_versions;
@@ -0,0 +1,23 @@
+-- Forms are edited as draft versions and published. Form fields belong to a
+-- version and submissions record the version they answered. Forms created
+-- before this migration get a published version 1 from migration 9.
+
+CREATE TABLE IF NOT EXISTS form_versions (
+    id bigserial PRIMARY KEY,
+    form_id bigint NOT NULL CONSTRAINT fk_form_versions_form REFERENCES forms (id),
+    version bigint NOT NULL,
+    status varchar(20) NOT NULL DEFAULT 'draft',
+    form_name varchar(50) NOT NULL,
+    description varchar(250),
+    created_on timestamptz DEFAULT CURRENT_TIMESTAMP,
+    published_on timestamptz DEFAULT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_form_versions_form_version ON form_versions (form_id, version);
+
+ALTER TABLE form_fields
+    ADD COLUMN IF NOT EXISTS form_version_id bigint;
+CREATE INDEX IF NOT EXISTS idx_form_fields_form_version_id ON form_fields (form_version_id);
+
+ALTER TABLE submissions
+    ADD COLUMN IF NOT EXISTS form_version_id bigint CONSTRAINT fk_submissions_form_version REFERENCES form_versions (id);
+CREATE INDEX IF NOT EXISTS idx_submissions_form_version_id ON submissions (form_version_id);
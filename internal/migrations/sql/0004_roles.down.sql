@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS reviewer_services;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS role;
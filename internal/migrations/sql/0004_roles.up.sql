@@ -0,0 +1,13 @@
+-- Users have a role, and reviewers see the submissions of the services they
+-- are assigned to. Existing users become applicants.
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role varchar(20) NOT NULL DEFAULT 'applicant';
+
+CREATE TABLE IF NOT EXISTS reviewer_services (
+    user_id bigint CONSTRAINT fk_reviewer_services_user REFERENCES users (id) ON DELETE CASCADE,
+    service_id bigint CONSTRAINT fk_reviewer_services_service REFERENCES services (id) ON DELETE CASCADE,
+    created_on timestamptz DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, service_id)
+);
+CREATE INDEX IF NOT EXISTS idx_reviewer_services_service_id ON reviewer_services (service_id);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS submission_status_history;
+DROP TABLE IF EXISTS workflow_transitions;
+
+DROP INDEX IF EXISTS idx_submissions_status;
+ALTER TABLE submissions
+    DROP COLUMN IF EXISTS updated_on,
+    DROP COLUMN IF EXISTS status;
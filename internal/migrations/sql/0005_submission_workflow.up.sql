@@ -0,0 +1,30 @@
+-- Submissions move through statuses along each service's workflow, and every
+-- move is kept in submission_status_history. Existing submissions start as
+-- submitted. updated_on tracks when a draft or status last changed.
+
+ALTER TABLE submissions
+    ADD COLUMN IF NOT EXISTS status varchar(20) NOT NULL DEFAULT 'submitted',
+    ADD COLUMN IF NOT EXISTS updated_on timestamptz DEFAULT CURRENT_TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_submissions_status ON submissions (status);
+
+CREATE TABLE IF NOT EXISTS workflow_transitions (
+    id bigserial PRIMARY KEY,
+    service_id bigint NOT NULL CONSTRAINT fk_workflow_transitions_service REFERENCES services (id) ON DELETE CASCADE,
+    from_status varchar(20) NOT NULL,
+    to_status varchar(20) NOT NULL,
+    actor varchar(20) NOT NULL,
+    comment_required boolean NOT NULL DEFAULT false
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workflow_transitions_unique ON workflow_transitions (service_id, from_status, to_status, actor);
+
+CREATE TABLE IF NOT EXISTS submission_status_history (
+    id bigserial PRIMARY KEY,
+    submission_id bigint NOT NULL CONSTRAINT fk_submission_status_history_submission REFERENCES submissions (id) ON DELETE CASCADE,
+    from_status varchar(20),
+    to_status varchar(20) NOT NULL,
+    comment varchar(1000),
+    changed_by bigint CONSTRAINT fk_submission_status_history_user REFERENCES users (id),
+    changed_on timestamptz DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_submission_status_history_submission_id ON submission_status_history (submission_id);
+CREATE INDEX IF NOT EXISTS idx_submission_status_history_changed_by ON submission_status_history (changed_by);
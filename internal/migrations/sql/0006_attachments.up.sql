@@ -0,0 +1,20 @@
+-- Files uploaded for file fields. The bytes live in storage under
+-- storage_key; a row without a submission is an upload not yet submitted.
+
+CREATE TABLE IF NOT EXISTS attachments (
+    id bigserial PRIMARY KEY,
+    storage_key varchar(255) NOT NULL,
+    file_name varchar(255) NOT NULL,
+    content_type varchar(100) NOT NULL,
+    size bigint NOT NULL,
+    sha256 varchar(64) NOT NULL,
+    form_field_id bigint CONSTRAINT fk_attachments_form_field REFERENCES form_fields (id),
+    submission_id bigint CONSTRAINT fk_attachments_submission REFERENCES submissions (id) ON DELETE SET NULL,
+    uploaded_by bigint CONSTRAINT fk_attachments_user REFERENCES users (id),
+    created_on timestamptz DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_attachments_storage_key ON attachments (storage_key);
+CREATE INDEX IF NOT EXISTS idx_attachments_sha256 ON attachments (sha256);
+CREATE INDEX IF NOT EXISTS idx_attachments_form_field_id ON attachments (form_field_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_submission_id ON attachments (submission_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_uploaded_by ON attachments (uploaded_by);
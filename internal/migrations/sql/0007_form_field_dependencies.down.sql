@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_form_fields_depends_on_form_field_id;
+ALTER TABLE form_fields
+    DROP COLUMN IF EXISTS depends_on_form_field_id;
@@ -0,0 +1,6 @@
+-- A cascading collection field offers the items under the one chosen in the
+-- field it depends on.
+
+ALTER TABLE form_fields
+    ADD COLUMN IF NOT EXISTS depends_on_form_field_id bigint;
+CREATE INDEX IF NOT EXISTS idx_form_fields_depends_on_form_field_id ON form_fields (depends_on_form_field_id);
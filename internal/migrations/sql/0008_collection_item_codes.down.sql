@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_collection_item_code;
+ALTER TABLE collection_items
+    DROP COLUMN IF EXISTS code;
@@ -0,0 +1,6 @@
+-- Collection items have a code unique within their collection, used by
+-- import and export. Existing items are given their ID by migration 9.
+
+ALTER TABLE collection_items
+    ADD COLUMN IF NOT EXISTS code varchar(50) DEFAULT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_collection_item_code ON collection_items (collection_id, code);
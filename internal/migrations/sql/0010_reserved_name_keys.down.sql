@@ -0,0 +1,8 @@
+-- The pg_trgm extension is left installed; other schemas may use it.
+
+DROP INDEX IF EXISTS idx_reserved_names_phonetic_key;
+DROP INDEX IF EXISTS idx_reserved_names_normalized_name_trgm;
+
+ALTER TABLE reserved_names
+    DROP COLUMN IF EXISTS phonetic_key,
+    DROP COLUMN IF EXISTS normalized_name;
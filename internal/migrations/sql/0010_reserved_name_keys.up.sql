@@ -0,0 +1,12 @@
+-- Keys that reserved names are matched by, see package names. The trigram
+-- index serves the % operator and similarity() of pg_trgm. Existing rows are
+-- keyed by migration 11, which needs Go.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE reserved_names
+    ADD COLUMN IF NOT EXISTS normalized_name varchar(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS phonetic_key varchar(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_reserved_names_normalized_name_trgm ON reserved_names USING gin (normalized_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_reserved_names_phonetic_key ON reserved_names (phonetic_key);
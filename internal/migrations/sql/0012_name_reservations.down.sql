@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_reserved_names_expires_on;
+DROP INDEX IF EXISTS idx_reserved_names_user_id;
+DROP INDEX IF EXISTS idx_reserved_names_held_normalized_name;
+
+ALTER TABLE reserved_names
+    DROP COLUMN IF EXISTS released_on,
+    DROP COLUMN IF EXISTS expires_on,
+    DROP COLUMN IF EXISTS reserved_on,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS service_id,
+    DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,26 @@
+-- Reservations belong to a user and a service and lapse at expires_on. Names
+-- reserved before this migration have neither and never lapse.
+--
+-- Only one held reservation may have a given normalised name. Where names
+-- reserved before clash, the oldest keeps the name and the others are
+-- released.
+
+ALTER TABLE reserved_names
+    ADD COLUMN IF NOT EXISTS user_id bigint CONSTRAINT fk_reserved_names_user REFERENCES users (id),
+    ADD COLUMN IF NOT EXISTS service_id bigint CONSTRAINT fk_reserved_names_service REFERENCES services (id),
+    ADD COLUMN IF NOT EXISTS status varchar(20) NOT NULL DEFAULT 'held',
+    ADD COLUMN IF NOT EXISTS reserved_on timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS expires_on timestamptz,
+    ADD COLUMN IF NOT EXISTS released_on timestamptz;
+
+UPDATE reserved_names r
+SET status = 'released', released_on = CURRENT_TIMESTAMP
+WHERE r.status = 'held'
+  AND EXISTS (
+    SELECT 1 FROM reserved_names o
+    WHERE o.status = 'held' AND o.normalized_name = r.normalized_name AND o.id < r.id
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reserved_names_held_normalized_name ON reserved_names (normalized_name) WHERE status = 'held';
+CREATE INDEX IF NOT EXISTS idx_reserved_names_user_id ON reserved_names (user_id);
+CREATE INDEX IF NOT EXISTS idx_reserved_names_expires_on ON reserved_names (expires_on) WHERE status = 'held';
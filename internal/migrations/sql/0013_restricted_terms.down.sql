@@ -0,0 +1,3 @@
+ALTER TABLE reserved_names DROP COLUMN IF EXISTS needs_review;
+
+DROP TABLE IF EXISTS restricted_terms;
@@ -0,0 +1,15 @@
+-- Words and phrases that names may not use (prohibited) or may use only with
+-- approval (restricted). Reservations of names using a restricted term are
+-- flagged for review.
+
+CREATE TABLE IF NOT EXISTS restricted_terms (
+    id bigserial PRIMARY KEY,
+    term varchar(100) NOT NULL,
+    normalized_term varchar(100) NOT NULL,
+    severity varchar(20) NOT NULL CONSTRAINT chk_restricted_terms_severity CHECK (severity IN ('prohibited', 'restricted')),
+    reason varchar(255),
+    created_on timestamptz DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_restricted_terms_normalized_term ON restricted_terms (normalized_term);
+
+ALTER TABLE reserved_names ADD COLUMN IF NOT EXISTS needs_review boolean NOT NULL DEFAULT false;
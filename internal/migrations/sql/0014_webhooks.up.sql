@@ -0,0 +1,41 @@
+-- Webhook subscriptions of services, the outbox of events written in the
+-- transaction that caused them, and one delivery per event and webhook, which
+-- doubles as the delivery log.
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id bigserial PRIMARY KEY,
+    service_id bigint NOT NULL CONSTRAINT fk_webhooks_service REFERENCES services (id) ON DELETE CASCADE,
+    url varchar(500) NOT NULL,
+    secret varchar(100) NOT NULL,
+    events varchar(255) NOT NULL DEFAULT '',
+    active boolean NOT NULL DEFAULT true,
+    created_on timestamptz DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_webhooks_service_id ON webhooks (service_id);
+
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id bigserial PRIMARY KEY,
+    event varchar(50) NOT NULL,
+    service_id bigint,
+    payload text NOT NULL,
+    created_on timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dispatched_on timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_events_undispatched ON webhook_events (id) WHERE dispatched_on IS NULL;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id bigserial PRIMARY KEY,
+    webhook_id bigint NOT NULL CONSTRAINT fk_webhook_deliveries_webhook REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_id bigint NOT NULL CONSTRAINT fk_webhook_deliveries_event REFERENCES webhook_events (id) ON DELETE CASCADE,
+    status varchar(20) NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_on timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_attempt_on timestamptz,
+    response_status integer,
+    last_error varchar(500),
+    delivered_on timestamptz,
+    created_on timestamptz DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uni_webhook_deliveries_webhook_event UNIQUE (webhook_id, event_id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_on) WHERE status = 'pending';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS notification_templates;
+DROP TABLE IF EXISTS notifications;
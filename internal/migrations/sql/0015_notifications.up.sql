@@ -0,0 +1,27 @@
+-- Emails queued for users in the transaction that causes them, and the
+-- templates services replace the default emails with.
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id bigserial PRIMARY KEY,
+    kind varchar(50) NOT NULL,
+    user_id bigint NOT NULL CONSTRAINT fk_notifications_user REFERENCES users (id) ON DELETE CASCADE,
+    service_id bigint CONSTRAINT fk_notifications_service REFERENCES services (id) ON DELETE SET NULL,
+    data text NOT NULL,
+    status varchar(20) NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_on timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error varchar(500),
+    sent_on timestamptz,
+    created_on timestamptz DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_due ON notifications (next_attempt_on) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS notification_templates (
+    id bigserial PRIMARY KEY,
+    service_id bigint NOT NULL CONSTRAINT fk_notification_templates_service REFERENCES services (id) ON DELETE CASCADE,
+    kind varchar(50) NOT NULL,
+    subject varchar(255) NOT NULL,
+    body text NOT NULL,
+    updated_on timestamptz DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uni_notification_templates_service_kind UNIQUE (service_id, kind)
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_form_answers_submission_field;
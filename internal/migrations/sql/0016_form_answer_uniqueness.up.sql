@@ -0,0 +1,9 @@
+-- A submission answers each form field at most once, which lets answers be
+-- saved with an upsert. Earlier duplicates give way to the latest answer.
+
+DELETE FROM form_answers a
+    USING form_answers b
+    WHERE a.submission_id = b.submission_id
+      AND a.form_field_id = b.form_field_id
+      AND a.id < b.id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_form_answers_submission_field ON form_answers (submission_id, form_field_id);
@@ -0,0 +1,5 @@
+-- A submission remembers a hash of the request its Idempotency-Key came with,
+-- so the key cannot be reused to replay it for a different request.
+
+ALTER TABLE submissions
+    ADD COLUMN IF NOT EXISTS idempotency_fingerprint char(64);
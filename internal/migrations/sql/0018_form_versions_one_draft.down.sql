@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_form_versions_one_draft;
@@ -0,0 +1,15 @@
+-- A form has at most one draft, so concurrent edits cannot each start their
+-- own. Where a form already has several, the oldest is kept and the others
+-- are deleted with their fields.
+
+DELETE FROM form_fields f
+    USING form_versions d, form_versions o
+    WHERE f.form_version_id = d.id
+      AND d.status = 'draft' AND o.status = 'draft'
+      AND o.form_id = d.form_id AND o.id < d.id;
+DELETE FROM form_versions d
+    USING form_versions o
+    WHERE d.status = 'draft' AND o.status = 'draft'
+      AND o.form_id = d.form_id AND o.id < d.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_form_versions_one_draft ON form_versions (form_id) WHERE status = 'draft';
@@ -0,0 +1,5 @@
+-- Tokens carry the user's token version, which a password change bumps so
+-- that the tokens issued before it stop working.
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS token_version integer NOT NULL DEFAULT 0;
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Attachment is an uploaded file kept in object storage. File answers store
+// the attachment's ID; the bytes live under StorageKey.
+type Attachment struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement"`
+	StorageKey   string    `gorm:"size:255;not null;uniqueIndex"`
+	FileName     string    `gorm:"size:255;not null"`
+	ContentType  string    `gorm:"size:100;not null"`
+	Size         int64     `gorm:"not null"`
+	SHA256       string    `gorm:"column:sha256;size:64;not null;index"`
+	FormFieldID  *uint     `gorm:"index"` // The file field the upload was made for
+	SubmissionID *uint     `gorm:"index"` // Set once an answer references the file
+	UploadedBy   *uint     `gorm:"index"`
+	CreatedOn    time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+
+	// Associations
+	FormField  *FormFields `gorm:"foreignKey:FormFieldID"`
+	Submission *Submission `gorm:"foreignKey:SubmissionID;constraint:OnDelete:SET NULL"`
+	User       *User       `gorm:"foreignKey:UploadedBy"`
+}
+
+func (Attachment) TableName() string {
+	return "attachments"
+}
+
+func CreateAttachment(db *gorm.DB, attachment *Attachment) error {
+	return db.Create(attachment).Error
+}
+
+func GetAttachment(db *gorm.DB, id uint) (*Attachment, error) {
+	var attachment Attachment
+	err := db.First(&attachment, id).Error
+	return &attachment, err
+}
+
+// GetAttachmentsBySubmissionID returns the files linked to a submission.
+func GetAttachmentsBySubmissionID(db *gorm.DB, submissionID uint) ([]Attachment, error) {
+	var attachments []Attachment
+	err := db.Where("submission_id = ?", submissionID).Order("id").Find(&attachments).Error
+	return attachments, err
+}
+
+// AttachmentUsable reports whether a user's upload for a form field may be
+// referenced by an answer on the submission: it must not already belong to a
+// different submission.
+func AttachmentUsable(db *gorm.DB, id, formFieldID, uploadedBy, submissionID uint) (bool, error) {
+	var count int64
+	err := db.Model(&Attachment{}).
+		Where("id = ? AND form_field_id = ? AND uploaded_by = ?", id, formFieldID, uploadedBy).
+		Where("submission_id IS NULL OR submission_id = ?", submissionID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// LinkAttachments marks the uploads referenced by a submission's answers as belonging to it.
+func LinkAttachments(db *gorm.DB, submissionID uint, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.Model(&Attachment{}).
+		Where("id IN ? AND submission_id IS NULL", ids).
+		Update("submission_id", submissionID).Error
+}
@@ -26,3 +26,10 @@ func GetAllCollections(db *gorm.DB) ([]Collection, error) {
 	err := db.Find(&collections).Error
 	return collections, err
 }
+
+// GetCollectionByName finds a collection by name, ignoring case.
+func GetCollectionByName(db *gorm.DB, name string) (*Collection, error) {
+	var collection Collection
+	err := db.Where("LOWER(collection_name) = LOWER(?)", name).Order("id").First(&collection).Error
+	return &collection, err
+}
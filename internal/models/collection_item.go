@@ -1,13 +1,21 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"strconv"
+
+	"gorm.io/gorm"
+)
 
 type CollectionItem struct {
 	ID                        uint   `gorm:"primaryKey;autoIncrement"`
-	CollectionID              *uint  `gorm:"index"` // Nullable to match schema 'int' without 'not null' constraint, though practically FK usually implies existence
+	CollectionID              *uint  `gorm:"index;uniqueIndex:idx_collection_item_code,priority:1"` // Nullable to match schema 'int' without 'not null' constraint, though practically FK usually implies existence
 	CollectionItem            string `gorm:"size:50"`
 	RelationCollectionItemsID *uint  `gorm:"index"`
 
+	// Code identifies the item within its collection across imports and exports.
+	// Items created without one are given their ID.
+	Code string `gorm:"size:50;default:null;uniqueIndex:idx_collection_item_code,priority:2"`
+
 	// Associations - optional but helpful, matching foreign keys
 	Collection              *Collection     `gorm:"foreignKey:CollectionID"`
 	RelationCollectionItems *CollectionItem `gorm:"foreignKey:RelationCollectionItemsID"`
@@ -17,6 +25,15 @@ func (CollectionItem) TableName() string {
 	return "collection_items"
 }
 
+// AfterCreate gives an item created without a code its ID as code.
+func (item *CollectionItem) AfterCreate(tx *gorm.DB) error {
+	if item.Code != "" {
+		return nil
+	}
+	item.Code = strconv.FormatUint(uint64(item.ID), 10)
+	return tx.Model(item).Update("code", item.Code).Error
+}
+
 func CreateCollectionItem(db *gorm.DB, item *CollectionItem) error {
 	return db.Create(item).Error
 }
@@ -26,3 +43,45 @@ func GetCollectionItem(db *gorm.DB, id uint) (*CollectionItem, error) {
 	err := db.Preload("Collection").Preload("RelationCollectionItems").First(&item, id).Error
 	return &item, err
 }
+
+func GetCollectionItemsByCollectionIDs(db *gorm.DB, collectionIDs []uint) ([]CollectionItem, error) {
+	var items []CollectionItem
+	err := db.Where("collection_id IN ?", collectionIDs).Order("id").Find(&items).Error
+	return items, err
+}
+
+// GetCollectionItems lists a collection's items, optionally only those related to a parent item.
+func GetCollectionItems(db *gorm.DB, collectionID uint, parentID *uint) ([]CollectionItem, error) {
+	var items []CollectionItem
+	query := db.Where("collection_id = ?", collectionID)
+	if parentID != nil {
+		query = query.Where("relation_collection_items_id = ?", *parentID)
+	}
+	err := query.Order("id").Find(&items).Error
+	return items, err
+}
+
+// GetChildCollectionItems lists the items of any collection related to one of the parent items.
+func GetChildCollectionItems(db *gorm.DB, parentIDs []uint) ([]CollectionItem, error) {
+	var items []CollectionItem
+	err := db.Where("relation_collection_items_id IN ?", parentIDs).Order("id").Find(&items).Error
+	return items, err
+}
+
+// CollectionItemCodeTaken reports whether another item of the collection already uses the code.
+func CollectionItemCodeTaken(db *gorm.DB, collectionID uint, code string, exceptID uint) (bool, error) {
+	var count int64
+	err := db.Model(&CollectionItem{}).Where("collection_id = ? AND code = ? AND id <> ?", collectionID, code, exceptID).Count(&count).Error
+	return count > 0, err
+}
+
+// BackfillCollectionItemCodes gives items created before codes existed their ID as code.
+func BackfillCollectionItemCodes(db *gorm.DB) error {
+	return db.Exec("UPDATE collection_items SET code = id::text WHERE code IS NULL OR code = ''").Error
+}
+
+func GetCollectionItemsByIDs(db *gorm.DB, ids []uint) ([]CollectionItem, error) {
+	var items []CollectionItem
+	err := db.Where("id IN ?", ids).Find(&items).Error
+	return items, err
+}
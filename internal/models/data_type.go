@@ -26,3 +26,10 @@ func GetAllDataTypes(db *gorm.DB) ([]DataType, error) {
 	err := db.Find(&dataTypes).Error
 	return dataTypes, err
 }
+
+// GetDataTypeByName finds a data type by name, ignoring case.
+func GetDataTypeByName(db *gorm.DB, name string) (*DataType, error) {
+	var dataType DataType
+	err := db.Where("LOWER(data_type) = LOWER(?)", name).Order("id").First(&dataType).Error
+	return &dataType, err
+}
@@ -30,10 +30,58 @@ func GetFields(db *gorm.DB, id uint) (*Field, error) {
 	return &field, err
 }
 
+// UpdateFields saves a field. Once a published form version places the field
+// only its status may change, since anything else would alter that version.
 func UpdateFields(db *gorm.DB, field *Field) error {
+	var stored Field
+	if err := db.First(&stored, field.ID).Error; err != nil {
+		return err
+	}
+	if stored.Label != field.Label || stored.DataTypeID != field.DataTypeID ||
+		!sameID(stored.GroupID, field.GroupID) || !sameID(stored.CollectionID, field.CollectionID) {
+		published, err := inPublishedVersion(db, "field_id", field.ID)
+		if err != nil {
+			return err
+		}
+		if published {
+			return ErrFormVersionPublished
+		}
+	}
 	return db.Save(field).Error
 }
 
 func DeleteFields(db *gorm.DB, id uint) error {
+	published, err := inPublishedVersion(db, "field_id", id)
+	if err != nil {
+		return err
+	}
+	if published {
+		return ErrFormVersionPublished
+	}
 	return db.Delete(&Field{}, id).Error
 }
+
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// FindField finds a catalog field with exactly the given label, data type, group and collection.
+func FindField(db *gorm.DB, label string, dataTypeID uint, groupID, collectionID *uint) (*Field, error) {
+	var field Field
+	query := db.Where("label = ? AND data_type_id = ?", label, dataTypeID)
+	if groupID != nil {
+		query = query.Where("group_id = ?", *groupID)
+	} else {
+		query = query.Where("group_id IS NULL")
+	}
+	if collectionID != nil {
+		query = query.Where("collection_id = ?", *collectionID)
+	} else {
+		query = query.Where("collection_id IS NULL")
+	}
+	err := query.Order("id").First(&field).Error
+	return &field, err
+}
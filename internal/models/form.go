@@ -35,10 +35,34 @@ func GetForm(db *gorm.DB, id uint) (*Form, error) {
 	return &form, nil
 }
 
+// UpdateForm saves a form. Once a version is published the form's data type,
+// service and status are fixed, since that version's submissions were filed
+// under them.
 func UpdateForm(db *gorm.DB, form *Form) error {
+	var stored Form
+	if err := db.First(&stored, form.ID).Error; err != nil {
+		return err
+	}
+	if stored.DataTypeID != form.DataTypeID || !sameID(stored.ServiceID, form.ServiceID) || !sameFlag(stored.Status, form.Status) {
+		var published int64
+		err := db.Model(&FormVersion{}).Where("form_id = ? AND status = ?", form.ID, FormVersionPublished).Count(&published).Error
+		if err != nil {
+			return err
+		}
+		if published > 0 {
+			return ErrFormVersionPublished
+		}
+	}
 	return db.Save(form).Error
 }
 
+func sameFlag(a, b *bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 func DeleteForm(db *gorm.DB, id uint) error {
 	return db.Delete(&Form{}, id).Error
 }
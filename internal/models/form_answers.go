@@ -1,12 +1,19 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
 type FormAnswer struct {
 	ID           uint   `gorm:"primaryKey;autoIncrement"`
-	FormFieldID  *uint  `gorm:"index"`
+	FormFieldID  *uint  `gorm:"index;uniqueIndex:idx_form_answers_submission_field,priority:2"`
 	Answer       string `gorm:"size:250"`
-	SubmissionID *uint  `gorm:"index"`
+	SubmissionID *uint  `gorm:"index;uniqueIndex:idx_form_answers_submission_field,priority:1"`
 
 	// Associations
 	FormField  *FormFields `gorm:"foreignKey:FormFieldID"`
@@ -34,3 +41,56 @@ func UpdateFormAnswer(db *gorm.DB, answer *FormAnswer) error {
 func DeleteFormAnswer(db *gorm.DB, id uint) error {
 	return db.Delete(&FormAnswer{}, id).Error
 }
+
+func GetFormAnswersBySubmissionID(db *gorm.DB, submissionID uint) ([]FormAnswer, error) {
+	var answers []FormAnswer
+	err := db.Preload("FormField.Field.DataType").Where("submission_id = ?", submissionID).Order("id").Find(&answers).Error
+	return answers, err
+}
+
+// SaveFormAnswers writes answers keyed by form field ID onto a submission,
+// replacing earlier answers to the same fields. A blank answer removes the
+// field's answer. Run it inside a transaction.
+func SaveFormAnswers(db *gorm.DB, submissionID uint, values map[uint]string) error {
+	var answers []FormAnswer
+	var cleared []uint
+	for _, formFieldID := range slices.Sorted(maps.Keys(values)) {
+		if strings.TrimSpace(values[formFieldID]) == "" {
+			cleared = append(cleared, formFieldID)
+			continue
+		}
+		answers = append(answers, FormAnswer{FormFieldID: &formFieldID, SubmissionID: &submissionID, Answer: values[formFieldID]})
+	}
+
+	if len(cleared) > 0 {
+		if err := db.Where("submission_id = ? AND form_field_id IN ?", submissionID, cleared).Delete(&FormAnswer{}).Error; err != nil {
+			return err
+		}
+	}
+	if len(answers) == 0 {
+		return nil
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "submission_id"}, {Name: "form_field_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"answer"}),
+	}).Create(&answers).Error
+}
+
+// GetFormAnswersBySubmissionIDs loads the answers of several submissions at
+// once, keyed by submission ID.
+func GetFormAnswersBySubmissionIDs(db *gorm.DB, submissionIDs []uint) (map[uint][]FormAnswer, error) {
+	byID := make(map[uint][]FormAnswer, len(submissionIDs))
+	if len(submissionIDs) == 0 {
+		return byID, nil
+	}
+	var answers []FormAnswer
+	if err := db.Preload("FormField.Field.DataType").Where("submission_id IN ?", submissionIDs).Order("id").Find(&answers).Error; err != nil {
+		return nil, err
+	}
+	for _, answer := range answers {
+		if answer.SubmissionID != nil {
+			byID[*answer.SubmissionID] = append(byID[*answer.SubmissionID], answer)
+		}
+	}
+	return byID, nil
+}
@@ -4,14 +4,19 @@ import "gorm.io/gorm"
 
 type FormFields struct { // Name matches 'form_fields' table but struct convention usually Singular. However keeping as FormFields to match file/usage context or TableName. I will use 'FormField' singular for the struct type if possible, but keep filenames. The previous file used FormFields. Let's start using singular 'FormField' for struct but map to 'form_fields'.
 
-	ID          uint   `gorm:"primaryKey;autoIncrement"`
-	FormID      uint   `gorm:"not null"`
-	FieldID     uint   `gorm:"not null"`
-	FieldName   string `gorm:"size:50"`
-	FormGroupID *uint  `gorm:"index"`
-	Validation  string `gorm:"size:250"`
-	FieldSpan   int
-	FieldRow    int
+	ID            uint   `gorm:"primaryKey;autoIncrement"`
+	FormID        uint   `gorm:"not null"`
+	FormVersionID *uint  `gorm:"index"`
+	FieldID       uint   `gorm:"not null"`
+	FieldName     string `gorm:"size:50"`
+	FormGroupID   *uint  `gorm:"index"`
+	Validation    string `gorm:"size:250"`
+	FieldSpan     int
+	FieldRow      int
+
+	// DependsOnFormFieldID names the field, in the same form version, whose
+	// chosen collection item filters this field's options to its children.
+	DependsOnFormFieldID *uint `gorm:"index"`
 
 	// Associations
 	Form      Form       `gorm:"foreignKey:FormID"`
@@ -23,6 +28,44 @@ func (FormFields) TableName() string {
 	return "form_fields"
 }
 
+// BeforeCreate keeps published form versions frozen.
+func (ff *FormFields) BeforeCreate(tx *gorm.DB) error {
+	return ff.ensureDraft(tx)
+}
+
+// BeforeUpdate keeps published form versions frozen.
+func (ff *FormFields) BeforeUpdate(tx *gorm.DB) error {
+	return ff.ensureDraft(tx)
+}
+
+// ensureDraft refuses changes to fields of published versions. Updates through
+// Model(&FormFields{}) skip the hooks, so callers that update that way check it
+// themselves.
+func (ff *FormFields) ensureDraft(tx *gorm.DB) error {
+	if ff.FormVersionID == nil {
+		return nil
+	}
+	version, err := GetFormVersionByID(tx.Session(&gorm.Session{NewDB: true}), *ff.FormVersionID)
+	if err != nil {
+		return err
+	}
+	if version.IsPublished() {
+		return ErrFormVersionPublished
+	}
+	return nil
+}
+
+// inPublishedVersion reports whether a published form version has a form field
+// whose column holds id.
+func inPublishedVersion(db *gorm.DB, column string, id uint) (bool, error) {
+	var count int64
+	err := db.Model(&FormFields{}).
+		Joins("JOIN form_versions ON form_versions.id = form_fields.form_version_id").
+		Where("form_fields."+column+" = ? AND form_versions.status = ?", id, FormVersionPublished).
+		Count(&count).Error
+	return count > 0, err
+}
+
 func CreateFormFields(db *gorm.DB, formField *FormFields) error {
 	return db.Create(formField).Error
 }
@@ -37,6 +80,46 @@ func UpdateFormFields(db *gorm.DB, formField *FormFields) error {
 	return db.Save(formField).Error
 }
 
+// SetFormFieldDependency points a draft field's options at the answer to
+// parentID, or frees them when parentID is nil.
+func SetFormFieldDependency(db *gorm.DB, formField *FormFields, parentID *uint) error {
+	if err := formField.ensureDraft(db); err != nil {
+		return err
+	}
+	formField.DependsOnFormFieldID = parentID
+	return db.Model(&FormFields{}).Where("id = ?", formField.ID).Update("depends_on_form_field_id", parentID).Error
+}
+
 func DeleteFormFields(db *gorm.DB, id uint) error {
 	return db.Delete(&FormFields{}, id).Error
 }
+
+func GetFormFieldsByVersionID(db *gorm.DB, formVersionID uint) ([]FormFields, error) {
+	var formFields []FormFields
+	err := db.Preload("Field.DataType").Where("form_version_id = ?", formVersionID).Order("id").Find(&formFields).Error
+	return formFields, err
+}
+
+// GetFieldPlacements returns the form fields of a version that place a catalog field, with the field loaded.
+func GetFieldPlacements(db *gorm.DB, formVersionID, fieldID uint) ([]FormFields, error) {
+	var formFields []FormFields
+	err := db.Preload("Field").Where("form_version_id = ? AND field_id = ?", formVersionID, fieldID).Order("id").Find(&formFields).Error
+	return formFields, err
+}
+
+func GetFormFieldsByIDs(db *gorm.DB, ids []uint) ([]FormFields, error) {
+	var formFields []FormFields
+	err := db.Preload("Field.DataType").Where("id IN ?", ids).Find(&formFields).Error
+	return formFields, err
+}
+
+// GetFormLayout returns the fields of a form version with their field, data
+// type and form group loaded, in row order.
+func GetFormLayout(db *gorm.DB, formVersionID uint) ([]FormFields, error) {
+	var formFields []FormFields
+	err := db.Preload("Field.DataType").Preload("FormGroup").
+		Where("form_version_id = ?", formVersionID).
+		Order("field_row").Order("id").
+		Find(&formFields).Error
+	return formFields, err
+}
@@ -28,3 +28,27 @@ func GetAllFormGroups(db *gorm.DB) ([]FormGroup, error) {
 	err := db.Find(&groups).Error
 	return groups, err
 }
+
+// UpdateFormGroup saves a form group, unless a published form version lays
+// fields out in it.
+func UpdateFormGroup(db *gorm.DB, formGroup *FormGroup) error {
+	published, err := inPublishedVersion(db, "form_group_id", formGroup.ID)
+	if err != nil {
+		return err
+	}
+	if published {
+		return ErrFormVersionPublished
+	}
+	return db.Save(formGroup).Error
+}
+
+func DeleteFormGroup(db *gorm.DB, id uint) error {
+	published, err := inPublishedVersion(db, "form_group_id", id)
+	if err != nil {
+		return err
+	}
+	if published {
+		return ErrFormVersionPublished
+	}
+	return db.Delete(&FormGroup{}, id).Error
+}
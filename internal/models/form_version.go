@@ -0,0 +1,236 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const (
+	FormVersionDraft     = "draft"
+	FormVersionPublished = "published"
+)
+
+// ErrFormVersionPublished is returned when a change would alter a published, and therefore frozen, form version.
+var ErrFormVersionPublished = errors.New("form version is published and cannot be changed")
+
+// FormVersion is one revision of a form's header and layout. A form has at most
+// one draft; published versions are immutable and submissions point at the
+// version they were filled against.
+type FormVersion struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement"`
+	FormID      uint       `gorm:"not null;uniqueIndex:idx_form_versions_form_version"`
+	Version     int        `gorm:"not null;uniqueIndex:idx_form_versions_form_version"`
+	Status      string     `gorm:"size:20;not null;default:draft"`
+	FormName    string     `gorm:"size:50;not null"`
+	Description string     `gorm:"size:250"`
+	CreatedOn   time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	PublishedOn *time.Time `gorm:"default:null"`
+
+	// Associations
+	Form Form `gorm:"foreignKey:FormID"`
+}
+
+func (FormVersion) TableName() string {
+	return "form_versions"
+}
+
+func (v *FormVersion) IsPublished() bool {
+	return v.Status == FormVersionPublished
+}
+
+func CreateFormVersion(db *gorm.DB, version *FormVersion) error {
+	return db.Create(version).Error
+}
+
+func GetFormVersionByID(db *gorm.DB, id uint) (*FormVersion, error) {
+	var version FormVersion
+	err := db.First(&version, id).Error
+	return &version, err
+}
+
+func GetFormVersion(db *gorm.DB, formID uint, number int) (*FormVersion, error) {
+	var version FormVersion
+	err := db.Where("form_id = ? AND version = ?", formID, number).First(&version).Error
+	return &version, err
+}
+
+// GetPublishedFormVersion returns the most recently published version of a form.
+func GetPublishedFormVersion(db *gorm.DB, formID uint) (*FormVersion, error) {
+	var version FormVersion
+	err := db.Where("form_id = ? AND status = ?", formID, FormVersionPublished).Order("version DESC").First(&version).Error
+	return &version, err
+}
+
+func GetDraftFormVersion(db *gorm.DB, formID uint) (*FormVersion, error) {
+	var version FormVersion
+	err := db.Where("form_id = ? AND status = ?", formID, FormVersionDraft).First(&version).Error
+	return &version, err
+}
+
+func GetLatestFormVersion(db *gorm.DB, formID uint) (*FormVersion, error) {
+	var version FormVersion
+	err := db.Where("form_id = ?", formID).Order("version DESC").First(&version).Error
+	return &version, err
+}
+
+func ListFormVersions(db *gorm.DB, formID uint) ([]FormVersion, error) {
+	var versions []FormVersion
+	err := db.Where("form_id = ?", formID).Order("version").Find(&versions).Error
+	return versions, err
+}
+
+func UpdateFormVersion(db *gorm.DB, version *FormVersion) error {
+	return db.Save(version).Error
+}
+
+// GetOrCreateDraftVersion returns the form's draft, creating one when there is
+// none. A new draft starts as a copy of the latest version's header and fields.
+// Run it inside a transaction so the copy is all-or-nothing. When a concurrent
+// edit creates the draft first, that draft is returned instead.
+func GetOrCreateDraftVersion(db *gorm.DB, form *Form) (*FormVersion, error) {
+	draft, err := GetDraftFormVersion(db, form.ID)
+	if err == nil {
+		return draft, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	draft = &FormVersion{
+		FormID:      form.ID,
+		Version:     1,
+		Status:      FormVersionDraft,
+		FormName:    form.FormName,
+		Description: form.Description,
+	}
+
+	latest, err := GetLatestFormVersion(db, form.ID)
+	switch {
+	case err == nil:
+		draft.Version = latest.Version + 1
+		draft.FormName = latest.FormName
+		draft.Description = latest.Description
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	// The savepoint keeps an outer transaction usable after losing the race
+	// on idx_form_versions_one_draft.
+	err = db.Transaction(func(tx *gorm.DB) error {
+		return CreateFormVersion(tx, draft)
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		if existing, readErr := GetDraftFormVersion(db, form.ID); readErr == nil {
+			return existing, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if latest.ID == 0 {
+		// A form that predates versioning: adopt its unversioned fields.
+		err := db.Model(&FormFields{}).
+			Where("form_id = ? AND form_version_id IS NULL", form.ID).
+			Update("form_version_id", draft.ID).Error
+		return draft, err
+	}
+
+	var fields []FormFields
+	if err := db.Where("form_version_id = ?", latest.ID).Order("id").Find(&fields).Error; err != nil {
+		return nil, err
+	}
+	clonedIDs := make(map[uint]uint, len(fields))
+	for _, ff := range fields {
+		clone := FormFields{
+			FormID:        ff.FormID,
+			FormVersionID: &draft.ID,
+			FieldID:       ff.FieldID,
+			FieldName:     ff.FieldName,
+			FormGroupID:   ff.FormGroupID,
+			Validation:    ff.Validation,
+			FieldSpan:     ff.FieldSpan,
+			FieldRow:      ff.FieldRow,
+		}
+		if err := CreateFormFields(db, &clone); err != nil {
+			return nil, err
+		}
+		clonedIDs[ff.ID] = clone.ID
+	}
+
+	// Point dependencies at the cloned parents rather than the old version's fields.
+	for _, ff := range fields {
+		if ff.DependsOnFormFieldID == nil {
+			continue
+		}
+		parentID, ok := clonedIDs[*ff.DependsOnFormFieldID]
+		if !ok {
+			continue
+		}
+		err := db.Model(&FormFields{}).Where("id = ?", clonedIDs[ff.ID]).Update("depends_on_form_field_id", parentID).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	return draft, nil
+}
+
+// PublishFormVersion freezes a draft and makes its header the form's current header.
+func PublishFormVersion(db *gorm.DB, version *FormVersion) error {
+	if version.IsPublished() {
+		return ErrFormVersionPublished
+	}
+
+	now := time.Now()
+	version.Status = FormVersionPublished
+	version.PublishedOn = &now
+	if err := UpdateFormVersion(db, version); err != nil {
+		return err
+	}
+
+	return db.Model(&Form{}).Where("id = ?", version.FormID).Updates(map[string]any{
+		"form_name":   version.FormName,
+		"description": version.Description,
+	}).Error
+}
+
+// BackfillFormVersions gives every form created before versioning a published
+// version 1 holding its existing fields, and links old submissions to it.
+func BackfillFormVersions(db *gorm.DB) error {
+	var forms []Form
+	err := db.Where("NOT EXISTS (SELECT 1 FROM form_versions v WHERE v.form_id = forms.id)").Find(&forms).Error
+	if err != nil {
+		return err
+	}
+
+	for _, form := range forms {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			now := time.Now()
+			version := &FormVersion{
+				FormID:      form.ID,
+				Version:     1,
+				Status:      FormVersionPublished,
+				FormName:    form.FormName,
+				Description: form.Description,
+				PublishedOn: &now,
+			}
+			if err := CreateFormVersion(tx, version); err != nil {
+				return err
+			}
+			return tx.Model(&FormFields{}).
+				Where("form_id = ? AND form_version_id IS NULL", form.ID).
+				Update("form_version_id", version.ID).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return db.Exec(`UPDATE submissions SET form_version_id = ff.form_version_id
+		FROM form_answers fa JOIN form_fields ff ON ff.id = fa.form_field_id
+		WHERE fa.submission_id = submissions.id AND submissions.form_version_id IS NULL`).Error
+}
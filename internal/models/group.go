@@ -35,3 +35,10 @@ func UpdateGroup(db *gorm.DB, group *Group) error {
 func DeleteGroup(db *gorm.DB, id uint) error {
 	return db.Delete(&Group{}, id).Error
 }
+
+// GetGroupByName finds a group by name, ignoring case.
+func GetGroupByName(db *gorm.DB, name string) (*Group, error) {
+	var group Group
+	err := db.Where("LOWER(group_name) = LOWER(?)", name).Order("id").First(&group).Error
+	return &group, err
+}
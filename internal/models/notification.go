@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Notification kinds, one email template each.
+const (
+	NotifySubmissionReceipt = "submission_receipt" // Tells the applicant their submission arrived
+	NotifyReviewRequested   = "review_requested"   // Tells the service's reviewers about a new submission
+	NotifyStatusChange      = "status_change"      // Tells the applicant their submission moved on
+	NotifyReservationExpiry = "reservation_expiry" // Tells the holder their name reservation lapsed
+)
+
+// NotificationKinds lists every kind of notification.
+var NotificationKinds = []string{NotifySubmissionReceipt, NotifyReviewRequested, NotifyStatusChange, NotifyReservationExpiry}
+
+// Notification statuses.
+const (
+	NotificationPending = "pending"
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed" // Gave up, after the maximum number of attempts or on an error retrying cannot fix
+)
+
+// Notification is an email queued for a user, written in the transaction that
+// causes it and rendered from its service's template when it is sent.
+type Notification struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement"`
+	Kind          string    `gorm:"size:50;not null"`
+	UserID        uint      `gorm:"not null"` // Recipient
+	ServiceID     *uint     // Whose template renders it; nil uses the default
+	Data          string    `gorm:"type:text;not null"` // What the template renders, as JSON
+	Status        string    `gorm:"size:20;not null;default:pending"`
+	Attempts      int       `gorm:"not null;default:0"`
+	NextAttemptOn time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	LastError     string    `gorm:"size:500"`
+	SentOn        *time.Time
+	CreatedOn     time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// NotificationTemplate replaces the default email of one kind for a service.
+// Subject and Body are Go text templates.
+type NotificationTemplate struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	ServiceID uint      `gorm:"not null;uniqueIndex:uni_notification_templates_service_kind"`
+	Kind      string    `gorm:"size:50;not null;uniqueIndex:uni_notification_templates_service_kind"`
+	Subject   string    `gorm:"size:255;not null"`
+	Body      string    `gorm:"type:text;not null"`
+	UpdatedOn time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func (NotificationTemplate) TableName() string {
+	return "notification_templates"
+}
+
+func CreateNotification(db *gorm.DB, notification *Notification) error {
+	return db.Create(notification).Error
+}
+
+// ClaimNotifications returns up to limit pending notifications due by now and
+// moves their next attempt to now+lease so that no other instance takes them
+// meanwhile. Should the claimer die, they are attempted again once the lease
+// ends.
+func ClaimNotifications(db *gorm.DB, now time.Time, lease time.Duration, limit int) ([]Notification, error) {
+	var notifications []Notification
+	err := db.Raw(`UPDATE notifications SET next_attempt_on = ?
+		WHERE id IN (
+			SELECT id FROM notifications
+			WHERE status = ? AND next_attempt_on <= ?
+			ORDER BY next_attempt_on
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(lease), NotificationPending, now, limit).Scan(&notifications).Error
+	return notifications, err
+}
+
+// RecordNotificationAttempt saves the outcome of an attempt to send.
+func RecordNotificationAttempt(db *gorm.DB, notification *Notification) error {
+	return db.Model(&Notification{}).Where("id = ?", notification.ID).Updates(map[string]any{
+		"status":          notification.Status,
+		"attempts":        notification.Attempts,
+		"next_attempt_on": notification.NextAttemptOn,
+		"last_error":      notification.LastError,
+		"sent_on":         notification.SentOn,
+	}).Error
+}
+
+func GetNotificationTemplate(db *gorm.DB, serviceID uint, kind string) (*NotificationTemplate, error) {
+	var template NotificationTemplate
+	err := db.Where("service_id = ? AND kind = ?", serviceID, kind).First(&template).Error
+	return &template, err
+}
+
+func GetNotificationTemplates(db *gorm.DB, serviceID uint) ([]NotificationTemplate, error) {
+	var templates []NotificationTemplate
+	err := db.Where("service_id = ?", serviceID).Order("kind").Find(&templates).Error
+	return templates, err
+}
+
+// SaveNotificationTemplate creates or replaces the service's template of the kind.
+func SaveNotificationTemplate(db *gorm.DB, template *NotificationTemplate) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "service_id"}, {Name: "kind"}},
+		DoUpdates: clause.AssignmentColumns([]string{"subject", "body", "updated_on"}),
+	}).Create(template).Error
+}
+
+func DeleteNotificationTemplate(db *gorm.DB, serviceID uint, kind string) error {
+	return db.Where("service_id = ? AND kind = ?", serviceID, kind).Delete(&NotificationTemplate{}).Error
+}
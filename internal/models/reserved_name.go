@@ -1,22 +1,190 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"kora_1/internal/names"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Reservation statuses. A held reservation keeps its name from everyone else
+// until it expires or is released.
+const (
+	ReservationHeld     = "held"
+	ReservationExpired  = "expired"
+	ReservationReleased = "released"
+)
+
+// ErrReservationNotHeld is returned when renewing or releasing a reservation
+// that has expired or been released.
+var ErrReservationNotHeld = errors.New("reservation is no longer held")
 
 type ReservedName struct {
-	ID           uint   `gorm:"primaryKey;autoIncrement"`
-	ReservedName string `gorm:"size:50"`
+	ID             uint   `gorm:"primaryKey;autoIncrement"`
+	ReservedName   string `gorm:"size:50"`
+	NormalizedName string `gorm:"size:255;not null;default:''"`
+	PhoneticKey    string `gorm:"size:255;not null;default:''"`
+
+	UserID     *uint      `gorm:"index"` // Who holds the name; nil for names reserved before reservations had owners
+	ServiceID  *uint      // The service the name is reserved for
+	Status     string     `gorm:"size:20;not null;default:held"`
+	ReservedOn time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	ExpiresOn  *time.Time // When the hold lapses; nil never lapses
+	ReleasedOn *time.Time
+
+	NeedsReview bool `gorm:"not null;default:false"` // The name uses a restricted term
+
+	// Associations
+	User    *User    `gorm:"foreignKey:UserID"`
+	Service *Service `gorm:"foreignKey:ServiceID"`
 }
 
 func (ReservedName) TableName() string {
 	return "reserved_names"
 }
 
-func CreateReservedName(db *gorm.DB, reservedName *ReservedName) error {
-	return db.Create(reservedName).Error
+// NewReservedName returns a held reservation of name, keyed for matching.
+func NewReservedName(name string) *ReservedName {
+	rn := &ReservedName{ReservedName: name, Status: ReservationHeld}
+	rn.SetKey()
+	return rn
+}
+
+// SetKey fills the keys the name is matched by from ReservedName.
+func (rn *ReservedName) SetKey() {
+	key := names.KeyOf(rn.ReservedName)
+	rn.NormalizedName, rn.PhoneticKey = key.Normalized, key.Phonetic
+}
+
+// Key returns the stored keys of the name.
+func (rn *ReservedName) Key() names.Key {
+	return names.Key{Normalized: rn.NormalizedName, Phonetic: rn.PhoneticKey}
+}
+
+// Lapsed reports whether a held reservation is past its expiry at now.
+func (rn *ReservedName) Lapsed(now time.Time) bool {
+	return rn.ExpiresOn != nil && !rn.ExpiresOn.After(now)
+}
+
+func GetReservedName(db *gorm.DB, id uint) (*ReservedName, error) {
+	var reservedName ReservedName
+	err := db.First(&reservedName, id).Error
+	return &reservedName, err
 }
 
-func GetSimilarNames(db *gorm.DB, name string) ([]ReservedName, error) {
+// ReserveName holds a name from its ReservedOn. Holds on the same normalised
+// name that lapsed by then are expired first, so they do not block it; a name
+// another reservation holds fails on idx_reserved_names_held_normalized_name.
+func ReserveName(db *gorm.DB, reservedName *ReservedName) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		_, err := expireReservedNames(tx, reservedName.ReservedOn, "normalized_name = @normalized", sql.Named("normalized", reservedName.NormalizedName))
+		if err != nil {
+			return err
+		}
+		return tx.Create(reservedName).Error
+	})
+}
+
+// RenewReservedName moves the expiry of a held, unexpired reservation to
+// expiresOn. Reservations that never lapse cannot be renewed.
+func RenewReservedName(db *gorm.DB, id uint, now, expiresOn time.Time) error {
+	result := db.Model(&ReservedName{}).
+		Where("id = ? AND status = ? AND expires_on > ?", id, ReservationHeld, now).
+		Update("expires_on", expiresOn)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReservationNotHeld
+	}
+	return nil
+}
+
+// ReleaseReservedName gives up a held reservation, freeing its name.
+func ReleaseReservedName(db *gorm.DB, id uint, now time.Time) error {
+	result := db.Model(&ReservedName{}).
+		Where("id = ? AND status = ?", id, ReservationHeld).
+		Updates(map[string]any{"status": ReservationReleased, "released_on": now})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReservationNotHeld
+	}
+	return nil
+}
+
+// ExpireReservedNames expires the held reservations that lapsed by now and
+// returns how many there were.
+func ExpireReservedNames(db *gorm.DB, now time.Time) (int64, error) {
+	return expireReservedNames(db, now, "true")
+}
+
+// expireReservedNames expires the held reservations matching where that
+// lapsed by now, and in the same statement queues a reservation_expiry
+// notification to each holder. where may use named arguments.
+func expireReservedNames(db *gorm.DB, now time.Time, where string, args ...any) (int64, error) {
+	args = append(args,
+		sql.Named("held", ReservationHeld), sql.Named("expired", ReservationExpired),
+		sql.Named("now", now), sql.Named("kind", NotifyReservationExpiry))
+	var expired int64
+	err := db.Raw(`WITH expired AS (
+			UPDATE reserved_names SET status = @expired
+			WHERE status = @held AND expires_on <= @now AND `+where+`
+			RETURNING id, reserved_name, user_id, service_id, expires_on
+		), queued AS (
+			INSERT INTO notifications (kind, user_id, service_id, data)
+			SELECT @kind, user_id, service_id,
+				json_build_object('reservation', json_build_object('id', id, 'name', reserved_name, 'expires_on', expires_on))::text
+			FROM expired WHERE user_id IS NOT NULL
+		)
+		SELECT count(*) FROM expired`, args...).Scan(&expired).Error
+	return expired, err
+}
+
+func DeleteReservedName(db *gorm.DB, id uint) error {
+	return db.Delete(&ReservedName{}, id).Error
+}
+
+// SimilarReservedNames returns up to limit names held at now that may clash
+// with key, most similar first: those whose normalised name is trigram-similar
+// or identical, and those that sound alike. Score them with names.Compare.
+func SimilarReservedNames(db *gorm.DB, key names.Key, now time.Time, limit int) ([]ReservedName, error) {
 	var reservedNames []ReservedName
-	err := db.Where("reserved_name LIKE ?", "%"+name+"%").Find(&reservedNames).Error
+	err := db.
+		Where("status = ? AND (expires_on IS NULL OR expires_on > ?)", ReservationHeld, now).
+		Where("(normalized_name % ? OR normalized_name = ? OR (phonetic_key <> '' AND phonetic_key = ?))", key.Normalized, key.Normalized, key.Phonetic).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "similarity(normalized_name, ?) DESC, id", Vars: []any{key.Normalized}}}).
+		Limit(limit).
+		Find(&reservedNames).Error
 	return reservedNames, err
 }
+
+// LockReservedNames keeps other transactions from reserving names until the
+// transaction db runs in ends. Reads are not blocked.
+func LockReservedNames(db *gorm.DB) error {
+	return db.Exec("LOCK TABLE reserved_names IN SHARE ROW EXCLUSIVE MODE").Error
+}
+
+// BackfillReservedNameKeys keys the reserved names stored before names were
+// matched by key.
+func BackfillReservedNameKeys(db *gorm.DB) error {
+	var pending []ReservedName
+	return db.Where("normalized_name = ''").FindInBatches(&pending, 500, func(tx *gorm.DB, batch int) error {
+		for i := range pending {
+			pending[i].SetKey()
+			err := tx.Model(&pending[i]).Updates(map[string]any{
+				"normalized_name": pending[i].NormalizedName,
+				"phonetic_key":    pending[i].PhoneticKey,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"kora_1/internal/names"
+
+	"gorm.io/gorm"
+)
+
+// RestrictedTerm is a word or phrase regulators limit the use of in names.
+type RestrictedTerm struct {
+	ID             uint      `gorm:"primaryKey;autoIncrement"`
+	Term           string    `gorm:"size:100;not null"`
+	NormalizedTerm string    `gorm:"size:100;not null;uniqueIndex:idx_restricted_terms_normalized_term"` // The words of Term, see names.Words
+	Severity       string    `gorm:"size:20;not null"`                                                   // names.Prohibited or names.Restricted
+	Reason         string    `gorm:"size:255"`                                                           // Why, or whose approval a restricted term needs
+	CreatedOn      time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func (RestrictedTerm) TableName() string {
+	return "restricted_terms"
+}
+
+// SetNormalizedTerm fills NormalizedTerm from Term.
+func (t *RestrictedTerm) SetNormalizedTerm() {
+	t.NormalizedTerm = strings.Join(names.Words(t.Term), " ")
+}
+
+func GetRestrictedTerm(db *gorm.DB, id uint) (*RestrictedTerm, error) {
+	var term RestrictedTerm
+	err := db.First(&term, id).Error
+	return &term, err
+}
+
+// GetRestrictedTerms returns every term, which names are checked against.
+func GetRestrictedTerms(db *gorm.DB) ([]RestrictedTerm, error) {
+	var terms []RestrictedTerm
+	err := db.Order("id").Find(&terms).Error
+	return terms, err
+}
+
+func CreateRestrictedTerm(db *gorm.DB, term *RestrictedTerm) error {
+	return db.Create(term).Error
+}
+
+func UpdateRestrictedTerm(db *gorm.DB, term *RestrictedTerm) error {
+	return db.Save(term).Error
+}
+
+func DeleteRestrictedTerm(db *gorm.DB, id uint) error {
+	return db.Delete(&RestrictedTerm{}, id).Error
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReviewerService assigns a reviewer to a service whose submissions they may review.
+type ReviewerService struct {
+	UserID    uint      `gorm:"primaryKey"`
+	ServiceID uint      `gorm:"primaryKey;index"`
+	CreatedOn time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+
+	// Associations
+	User    User    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Service Service `gorm:"foreignKey:ServiceID;constraint:OnDelete:CASCADE"`
+}
+
+func (ReviewerService) TableName() string {
+	return "reviewer_services"
+}
+
+// AssignReviewerService scopes a reviewer to a service. Assigning twice is a no-op.
+func AssignReviewerService(db *gorm.DB, userID, serviceID uint) error {
+	return db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&ReviewerService{UserID: userID, ServiceID: serviceID}).Error
+}
+
+func RemoveReviewerService(db *gorm.DB, userID, serviceID uint) error {
+	return db.Where("user_id = ? AND service_id = ?", userID, serviceID).Delete(&ReviewerService{}).Error
+}
+
+func GetReviewerServices(db *gorm.DB, userID uint) ([]Service, error) {
+	var services []Service
+	err := db.Joins("JOIN reviewer_services rs ON rs.service_id = services.id").
+		Where("rs.user_id = ?", userID).Order("services.id").Find(&services).Error
+	return services, err
+}
+
+func IsReviewerForService(db *gorm.DB, userID, serviceID uint) (bool, error) {
+	var count int64
+	err := db.Model(&ReviewerService{}).Where("user_id = ? AND service_id = ?", userID, serviceID).Count(&count).Error
+	return count > 0, err
+}
+
+// GetServiceReviewerIDs returns the IDs of the reviewers assigned to a service.
+func GetServiceReviewerIDs(db *gorm.DB, serviceID uint) ([]uint, error) {
+	var ids []uint
+	err := db.Model(&ReviewerService{}).Where("service_id = ?", serviceID).Order("user_id").Pluck("user_id", &ids).Error
+	return ids, err
+}
@@ -70,3 +70,10 @@ func UpdateService(db *gorm.DB, service *Service) error {
 func DeleteService(db *gorm.DB, id uint) error {
 	return db.Delete(&Service{}, id).Error
 }
+
+// GetServiceByName finds a service by name, ignoring case.
+func GetServiceByName(db *gorm.DB, name string) (*Service, error) {
+	var service Service
+	err := db.Where("LOWER(service_name) = LOWER(?)", name).Order("id").First(&service).Error
+	return &service, err
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SubmissionStatusHistory records each status change of a submission, who made it and why.
+type SubmissionStatusHistory struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement"`
+	SubmissionID uint      `gorm:"not null;index"`
+	FromStatus   string    `gorm:"size:20"` // Empty for the status a submission was created with
+	ToStatus     string    `gorm:"size:20;not null"`
+	Comment      string    `gorm:"size:1000"`
+	ChangedBy    *uint     `gorm:"index"`
+	ChangedOn    time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+
+	// Associations
+	Submission Submission `gorm:"foreignKey:SubmissionID;constraint:OnDelete:CASCADE"`
+	User       *User      `gorm:"foreignKey:ChangedBy"`
+}
+
+func (SubmissionStatusHistory) TableName() string {
+	return "submission_status_history"
+}
+
+func CreateSubmissionStatusHistory(db *gorm.DB, entry *SubmissionStatusHistory) error {
+	return db.Create(entry).Error
+}
+
+func GetSubmissionStatusHistory(db *gorm.DB, submissionID uint) ([]SubmissionStatusHistory, error) {
+	var history []SubmissionStatusHistory
+	err := db.Preload("User").Where("submission_id = ?", submissionID).Order("changed_on, id").Find(&history).Error
+	return history, err
+}
@@ -1,20 +1,33 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// ErrSubmissionStatusChanged is returned when a submission's status changed after it was read.
+var ErrSubmissionStatusChanged = errors.New("submission status has changed")
+
 type Submission struct {
-	ID         uint      `gorm:"primaryKey;autoIncrement"`
-	ServicesID *uint     `gorm:"index"`
-	CreatedBy  *uint     `gorm:"index"`
-	CreatedOn  time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	ID            uint      `gorm:"primaryKey;autoIncrement"`
+	ServicesID    *uint     `gorm:"index"`
+	FormVersionID *uint     `gorm:"index"` // The exact form version the applicant filled in
+	CreatedBy     *uint     `gorm:"index"`
+	Status        string    `gorm:"size:20;not null;default:submitted;index"` // Lifecycle status, see package workflow
+	CreatedOn     time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedOn     time.Time `gorm:"autoUpdateTime;default:CURRENT_TIMESTAMP"`
+
+	// IdempotencyKey is the client-supplied Idempotency-Key header, so a retried submit returns the original submission
+	IdempotencyKey *string `gorm:"size:100;uniqueIndex"`
+	// IdempotencyFingerprint is the SHA-256 of the request first sent with IdempotencyKey; a retry with another request is refused
+	IdempotencyFingerprint *string `gorm:"size:64"`
 
 	// Associations
-	Service *Service `gorm:"foreignKey:ServicesID"`
-	User    *User    `gorm:"foreignKey:CreatedBy"`
+	Service     *Service     `gorm:"foreignKey:ServicesID"`
+	FormVersion *FormVersion `gorm:"foreignKey:FormVersionID"`
+	User        *User        `gorm:"foreignKey:CreatedBy"`
 }
 
 func (Submission) TableName() string {
@@ -27,14 +40,109 @@ func CreateSubmission(db *gorm.DB, submission *Submission) error {
 
 func GetSubmission(db *gorm.DB, id uint) (*Submission, error) {
 	var submission Submission
-	err := db.Preload("Service").Preload("User").First(&submission, id).Error
+	err := db.Preload("Service").Preload("FormVersion").Preload("User").First(&submission, id).Error
+	return &submission, err
+}
+
+func GetSubmissionByIdempotencyKey(db *gorm.DB, key string) (*Submission, error) {
+	var submission Submission
+	err := db.Preload("FormVersion").Where("idempotency_key = ?", key).First(&submission).Error
 	return &submission, err
 }
 
+// GetSubmissionsByStatus lists a user's submissions in one status, most recently updated first.
+func GetSubmissionsByStatus(db *gorm.DB, userID uint, status string) ([]Submission, error) {
+	var submissions []Submission
+	err := db.Preload("FormVersion").
+		Where("created_by = ? AND status = ?", userID, status).
+		Order("updated_on DESC, id DESC").Find(&submissions).Error
+	return submissions, err
+}
+
+// TouchSubmission marks a submission as updated now.
+func TouchSubmission(db *gorm.DB, submission *Submission) error {
+	submission.UpdatedOn = time.Now()
+	return db.Model(&Submission{}).Where("id = ?", submission.ID).UpdateColumn("updated_on", submission.UpdatedOn).Error
+}
+
 func UpdateSubmission(db *gorm.DB, submission *Submission) error {
 	return db.Save(submission).Error
 }
 
+// UpdateSubmissionStatus moves a submission to a new status, provided no one
+// else has moved it since it was read.
+func UpdateSubmissionStatus(db *gorm.DB, submission *Submission, status string) error {
+	result := db.Model(&Submission{}).
+		Where("id = ? AND status = ?", submission.ID, submission.Status).
+		Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSubmissionStatusChanged
+	}
+	submission.Status = status
+	return nil
+}
+
 func DeleteSubmission(db *gorm.DB, id uint) error {
 	return db.Delete(&Submission{}, id).Error
 }
+
+// GetStaleSubmissionIDs lists the submissions in a status that were last changed before the given time.
+func GetStaleSubmissionIDs(db *gorm.DB, status string, before time.Time) ([]uint, error) {
+	var ids []uint
+	err := db.Model(&Submission{}).Where("status = ? AND updated_on < ?", status, before).Order("id").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// PurgeSubmissions deletes submissions with their answers, status history and
+// attachments, returning the attachments so their stored files can be removed.
+func PurgeSubmissions(db *gorm.DB, ids []uint) ([]Attachment, error) {
+	var attachments []Attachment
+	if len(ids) == 0 {
+		return attachments, nil
+	}
+	if err := db.Where("submission_id IN ?", ids).Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("submission_id IN ?", ids).Delete(&Attachment{}).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("submission_id IN ?", ids).Delete(&FormAnswer{}).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("submission_id IN ?", ids).Delete(&SubmissionStatusHistory{}).Error; err != nil {
+		return nil, err
+	}
+	return attachments, db.Delete(&Submission{}, ids).Error
+}
+
+// LoadSubmissionFormVersions fills in the FormVersion of each submission with
+// one query, for lists that were not loaded with Preload.
+func LoadSubmissionFormVersions(db *gorm.DB, submissions []Submission) error {
+	var ids []uint
+	for _, s := range submissions {
+		if s.FormVersionID != nil {
+			ids = append(ids, *s.FormVersionID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var versions []FormVersion
+	if err := db.Where("id IN ?", ids).Find(&versions).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint]*FormVersion, len(versions))
+	for i := range versions {
+		byID[versions[i].ID] = &versions[i]
+	}
+	for i := range submissions {
+		if submissions[i].FormVersionID != nil {
+			submissions[i].FormVersion = byID[*submissions[i].FormVersionID]
+		}
+	}
+	return nil
+}
@@ -6,6 +6,25 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	RoleAdmin     = "admin"
+	RoleDesigner  = "designer"
+	RoleReviewer  = "reviewer"
+	RoleApplicant = "applicant"
+)
+
+// Roles lists every role a user can hold.
+var Roles = []string{RoleAdmin, RoleDesigner, RoleReviewer, RoleApplicant}
+
+func IsValidRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
 type User struct {
 	ID         uint      `gorm:"primaryKey;autoIncrement"`
 	FirstName  string    `gorm:"size:100"`
@@ -14,12 +33,23 @@ type User struct {
 	Dob        time.Time `gorm:"type:date"`
 	Email      string    `gorm:"size:250;unique"`
 	Password   string    `gorm:"size:250"`
+	Role       string    `gorm:"size:20;not null;default:applicant"`
+
+	// TokenVersion is carried by the user's tokens; bumping it revokes every token issued before.
+	TokenVersion int `gorm:"not null;default:0"`
 }
 
 func (User) TableName() string {
 	return "users"
 }
 
+// SetPassword stores a new password hash and revokes the tokens issued while
+// the old password was in use.
+func (u *User) SetPassword(hash string) {
+	u.Password = hash
+	u.TokenVersion++
+}
+
 func CreateUser(db *gorm.DB, user *User) error {
 	return db.Create(user).Error
 }
@@ -40,6 +70,10 @@ func UpdateUser(db *gorm.DB, user *User) error {
 	return db.Save(user).Error
 }
 
+func UpdateUserRole(db *gorm.DB, id uint, role string) error {
+	return db.Model(&User{}).Where("id = ?", id).Update("role", role).Error
+}
+
 func DeleteUser(db *gorm.DB, id uint) error {
 	return db.Delete(&User{}, id).Error
 }
@@ -0,0 +1,212 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Webhook subscribes a URL to the events of a service.
+type Webhook struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	ServiceID uint      `gorm:"not null;index"`
+	URL       string    `gorm:"size:500;not null"`
+	Secret    string    `gorm:"size:100;not null"`            // Key of the HMAC signature of every payload
+	Events    string    `gorm:"size:255;not null;default:''"` // Comma-separated event names; empty subscribes to every event
+	Active    bool      `gorm:"not null;default:true"`
+	CreatedOn time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// EventList returns the events the webhook subscribes to, nil for all of them.
+func (w *Webhook) EventList() []string {
+	if w.Events == "" {
+		return nil
+	}
+	return strings.Split(w.Events, ",")
+}
+
+// SetEventList subscribes the webhook to events, or to every event when empty.
+func (w *Webhook) SetEventList(events []string) {
+	w.Events = strings.Join(events, ",")
+}
+
+// Subscribes reports whether the webhook wants event.
+func (w *Webhook) Subscribes(event string) bool {
+	return w.Events == "" || slices.Contains(w.EventList(), event)
+}
+
+// WebhookEvent is a row of the outbox: an event recorded in the transaction
+// that caused it and fanned out to the service's webhooks afterwards.
+type WebhookEvent struct {
+	ID           uint       `gorm:"primaryKey;autoIncrement"`
+	Event        string     `gorm:"size:50;not null"`
+	ServiceID    *uint      // Whose webhooks receive it; nil reaches none
+	Payload      string     `gorm:"type:text;not null"` // The event's data as JSON
+	CreatedOn    time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	DispatchedOn *time.Time // When deliveries were created for it
+}
+
+func (WebhookEvent) TableName() string {
+	return "webhook_events"
+}
+
+// Webhook delivery statuses.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed" // Gave up after the maximum number of attempts
+)
+
+// WebhookDelivery is one event sent to one webhook, with the outcome of the
+// latest attempt.
+type WebhookDelivery struct {
+	ID             uint      `gorm:"primaryKey;autoIncrement"`
+	WebhookID      uint      `gorm:"not null;index;uniqueIndex:uni_webhook_deliveries_webhook_event"`
+	EventID        uint      `gorm:"not null;uniqueIndex:uni_webhook_deliveries_webhook_event"`
+	Status         string    `gorm:"size:20;not null;default:pending"`
+	Attempts       int       `gorm:"not null;default:0"`
+	NextAttemptOn  time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	LastAttemptOn  *time.Time
+	ResponseStatus *int   // HTTP status of the latest attempt, nil when no response arrived
+	LastError      string `gorm:"size:500"`
+	DeliveredOn    *time.Time
+	CreatedOn      time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+
+	// Associations
+	Webhook *Webhook      `gorm:"foreignKey:WebhookID"`
+	Event   *WebhookEvent `gorm:"foreignKey:EventID"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func CreateWebhook(db *gorm.DB, webhook *Webhook) error {
+	return db.Create(webhook).Error
+}
+
+func GetWebhook(db *gorm.DB, id uint) (*Webhook, error) {
+	var webhook Webhook
+	err := db.First(&webhook, id).Error
+	return &webhook, err
+}
+
+func UpdateWebhook(db *gorm.DB, webhook *Webhook) error {
+	return db.Save(webhook).Error
+}
+
+func DeleteWebhook(db *gorm.DB, id uint) error {
+	return db.Delete(&Webhook{}, id).Error
+}
+
+func CreateWebhookEvent(db *gorm.DB, event *WebhookEvent) error {
+	return db.Create(event).Error
+}
+
+func GetWebhookDelivery(db *gorm.DB, id uint) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	err := db.Preload("Event").First(&delivery, id).Error
+	return &delivery, err
+}
+
+// FanOutWebhookEvents creates a pending delivery of up to limit undispatched
+// events for each active webhook of their service that subscribes to them,
+// and marks the events dispatched. It returns how many events it handled.
+// Instances running it together skip each other's events.
+func FanOutWebhookEvents(db *gorm.DB, now time.Time, limit int) (int, error) {
+	var handled int
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var events []WebhookEvent
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("dispatched_on IS NULL").Order("id").Limit(limit).Find(&events).Error
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if event.ServiceID != nil {
+				var webhooks []Webhook
+				if err := tx.Where("service_id = ? AND active", *event.ServiceID).Find(&webhooks).Error; err != nil {
+					return err
+				}
+				for _, webhook := range webhooks {
+					if !webhook.Subscribes(event.Event) {
+						continue
+					}
+					delivery := &WebhookDelivery{WebhookID: webhook.ID, EventID: event.ID, Status: DeliveryPending, NextAttemptOn: now}
+					if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(delivery).Error; err != nil {
+						return err
+					}
+				}
+			}
+			if err := tx.Model(&WebhookEvent{}).Where("id = ?", event.ID).Update("dispatched_on", now).Error; err != nil {
+				return err
+			}
+		}
+		handled = len(events)
+		return nil
+	})
+	return handled, err
+}
+
+// ClaimWebhookDeliveries returns up to limit pending deliveries due by now to
+// active webhooks, with their webhook and event, and moves their next attempt
+// to now+lease so that no other instance takes them meanwhile. Should the
+// claimer die, they are attempted again once the lease ends.
+func ClaimWebhookDeliveries(db *gorm.DB, now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error) {
+	var ids []uint
+	err := db.Raw(`UPDATE webhook_deliveries SET next_attempt_on = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_on <= ?
+				AND webhook_id IN (SELECT id FROM webhooks WHERE active)
+			ORDER BY next_attempt_on
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`, now.Add(lease), DeliveryPending, now, limit).Scan(&ids).Error
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	var deliveries []WebhookDelivery
+	err = db.Preload("Webhook").Preload("Event").Where("id IN ?", ids).Order("id").Find(&deliveries).Error
+	return deliveries, err
+}
+
+// RecordWebhookAttempt saves the outcome of an attempt to deliver.
+func RecordWebhookAttempt(db *gorm.DB, delivery *WebhookDelivery) error {
+	return db.Model(&WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]any{
+		"status":          delivery.Status,
+		"attempts":        delivery.Attempts,
+		"next_attempt_on": delivery.NextAttemptOn,
+		"last_attempt_on": delivery.LastAttemptOn,
+		"response_status": delivery.ResponseStatus,
+		"last_error":      delivery.LastError,
+		"delivered_on":    delivery.DeliveredOn,
+	}).Error
+}
+
+// RedeliverWebhookDelivery queues a delivery to be sent again at now, with a
+// fresh set of attempts, whatever became of it before.
+func RedeliverWebhookDelivery(db *gorm.DB, id uint, now time.Time) error {
+	result := db.Model(&WebhookDelivery{}).Where("id = ?", id).Updates(map[string]any{
+		"status":          DeliveryPending,
+		"attempts":        0,
+		"next_attempt_on": now,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
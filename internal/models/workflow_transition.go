@@ -0,0 +1,43 @@
+package models
+
+import "gorm.io/gorm"
+
+// WorkflowTransition is one transition of a service's configured submission
+// lifecycle. Services without any rows use workflow.Default.
+type WorkflowTransition struct {
+	ID              uint   `gorm:"primaryKey;autoIncrement"`
+	ServiceID       uint   `gorm:"not null;uniqueIndex:idx_workflow_transitions_unique"`
+	FromStatus      string `gorm:"size:20;not null;uniqueIndex:idx_workflow_transitions_unique"`
+	ToStatus        string `gorm:"size:20;not null;uniqueIndex:idx_workflow_transitions_unique"`
+	Actor           string `gorm:"size:20;not null;uniqueIndex:idx_workflow_transitions_unique"`
+	CommentRequired bool   `gorm:"not null;default:false"`
+
+	// Associations
+	Service Service `gorm:"foreignKey:ServiceID;constraint:OnDelete:CASCADE"`
+}
+
+func (WorkflowTransition) TableName() string {
+	return "workflow_transitions"
+}
+
+func GetWorkflowTransitions(db *gorm.DB, serviceID uint) ([]WorkflowTransition, error) {
+	var transitions []WorkflowTransition
+	err := db.Where("service_id = ?", serviceID).Order("id").Find(&transitions).Error
+	return transitions, err
+}
+
+// ReplaceWorkflowTransitions swaps a service's lifecycle for a new set of
+// transitions. Run it inside a transaction.
+func ReplaceWorkflowTransitions(db *gorm.DB, serviceID uint, transitions []WorkflowTransition) error {
+	if err := db.Where("service_id = ?", serviceID).Delete(&WorkflowTransition{}).Error; err != nil {
+		return err
+	}
+	if len(transitions) == 0 {
+		return nil
+	}
+	for i := range transitions {
+		transitions[i].ID = 0
+		transitions[i].ServiceID = serviceID
+	}
+	return db.Create(&transitions).Error
+}
@@ -0,0 +1,237 @@
+// Package names decides whether a business name is free to reserve. Names are
+// compared by a normalised form, which ignores case, accents, punctuation and
+// legal suffixes such as Ltd or PLC, and by a phonetic key, so that "Acme
+// Ltd", "ACME Limited" and "Akme" are all found to clash.
+//
+// Similarity is the trigram similarity of package pg_trgm, computed the same
+// way here so that candidates found by the database can be scored and the
+// in-memory repositories can search without Postgres.
+package names
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// legalSuffixes are dropped from the end of a name, however many follow one
+// another, and so are the qualifiers before them: "Acme Private Limited"
+// normalises to "acme" but "Friends of the Public" keeps its last word.
+var (
+	legalSuffixes = map[string]bool{
+		"ltd": true, "limited": true, "plc": true, "pvt": true, "pty": true,
+		"inc": true, "incorporated": true, "corp": true, "corporation": true, "co": true, "company": true,
+		"llc": true, "llp": true, "lp": true, "gmbh": true, "sa": true, "ag": true, "bv": true,
+	}
+	suffixQualifiers = map[string]bool{"public": true, "private": true}
+)
+
+// Key is what names are compared by.
+type Key struct {
+	Normalized string
+	Phonetic   string
+}
+
+// KeyOf returns the key of name.
+func KeyOf(name string) Key {
+	normalized := Normalize(name)
+	return Key{Normalized: normalized, Phonetic: Phonetic(normalized)}
+}
+
+// Normalize lower-cases name, strips accents and punctuation, drops a leading
+// "the" and trailing legal suffixes, and collapses whitespace. A name made up
+// only of such words keeps them.
+func Normalize(name string) string {
+	words := Words(name)
+	core := words
+	if len(core) > 1 && core[0] == "the" {
+		core = core[1:]
+	}
+	stripped := false
+	for len(core) > 0 && (legalSuffixes[core[len(core)-1]] || stripped && suffixQualifiers[core[len(core)-1]]) {
+		core = core[:len(core)-1]
+		stripped = true
+	}
+	if len(core) == 0 {
+		core = words
+	}
+	return strings.Join(core, " ")
+}
+
+// Words splits name into lower-case words without accents or punctuation.
+// It keeps every word, legal suffixes included.
+func Words(name string) []string {
+	name = strings.ReplaceAll(name, "&", " and ")
+	if folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), name); err == nil {
+		name = folded
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case r == '.' || r == '\'' || r == '’':
+			// Initials and possessives stay one word: "A.B.C." is "abc".
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Fields(b.String())
+}
+
+// Phonetic returns the Soundex code of each word of a normalised name. Words
+// of digits are kept as they are.
+func Phonetic(normalized string) string {
+	words := strings.Fields(normalized)
+	for i, w := range words {
+		words[i] = soundex(w)
+	}
+	return strings.Join(words, " ")
+}
+
+var soundexCodes = map[rune]byte{
+	'b': '1', 'f': '1', 'p': '1', 'v': '1',
+	'c': '2', 'g': '2', 'j': '2', 'k': '2', 'q': '2', 's': '2', 'x': '2', 'z': '2',
+	'd': '3', 't': '3',
+	'l': '4',
+	'm': '5', 'n': '5',
+	'r': '6',
+}
+
+// soundex is American Soundex: the first letter followed by three digits for
+// the consonants that follow, where h and w do not separate equal codes.
+func soundex(word string) string {
+	letters := []rune(word)
+	if len(letters) == 0 || letters[0] > unicode.MaxASCII || !unicode.IsLetter(letters[0]) {
+		return word
+	}
+
+	code := []byte{byte(unicode.ToUpper(letters[0]))}
+	last := soundexCodes[letters[0]]
+	for _, r := range letters[1:] {
+		if len(code) == 4 {
+			break
+		}
+		if r == 'h' || r == 'w' {
+			continue
+		}
+		digit, ok := soundexCodes[r]
+		if !ok {
+			last = 0
+			continue
+		}
+		if digit != last {
+			code = append(code, digit)
+		}
+		last = digit
+	}
+	for len(code) < 4 {
+		code = append(code, '0')
+	}
+	return string(code)
+}
+
+// Similarity is the trigram similarity of a and b as pg_trgm's similarity()
+// computes it: the shared trigrams of their words over all of them, from 0 to 1.
+func Similarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+func trigrams(s string) map[string]bool {
+	set := map[string]bool{}
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	for _, w := range words {
+		padded := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// Why a reserved name clashes with the one asked for.
+type Reason string
+
+const (
+	Identical       Reason = "identical"        // The same once normalised
+	SimilarSpelling Reason = "similar_spelling" // Trigram similarity of at least ConflictScore
+	SoundsAlike     Reason = "sounds_alike"     // The same phonetic key
+)
+
+const (
+	// ConflictScore is the lowest score reported as a conflict. It is also
+	// pg_trgm's default threshold for the % operator.
+	ConflictScore = 0.3
+	// SoundsAlikeScore is the least a name that sounds alike scores.
+	SoundsAlikeScore = 0.75
+	// UnavailableScore is the score from which a name is taken outright.
+	UnavailableScore = 0.9
+)
+
+// Match is how closely a reserved name resembles the one asked for.
+type Match struct {
+	Score   float64
+	Reasons []Reason
+}
+
+// Conflict reports whether the match is close enough to report.
+func (m Match) Conflict() bool {
+	return len(m.Reasons) > 0
+}
+
+// Compare scores a reserved name's key against the key of the name asked for.
+func Compare(name, reserved Key) Match {
+	if name.Normalized != "" && name.Normalized == reserved.Normalized {
+		return Match{Score: 1, Reasons: []Reason{Identical}}
+	}
+
+	var m Match
+	m.Score = Similarity(name.Normalized, reserved.Normalized)
+	if m.Score >= ConflictScore {
+		m.Reasons = append(m.Reasons, SimilarSpelling)
+	}
+	if name.Phonetic != "" && name.Phonetic == reserved.Phonetic {
+		m.Score = math.Max(m.Score, SoundsAlikeScore)
+		m.Reasons = append(m.Reasons, SoundsAlike)
+	}
+	m.Score = math.Round(m.Score*100) / 100
+	return m
+}
+
+// Verdict is whether a name can be reserved.
+type Verdict string
+
+const (
+	Available   Verdict = "available"   // Nothing resembles it
+	Review      Verdict = "review"      // It resembles a reserved name; someone should decide
+	Unavailable Verdict = "unavailable" // It is, or is all but, a reserved name
+)
+
+// Judge returns the verdict on a name given its matches against reserved names.
+func Judge(matches []Match) Verdict {
+	verdict := Available
+	for _, m := range matches {
+		switch {
+		case m.Score >= UnavailableScore:
+			return Unavailable
+		case m.Conflict():
+			verdict = Review
+		}
+	}
+	return verdict
+}
@@ -0,0 +1,123 @@
+package names
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	for name, want := range map[string]string{
+		"Acme Ltd":                    "acme",
+		"ACME Limited":                "acme",
+		"  acme   (pvt)  ltd. ":       "acme",
+		"Acme Private Limited":        "acme",
+		"The Acme Company":            "acme",
+		"A.B.C. Holdings PLC":         "abc holdings",
+		"Smith & Sons":                "smith and sons",
+		"O'Brien's Café":              "obriens cafe",
+		"Société Générale SA":         "societe generale",
+		"Friends of the Public":       "friends of the public",
+		"Limited":                     "limited",
+		"Lusaka 2024 Traders Co.":     "lusaka 2024 traders",
+		"Zambia Public Limited Comp.": "zambia public limited comp",
+	} {
+		if got := Normalize(name); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestPhonetic(t *testing.T) {
+	for name, want := range map[string]string{
+		"acme":          "A250",
+		"akme":          "A250",
+		"robert":        "R163",
+		"rupert":        "R163",
+		"ashcraft":      "A261",
+		"tymczak":       "T522",
+		"lusaka 2024":   "L220 2024",
+		"smith traders": "S530 T636",
+	} {
+		if got := Phonetic(name); got != want {
+			t.Errorf("Phonetic(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	// Values as returned by pg_trgm's similarity().
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"word", "two words", 4.0 / 11},
+		{"acme", "acme", 1},
+		{"acme", "", 0},
+		{"abc", "xyz", 0},
+	}
+	for _, tc := range tests {
+		if got := Similarity(tc.a, tc.b); got != tc.want {
+			t.Errorf("Similarity(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestCompareAndJudge(t *testing.T) {
+	name := KeyOf("Acme Ltd")
+	tests := []struct {
+		reserved string
+		reasons  []Reason
+		verdict  Verdict
+	}{
+		{"ACME Limited", []Reason{Identical}, Unavailable},
+		{"Akme", []Reason{SoundsAlike}, Review},
+		{"Acme Trading", []Reason{SimilarSpelling}, Review},
+		{"Zambezi Breweries", nil, Available},
+	}
+	for _, tc := range tests {
+		t.Run(tc.reserved, func(t *testing.T) {
+			m := Compare(name, KeyOf(tc.reserved))
+			if len(m.Reasons) != len(tc.reasons) {
+				t.Fatalf("got %+v, want reasons %v", m, tc.reasons)
+			}
+			for i := range m.Reasons {
+				if m.Reasons[i] != tc.reasons[i] {
+					t.Fatalf("got %+v, want reasons %v", m, tc.reasons)
+				}
+			}
+			if got := Judge([]Match{m}); got != tc.verdict {
+				t.Errorf("Judge = %s, want %s (score %v)", got, tc.verdict, m.Score)
+			}
+		})
+	}
+
+	if got := Judge(nil); got != Available {
+		t.Errorf("Judge(nil) = %s, want available", got)
+	}
+}
+
+func TestUses(t *testing.T) {
+	tests := []struct {
+		name, term string
+		want       bool
+	}{
+		{"Acme Bank Ltd", "bank", true},
+		{"ACME BANKING SERVICES", "Bank", true},
+		{"Acme Banks", "bank", true},
+		{"Banksia Florists", "bank", false},
+		{"Riverbank Farms", "bank", false},
+		{"Republic of Zambia Traders", "Republic of Zambia", true},
+		{"Zambia Republic Traders", "Republic of Zambia", false},
+		{"Government Supplies", "government", true},
+		{"Governmental Affairs Ltd", "government", true},
+		{"Acme", "", false},
+	}
+	for _, tc := range tests {
+		if got := Uses(tc.name, tc.term); got != tc.want {
+			t.Errorf("Uses(%q, %q) = %v, want %v", tc.name, tc.term, got, tc.want)
+		}
+	}
+}
+
+func TestWorse(t *testing.T) {
+	if Worse(Review, Available) != Review || Worse(Review, VerdictFor(Prohibited)) != Unavailable || VerdictFor(Restricted) != Review {
+		t.Error("verdicts are not ordered available < review < unavailable")
+	}
+}
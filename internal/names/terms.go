@@ -0,0 +1,69 @@
+package names
+
+import "strings"
+
+// Severity is how a restricted term limits the names that use it.
+type Severity string
+
+const (
+	Prohibited Severity = "prohibited" // Names using the term cannot be reserved
+	Restricted Severity = "restricted" // Names using the term need approval
+)
+
+// inflections are the endings a name's word may add to a term's last word and
+// still use it: "Banking" and "Banks" use "Bank".
+var inflections = []string{"", "s", "es", "er", "ers", "ing", "al"}
+
+// Uses reports whether name uses term: whether the words of term appear in
+// name in a row, the last of them possibly inflected. Case, accents and
+// punctuation do not matter.
+func Uses(name, term string) bool {
+	nameWords, termWords := Words(name), Words(term)
+	if len(termWords) == 0 {
+		return false
+	}
+	for start := 0; start+len(termWords) <= len(nameWords); start++ {
+		if usesAt(nameWords[start:start+len(termWords)], termWords) {
+			return true
+		}
+	}
+	return false
+}
+
+func usesAt(nameWords, termWords []string) bool {
+	last := len(termWords) - 1
+	for i, word := range termWords[:last] {
+		if nameWords[i] != word {
+			return false
+		}
+	}
+	ending, ok := strings.CutPrefix(nameWords[last], termWords[last])
+	if !ok {
+		return false
+	}
+	for _, inflection := range inflections {
+		if ending == inflection {
+			return true
+		}
+	}
+	return false
+}
+
+// VerdictFor returns the verdict a term of the severity imposes on the names
+// that use it.
+func VerdictFor(severity Severity) Verdict {
+	if severity == Prohibited {
+		return Unavailable
+	}
+	return Review
+}
+
+var verdictRank = map[Verdict]int{Available: 0, Review: 1, Unavailable: 2}
+
+// Worse returns the stricter of two verdicts.
+func Worse(a, b Verdict) Verdict {
+	if verdictRank[b] > verdictRank[a] {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,107 @@
+// Package notify emails users about their submissions and reservations.
+//
+// Enqueue writes a notification to a queue table in the transaction that
+// causes it, through a Queue bound to that transaction. A Sender later renders it with its service's template, or the
+// default one for its kind, and sends it through a Mailer, retrying failures
+// with exponential backoff. Templates are Go text templates executed with a
+// View.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"kora_1/internal/models"
+	"kora_1/internal/workflow"
+)
+
+// Data is what a notification's templates render besides its recipient and
+// service. Submission kinds set Submission; reservation_expiry sets Reservation.
+type Data struct {
+	Submission  *SubmissionData  `json:"submission,omitempty"`
+	Reservation *ReservationData `json:"reservation,omitempty"`
+}
+
+type SubmissionData struct {
+	ID         uint   `json:"id"`
+	FormName   string `json:"form_name"`
+	Status     string `json:"status"`
+	FromStatus string `json:"from_status,omitempty"` // Only for status_change
+	Comment    string `json:"comment,omitempty"`     // Only for status_change
+}
+
+type ReservationData struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	ExpiresOn time.Time `json:"expires_on"`
+}
+
+// Queue stores notifications until a Sender sends them.
+type Queue interface {
+	Enqueue(ctx context.Context, notification *models.Notification) error
+	// ServiceReviewers returns the IDs of the reviewers assigned to a service.
+	ServiceReviewers(ctx context.Context, serviceID uint) ([]uint, error)
+}
+
+// Enqueue queues a notification of the kind to a user. Pass a queue bound to
+// the transaction that makes the change it reports.
+func Enqueue(ctx context.Context, queue Queue, kind string, userID uint, serviceID *uint, data Data) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return queue.Enqueue(ctx, &models.Notification{Kind: kind, UserID: userID, ServiceID: serviceID, Data: string(encoded)})
+}
+
+// SubmissionChanged queues the emails a submission reaching its current
+// status causes. On being submitted, or filed out of draft into the status a
+// service's workflow submits to, the applicant gets a receipt and the
+// service's reviewers a review request. Any other change tells the applicant,
+// unless they made it. from is empty for a submission created submitted.
+func SubmissionChanged(ctx context.Context, queue Queue, submission *models.Submission, formName, from, comment string, actorID uint) error {
+	data := Data{Submission: &SubmissionData{ID: submission.ID, FormName: formName, Status: submission.Status}}
+
+	filed := from == "" || from == workflow.Draft || submission.Status == workflow.Submitted
+	if !filed {
+		if submission.CreatedBy == nil || *submission.CreatedBy == actorID {
+			return nil
+		}
+		data.Submission.FromStatus, data.Submission.Comment = from, comment
+		return Enqueue(ctx, queue, models.NotifyStatusChange, *submission.CreatedBy, submission.ServicesID, data)
+	}
+
+	if submission.CreatedBy != nil {
+		if err := Enqueue(ctx, queue, models.NotifySubmissionReceipt, *submission.CreatedBy, submission.ServicesID, data); err != nil {
+			return err
+		}
+	}
+	if submission.ServicesID == nil {
+		return nil
+	}
+	reviewers, err := queue.ServiceReviewers(ctx, *submission.ServicesID)
+	if err != nil {
+		return err
+	}
+	for _, reviewerID := range reviewers {
+		if err := Enqueue(ctx, queue, models.NotifyReviewRequested, reviewerID, submission.ServicesID, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const (
+	firstRetry = time.Minute
+	maxRetry   = 2 * time.Hour
+)
+
+// Backoff returns how long to wait after the attempts-th failed attempt:
+// a minute, doubling with each attempt, up to 2 hours.
+func Backoff(attempts int) time.Duration {
+	wait := firstRetry
+	for i := 1; i < attempts && wait < maxRetry; i++ {
+		wait *= 2
+	}
+	return min(wait, maxRetry)
+}
@@ -0,0 +1,199 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"kora_1/internal/config"
+	"kora_1/internal/models"
+)
+
+func TestDefaultTemplatesRender(t *testing.T) {
+	for _, kind := range models.NotificationKinds {
+		source, ok := Default(kind)
+		if !ok {
+			t.Fatalf("%s has no default template", kind)
+		}
+		tmpl, err := Parse(kind, source)
+		if err != nil {
+			t.Fatalf("%s: %v", kind, err)
+		}
+		subject, body, err := tmpl.Render(samples[kind])
+		if err != nil || subject == "" || strings.Contains(subject, "\n") || !strings.HasPrefix(body, "Hello Jane Banda,") {
+			t.Errorf("%s: got %q %q %v", kind, subject, body, err)
+		}
+	}
+
+	tmpl, _ := Parse(models.NotifyStatusChange, defaults[models.NotifyStatusChange])
+	subject, body, _ := tmpl.Render(samples[models.NotifyStatusChange])
+	if subject != "Your submission #42 is now under review" || !strings.Contains(body, "Comment: Looks complete") {
+		t.Errorf("status change: got %q\n%s", subject, body)
+	}
+}
+
+func TestParseRejectsTemplatesThatCannotRender(t *testing.T) {
+	for name, tc := range map[string]struct {
+		kind   string
+		source Source
+	}{
+		"syntax":                 {models.NotifySubmissionReceipt, Source{Subject: "{{.Submission.ID", Body: "x"}},
+		"unknown field":          {models.NotifySubmissionReceipt, Source{Subject: "x", Body: "{{.Submission.Reference}}"}},
+		"data of another kind":   {models.NotifyReservationExpiry, Source{Subject: "x", Body: "{{.Submission.ID}}"}},
+		"unknown kind":           {"newsletter", Source{Subject: "x", Body: "x"}},
+		"unknown function":       {models.NotifyStatusChange, Source{Subject: "{{upper .Submission.Status}}", Body: "x"}},
+		"method with bad layout": {models.NotifyReservationExpiry, Source{Subject: "x", Body: "{{.Reservation.ExpiresOn.Format}}"}},
+	} {
+		if _, err := Parse(tc.kind, tc.source); err == nil {
+			t.Errorf("%s: parsed", name)
+		}
+	}
+}
+
+func TestSettle(t *testing.T) {
+	now := time.Now()
+
+	n := &models.Notification{Status: models.NotificationPending}
+	settle(n, now, errors.New("connection refused"), 3)
+	if n.Status != models.NotificationPending || n.Attempts != 1 || !n.NextAttemptOn.Equal(now.Add(time.Minute)) {
+		t.Errorf("after a first failure: %+v", n)
+	}
+	settle(n, now, nil, 3)
+	if n.Status != models.NotificationSent || n.SentOn == nil || n.LastError != "" {
+		t.Errorf("after success: %+v", n)
+	}
+
+	n = &models.Notification{Status: models.NotificationPending}
+	settle(n, now, permanentError{errors.New("recipient no longer exists")}, 3)
+	if n.Status != models.NotificationFailed || n.Attempts != 1 {
+		t.Errorf("after a permanent error: %+v", n)
+	}
+
+	n = &models.Notification{Status: models.NotificationPending}
+	for range 3 {
+		settle(n, now, errors.New("connection refused"), 3)
+	}
+	if n.Status != models.NotificationFailed {
+		t.Errorf("after the last attempt: %+v", n)
+	}
+
+	n = &models.Notification{Status: models.NotificationPending}
+	settle(n, now, errors.New(strings.Repeat("x", maxErrorLength-1)+"ü rejected"), 3)
+	if len(n.LastError) != maxErrorLength-1 || !utf8.ValidString(n.LastError) {
+		t.Errorf("long error: got %d bytes, want %d of valid UTF-8", len(n.LastError), maxErrorLength-1)
+	}
+}
+
+// smtpServer accepts one SMTP conversation on a local port and sends what
+// it received on the returned channel.
+func smtpServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r, w := bufio.NewReader(conn), conn
+		var log strings.Builder
+		io.WriteString(w, "220 localhost ready\r\n")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			log.WriteString(line)
+			switch verb := strings.ToUpper(strings.Fields(line + " x")[0]); verb {
+			case "EHLO", "HELO":
+				io.WriteString(w, "250 localhost\r\n")
+			case "DATA":
+				io.WriteString(w, "354 go ahead\r\n")
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					log.WriteString(line)
+				}
+				io.WriteString(w, "250 queued\r\n")
+			case "QUIT":
+				io.WriteString(w, "221 bye\r\n")
+				received <- log.String()
+				return
+			default:
+				io.WriteString(w, "250 ok\r\n")
+			}
+		}
+	}()
+	return ln.Addr().String(), received
+}
+
+func TestSMTPMailerSends(t *testing.T) {
+	addr, received := smtpServer(t)
+	host, port, _ := net.SplitHostPort(addr)
+	portNumber, _ := strconv.Atoi(port)
+
+	mailer, err := NewMailer(config.Mail{Host: host, Port: portNumber, From: "Kora <no-reply@example.com>", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = mailer.Send(context.Background(), Message{
+		To:      mail.Address{Name: "Jane Banda", Address: "jane@example.com"},
+		Subject: "Submission #42 reçue",
+		Body:    "Hello Jane,\n\nWe received submission #42.\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got string
+	select {
+	case got = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server received nothing")
+	}
+	for _, want := range []string{
+		"MAIL FROM:<no-reply@example.com>",
+		"RCPT TO:<jane@example.com>",
+		`To: "Jane Banda" <jane@example.com>`,
+		"Subject: =?utf-8?q?Submission_#42_re=C3=A7ue?=",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("conversation lacks %q:\n%s", want, got)
+		}
+	}
+	_, encoded, _ := strings.Cut(got, "\r\n\r\n")
+	body, _ := io.ReadAll(quotedprintable.NewReader(strings.NewReader(encoded)))
+	if !strings.Contains(string(body), "We received submission #42.") {
+		t.Errorf("body: %q", body)
+	}
+}
+
+func TestNewMailerLogsWithoutHost(t *testing.T) {
+	mailer, err := NewMailer(config.Default().Mail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := mailer.(LogMailer); !ok {
+		t.Errorf("got %T, want LogMailer", mailer)
+	}
+}
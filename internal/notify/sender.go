@@ -0,0 +1,166 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"kora_1/internal/config"
+	"kora_1/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// batchSize is how many notifications a pass claims at a time.
+const batchSize = 20
+
+// Sender sends queued notifications. Several instances may run against one
+// database; each notification is claimed by one of them at a time.
+type Sender struct {
+	db          *gorm.DB
+	mailer      Mailer
+	maxAttempts int
+	lease       time.Duration
+}
+
+// NewSender returns a sender using db, mailer and the mail settings.
+func NewSender(db *gorm.DB, mailer Mailer, cfg config.Mail) *Sender {
+	return &Sender{
+		db:          db,
+		mailer:      mailer,
+		maxAttempts: cfg.MaxAttempts,
+		// A batch is sent one email at a time.
+		lease: batchSize*cfg.Timeout + time.Minute,
+	}
+}
+
+// Run sends the notifications that are due.
+func (s *Sender) Run(ctx context.Context) error {
+	db := s.db.WithContext(ctx)
+	for ctx.Err() == nil {
+		notifications, err := models.ClaimNotifications(db, time.Now(), s.lease, batchSize)
+		if err != nil {
+			return err
+		}
+		for i := range notifications {
+			if err := s.attempt(ctx, db, &notifications[i]); err != nil {
+				return err
+			}
+		}
+		if len(notifications) < batchSize {
+			break
+		}
+	}
+	return nil
+}
+
+// attempt sends notification once and records the outcome.
+func (s *Sender) attempt(ctx context.Context, db *gorm.DB, notification *models.Notification) error {
+	msg, err := compose(db, notification)
+	if err == nil {
+		err = s.mailer.Send(ctx, msg)
+	}
+	settle(notification, time.Now(), err, s.maxAttempts)
+	return models.RecordNotificationAttempt(db, notification)
+}
+
+// permanentError marks failures that retrying cannot fix.
+type permanentError struct{ error }
+
+// compose renders notification for its recipient with its service's
+// template, or the default one.
+func compose(db *gorm.DB, notification *models.Notification) (Message, error) {
+	user, err := models.GetUser(db, notification.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Message{}, permanentError{errors.New("recipient no longer exists")}
+	}
+	if err != nil {
+		return Message{}, err
+	}
+
+	view := View{Recipient: Recipient{Name: fullName(user), Email: user.Email}}
+	if err := json.Unmarshal([]byte(notification.Data), &view.Data); err != nil {
+		return Message{}, permanentError{err}
+	}
+
+	source, _ := Default(notification.Kind)
+	if notification.ServiceID != nil {
+		service, err := models.GetServiceByID(db, *notification.ServiceID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return Message{}, err
+		}
+		if err == nil {
+			view.Service = service.ServiceName
+		}
+
+		custom, err := models.GetNotificationTemplate(db, *notification.ServiceID, notification.Kind)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return Message{}, err
+		}
+		if err == nil {
+			source = Source{Subject: custom.Subject, Body: custom.Body}
+		}
+	}
+
+	t, err := Parse(notification.Kind, source)
+	if err != nil {
+		return Message{}, permanentError{err}
+	}
+	subject, body, err := t.Render(view)
+	if err != nil {
+		return Message{}, permanentError{err}
+	}
+	return Message{To: mail.Address{Name: view.Recipient.Name, Address: user.Email}, Subject: subject, Body: body}, nil
+}
+
+func fullName(user *models.User) string {
+	name := strings.Join(strings.Fields(user.FirstName+" "+user.Surname), " ")
+	if name == "" {
+		return user.Email
+	}
+	return name
+}
+
+// maxErrorLength is the size of notifications.last_error.
+const maxErrorLength = 500
+
+// lastError returns err's message as valid UTF-8 cut to at most
+// maxErrorLength bytes, without splitting a character. SMTP replies may
+// quote the recipient's non-ASCII address.
+func lastError(err error) string {
+	message := strings.ToValidUTF8(err.Error(), "\uFFFD")
+	if len(message) <= maxErrorLength {
+		return message
+	}
+	end := maxErrorLength
+	for end > 0 && !utf8.RuneStart(message[end]) {
+		end--
+	}
+	return message[:end]
+}
+
+// settle applies the outcome of an attempt made at now to notification: it
+// was sent, is retried after Backoff, or failed for good once maxAttempts
+// have been made or the error is permanent.
+func settle(notification *models.Notification, now time.Time, err error, maxAttempts int) {
+	notification.Attempts++
+	if err == nil {
+		notification.Status = models.NotificationSent
+		notification.SentOn = &now
+		notification.LastError = ""
+		return
+	}
+
+	notification.LastError = lastError(err)
+	var permanent permanentError
+	if notification.Attempts >= maxAttempts || errors.As(err, &permanent) {
+		notification.Status = models.NotificationFailed
+		return
+	}
+	notification.Status = models.NotificationPending
+	notification.NextAttemptOn = now.Add(Backoff(notification.Attempts))
+}
@@ -0,0 +1,127 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"kora_1/internal/models"
+)
+
+// View is what templates are executed with: {{.Recipient.Name}},
+// {{.Service}}, {{.Submission.ID}}, {{.Reservation.ExpiresOn}} and so on.
+type View struct {
+	Recipient Recipient
+	Service   string // Name of the service, empty without one
+	Data
+}
+
+type Recipient struct {
+	Name  string // Full name, or the email address for users without one
+	Email string
+}
+
+// Source is the text of a template.
+type Source struct {
+	Subject string
+	Body    string
+}
+
+var defaults = map[string]Source{
+	models.NotifySubmissionReceipt: {
+		Subject: `We received your submission #{{.Submission.ID}}`,
+		Body: `Hello {{.Recipient.Name}},
+
+We received your {{with .Submission.FormName}}{{.}} {{end}}submission #{{.Submission.ID}}{{with .Service}} to {{.}}{{end}}. We will email you when its status changes.
+`,
+	},
+	models.NotifyReviewRequested: {
+		Subject: `Submission #{{.Submission.ID}} is waiting for review`,
+		Body: `Hello {{.Recipient.Name}},
+
+Submission #{{.Submission.ID}}{{with .Submission.FormName}} ({{.}}){{end}}{{with .Service}} to {{.}}{{end}} was submitted and is waiting for review.
+`,
+	},
+	models.NotifyStatusChange: {
+		Subject: `Your submission #{{.Submission.ID}} is now {{humanize .Submission.Status}}`,
+		Body: `Hello {{.Recipient.Name}},
+
+Your {{with .Submission.FormName}}{{.}} {{end}}submission #{{.Submission.ID}}{{with .Service}} to {{.}}{{end}} moved from {{humanize .Submission.FromStatus}} to {{humanize .Submission.Status}}.
+{{with .Submission.Comment}}
+Comment: {{.}}
+{{end}}`,
+	},
+	models.NotifyReservationExpiry: {
+		Subject: `Your reservation of {{.Reservation.Name}} has expired`,
+		Body: `Hello {{.Recipient.Name}},
+
+Your reservation of the name {{.Reservation.Name}}{{with .Service}} for {{.}}{{end}} expired on {{.Reservation.ExpiresOn.Format "2 January 2006"}}. Others may now reserve it; reserve it again if you still need it.
+`,
+	},
+}
+
+// Default returns the template used for kind when a service has none.
+func Default(kind string) (Source, bool) {
+	source, ok := defaults[kind]
+	return source, ok
+}
+
+var funcs = template.FuncMap{
+	// humanize turns a status such as under_review into "under review".
+	"humanize": func(s string) string { return strings.ReplaceAll(s, "_", " ") },
+}
+
+// Template is a parsed template of one kind.
+type Template struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// Parse parses a template of the kind and renders it once with sample data,
+// so that fields the kind does not have are reported now rather than when
+// sending.
+func Parse(kind string, source Source) (*Template, error) {
+	sample, ok := samples[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification kind %q", kind)
+	}
+	subject, err := template.New("subject").Funcs(funcs).Parse(source.Subject)
+	if err != nil {
+		return nil, err
+	}
+	body, err := template.New("body").Funcs(funcs).Parse(source.Body)
+	if err != nil {
+		return nil, err
+	}
+	t := &Template{subject: subject, body: body}
+	if _, _, err := t.Render(sample); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Render returns the subject, on one line, and the body of an email.
+func (t *Template) Render(view View) (string, string, error) {
+	var subject, body strings.Builder
+	if err := t.subject.Execute(&subject, view); err != nil {
+		return "", "", err
+	}
+	if err := t.body.Execute(&body, view); err != nil {
+		return "", "", err
+	}
+	return strings.Join(strings.Fields(subject.String()), " "), body.String(), nil
+}
+
+var (
+	sampleRecipient  = Recipient{Name: "Jane Banda", Email: "jane@example.com"}
+	sampleSubmission = &SubmissionData{ID: 42, FormName: "Business registration", Status: "under_review", FromStatus: "submitted", Comment: "Looks complete"}
+	samples          = map[string]View{
+		models.NotifySubmissionReceipt: {Recipient: sampleRecipient, Service: "Business names", Data: Data{Submission: sampleSubmission}},
+		models.NotifyReviewRequested:   {Recipient: sampleRecipient, Service: "Business names", Data: Data{Submission: sampleSubmission}},
+		models.NotifyStatusChange:      {Recipient: sampleRecipient, Service: "Business names", Data: Data{Submission: sampleSubmission}},
+		models.NotifyReservationExpiry: {Recipient: sampleRecipient, Service: "Business names", Data: Data{
+			Reservation: &ReservationData{ID: 7, Name: "Acme Trading", ExpiresOn: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)},
+		}},
+	}
+)
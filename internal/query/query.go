@@ -0,0 +1,399 @@
+// Package query turns the query string of a list endpoint into a filtered,
+// sorted and paginated GORM query.
+//
+//	?limit=20&offset=40            offset pagination
+//	?limit=20&cursor=<next_cursor> cursor pagination, continuing from meta.next_cursor
+//	?sort=-created_on,id           sort fields, "-" for descending
+//	?status=draft,returned         filters declared by the endpoint's Spec
+//
+// Cursor pagination keeps its place while rows are inserted and is cheaper on
+// deep pages, but supports a single sort field (plus the ID tie-break).
+package query
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"kora_1/internal/structs"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+const dateLayout = "2006-01-02"
+
+// ErrInvalid marks errors caused by the query string rather than the database.
+var ErrInvalid = errors.New("invalid list query")
+
+// Op says how a filter compares its column with the given value.
+type Op int
+
+const (
+	Equal    Op = iota // Column equals one of the comma-separated values
+	Contains           // Column contains the value, ignoring case
+	From               // Column is on or after the date or time
+	To                 // Column is on or before the date or time; a date includes the whole day
+)
+
+// Kind is the type filter values are parsed as.
+type Kind int
+
+const (
+	String Kind = iota
+	Int
+	Bool
+	Time
+)
+
+// Filter maps a query parameter onto a column.
+type Filter struct {
+	Column string
+	Op     Op
+	Kind   Kind
+}
+
+// Spec declares what a list endpoint lets clients sort and filter by.
+type Spec struct {
+	Sorts       map[string]string // Sort parameter name to column
+	DefaultSort string            // Used without a sort parameter, e.g. "-created_on"
+	Filters     map[string]Filter // Query parameter name to filter
+}
+
+// Sort is one ORDER BY term.
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// Params is a parsed list query.
+type Params struct {
+	Limit  int
+	Offset int
+	Sorts  []Sort
+
+	cursor     *cursor
+	conditions []condition
+}
+
+type condition struct {
+	sql  string
+	args []any
+}
+
+// cursor points just past the last row of a page.
+type cursor struct {
+	Sort  string    `json:"s"`
+	Kind  string    `json:"k"`
+	Str   string    `json:"v,omitempty"`
+	Int   int64     `json:"i,omitempty"`
+	Float float64   `json:"f,omitempty"`
+	Time  time.Time `json:"t,omitzero"`
+	ID    uint64    `json:"id"`
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalid, fmt.Sprintf(format, args...))
+}
+
+// Parse reads limit, offset, cursor, sort and the Spec's filters from values.
+// Parameters the Spec does not mention are ignored.
+func Parse(values url.Values, spec Spec) (Params, error) {
+	p := Params{Limit: DefaultLimit}
+
+	if raw := values.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > MaxLimit {
+			return p, invalid("limit must be between 1 and %d", MaxLimit)
+		}
+		p.Limit = n
+	}
+	if raw := values.Get("offset"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return p, invalid("offset must be a non-negative integer")
+		}
+		p.Offset = n
+	}
+
+	sortParam := values.Get("sort")
+	if sortParam == "" {
+		sortParam = spec.DefaultSort
+	}
+	for _, name := range strings.Split(sortParam, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		desc := strings.HasPrefix(name, "-")
+		column, ok := spec.Sorts[strings.TrimPrefix(name, "-")]
+		if !ok {
+			return p, invalid("cannot sort by %q, use one of %s", strings.TrimPrefix(name, "-"), strings.Join(sortedKeys(spec.Sorts), ", "))
+		}
+		p.Sorts = append(p.Sorts, Sort{Column: column, Desc: desc})
+	}
+	if len(p.Sorts) == 0 || p.Sorts[len(p.Sorts)-1].Column != "id" {
+		desc := len(p.Sorts) > 0 && p.Sorts[len(p.Sorts)-1].Desc
+		p.Sorts = append(p.Sorts, Sort{Column: "id", Desc: desc})
+	}
+
+	if raw := values.Get("cursor"); raw != "" {
+		if values.Get("offset") != "" {
+			return p, invalid("use either offset or cursor, not both")
+		}
+		if len(p.Sorts) > 2 {
+			return p, invalid("cursor pagination supports a single sort field")
+		}
+		c, err := decodeCursor(raw)
+		if err != nil || c.Sort != sortKey(p.Sorts[0]) {
+			return p, invalid("cursor is invalid or was issued for another sort")
+		}
+		p.cursor = c
+	}
+
+	for _, name := range sortedKeys(spec.Filters) {
+		raw := strings.TrimSpace(values.Get(name))
+		if raw == "" {
+			continue
+		}
+		cond, err := parseFilter(name, raw, spec.Filters[name])
+		if err != nil {
+			return p, err
+		}
+		p.conditions = append(p.conditions, cond)
+	}
+	return p, nil
+}
+
+func parseFilter(name, raw string, f Filter) (condition, error) {
+	switch f.Op {
+	case Equal:
+		var list []any
+		for _, part := range strings.Split(raw, ",") {
+			part = strings.TrimSpace(part)
+			value, err := parseValue(part, f.Kind)
+			if err != nil {
+				return condition{}, invalid("%s: %v", name, err)
+			}
+			list = append(list, value)
+		}
+		return condition{sql: f.Column + " IN ?", args: []any{list}}, nil
+	case Contains:
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(raw)
+		return condition{sql: f.Column + " ILIKE ?", args: []any{"%" + escaped + "%"}}, nil
+	case From, To:
+		t, dateOnly, err := parseTime(raw)
+		if err != nil {
+			return condition{}, invalid("%s: %v", name, err)
+		}
+		if f.Op == From {
+			return condition{sql: f.Column + " >= ?", args: []any{t}}, nil
+		}
+		if dateOnly {
+			return condition{sql: f.Column + " < ?", args: []any{t.AddDate(0, 0, 1)}}, nil
+		}
+		return condition{sql: f.Column + " <= ?", args: []any{t}}, nil
+	}
+	return condition{}, fmt.Errorf("filter %s has an unknown operator", name)
+}
+
+func parseValue(raw string, kind Kind) (any, error) {
+	switch kind {
+	case Int:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", raw)
+		}
+		return n, nil
+	case Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not true or false", raw)
+		}
+		return b, nil
+	case Time:
+		t, _, err := parseTime(raw)
+		return t, err
+	}
+	return raw, nil
+}
+
+func parseTime(raw string) (time.Time, bool, error) {
+	if t, err := time.Parse(dateLayout, raw); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%q is not a YYYY-MM-DD date or RFC 3339 time", raw)
+	}
+	return t, false, nil
+}
+
+// Find loads one page of rows into dest. The filters of p are added to db,
+// which may already carry conditions of its own, such as access scoping.
+func Find[T any](db *gorm.DB, p Params, dest *[]T) (structs.Meta, error) {
+	meta := structs.Meta{Limit: p.Limit, Offset: p.Offset}
+
+	filtered := db.Model(new(T))
+	for _, cond := range p.conditions {
+		filtered = filtered.Where(cond.sql, cond.args...)
+	}
+	filtered = filtered.Session(&gorm.Session{})
+
+	if err := filtered.Count(&meta.Total).Error; err != nil {
+		return meta, err
+	}
+
+	page := filtered
+	for _, s := range p.Sorts {
+		page = page.Order(clause.OrderByColumn{Column: clause.Column{Name: s.Column}, Desc: s.Desc})
+	}
+	if p.cursor != nil {
+		meta.Offset = 0
+		page = page.Where(p.cursor.condition(p.Sorts[0]))
+	} else if p.Offset > 0 {
+		page = page.Offset(p.Offset)
+	}
+	if err := page.Limit(p.Limit + 1).Find(dest).Error; err != nil {
+		return meta, err
+	}
+
+	if len(*dest) > p.Limit {
+		*dest = (*dest)[:p.Limit]
+		if len(p.Sorts) <= 2 {
+			next, err := nextCursor(db, p.Sorts[0], (*dest)[p.Limit-1])
+			if err != nil {
+				return meta, err
+			}
+			meta.NextCursor = next
+		}
+	}
+	return meta, nil
+}
+
+func (c *cursor) condition(s Sort) clause.Expression {
+	op := ">"
+	if s.Desc {
+		op = "<"
+	}
+	if s.Column == "id" {
+		return clause.Expr{SQL: "id " + op + " ?", Vars: []any{c.ID}}
+	}
+	var value any
+	switch c.Kind {
+	case "s":
+		value = c.Str
+	case "i":
+		value = c.Int
+	case "f":
+		value = c.Float
+	case "t":
+		value = c.Time
+	}
+	return clause.Expr{
+		SQL:  fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", s.Column, op),
+		Vars: []any{value, value, c.ID},
+	}
+}
+
+var schemaCache sync.Map
+
+// nextCursor builds the cursor that continues after row.
+func nextCursor[T any](db *gorm.DB, s Sort, row T) (string, error) {
+	sch, err := schema.Parse(new(T), &schemaCache, db.NamingStrategy)
+	if err != nil {
+		return "", err
+	}
+	rv := reflect.ValueOf(row)
+	ctx := context.Background()
+
+	c := cursor{Sort: sortKey(s)}
+	idField := sch.LookUpField("id")
+	if idField == nil {
+		return "", nil
+	}
+	id, _ := idField.ValueOf(ctx, rv)
+	idValue := reflect.Indirect(reflect.ValueOf(id))
+	if !idValue.CanUint() {
+		return "", nil
+	}
+	c.ID = idValue.Uint()
+
+	if s.Column != "id" {
+		field := sch.LookUpField(s.Column)
+		if field == nil {
+			return "", nil
+		}
+		value, _ := field.ValueOf(ctx, rv)
+		v := reflect.ValueOf(value)
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return "", nil // Keyset pagination cannot continue past NULL
+			}
+			v = v.Elem()
+		}
+		switch {
+		case v.Type() == reflect.TypeOf(time.Time{}):
+			c.Kind, c.Time = "t", v.Interface().(time.Time)
+		case v.Kind() == reflect.String:
+			c.Kind, c.Str = "s", v.String()
+		case v.CanInt():
+			c.Kind, c.Int = "i", v.Int()
+		case v.CanUint():
+			c.Kind, c.Int = "i", int64(v.Uint())
+		case v.CanFloat():
+			c.Kind, c.Float = "f", v.Float()
+		default:
+			return "", nil
+		}
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(raw string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, err
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func sortKey(s Sort) string {
+	if s.Desc {
+		return "-" + s.Column
+	}
+	return s.Column
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
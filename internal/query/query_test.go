@@ -0,0 +1,124 @@
+package query
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var spec = Spec{
+	Sorts:       map[string]string{"id": "id", "name": "item_name", "created_on": "created_on"},
+	DefaultSort: "-created_on",
+	Filters: map[string]Filter{
+		"name":         {Column: "item_name", Op: Contains},
+		"status":       {Column: "status", Op: Equal},
+		"active":       {Column: "active", Op: Equal, Kind: Bool},
+		"created_by":   {Column: "created_by", Op: Equal, Kind: Int},
+		"created_from": {Column: "created_on", Op: From, Kind: Time},
+		"created_to":   {Column: "created_on", Op: To, Kind: Time},
+	},
+}
+
+type item struct {
+	ID        uint
+	ItemName  string
+	CreatedOn time.Time
+}
+
+func TestParseDefaults(t *testing.T) {
+	p, err := Parse(url.Values{}, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Limit != DefaultLimit || p.Offset != 0 {
+		t.Errorf("limit, offset = %d, %d", p.Limit, p.Offset)
+	}
+	want := []Sort{{Column: "created_on", Desc: true}, {Column: "id", Desc: true}}
+	if !reflect.DeepEqual(p.Sorts, want) {
+		t.Errorf("sorts = %+v, want %+v", p.Sorts, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	values, _ := url.ParseQuery("active=true&limit=10&offset=20&sort=name,-id&name=50%25_off&status=draft,returned&created_by=3&created_to=2026-01-31&ignored=1")
+	p, err := Parse(values, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Limit != 10 || p.Offset != 20 {
+		t.Errorf("limit, offset = %d, %d", p.Limit, p.Offset)
+	}
+	if want := []Sort{{Column: "item_name"}, {Column: "id", Desc: true}}; !reflect.DeepEqual(p.Sorts, want) {
+		t.Errorf("sorts = %+v, want %+v", p.Sorts, want)
+	}
+	want := []condition{
+		{sql: "active IN ?", args: []any{[]any{true}}},
+		{sql: "created_by IN ?", args: []any{[]any{int64(3)}}},
+		{sql: "created_on < ?", args: []any{time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)}},
+		{sql: "item_name ILIKE ?", args: []any{`%50\%\_off%`}},
+		{sql: "status IN ?", args: []any{[]any{"draft", "returned"}}},
+	}
+	if !reflect.DeepEqual(p.conditions, want) {
+		t.Errorf("conditions = %+v, want %+v", p.conditions, want)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, raw := range []string{
+		"limit=0",
+		"limit=201",
+		"offset=-1",
+		"sort=password",
+		"created_by=me",
+		"active=maybe",
+		"created_from=yesterday",
+		"cursor=not-a-cursor",
+		"sort=name,created_on&cursor=e30",
+		"offset=5&cursor=e30",
+	} {
+		values, _ := url.ParseQuery(raw)
+		if _, err := Parse(values, spec); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalid", raw, err)
+		}
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	db := &gorm.DB{Config: &gorm.Config{NamingStrategy: schema.NamingStrategy{}}}
+	created := time.Date(2026, 3, 4, 5, 6, 7, 123456000, time.UTC)
+	row := item{ID: 42, ItemName: "Lusaka", CreatedOn: created}
+
+	token, err := nextCursor(db, Sort{Column: "created_on", Desc: true}, row)
+	if err != nil || token == "" {
+		t.Fatalf("nextCursor = %q, %v", token, err)
+	}
+	p, err := Parse(url.Values{"cursor": {token}}, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.cursor.ID != 42 || !p.cursor.Time.Equal(created) {
+		t.Errorf("cursor = %+v", p.cursor)
+	}
+
+	// A cursor issued for one sort is refused for another.
+	if _, err := Parse(url.Values{"cursor": {token}, "sort": {"name"}}, spec); !errors.Is(err, ErrInvalid) {
+		t.Errorf("cursor accepted for a different sort: %v", err)
+	}
+
+	token, err = nextCursor(db, Sort{Column: "item_name"}, row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err = Parse(url.Values{"cursor": {token}, "sort": {"name"}}, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.cursor.Kind != "s" || p.cursor.Str != "Lusaka" || p.cursor.ID != 42 {
+		t.Errorf("cursor = %+v", p.cursor)
+	}
+}
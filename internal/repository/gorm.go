@@ -0,0 +1,597 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"kora_1/internal/collectionio"
+	"kora_1/internal/models"
+	"kora_1/internal/names"
+	"kora_1/internal/query"
+	"kora_1/internal/structs"
+
+	"gorm.io/gorm"
+)
+
+// NewGorm returns repositories backed by db.
+func NewGorm(db *gorm.DB) Repositories {
+	return Repositories{
+		UnitOfWork:    gormUnitOfWork{db},
+		Forms:         gormForms{db},
+		FormGroups:    gormFormGroups{db},
+		Groups:        gormGroups{db},
+		DataTypes:     gormDataTypes{db},
+		Submissions:   gormSubmissions{db},
+		Users:         gormUsers{db},
+		Collections:   gormCollections{db},
+		Services:      gormServices{db},
+		Names:         gormReservedNames{db},
+		Terms:         gormRestrictedTerms{db},
+		Webhooks:      gormWebhooks{db},
+		Notifications: gormNotifications{db},
+		Templates:     gormNotificationTemplates{db},
+	}
+}
+
+type gormUnitOfWork struct{ db *gorm.DB }
+
+func (u gormUnitOfWork) Transaction(ctx context.Context, fn func(tx Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewGorm(tx))
+	})
+}
+
+// found maps gorm's missing-row error onto ErrNotFound.
+func found[T any](row *T, err error) (*T, error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+func list[T any](db *gorm.DB, params query.Params) ([]T, structs.Meta, error) {
+	var rows []T
+	meta, err := query.Find(db, params, &rows)
+	return rows, meta, err
+}
+
+type gormReservedNames struct{ db *gorm.DB }
+
+func (r gormReservedNames) Get(ctx context.Context, id uint) (*models.ReservedName, error) {
+	return found(models.GetReservedName(r.db.WithContext(ctx), id))
+}
+
+func (r gormReservedNames) List(ctx context.Context, holderID *uint, params query.Params) ([]models.ReservedName, structs.Meta, error) {
+	db := r.db.WithContext(ctx)
+	if holderID != nil {
+		db = db.Where("user_id = ?", *holderID)
+	}
+	return list[models.ReservedName](db, params)
+}
+
+func (r gormReservedNames) Reserve(ctx context.Context, name *models.ReservedName) error {
+	return models.ReserveName(r.db.WithContext(ctx), name)
+}
+
+func (r gormReservedNames) Renew(ctx context.Context, id uint, now, expiresOn time.Time) error {
+	return models.RenewReservedName(r.db.WithContext(ctx), id, now, expiresOn)
+}
+
+func (r gormReservedNames) Release(ctx context.Context, id uint, now time.Time) error {
+	return models.ReleaseReservedName(r.db.WithContext(ctx), id, now)
+}
+
+func (r gormReservedNames) Delete(ctx context.Context, id uint) error {
+	return models.DeleteReservedName(r.db.WithContext(ctx), id)
+}
+
+func (r gormReservedNames) Expire(ctx context.Context, now time.Time) (int64, error) {
+	return models.ExpireReservedNames(r.db.WithContext(ctx), now)
+}
+
+func (r gormReservedNames) Similar(ctx context.Context, key names.Key, now time.Time, limit int) ([]models.ReservedName, error) {
+	return models.SimilarReservedNames(r.db.WithContext(ctx), key, now, limit)
+}
+
+func (r gormReservedNames) Lock(ctx context.Context) error {
+	return models.LockReservedNames(r.db.WithContext(ctx))
+}
+
+type gormWebhooks struct{ db *gorm.DB }
+
+func (r gormWebhooks) Get(ctx context.Context, id uint) (*models.Webhook, error) {
+	return found(models.GetWebhook(r.db.WithContext(ctx), id))
+}
+
+func (r gormWebhooks) List(ctx context.Context, serviceID uint, params query.Params) ([]models.Webhook, structs.Meta, error) {
+	return list[models.Webhook](r.db.WithContext(ctx).Where("service_id = ?", serviceID), params)
+}
+
+func (r gormWebhooks) Create(ctx context.Context, webhook *models.Webhook) error {
+	return models.CreateWebhook(r.db.WithContext(ctx), webhook)
+}
+
+func (r gormWebhooks) Update(ctx context.Context, webhook *models.Webhook) error {
+	return models.UpdateWebhook(r.db.WithContext(ctx), webhook)
+}
+
+func (r gormWebhooks) Delete(ctx context.Context, id uint) error {
+	return models.DeleteWebhook(r.db.WithContext(ctx), id)
+}
+
+func (r gormWebhooks) GetDelivery(ctx context.Context, id uint) (*models.WebhookDelivery, error) {
+	return found(models.GetWebhookDelivery(r.db.WithContext(ctx), id))
+}
+
+func (r gormWebhooks) Deliveries(ctx context.Context, webhookID uint, params query.Params) ([]models.WebhookDelivery, structs.Meta, error) {
+	return list[models.WebhookDelivery](r.db.WithContext(ctx).Preload("Event").Where("webhook_id = ?", webhookID), params)
+}
+
+func (r gormWebhooks) Redeliver(ctx context.Context, id uint, now time.Time) error {
+	err := models.RedeliverWebhookDelivery(r.db.WithContext(ctx), id, now)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r gormWebhooks) Enqueue(ctx context.Context, event *models.WebhookEvent) error {
+	return models.CreateWebhookEvent(r.db.WithContext(ctx), event)
+}
+
+type gormNotifications struct{ db *gorm.DB }
+
+func (r gormNotifications) Enqueue(ctx context.Context, notification *models.Notification) error {
+	return models.CreateNotification(r.db.WithContext(ctx), notification)
+}
+
+func (r gormNotifications) ServiceReviewers(ctx context.Context, serviceID uint) ([]uint, error) {
+	return models.GetServiceReviewerIDs(r.db.WithContext(ctx), serviceID)
+}
+
+type gormNotificationTemplates struct{ db *gorm.DB }
+
+func (r gormNotificationTemplates) Get(ctx context.Context, serviceID uint, kind string) (*models.NotificationTemplate, error) {
+	return found(models.GetNotificationTemplate(r.db.WithContext(ctx), serviceID, kind))
+}
+
+func (r gormNotificationTemplates) List(ctx context.Context, serviceID uint) ([]models.NotificationTemplate, error) {
+	return models.GetNotificationTemplates(r.db.WithContext(ctx), serviceID)
+}
+
+func (r gormNotificationTemplates) Save(ctx context.Context, template *models.NotificationTemplate) error {
+	return models.SaveNotificationTemplate(r.db.WithContext(ctx), template)
+}
+
+func (r gormNotificationTemplates) Delete(ctx context.Context, serviceID uint, kind string) error {
+	return models.DeleteNotificationTemplate(r.db.WithContext(ctx), serviceID, kind)
+}
+
+type gormRestrictedTerms struct{ db *gorm.DB }
+
+func (r gormRestrictedTerms) Get(ctx context.Context, id uint) (*models.RestrictedTerm, error) {
+	return found(models.GetRestrictedTerm(r.db.WithContext(ctx), id))
+}
+
+func (r gormRestrictedTerms) List(ctx context.Context, params query.Params) ([]models.RestrictedTerm, structs.Meta, error) {
+	return list[models.RestrictedTerm](r.db.WithContext(ctx), params)
+}
+
+func (r gormRestrictedTerms) All(ctx context.Context) ([]models.RestrictedTerm, error) {
+	return models.GetRestrictedTerms(r.db.WithContext(ctx))
+}
+
+func (r gormRestrictedTerms) Create(ctx context.Context, term *models.RestrictedTerm) error {
+	return models.CreateRestrictedTerm(r.db.WithContext(ctx), term)
+}
+
+func (r gormRestrictedTerms) Update(ctx context.Context, term *models.RestrictedTerm) error {
+	return models.UpdateRestrictedTerm(r.db.WithContext(ctx), term)
+}
+
+func (r gormRestrictedTerms) Delete(ctx context.Context, id uint) error {
+	return models.DeleteRestrictedTerm(r.db.WithContext(ctx), id)
+}
+
+type gormServices struct{ db *gorm.DB }
+
+func (r gormServices) Get(ctx context.Context, id uint) (*models.Service, error) {
+	return found(models.GetServiceByID(r.db.WithContext(ctx), id))
+}
+
+func (r gormServices) List(ctx context.Context, params query.Params) ([]models.Service, structs.Meta, error) {
+	return list[models.Service](r.db.WithContext(ctx), params)
+}
+
+func (r gormServices) Create(ctx context.Context, service *models.Service) error {
+	return service.Create(r.db.WithContext(ctx))
+}
+
+func (r gormServices) Update(ctx context.Context, service *models.Service) error {
+	return models.UpdateService(r.db.WithContext(ctx), service)
+}
+
+func (r gormServices) Delete(ctx context.Context, id uint) error {
+	return models.DeleteService(r.db.WithContext(ctx), id)
+}
+
+func (r gormServices) Workflow(ctx context.Context, serviceID uint) ([]models.WorkflowTransition, error) {
+	return models.GetWorkflowTransitions(r.db.WithContext(ctx), serviceID)
+}
+
+func (r gormServices) SetWorkflow(ctx context.Context, serviceID uint, transitions []models.WorkflowTransition) error {
+	return models.ReplaceWorkflowTransitions(r.db.WithContext(ctx), serviceID, transitions)
+}
+
+type gormUsers struct{ db *gorm.DB }
+
+func (r gormUsers) Get(ctx context.Context, id uint) (*models.User, error) {
+	return found(models.GetUser(r.db.WithContext(ctx), id))
+}
+
+func (r gormUsers) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return found(models.GetUserByEmail(r.db.WithContext(ctx), email))
+}
+
+func (r gormUsers) List(ctx context.Context, params query.Params) ([]models.User, structs.Meta, error) {
+	return list[models.User](r.db.WithContext(ctx), params)
+}
+
+func (r gormUsers) Create(ctx context.Context, user *models.User) error {
+	return models.CreateUser(r.db.WithContext(ctx), user)
+}
+
+func (r gormUsers) Update(ctx context.Context, user *models.User) error {
+	return models.UpdateUser(r.db.WithContext(ctx), user)
+}
+
+func (r gormUsers) UpdateRole(ctx context.Context, id uint, role string) error {
+	return models.UpdateUserRole(r.db.WithContext(ctx), id, role)
+}
+
+func (r gormUsers) Delete(ctx context.Context, id uint) error {
+	return models.DeleteUser(r.db.WithContext(ctx), id)
+}
+
+func (r gormUsers) ReviewerServices(ctx context.Context, userID uint) ([]models.Service, error) {
+	return models.GetReviewerServices(r.db.WithContext(ctx), userID)
+}
+
+func (r gormUsers) IsReviewer(ctx context.Context, userID, serviceID uint) (bool, error) {
+	return models.IsReviewerForService(r.db.WithContext(ctx), userID, serviceID)
+}
+
+func (r gormUsers) AssignReviewer(ctx context.Context, userID, serviceID uint) error {
+	return models.AssignReviewerService(r.db.WithContext(ctx), userID, serviceID)
+}
+
+func (r gormUsers) RemoveReviewer(ctx context.Context, userID, serviceID uint) error {
+	return models.RemoveReviewerService(r.db.WithContext(ctx), userID, serviceID)
+}
+
+type gormCollections struct{ db *gorm.DB }
+
+func (r gormCollections) Get(ctx context.Context, id uint) (*models.Collection, error) {
+	return found(models.GetCollection(r.db.WithContext(ctx), id))
+}
+
+func (r gormCollections) List(ctx context.Context, params query.Params) ([]models.Collection, structs.Meta, error) {
+	return list[models.Collection](r.db.WithContext(ctx), params)
+}
+
+func (r gormCollections) Create(ctx context.Context, collection *models.Collection) error {
+	return models.CreateCollection(r.db.WithContext(ctx), collection)
+}
+
+func (r gormCollections) Update(ctx context.Context, collection *models.Collection) error {
+	return r.db.WithContext(ctx).Save(collection).Error
+}
+
+func (r gormCollections) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Collection{}, id).Error
+}
+
+func (r gormCollections) GetItem(ctx context.Context, id uint) (*models.CollectionItem, error) {
+	return found(models.GetCollectionItem(r.db.WithContext(ctx), id))
+}
+
+func (r gormCollections) Items(ctx context.Context, collectionID uint, parentID *uint) ([]models.CollectionItem, error) {
+	return models.GetCollectionItems(r.db.WithContext(ctx), collectionID, parentID)
+}
+
+func (r gormCollections) Children(ctx context.Context, parentIDs []uint) ([]models.CollectionItem, error) {
+	return models.GetChildCollectionItems(r.db.WithContext(ctx), parentIDs)
+}
+
+func (r gormCollections) CreateItem(ctx context.Context, item *models.CollectionItem) error {
+	return models.CreateCollectionItem(r.db.WithContext(ctx), item)
+}
+
+func (r gormCollections) UpdateItem(ctx context.Context, item *models.CollectionItem) error {
+	return r.db.WithContext(ctx).Save(item).Error
+}
+
+func (r gormCollections) DeleteItem(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.CollectionItem{}, id).Error
+}
+
+func (r gormCollections) ItemCodeTaken(ctx context.Context, collectionID uint, code string, exceptID uint) (bool, error) {
+	return models.CollectionItemCodeTaken(r.db.WithContext(ctx), collectionID, code, exceptID)
+}
+
+func (r gormCollections) ItemsIn(ctx context.Context, collectionIDs []uint) ([]models.CollectionItem, error) {
+	return models.GetCollectionItemsByCollectionIDs(r.db.WithContext(ctx), collectionIDs)
+}
+
+func (r gormCollections) Import(ctx context.Context, collectionID uint, rows []collectionio.Row) (collectionio.Result, error) {
+	return collectionio.Import(r.db.WithContext(ctx), collectionID, rows)
+}
+
+func (r gormCollections) Export(ctx context.Context, collectionID uint) ([]collectionio.Row, error) {
+	return collectionio.Export(r.db.WithContext(ctx), collectionID)
+}
+
+type gormForms struct{ db *gorm.DB }
+
+func (r gormForms) Get(ctx context.Context, id uint) (*models.Form, error) {
+	return found(models.GetForm(r.db.WithContext(ctx), id))
+}
+
+func (r gormForms) List(ctx context.Context, params query.Params) ([]models.Form, structs.Meta, error) {
+	return list[models.Form](r.db.WithContext(ctx), params)
+}
+
+func (r gormForms) Create(ctx context.Context, form *models.Form) error {
+	_, err := models.CreateForm(r.db.WithContext(ctx), form)
+	return err
+}
+
+func (r gormForms) Update(ctx context.Context, form *models.Form) error {
+	return models.UpdateForm(r.db.WithContext(ctx), form)
+}
+
+func (r gormForms) Delete(ctx context.Context, id uint) error {
+	return models.DeleteForm(r.db.WithContext(ctx), id)
+}
+
+func (r gormForms) Versions(ctx context.Context, formID uint) ([]models.FormVersion, error) {
+	return models.ListFormVersions(r.db.WithContext(ctx), formID)
+}
+
+func (r gormForms) Version(ctx context.Context, formID uint, number int) (*models.FormVersion, error) {
+	return found(models.GetFormVersion(r.db.WithContext(ctx), formID, number))
+}
+
+func (r gormForms) VersionByID(ctx context.Context, id uint) (*models.FormVersion, error) {
+	return found(models.GetFormVersionByID(r.db.WithContext(ctx), id))
+}
+
+func (r gormForms) PublishedVersion(ctx context.Context, formID uint) (*models.FormVersion, error) {
+	return found(models.GetPublishedFormVersion(r.db.WithContext(ctx), formID))
+}
+
+func (r gormForms) DraftVersion(ctx context.Context, formID uint) (*models.FormVersion, error) {
+	return found(models.GetDraftFormVersion(r.db.WithContext(ctx), formID))
+}
+
+func (r gormForms) OpenDraft(ctx context.Context, form *models.Form) (*models.FormVersion, error) {
+	return models.GetOrCreateDraftVersion(r.db.WithContext(ctx), form)
+}
+
+func (r gormForms) UpdateVersion(ctx context.Context, version *models.FormVersion) error {
+	return models.UpdateFormVersion(r.db.WithContext(ctx), version)
+}
+
+func (r gormForms) Publish(ctx context.Context, version *models.FormVersion) error {
+	return models.PublishFormVersion(r.db.WithContext(ctx), version)
+}
+
+func (r gormForms) Layout(ctx context.Context, versionID uint) ([]models.FormFields, error) {
+	return models.GetFormLayout(r.db.WithContext(ctx), versionID)
+}
+
+func (r gormForms) VersionFields(ctx context.Context, versionID uint) ([]models.FormFields, error) {
+	return models.GetFormFieldsByVersionID(r.db.WithContext(ctx), versionID)
+}
+
+func (r gormForms) Placements(ctx context.Context, versionID, fieldID uint) ([]models.FormFields, error) {
+	return models.GetFieldPlacements(r.db.WithContext(ctx), versionID, fieldID)
+}
+
+func (r gormForms) FormField(ctx context.Context, id uint) (*models.FormFields, error) {
+	return found(models.GetFormFields(r.db.WithContext(ctx), id))
+}
+
+func (r gormForms) FormFields(ctx context.Context, ids []uint) ([]models.FormFields, error) {
+	return models.GetFormFieldsByIDs(r.db.WithContext(ctx), ids)
+}
+
+func (r gormForms) CreateFormField(ctx context.Context, formField *models.FormFields) error {
+	return models.CreateFormFields(r.db.WithContext(ctx), formField)
+}
+
+func (r gormForms) SetDependency(ctx context.Context, formField *models.FormFields, parentID *uint) error {
+	return models.SetFormFieldDependency(r.db.WithContext(ctx), formField, parentID)
+}
+
+func (r gormForms) Field(ctx context.Context, id uint) (*models.Field, error) {
+	return found(models.GetFields(r.db.WithContext(ctx), id))
+}
+
+func (r gormForms) Fields(ctx context.Context, params query.Params) ([]models.Field, structs.Meta, error) {
+	return list[models.Field](r.db.WithContext(ctx), params)
+}
+
+func (r gormForms) CreateField(ctx context.Context, field *models.Field) error {
+	return models.CreateFields(r.db.WithContext(ctx), field)
+}
+
+func (r gormForms) UpdateField(ctx context.Context, field *models.Field) error {
+	err := models.UpdateFields(r.db.WithContext(ctx), field)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r gormForms) DeleteField(ctx context.Context, id uint) error {
+	return models.DeleteFields(r.db.WithContext(ctx), id)
+}
+
+type gormFormGroups struct{ db *gorm.DB }
+
+func (r gormFormGroups) Get(ctx context.Context, id uint) (*models.FormGroup, error) {
+	return found(models.GetFormGroup(r.db.WithContext(ctx), id))
+}
+
+func (r gormFormGroups) List(ctx context.Context, params query.Params) ([]models.FormGroup, structs.Meta, error) {
+	return list[models.FormGroup](r.db.WithContext(ctx), params)
+}
+
+func (r gormFormGroups) Create(ctx context.Context, formGroup *models.FormGroup) error {
+	return models.CreateFormGroup(r.db.WithContext(ctx), formGroup)
+}
+
+func (r gormFormGroups) Update(ctx context.Context, formGroup *models.FormGroup) error {
+	return models.UpdateFormGroup(r.db.WithContext(ctx), formGroup)
+}
+
+func (r gormFormGroups) Delete(ctx context.Context, id uint) error {
+	return models.DeleteFormGroup(r.db.WithContext(ctx), id)
+}
+
+type gormGroups struct{ db *gorm.DB }
+
+func (r gormGroups) Get(ctx context.Context, id uint) (*models.Group, error) {
+	return found(models.GetGroupByID(r.db.WithContext(ctx), id))
+}
+
+func (r gormGroups) List(ctx context.Context, params query.Params) ([]models.Group, structs.Meta, error) {
+	return list[models.Group](r.db.WithContext(ctx), params)
+}
+
+func (r gormGroups) Create(ctx context.Context, group *models.Group) error {
+	return models.CreateGroup(r.db.WithContext(ctx), group)
+}
+
+func (r gormGroups) Update(ctx context.Context, group *models.Group) error {
+	return models.UpdateGroup(r.db.WithContext(ctx), group)
+}
+
+func (r gormGroups) Delete(ctx context.Context, id uint) error {
+	return models.DeleteGroup(r.db.WithContext(ctx), id)
+}
+
+type gormDataTypes struct{ db *gorm.DB }
+
+func (r gormDataTypes) Get(ctx context.Context, id uint) (*models.DataType, error) {
+	return found(models.GetDataType(r.db.WithContext(ctx), id))
+}
+
+func (r gormDataTypes) List(ctx context.Context, params query.Params) ([]models.DataType, structs.Meta, error) {
+	return list[models.DataType](r.db.WithContext(ctx), params)
+}
+
+func (r gormDataTypes) Create(ctx context.Context, dataType *models.DataType) error {
+	return models.CreateDataType(r.db.WithContext(ctx), dataType)
+}
+
+func (r gormDataTypes) Update(ctx context.Context, dataType *models.DataType) error {
+	return r.db.WithContext(ctx).Save(dataType).Error
+}
+
+func (r gormDataTypes) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.DataType{}, id).Error
+}
+
+type gormSubmissions struct{ db *gorm.DB }
+
+func (r gormSubmissions) Get(ctx context.Context, id uint) (*models.Submission, error) {
+	return found(models.GetSubmission(r.db.WithContext(ctx), id))
+}
+
+func (r gormSubmissions) ByIdempotencyKey(ctx context.Context, key string) (*models.Submission, error) {
+	return found(models.GetSubmissionByIdempotencyKey(r.db.WithContext(ctx), key))
+}
+
+func (r gormSubmissions) List(ctx context.Context, scope SubmissionScope, params query.Params) ([]models.Submission, structs.Meta, error) {
+	db := r.db.WithContext(ctx)
+	if scope.None {
+		db = db.Where("1 = 0")
+	}
+	if scope.ServiceID != nil {
+		db = db.Where("submissions.services_id = ?", *scope.ServiceID)
+	}
+	if scope.ReviewerID != nil {
+		db = db.Where("submissions.services_id IN (SELECT service_id FROM reviewer_services WHERE user_id = ?)", *scope.ReviewerID)
+	}
+	if scope.CreatedBy != nil {
+		db = db.Where("submissions.created_by = ?", *scope.CreatedBy)
+	}
+
+	submissions, meta, err := list[models.Submission](db, params)
+	if err != nil {
+		return nil, meta, err
+	}
+	return submissions, meta, models.LoadSubmissionFormVersions(r.db.WithContext(ctx), submissions)
+}
+
+func (r gormSubmissions) ByStatus(ctx context.Context, createdBy uint, status string) ([]models.Submission, error) {
+	return models.GetSubmissionsByStatus(r.db.WithContext(ctx), createdBy, status)
+}
+
+func (r gormSubmissions) Create(ctx context.Context, submission *models.Submission) error {
+	return models.CreateSubmission(r.db.WithContext(ctx), submission)
+}
+
+func (r gormSubmissions) UpdateStatus(ctx context.Context, submission *models.Submission, status string) error {
+	return models.UpdateSubmissionStatus(r.db.WithContext(ctx), submission, status)
+}
+
+func (r gormSubmissions) Touch(ctx context.Context, submission *models.Submission) error {
+	return models.TouchSubmission(r.db.WithContext(ctx), submission)
+}
+
+func (r gormSubmissions) Answers(ctx context.Context, submissionIDs []uint) (map[uint][]models.FormAnswer, error) {
+	return models.GetFormAnswersBySubmissionIDs(r.db.WithContext(ctx), submissionIDs)
+}
+
+func (r gormSubmissions) CreateAnswer(ctx context.Context, answer *models.FormAnswer) error {
+	return models.CreateFormAnswer(r.db.WithContext(ctx), answer)
+}
+
+func (r gormSubmissions) SaveAnswers(ctx context.Context, submissionID uint, values map[uint]string) error {
+	return models.SaveFormAnswers(r.db.WithContext(ctx), submissionID, values)
+}
+
+func (r gormSubmissions) History(ctx context.Context, submissionID uint) ([]models.SubmissionStatusHistory, error) {
+	return models.GetSubmissionStatusHistory(r.db.WithContext(ctx), submissionID)
+}
+
+func (r gormSubmissions) AddHistory(ctx context.Context, entry *models.SubmissionStatusHistory) error {
+	return models.CreateSubmissionStatusHistory(r.db.WithContext(ctx), entry)
+}
+
+func (r gormSubmissions) Attachments(ctx context.Context, submissionID uint) ([]models.Attachment, error) {
+	return models.GetAttachmentsBySubmissionID(r.db.WithContext(ctx), submissionID)
+}
+
+func (r gormSubmissions) Attachment(ctx context.Context, id uint) (*models.Attachment, error) {
+	return found(models.GetAttachment(r.db.WithContext(ctx), id))
+}
+
+func (r gormSubmissions) CreateAttachment(ctx context.Context, attachment *models.Attachment) error {
+	return models.CreateAttachment(r.db.WithContext(ctx), attachment)
+}
+
+func (r gormSubmissions) AttachmentUsable(ctx context.Context, id, formFieldID, uploadedBy, submissionID uint) (bool, error) {
+	return models.AttachmentUsable(r.db.WithContext(ctx), id, formFieldID, uploadedBy, submissionID)
+}
+
+func (r gormSubmissions) LinkAttachments(ctx context.Context, submissionID uint, ids []uint) error {
+	return models.LinkAttachments(r.db.WithContext(ctx), submissionID, ids)
+}
@@ -0,0 +1,1552 @@
+// Package memory implements the repository interfaces in memory, so handlers
+// can be tested without Postgres. Lists page by limit and offset in ID order;
+// they ignore the filters and sort of the query.
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"kora_1/internal/collectionio"
+	"kora_1/internal/models"
+	"kora_1/internal/names"
+	"kora_1/internal/query"
+	"kora_1/internal/repository"
+	"kora_1/internal/structs"
+	"kora_1/internal/workflow"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Store holds every table. Rows are copied in and out, so callers cannot
+// change stored rows through the pointers they pass or receive.
+type Store struct {
+	mu sync.Mutex
+	tables
+}
+
+type tables struct {
+	nextID uint
+
+	services      table[models.Service]
+	transitions   table[models.WorkflowTransition]
+	names         table[models.ReservedName]
+	terms         table[models.RestrictedTerm]
+	webhooks      table[models.Webhook]
+	events        table[models.WebhookEvent]
+	deliveries    table[models.WebhookDelivery]
+	notifications table[models.Notification]
+	templates     table[models.NotificationTemplate]
+	users         table[models.User]
+	reviewers     map[[2]uint]bool
+	collections   table[models.Collection]
+	items         table[models.CollectionItem]
+	forms         table[models.Form]
+	versions      table[models.FormVersion]
+	formFields    table[models.FormFields]
+	formGroups    table[models.FormGroup]
+	fields        table[models.Field]
+	groups        table[models.Group]
+	dataTypes     table[models.DataType]
+	submissions   table[models.Submission]
+	answers       table[models.FormAnswer]
+	history       table[models.SubmissionStatusHistory]
+	attachments   table[models.Attachment]
+}
+
+func New() *Store {
+	return &Store{tables: tables{
+		services:      newTable(func(r *models.Service) *uint { return &r.ID }),
+		transitions:   newTable(func(r *models.WorkflowTransition) *uint { return &r.ID }),
+		names:         newTable(func(r *models.ReservedName) *uint { return &r.ID }),
+		terms:         newTable(func(r *models.RestrictedTerm) *uint { return &r.ID }),
+		webhooks:      newTable(func(r *models.Webhook) *uint { return &r.ID }),
+		events:        newTable(func(r *models.WebhookEvent) *uint { return &r.ID }),
+		deliveries:    newTable(func(r *models.WebhookDelivery) *uint { return &r.ID }),
+		notifications: newTable(func(r *models.Notification) *uint { return &r.ID }),
+		templates:     newTable(func(r *models.NotificationTemplate) *uint { return &r.ID }),
+		users:         newTable(func(r *models.User) *uint { return &r.ID }),
+		reviewers:     map[[2]uint]bool{},
+		collections:   newTable(func(r *models.Collection) *uint { return &r.ID }),
+		items:         newTable(func(r *models.CollectionItem) *uint { return &r.ID }),
+		forms:         newTable(func(r *models.Form) *uint { return &r.ID }),
+		versions:      newTable(func(r *models.FormVersion) *uint { return &r.ID }),
+		formFields:    newTable(func(r *models.FormFields) *uint { return &r.ID }),
+		formGroups:    newTable(func(r *models.FormGroup) *uint { return &r.ID }),
+		fields:        newTable(func(r *models.Field) *uint { return &r.ID }),
+		groups:        newTable(func(r *models.Group) *uint { return &r.ID }),
+		dataTypes:     newTable(func(r *models.DataType) *uint { return &r.ID }),
+		submissions:   newTable(func(r *models.Submission) *uint { return &r.ID }),
+		answers:       newTable(func(r *models.FormAnswer) *uint { return &r.ID }),
+		history:       newTable(func(r *models.SubmissionStatusHistory) *uint { return &r.ID }),
+		attachments:   newTable(func(r *models.Attachment) *uint { return &r.ID }),
+	}}
+}
+
+// clone copies every table, so that a failed transaction can be rolled back.
+func (t *tables) clone() tables {
+	return tables{
+		nextID:        t.nextID,
+		services:      t.services.clone(),
+		transitions:   t.transitions.clone(),
+		names:         t.names.clone(),
+		terms:         t.terms.clone(),
+		webhooks:      t.webhooks.clone(),
+		events:        t.events.clone(),
+		deliveries:    t.deliveries.clone(),
+		notifications: t.notifications.clone(),
+		templates:     t.templates.clone(),
+		users:         t.users.clone(),
+		reviewers:     maps.Clone(t.reviewers),
+		collections:   t.collections.clone(),
+		items:         t.items.clone(),
+		forms:         t.forms.clone(),
+		versions:      t.versions.clone(),
+		formFields:    t.formFields.clone(),
+		formGroups:    t.formGroups.clone(),
+		fields:        t.fields.clone(),
+		groups:        t.groups.clone(),
+		dataTypes:     t.dataTypes.clone(),
+		submissions:   t.submissions.clone(),
+		answers:       t.answers.clone(),
+		history:       t.history.clone(),
+		attachments:   t.attachments.clone(),
+	}
+}
+
+// Repositories returns repositories that share the store.
+func (s *Store) Repositories() repository.Repositories {
+	return repository.Repositories{
+		UnitOfWork:    unitOfWork{s},
+		Forms:         forms{s},
+		FormGroups:    formGroups{s},
+		Groups:        groups{s},
+		DataTypes:     dataTypes{s},
+		Submissions:   submissions{s},
+		Users:         users{s},
+		Collections:   collections{s},
+		Services:      services{s},
+		Names:         reservedNames{s},
+		Terms:         restrictedTerms{s},
+		Webhooks:      webhooks{s},
+		Notifications: notifications{s},
+		Templates:     notificationTemplates{s},
+	}
+}
+
+// Events returns the webhook events in the outbox, for tests to check what a
+// change reported.
+func (s *Store) Events() []models.WebhookEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.events.where(nil)
+}
+
+// Notifications returns the queued notifications.
+func (s *Store) Notifications() []models.Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.notifications.where(nil)
+}
+
+type unitOfWork struct{ s *Store }
+
+// Transaction runs fn on the store's own repositories and puts every table
+// back as it was when fn fails. Unlike Postgres it does not isolate
+// transactions from each other, so tests should not run them concurrently.
+func (u unitOfWork) Transaction(ctx context.Context, fn func(tx repository.Repositories) error) error {
+	u.s.mu.Lock()
+	saved := u.s.tables.clone()
+	u.s.mu.Unlock()
+
+	if err := fn(u.s.Repositories()); err != nil {
+		u.s.mu.Lock()
+		u.s.tables = saved
+		u.s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Add inserts rows the repositories have no create method for, such as forms
+// and submissions, assigning IDs to rows without one.
+func (s *Store) Add(rows ...any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, row := range rows {
+		switch r := row.(type) {
+		case *models.Service:
+			s.services.put(s, r)
+		case *models.WorkflowTransition:
+			s.transitions.put(s, r)
+		case *models.User:
+			s.users.put(s, r)
+		case *models.ReservedName:
+			s.names.put(s, r)
+		case *models.RestrictedTerm:
+			s.terms.put(s, r)
+		case *models.Webhook:
+			s.webhooks.put(s, r)
+		case *models.WebhookEvent:
+			s.events.put(s, r)
+		case *models.WebhookDelivery:
+			s.deliveries.put(s, r)
+		case *models.Notification:
+			s.notifications.put(s, r)
+		case *models.NotificationTemplate:
+			s.templates.put(s, r)
+		case *models.Collection:
+			s.collections.put(s, r)
+		case *models.CollectionItem:
+			s.items.put(s, r)
+		case *models.Form:
+			s.forms.put(s, r)
+		case *models.FormVersion:
+			s.versions.put(s, r)
+		case *models.FormFields:
+			s.formFields.put(s, r)
+		case *models.FormGroup:
+			s.formGroups.put(s, r)
+		case *models.Field:
+			s.fields.put(s, r)
+		case *models.Group:
+			s.groups.put(s, r)
+		case *models.DataType:
+			s.dataTypes.put(s, r)
+		case *models.Submission:
+			s.submissions.put(s, r)
+		case *models.FormAnswer:
+			s.answers.put(s, r)
+		case *models.SubmissionStatusHistory:
+			s.history.put(s, r)
+		case *models.Attachment:
+			s.attachments.put(s, r)
+		default:
+			panic(fmt.Sprintf("memory: cannot store %T", row))
+		}
+	}
+}
+
+type table[T any] struct {
+	rows map[uint]T
+	id   func(*T) *uint
+}
+
+func newTable[T any](id func(*T) *uint) table[T] {
+	return table[T]{rows: map[uint]T{}, id: id}
+}
+
+func (t table[T]) clone() table[T] {
+	return table[T]{rows: maps.Clone(t.rows), id: t.id}
+}
+
+func (t table[T]) put(s *Store, row *T) {
+	id := t.id(row)
+	if *id == 0 {
+		s.nextID++
+		*id = s.nextID
+	} else if *id > s.nextID {
+		s.nextID = *id
+	}
+	t.rows[*id] = *row
+}
+
+func (t table[T]) get(id uint) (*T, error) {
+	row, ok := t.rows[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &row, nil
+}
+
+func (t table[T]) update(row *T) error {
+	if _, ok := t.rows[*t.id(row)]; !ok {
+		return repository.ErrNotFound
+	}
+	t.rows[*t.id(row)] = *row
+	return nil
+}
+
+// where returns the rows keep accepts, in ID order.
+func (t table[T]) where(keep func(*T) bool) []T {
+	ids := make([]uint, 0, len(t.rows))
+	for id := range t.rows {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var rows []T
+	for _, id := range ids {
+		row := t.rows[id]
+		if keep == nil || keep(&row) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func page[T any](rows []T, params query.Params) ([]T, structs.Meta) {
+	meta := structs.Meta{Total: int64(len(rows)), Limit: params.Limit, Offset: params.Offset}
+	if params.Offset >= len(rows) {
+		return []T{}, meta
+	}
+	rows = rows[params.Offset:]
+	if params.Limit > 0 && len(rows) > params.Limit {
+		rows = rows[:params.Limit]
+	}
+	return rows, meta
+}
+
+type services struct{ s *Store }
+
+func (r services) Get(ctx context.Context, id uint) (*models.Service, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.services.get(id)
+}
+
+func (r services) List(ctx context.Context, params query.Params) ([]models.Service, structs.Meta, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows, meta := page(r.s.services.where(nil), params)
+	return rows, meta, nil
+}
+
+func (r services) Create(ctx context.Context, service *models.Service) error {
+	r.s.Add(service)
+	return nil
+}
+
+func (r services) Update(ctx context.Context, service *models.Service) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.services.update(service)
+}
+
+func (r services) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.services.rows, id)
+	return nil
+}
+
+func (r services) Workflow(ctx context.Context, serviceID uint) ([]models.WorkflowTransition, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.transitions.where(func(t *models.WorkflowTransition) bool { return t.ServiceID == serviceID }), nil
+}
+
+func (r services) SetWorkflow(ctx context.Context, serviceID uint, transitions []models.WorkflowTransition) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, old := range r.s.transitions.where(func(t *models.WorkflowTransition) bool { return t.ServiceID == serviceID }) {
+		delete(r.s.transitions.rows, old.ID)
+	}
+	for i := range transitions {
+		transitions[i].ID = 0
+		transitions[i].ServiceID = serviceID
+		r.s.transitions.put(r.s, &transitions[i])
+	}
+	return nil
+}
+
+type reservedNames struct{ s *Store }
+
+func (r reservedNames) Get(ctx context.Context, id uint) (*models.ReservedName, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.names.get(id)
+}
+
+func (r reservedNames) List(ctx context.Context, holderID *uint, params query.Params) ([]models.ReservedName, structs.Meta, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows, meta := page(r.s.names.where(func(n *models.ReservedName) bool {
+		return holderID == nil || (n.UserID != nil && *n.UserID == *holderID)
+	}), params)
+	return rows, meta, nil
+}
+
+// Reserve fails like the partial unique index of Postgres would.
+func (r reservedNames) Reserve(ctx context.Context, name *models.ReservedName) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, held := range r.s.names.where(func(n *models.ReservedName) bool {
+		return n.Status == models.ReservationHeld && n.NormalizedName == name.NormalizedName
+	}) {
+		if !held.Lapsed(name.ReservedOn) {
+			return &pgconn.PgError{Code: "23505", ConstraintName: "idx_reserved_names_held_normalized_name"}
+		}
+		held.Status = models.ReservationExpired
+		r.s.names.rows[held.ID] = held
+	}
+	if name.Status == "" {
+		name.Status = models.ReservationHeld
+	}
+	r.s.names.put(r.s, name)
+	return nil
+}
+
+func (r reservedNames) Renew(ctx context.Context, id uint, now, expiresOn time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	name, ok := r.s.names.rows[id]
+	if !ok || name.Status != models.ReservationHeld || name.ExpiresOn == nil || name.Lapsed(now) {
+		return models.ErrReservationNotHeld
+	}
+	name.ExpiresOn = &expiresOn
+	r.s.names.rows[id] = name
+	return nil
+}
+
+func (r reservedNames) Release(ctx context.Context, id uint, now time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	name, ok := r.s.names.rows[id]
+	if !ok || name.Status != models.ReservationHeld {
+		return models.ErrReservationNotHeld
+	}
+	name.Status, name.ReleasedOn = models.ReservationReleased, &now
+	r.s.names.rows[id] = name
+	return nil
+}
+
+func (r reservedNames) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.names.rows, id)
+	return nil
+}
+
+func (r reservedNames) Expire(ctx context.Context, now time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var expired int64
+	for id, name := range r.s.names.rows {
+		if name.Status == models.ReservationHeld && name.Lapsed(now) {
+			name.Status = models.ReservationExpired
+			r.s.names.rows[id] = name
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// Similar stands in for pg_trgm with names.Similarity and returns the
+// candidates in ID order.
+func (r reservedNames) Similar(ctx context.Context, key names.Key, now time.Time, limit int) ([]models.ReservedName, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows := r.s.names.where(func(n *models.ReservedName) bool {
+		if n.Status != models.ReservationHeld || n.Lapsed(now) {
+			return false
+		}
+		return n.NormalizedName == key.Normalized ||
+			names.Similarity(n.NormalizedName, key.Normalized) >= names.ConflictScore ||
+			(n.PhoneticKey != "" && n.PhoneticKey == key.Phonetic)
+	})
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows, nil
+}
+
+// Lock does nothing: each call already holds the store's mutex.
+func (r reservedNames) Lock(ctx context.Context) error {
+	return nil
+}
+
+type restrictedTerms struct{ s *Store }
+
+func (r restrictedTerms) Get(ctx context.Context, id uint) (*models.RestrictedTerm, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.terms.get(id)
+}
+
+func (r restrictedTerms) List(ctx context.Context, params query.Params) ([]models.RestrictedTerm, structs.Meta, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows, meta := page(r.s.terms.where(nil), params)
+	return rows, meta, nil
+}
+
+func (r restrictedTerms) All(ctx context.Context) ([]models.RestrictedTerm, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.terms.where(nil), nil
+}
+
+func (r restrictedTerms) Create(ctx context.Context, term *models.RestrictedTerm) error {
+	r.s.Add(term)
+	return nil
+}
+
+func (r restrictedTerms) Update(ctx context.Context, term *models.RestrictedTerm) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.terms.update(term)
+}
+
+func (r restrictedTerms) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.terms.rows, id)
+	return nil
+}
+
+type webhooks struct{ s *Store }
+
+func (r webhooks) Get(ctx context.Context, id uint) (*models.Webhook, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.webhooks.get(id)
+}
+
+func (r webhooks) List(ctx context.Context, serviceID uint, params query.Params) ([]models.Webhook, structs.Meta, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows, meta := page(r.s.webhooks.where(func(w *models.Webhook) bool { return w.ServiceID == serviceID }), params)
+	return rows, meta, nil
+}
+
+func (r webhooks) Create(ctx context.Context, webhook *models.Webhook) error {
+	r.s.Add(webhook)
+	return nil
+}
+
+func (r webhooks) Update(ctx context.Context, webhook *models.Webhook) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.webhooks.update(webhook)
+}
+
+// Delete also deletes the webhook's deliveries, as the foreign key cascades.
+func (r webhooks) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.webhooks.rows, id)
+	for _, delivery := range r.s.deliveries.where(func(d *models.WebhookDelivery) bool { return d.WebhookID == id }) {
+		delete(r.s.deliveries.rows, delivery.ID)
+	}
+	return nil
+}
+
+func (r webhooks) GetDelivery(ctx context.Context, id uint) (*models.WebhookDelivery, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delivery, err := r.s.deliveries.get(id)
+	if err != nil {
+		return nil, err
+	}
+	r.s.withEvent(delivery)
+	return delivery, nil
+}
+
+func (s *Store) withEvent(delivery *models.WebhookDelivery) {
+	if event, ok := s.events.rows[delivery.EventID]; ok {
+		delivery.Event = &event
+	}
+}
+
+func (r webhooks) Deliveries(ctx context.Context, webhookID uint, params query.Params) ([]models.WebhookDelivery, structs.Meta, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows, meta := page(r.s.deliveries.where(func(d *models.WebhookDelivery) bool { return d.WebhookID == webhookID }), params)
+	for i := range rows {
+		r.s.withEvent(&rows[i])
+	}
+	return rows, meta, nil
+}
+
+func (r webhooks) Redeliver(ctx context.Context, id uint, now time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delivery, err := r.s.deliveries.get(id)
+	if err != nil {
+		return err
+	}
+	delivery.Status, delivery.Attempts, delivery.NextAttemptOn = models.DeliveryPending, 0, now
+	return r.s.deliveries.update(delivery)
+}
+
+func (r webhooks) Enqueue(ctx context.Context, event *models.WebhookEvent) error {
+	if event.CreatedOn.IsZero() {
+		event.CreatedOn = time.Now()
+	}
+	r.s.Add(event)
+	return nil
+}
+
+type notifications struct{ s *Store }
+
+func (r notifications) Enqueue(ctx context.Context, notification *models.Notification) error {
+	if notification.Status == "" {
+		notification.Status = models.NotificationPending
+	}
+	r.s.Add(notification)
+	return nil
+}
+
+func (r notifications) ServiceReviewers(ctx context.Context, serviceID uint) ([]uint, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var ids []uint
+	for key := range r.s.reviewers {
+		if key[1] == serviceID {
+			ids = append(ids, key[0])
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+type notificationTemplates struct{ s *Store }
+
+func (r notificationTemplates) find(serviceID uint, kind string) []models.NotificationTemplate {
+	return r.s.templates.where(func(t *models.NotificationTemplate) bool { return t.ServiceID == serviceID && t.Kind == kind })
+}
+
+func (r notificationTemplates) Get(ctx context.Context, serviceID uint, kind string) (*models.NotificationTemplate, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows := r.find(serviceID, kind)
+	if len(rows) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &rows[0], nil
+}
+
+func (r notificationTemplates) List(ctx context.Context, serviceID uint) ([]models.NotificationTemplate, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows := r.s.templates.where(func(t *models.NotificationTemplate) bool { return t.ServiceID == serviceID })
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Kind < rows[j].Kind })
+	return rows, nil
+}
+
+// Save replaces an existing template like the upsert on
+// uni_notification_templates_service_kind.
+func (r notificationTemplates) Save(ctx context.Context, template *models.NotificationTemplate) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if rows := r.find(template.ServiceID, template.Kind); len(rows) > 0 {
+		template.ID = rows[0].ID
+	}
+	r.s.templates.put(r.s, template)
+	return nil
+}
+
+func (r notificationTemplates) Delete(ctx context.Context, serviceID uint, kind string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, row := range r.find(serviceID, kind) {
+		delete(r.s.templates.rows, row.ID)
+	}
+	return nil
+}
+
+type users struct{ s *Store }
+
+func (r users) Get(ctx context.Context, id uint) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.users.get(id)
+}
+
+func (r users) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows := r.s.users.where(func(u *models.User) bool { return u.Email == email })
+	if len(rows) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &rows[0], nil
+}
+
+func (r users) List(ctx context.Context, params query.Params) ([]models.User, structs.Meta, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows, meta := page(r.s.users.where(nil), params)
+	return rows, meta, nil
+}
+
+func (r users) Create(ctx context.Context, user *models.User) error {
+	if user.Role == "" {
+		user.Role = models.RoleApplicant
+	}
+	r.s.Add(user)
+	return nil
+}
+
+func (r users) Update(ctx context.Context, user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.users.update(user)
+}
+
+func (r users) UpdateRole(ctx context.Context, id uint, role string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	user, err := r.s.users.get(id)
+	if err != nil {
+		return err
+	}
+	user.Role = role
+	return r.s.users.update(user)
+}
+
+func (r users) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.users.rows, id)
+	return nil
+}
+
+func (r users) ReviewerServices(ctx context.Context, userID uint) ([]models.Service, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.services.where(func(s *models.Service) bool { return r.s.reviewers[[2]uint{userID, s.ID}] }), nil
+}
+
+func (r users) IsReviewer(ctx context.Context, userID, serviceID uint) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.reviewers[[2]uint{userID, serviceID}], nil
+}
+
+func (r users) AssignReviewer(ctx context.Context, userID, serviceID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.reviewers[[2]uint{userID, serviceID}] = true
+	return nil
+}
+
+func (r users) RemoveReviewer(ctx context.Context, userID, serviceID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.reviewers, [2]uint{userID, serviceID})
+	return nil
+}
+
+type collections struct{ s *Store }
+
+func (r collections) Get(ctx context.Context, id uint) (*models.Collection, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.collections.get(id)
+}
+
+func (r collections) List(ctx context.Context, params query.Params) ([]models.Collection, structs.Meta, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows, meta := page(r.s.collections.where(nil), params)
+	return rows, meta, nil
+}
+
+func (r collections) Create(ctx context.Context, collection *models.Collection) error {
+	r.s.Add(collection)
+	return nil
+}
+
+func (r collections) Update(ctx context.Context, collection *models.Collection) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.collections.update(collection)
+}
+
+func (r collections) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.collections.rows, id)
+	return nil
+}
+
+func (r collections) GetItem(ctx context.Context, id uint) (*models.CollectionItem, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.items.get(id)
+}
+
+func (r collections) Items(ctx context.Context, collectionID uint, parentID *uint) ([]models.CollectionItem, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.items.where(func(item *models.CollectionItem) bool {
+		if item.CollectionID == nil || *item.CollectionID != collectionID {
+			return false
+		}
+		return parentID == nil || (item.RelationCollectionItemsID != nil && *item.RelationCollectionItemsID == *parentID)
+	}), nil
+}
+
+func (r collections) Children(ctx context.Context, parentIDs []uint) ([]models.CollectionItem, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	parents := make(map[uint]bool, len(parentIDs))
+	for _, id := range parentIDs {
+		parents[id] = true
+	}
+	return r.s.items.where(func(item *models.CollectionItem) bool {
+		return item.RelationCollectionItemsID != nil && parents[*item.RelationCollectionItemsID]
+	}), nil
+}
+
+func (r collections) CreateItem(ctx context.Context, item *models.CollectionItem) error {
+	r.s.Add(item)
+	if item.Code == "" {
+		// Like models.CollectionItem.AfterCreate, default the code to the ID.
+		item.Code = strconv.FormatUint(uint64(item.ID), 10)
+		return r.UpdateItem(ctx, item)
+	}
+	return nil
+}
+
+func (r collections) UpdateItem(ctx context.Context, item *models.CollectionItem) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.items.update(item)
+}
+
+func (r collections) DeleteItem(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.items.rows, id)
+	return nil
+}
+
+func (r collections) ItemCodeTaken(ctx context.Context, collectionID uint, code string, exceptID uint) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	taken := r.s.items.where(func(item *models.CollectionItem) bool {
+		return item.CollectionID != nil && *item.CollectionID == collectionID && item.Code == code && item.ID != exceptID
+	})
+	return len(taken) > 0, nil
+}
+
+func (r collections) ItemsIn(ctx context.Context, collectionIDs []uint) ([]models.CollectionItem, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.items.where(func(item *models.CollectionItem) bool {
+		return item.CollectionID != nil && slices.Contains(collectionIDs, *item.CollectionID)
+	}), nil
+}
+
+// Import is not implemented in memory; test imports against Postgres.
+func (r collections) Import(ctx context.Context, collectionID uint, rows []collectionio.Row) (collectionio.Result, error) {
+	return collectionio.Result{}, errors.New("memory: collection import is not supported")
+}
+
+func (r collections) Export(ctx context.Context, collectionID uint) ([]collectionio.Row, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.collections.rows[collectionID]; !ok {
+		return nil, collectionio.ErrCollectionNotFound
+	}
+	items := r.s.items.where(func(item *models.CollectionItem) bool {
+		return item.CollectionID != nil && *item.CollectionID == collectionID
+	})
+	rows := make([]collectionio.Row, 0, len(items))
+	for _, item := range items {
+		row := collectionio.Row{Code: item.Code, Label: item.CollectionItem}
+		if item.RelationCollectionItemsID != nil {
+			if parent, ok := r.s.items.rows[*item.RelationCollectionItemsID]; ok {
+				row.ParentCode = parent.Code
+				if parent.CollectionID != nil && *parent.CollectionID != collectionID {
+					row.ParentCollectionID = parent.CollectionID
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+type forms struct{ s *Store }
+
+func (r forms) Get(ctx context.Context, id uint) (*models.Form, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.forms.get(id)
+}
+
+func (r forms) List(ctx context.Context, params query.Params) ([]models.Form, structs.Meta, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows, meta := page(r.s.forms.where(nil), params)
+	return rows, meta, nil
+}
+
+func (r forms) Create(ctx context.Context, form *models.Form) error {
+	r.s.Add(form)
+	return nil
+}
+
+func (r forms) Update(ctx context.Context, form *models.Form) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	stored, err := r.s.forms.get(form.ID)
+	if err != nil {
+		return err
+	}
+	frozen := stored.DataTypeID != form.DataTypeID || !sameID(stored.ServiceID, form.ServiceID) || !sameFlag(stored.Status, form.Status)
+	if frozen && len(r.s.versions.where(func(v *models.FormVersion) bool { return v.FormID == form.ID && v.IsPublished() })) > 0 {
+		return models.ErrFormVersionPublished
+	}
+	return r.s.forms.update(form)
+}
+
+func (r forms) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.forms.rows, id)
+	return nil
+}
+
+func (r forms) Versions(ctx context.Context, formID uint) ([]models.FormVersion, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.versions.where(func(v *models.FormVersion) bool { return v.FormID == formID }), nil
+}
+
+func (r forms) version(keep func(*models.FormVersion) bool) (*models.FormVersion, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.lastVersion(keep)
+}
+
+func (s *Store) lastVersion(keep func(*models.FormVersion) bool) (*models.FormVersion, error) {
+	rows := s.versions.where(keep)
+	if len(rows) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &rows[len(rows)-1], nil
+}
+
+func (r forms) Version(ctx context.Context, formID uint, number int) (*models.FormVersion, error) {
+	return r.version(func(v *models.FormVersion) bool { return v.FormID == formID && v.Version == number })
+}
+
+func (r forms) VersionByID(ctx context.Context, id uint) (*models.FormVersion, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.versions.get(id)
+}
+
+func (r forms) PublishedVersion(ctx context.Context, formID uint) (*models.FormVersion, error) {
+	return r.version(func(v *models.FormVersion) bool { return v.FormID == formID && v.IsPublished() })
+}
+
+func (r forms) DraftVersion(ctx context.Context, formID uint) (*models.FormVersion, error) {
+	return r.version(func(v *models.FormVersion) bool { return v.FormID == formID && v.Status == models.FormVersionDraft })
+}
+
+// OpenDraft copies the latest version's header and fields into a new draft
+// like models.GetOrCreateDraftVersion.
+func (r forms) OpenDraft(ctx context.Context, form *models.Form) (*models.FormVersion, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	draft, err := r.s.lastVersion(func(v *models.FormVersion) bool { return v.FormID == form.ID && v.Status == models.FormVersionDraft })
+	if err == nil {
+		return draft, nil
+	}
+
+	draft = &models.FormVersion{
+		FormID:      form.ID,
+		Version:     1,
+		Status:      models.FormVersionDraft,
+		FormName:    form.FormName,
+		Description: form.Description,
+		CreatedOn:   time.Now(),
+	}
+	latest, err := r.s.lastVersion(func(v *models.FormVersion) bool { return v.FormID == form.ID })
+	if err == nil {
+		draft.Version = latest.Version + 1
+		draft.FormName = latest.FormName
+		draft.Description = latest.Description
+	}
+	r.s.versions.put(r.s, draft)
+
+	if latest == nil {
+		for _, ff := range r.s.formFields.where(func(ff *models.FormFields) bool { return ff.FormID == form.ID && ff.FormVersionID == nil }) {
+			ff.FormVersionID = &draft.ID
+			r.s.formFields.rows[ff.ID] = ff
+		}
+		return draft, nil
+	}
+
+	fields := r.s.formFields.where(func(ff *models.FormFields) bool {
+		return ff.FormVersionID != nil && *ff.FormVersionID == latest.ID
+	})
+	clonedIDs := make(map[uint]uint, len(fields))
+	for _, ff := range fields {
+		clone := ff
+		clone.ID, clone.FormVersionID, clone.DependsOnFormFieldID = 0, &draft.ID, nil
+		r.s.formFields.put(r.s, &clone)
+		clonedIDs[ff.ID] = clone.ID
+	}
+	for _, ff := range fields {
+		if ff.DependsOnFormFieldID == nil {
+			continue
+		}
+		if parentID, ok := clonedIDs[*ff.DependsOnFormFieldID]; ok {
+			clone := r.s.formFields.rows[clonedIDs[ff.ID]]
+			clone.DependsOnFormFieldID = &parentID
+			r.s.formFields.rows[clone.ID] = clone
+		}
+	}
+	return draft, nil
+}
+
+func (r forms) UpdateVersion(ctx context.Context, version *models.FormVersion) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.versions.update(version)
+}
+
+func (r forms) Publish(ctx context.Context, version *models.FormVersion) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if version.IsPublished() {
+		return models.ErrFormVersionPublished
+	}
+	now := time.Now()
+	version.Status, version.PublishedOn = models.FormVersionPublished, &now
+	if err := r.s.versions.update(version); err != nil {
+		return err
+	}
+	if form, ok := r.s.forms.rows[version.FormID]; ok {
+		form.FormName, form.Description = version.FormName, version.Description
+		r.s.forms.rows[form.ID] = form
+	}
+	return nil
+}
+
+func (s *Store) versionFields(versionID uint) []models.FormFields {
+	rows := s.formFields.where(func(ff *models.FormFields) bool {
+		return ff.FormVersionID != nil && *ff.FormVersionID == versionID
+	})
+	for i := range rows {
+		s.withField(&rows[i])
+	}
+	return rows
+}
+
+// withField loads a form field's field, with its data type, and its group.
+func (s *Store) withField(ff *models.FormFields) {
+	if field, ok := s.fields.rows[ff.FieldID]; ok {
+		field.DataType = s.dataTypes.rows[field.DataTypeID]
+		ff.Field = field
+	}
+	if ff.FormGroupID != nil {
+		if group, ok := s.formGroups.rows[*ff.FormGroupID]; ok {
+			ff.FormGroup = &group
+		}
+	}
+}
+
+func (r forms) Layout(ctx context.Context, versionID uint) ([]models.FormFields, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows := r.s.versionFields(versionID)
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].FieldRow < rows[j].FieldRow })
+	return rows, nil
+}
+
+func (r forms) VersionFields(ctx context.Context, versionID uint) ([]models.FormFields, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.versionFields(versionID), nil
+}
+
+func (r forms) Placements(ctx context.Context, versionID, fieldID uint) ([]models.FormFields, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var rows []models.FormFields
+	for _, ff := range r.s.versionFields(versionID) {
+		if ff.FieldID == fieldID {
+			rows = append(rows, ff)
+		}
+	}
+	return rows, nil
+}
+
+func (r forms) FormField(ctx context.Context, id uint) (*models.FormFields, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	ff, err := r.s.formFields.get(id)
+	if err != nil {
+		return nil, err
+	}
+	r.s.withField(ff)
+	return ff, nil
+}
+
+func (r forms) FormFields(ctx context.Context, ids []uint) ([]models.FormFields, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows := r.s.formFields.where(func(ff *models.FormFields) bool { return slices.Contains(ids, ff.ID) })
+	for i := range rows {
+		r.s.withField(&rows[i])
+	}
+	return rows, nil
+}
+
+// ensureDraft refuses changes to form fields of published versions, like the
+// hooks of models.FormFields.
+func (s *Store) ensureDraft(ff *models.FormFields) error {
+	if ff.FormVersionID == nil {
+		return nil
+	}
+	version, err := s.versions.get(*ff.FormVersionID)
+	if err != nil {
+		return err
+	}
+	if version.IsPublished() {
+		return models.ErrFormVersionPublished
+	}
+	return nil
+}
+
+// inPublishedVersion reports whether a published version has a form field keep accepts.
+func (s *Store) inPublishedVersion(keep func(*models.FormFields) bool) bool {
+	placed := s.formFields.where(func(ff *models.FormFields) bool {
+		if ff.FormVersionID == nil || !keep(ff) {
+			return false
+		}
+		version, ok := s.versions.rows[*ff.FormVersionID]
+		return ok && version.IsPublished()
+	})
+	return len(placed) > 0
+}
+
+func (r forms) CreateFormField(ctx context.Context, formField *models.FormFields) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.s.ensureDraft(formField); err != nil {
+		return err
+	}
+	r.s.formFields.put(r.s, formField)
+	return nil
+}
+
+func (r forms) SetDependency(ctx context.Context, formField *models.FormFields, parentID *uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.s.ensureDraft(formField); err != nil {
+		return err
+	}
+	stored, err := r.s.formFields.get(formField.ID)
+	if err != nil {
+		return err
+	}
+	formField.DependsOnFormFieldID, stored.DependsOnFormFieldID = parentID, parentID
+	return r.s.formFields.update(stored)
+}
+
+func (r forms) Field(ctx context.Context, id uint) (*models.Field, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.fields.get(id)
+}
+
+func (r forms) Fields(ctx context.Context, params query.Params) ([]models.Field, structs.Meta, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows, meta := page(r.s.fields.where(nil), params)
+	return rows, meta, nil
+}
+
+func (r forms) CreateField(ctx context.Context, field *models.Field) error {
+	r.s.Add(field)
+	return nil
+}
+
+func (r forms) UpdateField(ctx context.Context, field *models.Field) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	stored, err := r.s.fields.get(field.ID)
+	if err != nil {
+		return err
+	}
+	frozen := stored.Label != field.Label || stored.DataTypeID != field.DataTypeID ||
+		!sameID(stored.GroupID, field.GroupID) || !sameID(stored.CollectionID, field.CollectionID)
+	if frozen && r.s.inPublishedVersion(func(ff *models.FormFields) bool { return ff.FieldID == field.ID }) {
+		return models.ErrFormVersionPublished
+	}
+	return r.s.fields.update(field)
+}
+
+func (r forms) DeleteField(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if r.s.inPublishedVersion(func(ff *models.FormFields) bool { return ff.FieldID == id }) {
+		return models.ErrFormVersionPublished
+	}
+	delete(r.s.fields.rows, id)
+	return nil
+}
+
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func sameFlag(a, b *bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+type formGroups struct{ s *Store }
+
+func (r formGroups) Get(ctx context.Context, id uint) (*models.FormGroup, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.formGroups.get(id)
+}
+
+func (r formGroups) List(ctx context.Context, params query.Params) ([]models.FormGroup, structs.Meta, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows, meta := page(r.s.formGroups.where(nil), params)
+	return rows, meta, nil
+}
+
+func (r formGroups) Create(ctx context.Context, formGroup *models.FormGroup) error {
+	r.s.Add(formGroup)
+	return nil
+}
+
+func (r formGroups) used(id uint) bool {
+	return r.s.inPublishedVersion(func(ff *models.FormFields) bool { return ff.FormGroupID != nil && *ff.FormGroupID == id })
+}
+
+func (r formGroups) Update(ctx context.Context, formGroup *models.FormGroup) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if r.used(formGroup.ID) {
+		return models.ErrFormVersionPublished
+	}
+	return r.s.formGroups.update(formGroup)
+}
+
+func (r formGroups) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if r.used(id) {
+		return models.ErrFormVersionPublished
+	}
+	delete(r.s.formGroups.rows, id)
+	return nil
+}
+
+type groups struct{ s *Store }
+
+func (r groups) Get(ctx context.Context, id uint) (*models.Group, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.groups.get(id)
+}
+
+func (r groups) List(ctx context.Context, params query.Params) ([]models.Group, structs.Meta, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows, meta := page(r.s.groups.where(nil), params)
+	return rows, meta, nil
+}
+
+func (r groups) Create(ctx context.Context, group *models.Group) error {
+	r.s.Add(group)
+	return nil
+}
+
+func (r groups) Update(ctx context.Context, group *models.Group) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.groups.update(group)
+}
+
+func (r groups) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.groups.rows, id)
+	return nil
+}
+
+type dataTypes struct{ s *Store }
+
+func (r dataTypes) Get(ctx context.Context, id uint) (*models.DataType, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.dataTypes.get(id)
+}
+
+func (r dataTypes) List(ctx context.Context, params query.Params) ([]models.DataType, structs.Meta, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows, meta := page(r.s.dataTypes.where(nil), params)
+	return rows, meta, nil
+}
+
+func (r dataTypes) Create(ctx context.Context, dataType *models.DataType) error {
+	r.s.Add(dataType)
+	return nil
+}
+
+func (r dataTypes) Update(ctx context.Context, dataType *models.DataType) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.dataTypes.update(dataType)
+}
+
+func (r dataTypes) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.dataTypes.rows, id)
+	return nil
+}
+
+type submissions struct{ s *Store }
+
+func (r submissions) Get(ctx context.Context, id uint) (*models.Submission, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	submission, err := r.s.submissions.get(id)
+	if err != nil {
+		return nil, err
+	}
+	r.s.withVersion(submission)
+	return submission, nil
+}
+
+func (s *Store) withVersion(submission *models.Submission) {
+	if submission.FormVersionID == nil {
+		return
+	}
+	if version, ok := s.versions.rows[*submission.FormVersionID]; ok {
+		submission.FormVersion = &version
+	}
+}
+
+func (r submissions) List(ctx context.Context, scope repository.SubmissionScope, params query.Params) ([]models.Submission, structs.Meta, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows := r.s.submissions.where(func(sub *models.Submission) bool {
+		switch {
+		case scope.None:
+			return false
+		case scope.ServiceID != nil && (sub.ServicesID == nil || *sub.ServicesID != *scope.ServiceID):
+			return false
+		case scope.ReviewerID != nil && (sub.ServicesID == nil || !r.s.reviewers[[2]uint{*scope.ReviewerID, *sub.ServicesID}]):
+			return false
+		case scope.CreatedBy != nil && (sub.CreatedBy == nil || *sub.CreatedBy != *scope.CreatedBy):
+			return false
+		}
+		return true
+	})
+	rows, meta := page(rows, params)
+	for i := range rows {
+		r.s.withVersion(&rows[i])
+	}
+	return rows, meta, nil
+}
+
+func (r submissions) ByIdempotencyKey(ctx context.Context, key string) (*models.Submission, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows := r.s.submissions.where(func(sub *models.Submission) bool {
+		return sub.IdempotencyKey != nil && *sub.IdempotencyKey == key
+	})
+	if len(rows) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	r.s.withVersion(&rows[0])
+	return &rows[0], nil
+}
+
+func (r submissions) ByStatus(ctx context.Context, createdBy uint, status string) ([]models.Submission, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	rows := r.s.submissions.where(func(sub *models.Submission) bool {
+		return sub.CreatedBy != nil && *sub.CreatedBy == createdBy && sub.Status == status
+	})
+	slices.Reverse(rows)
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].UpdatedOn.After(rows[j].UpdatedOn) })
+	for i := range rows {
+		r.s.withVersion(&rows[i])
+	}
+	return rows, nil
+}
+
+// Create fails like the unique index on idempotency_key would.
+func (r submissions) Create(ctx context.Context, submission *models.Submission) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if key := submission.IdempotencyKey; key != nil {
+		if len(r.s.submissions.where(func(sub *models.Submission) bool {
+			return sub.IdempotencyKey != nil && *sub.IdempotencyKey == *key
+		})) > 0 {
+			return &pgconn.PgError{Code: "23505", ConstraintName: "idx_submissions_idempotency_key"}
+		}
+	}
+	now := time.Now()
+	if submission.CreatedOn.IsZero() {
+		submission.CreatedOn = now
+	}
+	if submission.UpdatedOn.IsZero() {
+		submission.UpdatedOn = now
+	}
+	if submission.Status == "" {
+		submission.Status = workflow.Submitted
+	}
+	r.s.submissions.put(r.s, submission)
+	return nil
+}
+
+func (r submissions) UpdateStatus(ctx context.Context, submission *models.Submission, status string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	stored, err := r.s.submissions.get(submission.ID)
+	if err != nil || stored.Status != submission.Status {
+		return models.ErrSubmissionStatusChanged
+	}
+	stored.Status, stored.UpdatedOn = status, time.Now()
+	submission.Status = status
+	return r.s.submissions.update(stored)
+}
+
+func (r submissions) Touch(ctx context.Context, submission *models.Submission) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	stored, err := r.s.submissions.get(submission.ID)
+	if err != nil {
+		return err
+	}
+	submission.UpdatedOn = time.Now()
+	stored.UpdatedOn = submission.UpdatedOn
+	return r.s.submissions.update(stored)
+}
+
+func (r submissions) Answers(ctx context.Context, submissionIDs []uint) (map[uint][]models.FormAnswer, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	byID := make(map[uint][]models.FormAnswer, len(submissionIDs))
+	wanted := make(map[uint]bool, len(submissionIDs))
+	for _, id := range submissionIDs {
+		wanted[id] = true
+	}
+	for _, answer := range r.s.answers.where(func(a *models.FormAnswer) bool {
+		return a.SubmissionID != nil && wanted[*a.SubmissionID]
+	}) {
+		if answer.FormFieldID != nil {
+			if ff, ok := r.s.formFields.rows[*answer.FormFieldID]; ok {
+				r.s.withField(&ff)
+				answer.FormField = &ff
+			}
+		}
+		byID[*answer.SubmissionID] = append(byID[*answer.SubmissionID], answer)
+	}
+	return byID, nil
+}
+
+func (r submissions) CreateAnswer(ctx context.Context, answer *models.FormAnswer) error {
+	r.s.Add(answer)
+	return nil
+}
+
+// SaveAnswers replaces answers like the upsert on idx_form_answers_submission_field.
+func (r submissions) SaveAnswers(ctx context.Context, submissionID uint, values map[uint]string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	existing := make(map[uint]models.FormAnswer)
+	for _, answer := range r.s.answers.where(func(a *models.FormAnswer) bool {
+		return a.SubmissionID != nil && *a.SubmissionID == submissionID && a.FormFieldID != nil
+	}) {
+		existing[*answer.FormFieldID] = answer
+	}
+	for _, formFieldID := range slices.Sorted(maps.Keys(values)) {
+		value := values[formFieldID]
+		answer, ok := existing[formFieldID]
+		switch {
+		case strings.TrimSpace(value) == "":
+			if ok {
+				delete(r.s.answers.rows, answer.ID)
+			}
+		case ok:
+			answer.Answer = value
+			r.s.answers.rows[answer.ID] = answer
+		default:
+			answer := models.FormAnswer{FormFieldID: &formFieldID, SubmissionID: &submissionID, Answer: value}
+			r.s.answers.put(r.s, &answer)
+		}
+	}
+	return nil
+}
+
+func (r submissions) History(ctx context.Context, submissionID uint) ([]models.SubmissionStatusHistory, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.history.where(func(h *models.SubmissionStatusHistory) bool { return h.SubmissionID == submissionID }), nil
+}
+
+func (r submissions) AddHistory(ctx context.Context, entry *models.SubmissionStatusHistory) error {
+	if entry.ChangedOn.IsZero() {
+		entry.ChangedOn = time.Now()
+	}
+	r.s.Add(entry)
+	return nil
+}
+
+func (r submissions) Attachments(ctx context.Context, submissionID uint) ([]models.Attachment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.attachments.where(func(a *models.Attachment) bool {
+		return a.SubmissionID != nil && *a.SubmissionID == submissionID
+	}), nil
+}
+
+func (r submissions) Attachment(ctx context.Context, id uint) (*models.Attachment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.attachments.get(id)
+}
+
+func (r submissions) CreateAttachment(ctx context.Context, attachment *models.Attachment) error {
+	if attachment.CreatedOn.IsZero() {
+		attachment.CreatedOn = time.Now()
+	}
+	r.s.Add(attachment)
+	return nil
+}
+
+func (r submissions) AttachmentUsable(ctx context.Context, id, formFieldID, uploadedBy, submissionID uint) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	a, ok := r.s.attachments.rows[id]
+	return ok && a.FormFieldID != nil && *a.FormFieldID == formFieldID &&
+		a.UploadedBy != nil && *a.UploadedBy == uploadedBy &&
+		(a.SubmissionID == nil || *a.SubmissionID == submissionID), nil
+}
+
+func (r submissions) LinkAttachments(ctx context.Context, submissionID uint, ids []uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, id := range ids {
+		if a, ok := r.s.attachments.rows[id]; ok && a.SubmissionID == nil {
+			a.SubmissionID = &submissionID
+			r.s.attachments.rows[id] = a
+		}
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+// Package repository defines how handlers read and write each aggregate, so
+// that they depend on these interfaces rather than on a *gorm.DB. NewGorm
+// returns the Postgres implementations; package memory has in-memory fakes
+// for tests. Writes that must land together run through the UnitOfWork.
+//
+// Lookups of a missing row return ErrNotFound. List methods take the parsed
+// parameters of package query and return the page's meta block.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"kora_1/internal/collectionio"
+	"kora_1/internal/models"
+	"kora_1/internal/names"
+	"kora_1/internal/query"
+	"kora_1/internal/structs"
+)
+
+// ErrNotFound is returned when the requested row does not exist.
+var ErrNotFound = errors.New("not found")
+
+// Repositories groups one repository per aggregate.
+type Repositories struct {
+	UnitOfWork
+
+	Forms         Forms
+	FormGroups    FormGroups
+	Groups        Groups
+	DataTypes     DataTypes
+	Submissions   Submissions
+	Users         Users
+	Collections   Collections
+	Services      Services
+	Names         ReservedNames
+	Terms         RestrictedTerms
+	Webhooks      Webhooks
+	Notifications Notifications
+	Templates     NotificationTemplates
+}
+
+// UnitOfWork runs writes to several repositories as one.
+type UnitOfWork interface {
+	// Transaction calls fn with repositories bound to a single transaction,
+	// which commits when fn returns nil and rolls back when it returns an error.
+	Transaction(ctx context.Context, fn func(tx Repositories) error) error
+}
+
+// Services stores the services forms are published under and their lifecycles.
+type Services interface {
+	Get(ctx context.Context, id uint) (*models.Service, error)
+	List(ctx context.Context, params query.Params) ([]models.Service, structs.Meta, error)
+	Create(ctx context.Context, service *models.Service) error
+	Update(ctx context.Context, service *models.Service) error
+	Delete(ctx context.Context, id uint) error
+
+	// Workflow returns the transitions configured for a service; none means the default lifecycle.
+	Workflow(ctx context.Context, serviceID uint) ([]models.WorkflowTransition, error)
+	// SetWorkflow replaces a service's transitions. Run it in a transaction.
+	SetWorkflow(ctx context.Context, serviceID uint, transitions []models.WorkflowTransition) error
+}
+
+// ReservedNames stores name reservations. Times are passed in so that
+// callers decide what now is.
+type ReservedNames interface {
+	Get(ctx context.Context, id uint) (*models.ReservedName, error)
+	// List returns a page of reservations, only those held by holderID unless it is nil.
+	List(ctx context.Context, holderID *uint, params query.Params) ([]models.ReservedName, structs.Meta, error)
+	// Reserve holds a name; a name another reservation holds fails with a unique violation.
+	Reserve(ctx context.Context, name *models.ReservedName) error
+	// Renew and Release return models.ErrReservationNotHeld for reservations that are no longer held.
+	Renew(ctx context.Context, id uint, now, expiresOn time.Time) error
+	Release(ctx context.Context, id uint, now time.Time) error
+	Delete(ctx context.Context, id uint) error
+	// Expire expires the holds that lapsed by now and returns how many.
+	Expire(ctx context.Context, now time.Time) (int64, error)
+	// Similar returns up to limit names held at now that may clash with key, for names.Compare to score.
+	Similar(ctx context.Context, key names.Key, now time.Time, limit int) ([]models.ReservedName, error)
+	// Lock keeps other transactions from reserving names until the calling one
+	// ends. Take it inside a transaction before checking a name to reserve.
+	Lock(ctx context.Context) error
+}
+
+// RestrictedTerms stores the words names may not use, or only with approval.
+type RestrictedTerms interface {
+	Get(ctx context.Context, id uint) (*models.RestrictedTerm, error)
+	List(ctx context.Context, params query.Params) ([]models.RestrictedTerm, structs.Meta, error)
+	// All returns every term, for checking a name against.
+	All(ctx context.Context) ([]models.RestrictedTerm, error)
+	Create(ctx context.Context, term *models.RestrictedTerm) error
+	Update(ctx context.Context, term *models.RestrictedTerm) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// Webhooks stores the webhook subscriptions of services and the log of their
+// deliveries. Events are queued and delivered through package webhooks.
+type Webhooks interface {
+	Get(ctx context.Context, id uint) (*models.Webhook, error)
+	// List returns a page of the webhooks of a service.
+	List(ctx context.Context, serviceID uint, params query.Params) ([]models.Webhook, structs.Meta, error)
+	Create(ctx context.Context, webhook *models.Webhook) error
+	Update(ctx context.Context, webhook *models.Webhook) error
+	Delete(ctx context.Context, id uint) error
+
+	// GetDelivery returns a delivery with its event.
+	GetDelivery(ctx context.Context, id uint) (*models.WebhookDelivery, error)
+	// Deliveries returns a page of a webhook's deliveries with their events.
+	Deliveries(ctx context.Context, webhookID uint, params query.Params) ([]models.WebhookDelivery, structs.Meta, error)
+	// Redeliver queues a delivery to be sent again at now.
+	Redeliver(ctx context.Context, id uint, now time.Time) error
+
+	// Enqueue writes an event to the outbox. Use the transaction of the change it reports.
+	Enqueue(ctx context.Context, event *models.WebhookEvent) error
+}
+
+// Notifications queues the emails package notify sends.
+type Notifications interface {
+	// Enqueue queues a notification. Use the transaction of the change it reports.
+	Enqueue(ctx context.Context, notification *models.Notification) error
+	// ServiceReviewers returns the IDs of the reviewers assigned to a service.
+	ServiceReviewers(ctx context.Context, serviceID uint) ([]uint, error)
+}
+
+// NotificationTemplates stores the email templates services replace the
+// defaults of package notify with, one per service and kind.
+type NotificationTemplates interface {
+	Get(ctx context.Context, serviceID uint, kind string) (*models.NotificationTemplate, error)
+	List(ctx context.Context, serviceID uint) ([]models.NotificationTemplate, error)
+	// Save creates the service's template of the kind or replaces it.
+	Save(ctx context.Context, template *models.NotificationTemplate) error
+	Delete(ctx context.Context, serviceID uint, kind string) error
+}
+
+// Users stores accounts and the services reviewers are assigned to.
+type Users interface {
+	Get(ctx context.Context, id uint) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	List(ctx context.Context, params query.Params) ([]models.User, structs.Meta, error)
+	Create(ctx context.Context, user *models.User) error
+	Update(ctx context.Context, user *models.User) error
+	UpdateRole(ctx context.Context, id uint, role string) error
+	Delete(ctx context.Context, id uint) error
+
+	ReviewerServices(ctx context.Context, userID uint) ([]models.Service, error)
+	IsReviewer(ctx context.Context, userID, serviceID uint) (bool, error)
+	AssignReviewer(ctx context.Context, userID, serviceID uint) error
+	RemoveReviewer(ctx context.Context, userID, serviceID uint) error
+}
+
+// Collections stores option lists and their items.
+type Collections interface {
+	Get(ctx context.Context, id uint) (*models.Collection, error)
+	List(ctx context.Context, params query.Params) ([]models.Collection, structs.Meta, error)
+	Create(ctx context.Context, collection *models.Collection) error
+	Update(ctx context.Context, collection *models.Collection) error
+	Delete(ctx context.Context, id uint) error
+
+	GetItem(ctx context.Context, id uint) (*models.CollectionItem, error)
+	// Items returns a collection's items, only the children of parentID when it is set.
+	Items(ctx context.Context, collectionID uint, parentID *uint) ([]models.CollectionItem, error)
+	// Children returns the items whose parent is one of parentIDs, in any collection.
+	Children(ctx context.Context, parentIDs []uint) ([]models.CollectionItem, error)
+	CreateItem(ctx context.Context, item *models.CollectionItem) error
+	UpdateItem(ctx context.Context, item *models.CollectionItem) error
+	DeleteItem(ctx context.Context, id uint) error
+	// ItemCodeTaken reports whether another item of the collection uses code.
+	ItemCodeTaken(ctx context.Context, collectionID uint, code string, exceptID uint) (bool, error)
+	// ItemsIn returns the items of several collections at once.
+	ItemsIn(ctx context.Context, collectionIDs []uint) ([]models.CollectionItem, error)
+
+	// Import and Export read and write a collection's items in bulk, see package collectionio.
+	Import(ctx context.Context, collectionID uint, rows []collectionio.Row) (collectionio.Result, error)
+	Export(ctx context.Context, collectionID uint) ([]collectionio.Row, error)
+}
+
+// Forms stores forms, their versions, the fields placed on them and the
+// catalog of fields. Writes that would change a published version return
+// models.ErrFormVersionPublished.
+type Forms interface {
+	Get(ctx context.Context, id uint) (*models.Form, error)
+	List(ctx context.Context, params query.Params) ([]models.Form, structs.Meta, error)
+	Create(ctx context.Context, form *models.Form) error
+	Update(ctx context.Context, form *models.Form) error
+	Delete(ctx context.Context, id uint) error
+
+	Versions(ctx context.Context, formID uint) ([]models.FormVersion, error)
+	Version(ctx context.Context, formID uint, number int) (*models.FormVersion, error)
+	VersionByID(ctx context.Context, id uint) (*models.FormVersion, error)
+	PublishedVersion(ctx context.Context, formID uint) (*models.FormVersion, error)
+	DraftVersion(ctx context.Context, formID uint) (*models.FormVersion, error)
+	// OpenDraft returns the form's draft, creating one from the latest version
+	// when there is none. Run it in a transaction.
+	OpenDraft(ctx context.Context, form *models.Form) (*models.FormVersion, error)
+	UpdateVersion(ctx context.Context, version *models.FormVersion) error
+	// Publish freezes a draft and makes its header the form's.
+	Publish(ctx context.Context, version *models.FormVersion) error
+
+	// Layout returns the fields of a version with their field, data type and group, in row order.
+	Layout(ctx context.Context, versionID uint) ([]models.FormFields, error)
+	// VersionFields returns the fields of a version with their field and data type, in ID order.
+	VersionFields(ctx context.Context, versionID uint) ([]models.FormFields, error)
+	// Placements returns the fields of a version that place the catalog field fieldID.
+	Placements(ctx context.Context, versionID, fieldID uint) ([]models.FormFields, error)
+	FormField(ctx context.Context, id uint) (*models.FormFields, error)
+	// FormFields returns the form fields with the IDs, with their field and data type.
+	FormFields(ctx context.Context, ids []uint) ([]models.FormFields, error)
+	CreateFormField(ctx context.Context, formField *models.FormFields) error
+	// SetDependency points a draft field's options at the answer to parentID, or frees them when it is nil.
+	SetDependency(ctx context.Context, formField *models.FormFields, parentID *uint) error
+
+	Field(ctx context.Context, id uint) (*models.Field, error)
+	Fields(ctx context.Context, params query.Params) ([]models.Field, structs.Meta, error)
+	CreateField(ctx context.Context, field *models.Field) error
+	// UpdateField may only change the status of a field a published version places.
+	UpdateField(ctx context.Context, field *models.Field) error
+	DeleteField(ctx context.Context, id uint) error
+}
+
+// FormGroups stores the groups form fields are laid out in. Groups a
+// published version uses cannot change.
+type FormGroups interface {
+	Get(ctx context.Context, id uint) (*models.FormGroup, error)
+	List(ctx context.Context, params query.Params) ([]models.FormGroup, structs.Meta, error)
+	Create(ctx context.Context, formGroup *models.FormGroup) error
+	Update(ctx context.Context, formGroup *models.FormGroup) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// Groups stores the groups that organise the catalog of fields.
+type Groups interface {
+	Get(ctx context.Context, id uint) (*models.Group, error)
+	List(ctx context.Context, params query.Params) ([]models.Group, structs.Meta, error)
+	Create(ctx context.Context, group *models.Group) error
+	Update(ctx context.Context, group *models.Group) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// DataTypes stores the data types fields are declared with.
+type DataTypes interface {
+	Get(ctx context.Context, id uint) (*models.DataType, error)
+	List(ctx context.Context, params query.Params) ([]models.DataType, structs.Meta, error)
+	Create(ctx context.Context, dataType *models.DataType) error
+	Update(ctx context.Context, dataType *models.DataType) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// SubmissionScope narrows a submission listing. Unset fields do not filter.
+type SubmissionScope struct {
+	ServiceID  *uint // Only submissions to this service
+	ReviewerID *uint // Only submissions to services this reviewer is assigned to
+	CreatedBy  *uint // Only this applicant's submissions
+	None       bool  // No submissions at all
+}
+
+// Submissions stores submissions and what hangs off them: answers, status
+// history and uploaded files. Submitting and transitions write several of
+// these and run in a transaction.
+type Submissions interface {
+	// Get returns a submission with its service, form version and author.
+	Get(ctx context.Context, id uint) (*models.Submission, error)
+	// ByIdempotencyKey returns the submission a submit with the Idempotency-Key created, with its form version.
+	ByIdempotencyKey(ctx context.Context, key string) (*models.Submission, error)
+	// List returns a page of submissions with their form versions.
+	List(ctx context.Context, scope SubmissionScope, params query.Params) ([]models.Submission, structs.Meta, error)
+	// ByStatus returns a user's submissions in a status with their form versions, most recently updated first.
+	ByStatus(ctx context.Context, createdBy uint, status string) ([]models.Submission, error)
+	// Create fails with a unique violation when the Idempotency-Key was used before.
+	Create(ctx context.Context, submission *models.Submission) error
+	// UpdateStatus returns models.ErrSubmissionStatusChanged when the status changed since the submission was read.
+	UpdateStatus(ctx context.Context, submission *models.Submission, status string) error
+	// Touch marks a submission as updated now.
+	Touch(ctx context.Context, submission *models.Submission) error
+
+	// Answers returns the answers of the submissions, with their field's data type, keyed by submission ID.
+	Answers(ctx context.Context, submissionIDs []uint) (map[uint][]models.FormAnswer, error)
+	CreateAnswer(ctx context.Context, answer *models.FormAnswer) error
+	// SaveAnswers writes answers keyed by form field ID, replacing earlier answers to the same fields; a blank one removes the answer.
+	SaveAnswers(ctx context.Context, submissionID uint, values map[uint]string) error
+
+	History(ctx context.Context, submissionID uint) ([]models.SubmissionStatusHistory, error)
+	AddHistory(ctx context.Context, entry *models.SubmissionStatusHistory) error
+
+	Attachments(ctx context.Context, submissionID uint) ([]models.Attachment, error)
+	Attachment(ctx context.Context, id uint) (*models.Attachment, error)
+	CreateAttachment(ctx context.Context, attachment *models.Attachment) error
+	// AttachmentUsable reports whether a user's upload for a form field may be an answer on the submission.
+	AttachmentUsable(ctx context.Context, id, formFieldID, uploadedBy, submissionID uint) (bool, error)
+	// LinkAttachments makes unlinked uploads belong to the submission.
+	LinkAttachments(ctx context.Context, submissionID uint, ids []uint) error
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// every runs job each interval until ctx is done. Failures are logged and the
+// job runs again at the next tick.
+func every(ctx context.Context, interval time.Duration, name string, job func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "background job failed", "job", name, "error", err.Error())
+			}
+		}
+	}
+}
+
+// expireReservations expires the name reservations whose hold has lapsed.
+func (s *Server) expireReservations(ctx context.Context) error {
+	expired, err := s.api.Names.Expire(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		slog.InfoContext(ctx, "expired name reservations", "count", expired)
+	}
+	return nil
+}
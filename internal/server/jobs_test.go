@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kora_1/internal/handlers"
+	"kora_1/internal/models"
+	"kora_1/internal/repository/memory"
+)
+
+func TestExpireReservations(t *testing.T) {
+	store := memory.New()
+	lapsed, held := models.NewReservedName("Acme"), models.NewReservedName("Zambezi")
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+	lapsed.ExpiresOn, held.ExpiresOn = &past, &future
+	store.Add(lapsed, held)
+	s := &Server{api: &handlers.Handler{Repositories: store.Repositories()}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		every(ctx, time.Millisecond, "expire reservations", s.expireReservations)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		got, _ := store.Repositories().Names.Get(context.Background(), lapsed.ID)
+		if got.Status == models.ReservationExpired {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("lapsed reservation is still %s", got.Status)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if got, _ := store.Repositories().Names.Get(context.Background(), held.ID); got.Status != models.ReservationHeld {
+		t.Errorf("unexpired reservation became %s", got.Status)
+	}
+}
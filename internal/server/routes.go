@@ -2,7 +2,8 @@ package server
 
 import (
 	_ "kora_1/docs"
-	"kora_1/internal/handlers"
+	"kora_1/internal/auth"
+	"kora_1/internal/middleware"
 	"net/http"
 
 	"github.com/gin-contrib/cors"
@@ -12,127 +13,216 @@ import (
 )
 
 func (s *Server) RegisterRoutes() http.Handler {
-	r := gin.Default()
+	h := s.api
+	r := gin.New()
+	r.Use(middleware.RequestID(), middleware.AccessLog(), middleware.Recovery())
 
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:5173"},
+		AllowOrigins:     s.cfg.CORS.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type"},
+		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type", "Idempotency-Key", middleware.RequestIDHeader},
+		ExposeHeaders:    []string{middleware.RequestIDHeader},
 		AllowCredentials: true,
 	}))
 
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	if s.cfg.Features.Swagger {
+		r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	}
 	r.GET("/", s.HelloWorldHandler)
 	r.GET("/health", s.healthHandler)
 
+	// Auth
+	authRoutes := r.Group("/auth")
+	{
+		authRoutes.POST("/login", h.LoginHandler)
+		authRoutes.POST("/refresh", h.RefreshHandler)
+		authRoutes.GET("/me", middleware.RequireAuth(h.Users, h.Tokens), h.MeHandler)
+	}
+
+	// Registration stays public; every other route needs an access token.
+	if s.cfg.Features.Registration {
+		r.POST("/users/", h.CreateUserHandler)
+	}
+
+	api := r.Group("", middleware.RequireAuth(h.Users, h.Tokens))
+
+	// Write routes are guarded per route; every group requires at least read access.
+	manageServices := middleware.RequirePermission(auth.ManageServices)
+	designForms := middleware.RequirePermission(auth.DesignForms)
+	manageUsers := middleware.RequirePermission(auth.ManageUsers)
+	manageNames := middleware.RequirePermission(auth.ManageReservedNames)
+
 	// Reserved Names
-	reservedName := r.Group("/reserved-name")
+	reservedName := api.Group("/reserved-name", middleware.RequirePermission(auth.ReserveNames))
 	{
-		reservedName.POST("", handlers.CreateReservedNameHandler)
-		reservedName.GET("/:name", handlers.GetReservedNameHandler)
-		reservedName.DELETE("/:id", handlers.DeleteReservedNameHandler)
+		reservedName.GET("", h.ListReservedNamesHandler)
+		reservedName.POST("", h.CreateReservedNameHandler)
+		reservedName.GET("/:name", h.GetReservedNameHandler)
+		reservedName.POST("/:id/renew", h.RenewReservedNameHandler)
+		reservedName.POST("/:id/release", h.ReleaseReservedNameHandler)
+		reservedName.DELETE("/:id", manageNames, h.DeleteReservedNameHandler)
+	}
+
+	// Restricted Terms
+	restrictedTerms := api.Group("/restricted-terms", middleware.RequirePermission(auth.ReserveNames))
+	{
+		restrictedTerms.GET("", h.ListRestrictedTermsHandler)
+		restrictedTerms.GET("/:id", h.GetRestrictedTermHandler)
+		restrictedTerms.POST("", manageNames, h.CreateRestrictedTermHandler)
+		restrictedTerms.PUT("/:id", manageNames, h.UpdateRestrictedTermHandler)
+		restrictedTerms.DELETE("/:id", manageNames, h.DeleteRestrictedTermHandler)
 	}
 
 	// Services
-	services := r.Group("/services")
+	services := api.Group("/services", middleware.RequirePermission(auth.ViewCatalog))
+	{
+		services.GET("/:id", h.GetServiceHandler)
+		services.GET("/", h.ListServicesHandler)
+		services.POST("/", manageServices, h.AddServiceHandler)
+		services.PUT("/:id", manageServices, h.UpdateServiceHandler)
+		services.DELETE("/:id", manageServices, h.DeleteServiceHandler)
+		services.GET("/:id/workflow", h.GetServiceWorkflowHandler)
+		services.PUT("/:id/workflow", manageServices, h.UpdateServiceWorkflowHandler)
+		services.GET("/:id/webhooks", manageServices, h.ListServiceWebhooksHandler)
+		services.POST("/:id/webhooks", manageServices, h.CreateServiceWebhookHandler)
+		services.GET("/:id/notification-templates", manageServices, h.ListNotificationTemplatesHandler)
+		services.PUT("/:id/notification-templates/:kind", manageServices, h.SaveNotificationTemplateHandler)
+		services.DELETE("/:id/notification-templates/:kind", manageServices, h.DeleteNotificationTemplateHandler)
+	}
+
+	// Webhooks and their delivery log
+	webhooks := api.Group("/webhooks", manageServices)
 	{
-		services.GET("/:id", handlers.GetServiceHandler)
-		services.GET("/", handlers.ListServicesHandler)
-		services.POST("/", handlers.AddServiceHandler)
-		services.PUT("/:id", handlers.UpdateServiceHandler)
-		services.DELETE("/:id", handlers.DeleteServiceHandler)
+		webhooks.GET("/:id", h.GetWebhookHandler)
+		webhooks.PUT("/:id", h.UpdateWebhookHandler)
+		webhooks.DELETE("/:id", h.DeleteWebhookHandler)
+		webhooks.GET("/:id/deliveries", h.ListWebhookDeliveriesHandler)
+		webhooks.POST("/:id/deliveries/:delivery_id/redeliver", h.RedeliverWebhookDeliveryHandler)
 	}
 
 	// Forms
-	form := r.Group("/form")
+	form := api.Group("/form", middleware.RequirePermission(auth.ViewCatalog))
 	{
-		form.POST("/", handlers.FormHandler)
-		form.GET("/:id", handlers.GetFormWithFieldsHandler)
-		form.PUT("/:id", handlers.UpdateFormHandler)
-		form.DELETE("/:id", handlers.DeleteFormHandler)
+		form.GET("/", h.ListFormsHandler)
+		form.POST("/", designForms, h.FormHandler)
+		form.GET("/:id", h.GetFormWithFieldsHandler)
+		form.GET("/:id/fields", h.ListFormFieldsHandler)
+		form.GET("/:id/versions", h.ListFormVersionsHandler)
+		form.POST("/:id/publish", designForms, h.PublishFormHandler)
+		form.PUT("/:id", designForms, h.UpdateFormHandler)
+		form.DELETE("/:id", designForms, h.DeleteFormHandler)
 	}
 
 	// Form Fields
-	formFields := r.Group("/form_fields")
+	formFields := api.Group("/form_fields", middleware.RequirePermission(auth.ViewCatalog))
 	{
-		formFields.POST("/", handlers.CreateFormFieldsHandler)
-		formFields.POST("/multiple", handlers.CreateMultipleFormFieldsHandler)
+		formFields.GET("/:id", h.GetFormFieldHandler)
+		formFields.POST("/", designForms, h.CreateFormFieldsHandler)
+		formFields.POST("/multiple", designForms, h.CreateMultipleFormFieldsHandler)
+		formFields.PUT("/:id/dependency", designForms, h.UpdateFormFieldDependencyHandler)
 	}
 
 	// Form Groups
-	formGroups := r.Group("/form_groups")
+	formGroups := api.Group("/form_groups", middleware.RequirePermission(auth.ViewCatalog))
 	{
-		formGroups.POST("/", handlers.CreateFormGroupHandler)
-		formGroups.GET("/:id", handlers.GetFormGroupHandler)
-		formGroups.GET("/", handlers.GetAllFormGroupsHandler)
-		formGroups.PUT("/:id", handlers.UpdateFormGroupHandler)
-		formGroups.DELETE("/:id", handlers.DeleteFormGroupHandler)
+		formGroups.POST("/", designForms, h.CreateFormGroupHandler)
+		formGroups.GET("/:id", h.GetFormGroupHandler)
+		formGroups.GET("/", h.GetAllFormGroupsHandler)
+		formGroups.PUT("/:id", designForms, h.UpdateFormGroupHandler)
+		formGroups.DELETE("/:id", designForms, h.DeleteFormGroupHandler)
 	}
 
 	// Fields
-	fields := r.Group("/field")
+	fields := api.Group("/field", middleware.RequirePermission(auth.ViewCatalog))
 	{
-		fields.GET("/:id", handlers.GetFieldHandler)
-		fields.POST("/", handlers.CreateFieldHandler)
-		fields.PUT("/:id", handlers.UpdateFieldHandler) // Changed PATCH to PUT for consistency, check Handler
-		fields.DELETE("/:id", handlers.DeleteFieldHandler)
+		fields.GET("/", h.ListFieldsHandler)
+		fields.GET("/:id", h.GetFieldHandler)
+		fields.POST("/", designForms, h.CreateFieldHandler)
+		fields.PUT("/:id", designForms, h.UpdateFieldHandler) // Changed PATCH to PUT for consistency, check Handler
+		fields.DELETE("/:id", designForms, h.DeleteFieldHandler)
 	}
 
 	// Groups
-	groups := r.Group("/groups")
+	groups := api.Group("/groups", middleware.RequirePermission(auth.ViewCatalog))
 	{
-		groups.GET("/:id", handlers.GetGroupByIDHandler)
-		groups.GET("/", handlers.GetAllGroupsHandler)
-		groups.POST("/", handlers.CreateGroupHandler)
-		groups.PUT("/:id", handlers.UpdateGroupHandler)
-		groups.DELETE("/:id", handlers.DeleteGroupHandler)
+		groups.GET("/:id", h.GetGroupByIDHandler)
+		groups.GET("/", h.GetAllGroupsHandler)
+		groups.POST("/", designForms, h.CreateGroupHandler)
+		groups.PUT("/:id", designForms, h.UpdateGroupHandler)
+		groups.DELETE("/:id", designForms, h.DeleteGroupHandler)
 	}
 
 	// Collections
-	collections := r.Group("/collections")
+	collections := api.Group("/collections", middleware.RequirePermission(auth.ViewCatalog))
 	{
-		collections.GET("/:id", handlers.GetCollectionHandler)
-		collections.GET("/", handlers.GetAllCollectionsHandler)
-		collections.POST("/", handlers.CreateCollectionHandler)
-		collections.PUT("/:id", handlers.UpdateCollectionHandler)
-		// collections.DELETE("/:id", handlers.DeleteCollectionHandler)
+		collections.GET("/:id", h.GetCollectionHandler)
+		collections.GET("/", h.GetAllCollectionsHandler)
+		collections.GET("/:id/items", h.GetCollectionItemsHandler)
+		collections.GET("/:id/tree", h.GetCollectionTreeHandler)
+		collections.GET("/:id/export", h.ExportCollectionHandler)
+		collections.POST("/:id/import", designForms, h.ImportCollectionHandler)
+		collections.POST("/", designForms, h.CreateCollectionHandler)
+		collections.PUT("/:id", designForms, h.UpdateCollectionHandler)
+		// collections.DELETE("/:id", h.DeleteCollectionHandler)
 	}
 
 	// Collection Items
-	collectionItems := r.Group("/collection_items")
+	collectionItems := api.Group("/collection_items", middleware.RequirePermission(auth.ViewCatalog))
 	{
-		collectionItems.GET("/:id", handlers.GetCollectionItemHandler)
-		collectionItems.POST("/", handlers.CreateCollectionItemHandler)
-		collectionItems.PUT("/:id", handlers.UpdateCollectionItemHandler)
-		// collectionItems.DELETE("/:id", handlers.DeleteCollectionItemHandler)
+		collectionItems.GET("/:id", h.GetCollectionItemHandler)
+		collectionItems.POST("/", designForms, h.CreateCollectionItemHandler)
+		collectionItems.PUT("/:id", designForms, h.UpdateCollectionItemHandler)
+		// collectionItems.DELETE("/:id", h.DeleteCollectionItemHandler)
 	}
 
 	// Data Types
-	dataTypes := r.Group("/data_types")
+	dataTypes := api.Group("/data_types", middleware.RequirePermission(auth.ViewCatalog))
 	{
-		dataTypes.GET("/:id", handlers.GetDataTypeHandler)
-		dataTypes.GET("/", handlers.GetAllDataTypesHandler)
-		dataTypes.POST("/", handlers.CreateDataTypeHandler)
-		dataTypes.PUT("/:id", handlers.UpdateDataTypeHandler)
-		dataTypes.DELETE("/:id", handlers.DeleteDataTypeHandler)
+		dataTypes.GET("/:id", h.GetDataTypeHandler)
+		dataTypes.GET("/", h.GetAllDataTypesHandler)
+		dataTypes.POST("/", designForms, h.CreateDataTypeHandler)
+		dataTypes.PUT("/:id", designForms, h.UpdateDataTypeHandler)
+		dataTypes.DELETE("/:id", designForms, h.DeleteDataTypeHandler)
 	}
 
-	// Users
-	users := r.Group("/users")
+	// Users: anyone may read and edit their own profile; the handlers enforce that.
+	users := api.Group("/users")
 	{
-		users.GET("/:id", handlers.GetUserHandler)
-		users.POST("/", handlers.CreateUserHandler)
-		users.PUT("/:id", handlers.UpdateUserHandler)
-		users.DELETE("/:id", handlers.DeleteUserHandler)
+		users.GET("/", manageUsers, h.ListUsersHandler)
+		users.GET("/:id", h.GetUserHandler)
+		users.PUT("/:id", h.UpdateUserHandler)
+		users.DELETE("/:id", manageUsers, h.DeleteUserHandler)
+		users.PUT("/:id/role", manageUsers, h.UpdateUserRoleHandler)
+		users.GET("/:id/services", manageUsers, h.ListReviewerServicesHandler)
+		users.POST("/:id/services", manageUsers, h.AssignReviewerServiceHandler)
+		users.DELETE("/:id/services/:service_id", manageUsers, h.RemoveReviewerServiceHandler)
 	}
 
-	// Submissions
-	submissions := r.Group("/submission")
+	// Submissions: handlers further limit applicants to their own rows and reviewers to their services.
+	submissions := api.Group("/submission", middleware.RequirePermission(auth.ViewSubmissions))
 	{
-		submissions.POST("/", handlers.SubmitFormHandler)
-		submissions.GET("/:id", handlers.GetSubmissionHandler)
+		submitForms := middleware.RequirePermission(auth.SubmitForms)
+		submissions.GET("/", h.ListSubmissionsHandler)
+		submissions.POST("/", submitForms, h.SubmitFormHandler)
+		submissions.POST("/draft", submitForms, h.CreateDraftHandler)
+		submissions.GET("/drafts", submitForms, h.ListDraftsHandler)
+		submissions.PATCH("/:id/answers", submitForms, h.UpdateDraftAnswersHandler)
+		submissions.POST("/:id/submit", submitForms, h.SubmitDraftHandler)
+		submissions.GET("/:id", h.GetSubmissionHandler)
+		submissions.POST("/:id/transition", h.TransitionSubmissionHandler)
+		submissions.GET("/:id/history", h.GetSubmissionHistoryHandler)
+		submissions.GET("/:id/answers", h.ListSubmissionAnswersHandler)
+		submissions.GET("/:id/attachments", h.ListSubmissionAttachmentsHandler)
 		// Changed path to service/:service_id as discussed in handler update logic
-		submissions.GET("/service/:service_id", handlers.GetSubmissionsByFormIDHandler)
+		submissions.GET("/service/:service_id", h.GetSubmissionsByFormIDHandler)
+	}
+
+	// Attachments
+	attachments := api.Group("/attachments", middleware.RequirePermission(auth.ViewSubmissions))
+	{
+		attachments.POST("", middleware.RequirePermission(auth.SubmitForms), h.UploadAttachmentHandler)
+		attachments.GET("/:id", h.GetAttachmentHandler)
+		attachments.GET("/:id/content", h.DownloadAttachmentHandler)
 	}
 
 	return r
@@ -1,39 +1,84 @@
 package server
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
-	"os"
-	"strconv"
-	"time"
-
-	_ "github.com/joho/godotenv/autoload"
 
+	"kora_1/internal/config"
 	"kora_1/internal/database"
+	"kora_1/internal/handlers"
+	"kora_1/internal/notify"
+	"kora_1/internal/storage"
+	"kora_1/internal/webhooks"
+
+	"github.com/gin-gonic/gin"
 )
 
 type Server struct {
-	port int
+	cfg *config.Config
 
-	db database.Service
+	db  database.Service
+	api *handlers.Handler
 }
 
-func NewServer() *http.Server {
-	port, _ := strconv.Atoi(os.Getenv("PORT"))
+func NewServer(cfg *config.Config) (*http.Server, error) {
+	if cfg.Log.Level == "debug" {
+		gin.SetMode(gin.DebugMode)
+	} else {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	db, err := database.Open(cfg.DB)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.EnsureSchema(context.Background(), db, cfg.Features.MigrateOnStart, cfg.Auth.AdminEmail); err != nil {
+		return nil, err
+	}
+	files, err := storage.New(cfg.Storage)
+	if err != nil {
+		return nil, err
+	}
+
 	NewServer := &Server{
-		port: port,
+		cfg: cfg,
 
-		db: database.New(),
+		db:  database.New(db),
+		api: handlers.New(cfg, db, files),
 	}
 
 	// Declare Server config
 	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", NewServer.port),
+		Addr:         fmt.Sprintf(":%d", cfg.Port),
 		Handler:      NewServer.RegisterRoutes(),
-		IdleTimeout:  time.Minute,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  cfg.Timeouts.Idle,
+		ReadTimeout:  cfg.Timeouts.Read,
+		WriteTimeout: cfg.Timeouts.Write,
+		ErrorLog:     slog.NewLogLogger(slog.Default().Handler(), slog.LevelError),
+	}
+
+	// Background jobs stop when the server shuts down.
+	if interval := cfg.Reservations.ExpiryInterval; interval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		server.RegisterOnShutdown(cancel)
+		go every(ctx, interval, "expire reservations", NewServer.expireReservations)
+	}
+	if interval := cfg.Webhooks.DispatchInterval; interval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		server.RegisterOnShutdown(cancel)
+		go every(ctx, interval, "dispatch webhooks", webhooks.NewDispatcher(db, cfg.Webhooks).Run)
+	}
+	if interval := cfg.Mail.SendInterval; interval > 0 {
+		mailer, err := notify.NewMailer(cfg.Mail)
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		server.RegisterOnShutdown(cancel)
+		go every(ctx, interval, "send notifications", notify.NewSender(db, mailer, cfg.Mail).Run)
 	}
 
-	return server
+	return server, nil
 }
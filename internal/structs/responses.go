@@ -6,11 +6,41 @@ type SuccessResponse[T any] struct {
 	Status  bool   `json:"status"`
 	Message string `json:"message,omitempty"`
 	Data    T      `json:"data,omitempty"`
+	Meta    *Meta  `json:"meta,omitempty"`
 }
 
-// ErrorResponse handles standard error structures
+// Meta describes the page of a list response
+type Meta struct {
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"` // Pass as cursor to fetch the following page
+}
+
+// ErrorResponse handles standard error structures. Code is stable across
+// releases, unlike Error, and is what clients should branch on.
 type ErrorResponse struct {
-	Status bool   `json:"status"`
-	Error  string `json:"error"`
-	Code   int    `json:"code,omitempty"`
-}
\ No newline at end of file
+	Status  bool         `json:"status"`
+	Error   string       `json:"error"`
+	Code    string       `json:"code" example:"FORM_NOT_FOUND"`
+	Details []FieldError `json:"details,omitempty"`
+	Rows    []RowError   `json:"rows,omitempty"`
+
+	RequestID string `json:"request_id,omitempty"` // Quote when reporting a problem; it finds the request in the logs
+}
+
+// FieldError describes a single form field, or request body field, that
+// failed a validation rule
+type FieldError struct {
+	FormFieldID uint   `json:"form_field_id,omitempty"`
+	Field       string `json:"field,omitempty"` // JSON name of the request body field
+	Rule        string `json:"rule"`
+	Message     string `json:"message"`
+}
+
+// RowError describes a row of a bulk import that could not be applied
+type RowError struct {
+	Row     int    `json:"row"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,386 @@
+// Package validation interprets the rule strings stored in FormFields.Validation
+// and evaluates them against submitted answers.
+//
+// Rules are separated by "|" and take an optional argument after ":".
+//
+//	required                 answer must not be blank
+//	min_length:3             at least 3 characters
+//	max_length:50            at most 50 characters
+//	min:0                    numeric value >= 0
+//	max:100                  numeric value <= 100
+//	date_min:2020-01-01      date (YYYY-MM-DD) on or after the given day
+//	date_max:2030-12-31      date (YYYY-MM-DD) on or before the given day
+//	email                    a single email address
+//	phone                    a phone number (digits, spaces, "+", "-", "(", ")")
+//	in_collection            an item ID of the field's collection
+//	in_collection:4          an item ID of collection 4
+//	max_size:5MB             uploaded file at most 5 MB (B, KB, MB or GB)
+//	mime:application/pdf,image/*  uploaded file of one of the listed content types
+//	regex:^[A-Z]{2}[0-9]+$   must match the pattern
+//
+// Because patterns may contain "|", regex must be the last rule; everything
+// after "regex:" is taken as the pattern. max_size and mime apply to the file
+// when it is uploaded (see ValidateFile) rather than to the stored answer.
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	Required     = "required"
+	MinLength    = "min_length"
+	MaxLength    = "max_length"
+	Min          = "min"
+	Max          = "max"
+	DateMin      = "date_min"
+	DateMax      = "date_max"
+	Email        = "email"
+	Phone        = "phone"
+	InCollection = "in_collection"
+	MaxSize      = "max_size"
+	Mime         = "mime"
+	Regex        = "regex"
+)
+
+const dateLayout = "2006-01-02"
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9 ()\-]{7,20}$`)
+
+// Rule is a single parsed validation rule.
+type Rule struct {
+	Name string
+	Arg  string
+
+	length int
+	number float64
+	date   time.Time
+	id     uint
+	size   int64
+	types  []string
+	re     *regexp.Regexp
+}
+
+// Rules is the parsed form of a FormFields.Validation string.
+type Rules []Rule
+
+// Failure describes a rule an answer did not satisfy.
+type Failure struct {
+	Rule    string
+	Message string
+}
+
+// CollectionChecker reports whether value identifies an item of the collection.
+type CollectionChecker func(collectionID uint, value string) (bool, error)
+
+// Env carries what rules need beyond the answer itself.
+type Env struct {
+	// CollectionID is the collection of the underlying Field, used by a bare in_collection rule.
+	CollectionID *uint
+	InCollection CollectionChecker
+}
+
+// Parse parses a rule string. An empty string yields no rules.
+func Parse(spec string) (Rules, error) {
+	var rules Rules
+	rest := strings.TrimSpace(spec)
+	for rest != "" {
+		var part string
+		if strings.HasPrefix(rest, Regex+":") {
+			part, rest = rest, ""
+		} else if i := strings.IndexByte(rest, '|'); i >= 0 {
+			part, rest = rest[:i], rest[i+1:]
+		} else {
+			part, rest = rest, ""
+		}
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		rule, err := parseRule(part)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseRule(part string) (Rule, error) {
+	name, arg, hasArg := strings.Cut(part, ":")
+	rule := Rule{Name: strings.TrimSpace(name), Arg: arg}
+	if rule.Name != Regex {
+		rule.Arg = strings.TrimSpace(arg)
+	}
+
+	needsArg := func() error {
+		if !hasArg || rule.Arg == "" {
+			return fmt.Errorf("rule %q requires an argument", rule.Name)
+		}
+		return nil
+	}
+
+	switch rule.Name {
+	case Required, Email, Phone:
+		if hasArg {
+			return rule, fmt.Errorf("rule %q does not take an argument", rule.Name)
+		}
+	case MinLength, MaxLength:
+		if err := needsArg(); err != nil {
+			return rule, err
+		}
+		n, err := strconv.Atoi(rule.Arg)
+		if err != nil || n < 0 {
+			return rule, fmt.Errorf("rule %q expects a non-negative integer, got %q", rule.Name, rule.Arg)
+		}
+		rule.length = n
+	case Min, Max:
+		if err := needsArg(); err != nil {
+			return rule, err
+		}
+		n, err := strconv.ParseFloat(rule.Arg, 64)
+		if err != nil {
+			return rule, fmt.Errorf("rule %q expects a number, got %q", rule.Name, rule.Arg)
+		}
+		rule.number = n
+	case DateMin, DateMax:
+		if err := needsArg(); err != nil {
+			return rule, err
+		}
+		d, err := time.Parse(dateLayout, rule.Arg)
+		if err != nil {
+			return rule, fmt.Errorf("rule %q expects a YYYY-MM-DD date, got %q", rule.Name, rule.Arg)
+		}
+		rule.date = d
+	case InCollection:
+		if hasArg {
+			id, err := strconv.ParseUint(rule.Arg, 10, 32)
+			if err != nil {
+				return rule, fmt.Errorf("rule %q expects a collection ID, got %q", rule.Name, rule.Arg)
+			}
+			rule.id = uint(id)
+		}
+	case MaxSize:
+		if err := needsArg(); err != nil {
+			return rule, err
+		}
+		size, err := parseSize(rule.Arg)
+		if err != nil {
+			return rule, fmt.Errorf("rule %q expects a size such as 512KB or 5MB, got %q", rule.Name, rule.Arg)
+		}
+		rule.size = size
+	case Mime:
+		if err := needsArg(); err != nil {
+			return rule, err
+		}
+		for _, t := range strings.Split(rule.Arg, ",") {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if major, minor, ok := strings.Cut(t, "/"); !ok || major == "" || minor == "" {
+				return rule, fmt.Errorf("rule %q expects content types such as image/png or image/*, got %q", rule.Name, rule.Arg)
+			}
+			rule.types = append(rule.types, t)
+		}
+	case Regex:
+		if err := needsArg(); err != nil {
+			return rule, err
+		}
+		re, err := regexp.Compile(rule.Arg)
+		if err != nil {
+			return rule, fmt.Errorf("rule %q has an invalid pattern: %v", rule.Name, err)
+		}
+		rule.re = re
+	default:
+		return rule, fmt.Errorf("unknown rule %q", rule.Name)
+	}
+	return rule, nil
+}
+
+// Has reports whether the rule set contains a rule with the given name.
+func (r Rules) Has(name string) bool {
+	for _, rule := range r {
+		if rule.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Without returns the rule set minus any rules with the given names.
+func (r Rules) Without(names ...string) Rules {
+	kept := make(Rules, 0, len(r))
+	for _, rule := range r {
+		if !slices.Contains(names, rule.Name) {
+			kept = append(kept, rule)
+		}
+	}
+	return kept
+}
+
+// Validate evaluates every rule against value and returns the failures.
+// A blank value only fails "required"; the remaining rules apply to non-blank answers.
+// The returned error is reserved for lookups that could not be performed.
+func (r Rules) Validate(value string, env Env) ([]Failure, error) {
+	var failures []Failure
+	if strings.TrimSpace(value) == "" {
+		if r.Has(Required) {
+			failures = append(failures, Failure{Rule: Required, Message: "This field is required"})
+		}
+		return failures, nil
+	}
+
+	for _, rule := range r {
+		msg, err := rule.check(value, env)
+		if err != nil {
+			return nil, err
+		}
+		if msg != "" {
+			failures = append(failures, Failure{Rule: rule.Name, Message: msg})
+		}
+	}
+	return failures, nil
+}
+
+func (rule Rule) check(value string, env Env) (string, error) {
+	switch rule.Name {
+	case MinLength:
+		if utf8.RuneCountInString(value) < rule.length {
+			return fmt.Sprintf("Must be at least %d characters", rule.length), nil
+		}
+	case MaxLength:
+		if utf8.RuneCountInString(value) > rule.length {
+			return fmt.Sprintf("Must be at most %d characters", rule.length), nil
+		}
+	case Min, Max:
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return "Must be a number", nil
+		}
+		if rule.Name == Min && n < rule.number {
+			return fmt.Sprintf("Must be at least %s", rule.Arg), nil
+		}
+		if rule.Name == Max && n > rule.number {
+			return fmt.Sprintf("Must be at most %s", rule.Arg), nil
+		}
+	case DateMin, DateMax:
+		d, err := time.Parse(dateLayout, strings.TrimSpace(value))
+		if err != nil {
+			return "Must be a date in YYYY-MM-DD format", nil
+		}
+		if rule.Name == DateMin && d.Before(rule.date) {
+			return fmt.Sprintf("Must be on or after %s", rule.Arg), nil
+		}
+		if rule.Name == DateMax && d.After(rule.date) {
+			return fmt.Sprintf("Must be on or before %s", rule.Arg), nil
+		}
+	case Email:
+		addr, err := mail.ParseAddress(value)
+		if err != nil || addr.Address != strings.TrimSpace(value) {
+			return "Must be a valid email address", nil
+		}
+	case Phone:
+		if !phonePattern.MatchString(value) || countDigits(value) < 7 {
+			return "Must be a valid phone number", nil
+		}
+	case InCollection:
+		collectionID := rule.id
+		if collectionID == 0 && env.CollectionID != nil {
+			collectionID = *env.CollectionID
+		}
+		if collectionID == 0 {
+			return "", fmt.Errorf("rule %q has no collection to check against", rule.Name)
+		}
+		if env.InCollection == nil {
+			return "", fmt.Errorf("rule %q cannot be checked without a collection lookup", rule.Name)
+		}
+		ok, err := env.InCollection(collectionID, strings.TrimSpace(value))
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "Must be one of the available options", nil
+		}
+	case Regex:
+		if !rule.re.MatchString(value) {
+			return "Has an invalid format", nil
+		}
+	}
+	return "", nil
+}
+
+// ValidateFile evaluates the max_size and mime rules against an uploaded file.
+func (r Rules) ValidateFile(size int64, contentType string) []Failure {
+	var failures []Failure
+	contentType = strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+	for _, rule := range r {
+		switch rule.Name {
+		case MaxSize:
+			if size > rule.size {
+				failures = append(failures, Failure{Rule: MaxSize, Message: fmt.Sprintf("File must be at most %s", rule.Arg)})
+			}
+		case Mime:
+			if !matchesType(contentType, rule.types) {
+				failures = append(failures, Failure{Rule: Mime, Message: fmt.Sprintf("File must be of type %s", strings.Join(rule.types, ", "))})
+			}
+		}
+	}
+	return failures
+}
+
+// MaxFileSize returns the limit of the max_size rule, if there is one.
+func (r Rules) MaxFileSize() (int64, bool) {
+	for _, rule := range r {
+		if rule.Name == MaxSize {
+			return rule.size, true
+		}
+	}
+	return 0, false
+}
+
+func matchesType(contentType string, types []string) bool {
+	for _, t := range types {
+		if t == contentType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok && strings.HasPrefix(contentType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func parseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		bytes  int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if n, ok := strings.CutSuffix(s, unit.suffix); ok {
+			s, multiplier = strings.TrimSpace(n), unit.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * multiplier, nil
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,145 @@
+package validation
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	rules, err := Parse("required|min_length:2|max_length:10|regex:^(a|b)+$")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(rules) != 4 {
+		t.Fatalf("expected 4 rules, got %d", len(rules))
+	}
+	if rules[3].Name != Regex || rules[3].Arg != "^(a|b)+$" {
+		t.Fatalf("regex rule not parsed as trailing rule: %+v", rules[3])
+	}
+
+	invalid := []string{
+		"unknown",
+		"min_length",
+		"min_length:abc",
+		"min:ten",
+		"date_min:01/02/2020",
+		"required:yes",
+		"regex:(",
+		"in_collection:x",
+	}
+	for _, spec := range invalid {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("expected Parse(%q) to fail", spec)
+		}
+	}
+
+	if rules, err := Parse(""); err != nil || len(rules) != 0 {
+		t.Fatalf("expected empty spec to yield no rules, got %v, %v", rules, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	collectionID := uint(7)
+	env := Env{
+		CollectionID: &collectionID,
+		InCollection: func(id uint, value string) (bool, error) {
+			return id == 7 && value == "3", nil
+		},
+	}
+
+	tests := []struct {
+		spec  string
+		value string
+		fails []string
+	}{
+		{"required", "", []string{Required}},
+		{"required", "  ", []string{Required}},
+		{"min_length:3", "", nil},
+		{"min_length:3|max_length:5", "ab", []string{MinLength}},
+		{"min_length:3|max_length:5", "abcdef", []string{MaxLength}},
+		{"min:1|max:10", "11", []string{Max}},
+		{"min:1|max:10", "0.5", []string{Min}},
+		{"min:1", "abc", []string{Min}},
+		{"date_min:2020-01-01|date_max:2020-12-31", "2021-01-01", []string{DateMax}},
+		{"date_min:2020-01-01|date_max:2020-12-31", "2020-06-15", nil},
+		{"email", "someone@example.com", nil},
+		{"email", "Someone <someone@example.com>", []string{Email}},
+		{"phone", "+260 97 123 4567", nil},
+		{"phone", "12-34", []string{Phone}},
+		{"in_collection", "3", nil},
+		{"in_collection", "4", []string{InCollection}},
+		{"in_collection:8", "3", []string{InCollection}},
+		{"regex:^[A-Z]{2}[0-9]+$", "AB123", nil},
+		{"regex:^[A-Z]{2}[0-9]+$", "ab123", []string{Regex}},
+	}
+
+	for _, tt := range tests {
+		rules, err := Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", tt.spec, err)
+		}
+		failures, err := rules.Validate(tt.value, env)
+		if err != nil {
+			t.Fatalf("Validate(%q, %q) returned error: %v", tt.spec, tt.value, err)
+		}
+		if len(failures) != len(tt.fails) {
+			t.Errorf("Validate(%q, %q) = %+v, want failures %v", tt.spec, tt.value, failures, tt.fails)
+			continue
+		}
+		for i, f := range failures {
+			if f.Rule != tt.fails[i] {
+				t.Errorf("Validate(%q, %q) failure %d = %q, want %q", tt.spec, tt.value, i, f.Rule, tt.fails[i])
+			}
+		}
+	}
+}
+
+func TestWithout(t *testing.T) {
+	rules, err := Parse("required|min_length:3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	draft := rules.Without(Required)
+	if draft.Has(Required) || !draft.Has(MinLength) {
+		t.Errorf("Without(required) = %+v, want only min_length", draft)
+	}
+	if !rules.Has(Required) {
+		t.Error("Without modified the original rules")
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	rules, err := Parse("required|max_size:1KB|mime:application/pdf,image/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		size        int64
+		contentType string
+		fails       []string
+	}{
+		{512, "application/pdf", nil},
+		{1024, "image/png", nil},
+		{1025, "image/jpeg", []string{MaxSize}},
+		{10, "text/plain; charset=utf-8", []string{Mime}},
+	}
+	for _, tt := range tests {
+		failures := rules.ValidateFile(tt.size, tt.contentType)
+		if len(failures) != len(tt.fails) {
+			t.Errorf("ValidateFile(%d, %q) = %+v, want %v", tt.size, tt.contentType, failures, tt.fails)
+			continue
+		}
+		for i, f := range failures {
+			if f.Rule != tt.fails[i] {
+				t.Errorf("ValidateFile(%d, %q) failure %d = %q, want %q", tt.size, tt.contentType, i, f.Rule, tt.fails[i])
+			}
+		}
+	}
+
+	if _, err := Parse("max_size:lots"); err == nil {
+		t.Error("Parse(max_size:lots) succeeded, want error")
+	}
+	if _, err := Parse("mime:pdf"); err == nil {
+		t.Error("Parse(mime:pdf) succeeded, want error")
+	}
+}
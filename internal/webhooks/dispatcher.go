@@ -0,0 +1,179 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"kora_1/internal/config"
+	"kora_1/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// batchSize is how many events a pass fans out and how many deliveries it
+// sends at a time.
+const batchSize = 20
+
+// Dispatcher sends the deliveries of outbox events. Several instances may run
+// against one database; each delivery is claimed by one of them at a time.
+type Dispatcher struct {
+	db          *gorm.DB
+	client      *http.Client
+	maxAttempts int
+	lease       time.Duration
+}
+
+// NewDispatcher returns a dispatcher using db and the webhook settings.
+func NewDispatcher(db *gorm.DB, cfg config.Webhooks) *Dispatcher {
+	return &Dispatcher{
+		db: db,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			// A redirect is an answer like any other non-2xx: it fails the attempt.
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		maxAttempts: cfg.MaxAttempts,
+		// Deliveries of a batch are sent together, so a claim outlives the
+		// slowest of them with room to record the outcome.
+		lease: 2*cfg.Timeout + time.Minute,
+	}
+}
+
+// Run fans out the events recorded since the last run into deliveries, then
+// sends the deliveries that are due.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	db := d.db.WithContext(ctx)
+	for {
+		handled, err := models.FanOutWebhookEvents(db, time.Now(), batchSize)
+		if err != nil {
+			return err
+		}
+		if handled < batchSize {
+			break
+		}
+	}
+
+	for ctx.Err() == nil {
+		deliveries, err := models.ClaimWebhookDeliveries(db, time.Now(), d.lease, batchSize)
+		if err != nil {
+			return err
+		}
+
+		var (
+			wg   sync.WaitGroup
+			mu   sync.Mutex
+			errs []error
+		)
+		for i := range deliveries {
+			wg.Add(1)
+			go func(delivery *models.WebhookDelivery) {
+				defer wg.Done()
+				if err := d.attempt(ctx, db, delivery); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}(&deliveries[i])
+		}
+		wg.Wait()
+		if err := errors.Join(errs...); err != nil {
+			return err
+		}
+		if len(deliveries) < batchSize {
+			break
+		}
+	}
+	return nil
+}
+
+// attempt sends delivery once and records the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, db *gorm.DB, delivery *models.WebhookDelivery) error {
+	now := time.Now()
+	status, err := send(ctx, d.client, delivery, now)
+	settle(delivery, now, status, err, d.maxAttempts)
+	return models.RecordWebhookAttempt(db, delivery)
+}
+
+// send POSTs delivery's event to its webhook and returns the status of the
+// answer, or nil and an error when there was none.
+func send(ctx context.Context, client *http.Client, delivery *models.WebhookDelivery, now time.Time) (*int, error) {
+	body, err := Body(delivery.Event)
+	if err != nil {
+		return nil, err
+	}
+	timestamp := now.Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "kora-webhooks")
+	req.Header.Set(EventHeader, delivery.Event.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Webhook.Secret, timestamp, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &resp.StatusCode, fmt.Errorf("receiver answered %s", resp.Status)
+	}
+	return &resp.StatusCode, nil
+}
+
+// maxErrorLength is the size of webhook_deliveries.last_error.
+const maxErrorLength = 500
+
+// lastError returns err's message as valid UTF-8 cut to at most
+// maxErrorLength bytes, without splitting a character; Postgres refuses
+// invalid UTF-8.
+func lastError(err error) string {
+	message := strings.ToValidUTF8(err.Error(), "\uFFFD")
+	if len(message) <= maxErrorLength {
+		return message
+	}
+	end := maxErrorLength
+	for end > 0 && !utf8.RuneStart(message[end]) {
+		end--
+	}
+	return message[:end]
+}
+
+// settle applies the outcome of an attempt made at now to delivery: it
+// succeeded, is retried after Backoff, or failed for good once maxAttempts
+// have been made.
+func settle(delivery *models.WebhookDelivery, now time.Time, status *int, err error, maxAttempts int) {
+	delivery.Attempts++
+	delivery.LastAttemptOn = &now
+	delivery.ResponseStatus = status
+
+	if err == nil {
+		delivery.Status = models.DeliverySucceeded
+		delivery.DeliveredOn = &now
+		delivery.LastError = ""
+		return
+	}
+
+	delivery.LastError = lastError(err)
+	if delivery.Attempts >= maxAttempts {
+		delivery.Status = models.DeliveryFailed
+		return
+	}
+	delivery.Status = models.DeliveryPending
+	delivery.NextAttemptOn = now.Add(Backoff(delivery.Attempts))
+}
@@ -0,0 +1,143 @@
+// Package webhooks tells the webhooks of a service what happens to its forms
+// and submissions.
+//
+// Enqueue writes an event to an outbox table in the transaction that causes
+// it, through an Outbox bound to that transaction, so an event is recorded
+// exactly when its change commits. A Dispatcher then creates a delivery of
+// each event for every webhook that subscribes to it and POSTs the
+// deliveries, retrying failures with exponential backoff. Every body is
+// signed with the webhook's secret. A receiver may get the same delivery more
+// than once and should use the X-Kora-Delivery header to ignore repeats.
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"time"
+
+	"kora_1/internal/models"
+)
+
+// Events.
+const (
+	SubmissionCreated       = "submission.created"        // A form was submitted in one step; submitting a draft changes its status instead
+	SubmissionStatusChanged = "submission.status_changed" // A submission moved through its workflow
+	FormPublished           = "form.published"            // A new version of a form was published
+)
+
+// Events lists every event a webhook can subscribe to.
+var Events = []string{SubmissionCreated, SubmissionStatusChanged, FormPublished}
+
+// Valid reports whether event is one of Events.
+func Valid(event string) bool {
+	return slices.Contains(Events, event)
+}
+
+// Request headers of a delivery.
+const (
+	EventHeader     = "X-Kora-Event"
+	DeliveryHeader  = "X-Kora-Delivery"  // ID of the delivery, the same on every attempt
+	TimestampHeader = "X-Kora-Timestamp" // Unix time of the attempt, part of the signature
+	SignatureHeader = "X-Kora-Signature" // sha256= and the hex HMAC of timestamp, "." and body
+)
+
+// Outbox stores events until a Dispatcher fans them out.
+type Outbox interface {
+	Enqueue(ctx context.Context, event *models.WebhookEvent) error
+}
+
+// Enqueue records event in the outbox for the webhooks of the service. Pass
+// an outbox bound to the transaction that makes the change the event reports.
+// data is sent as the payload's data and should identify what changed rather
+// than copy it.
+func Enqueue(ctx context.Context, outbox Outbox, event string, serviceID *uint, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return outbox.Enqueue(ctx, &models.WebhookEvent{Event: event, ServiceID: serviceID, Payload: string(payload)})
+}
+
+// SubmissionData is the data of SubmissionCreated.
+type SubmissionData struct {
+	SubmissionID  uint   `json:"submission_id"`
+	ServiceID     *uint  `json:"service_id"`
+	FormVersionID *uint  `json:"form_version_id"`
+	Status        string `json:"status"`
+	CreatedBy     *uint  `json:"created_by"`
+}
+
+// StatusChangeData is the data of SubmissionStatusChanged.
+type StatusChangeData struct {
+	SubmissionID uint   `json:"submission_id"`
+	ServiceID    *uint  `json:"service_id"`
+	FromStatus   string `json:"from_status"`
+	ToStatus     string `json:"to_status"`
+	Comment      string `json:"comment,omitempty"`
+	ChangedBy    uint   `json:"changed_by"`
+}
+
+// FormData is the data of FormPublished.
+type FormData struct {
+	FormID        uint `json:"form_id"`
+	FormVersionID uint `json:"form_version_id"`
+	Version       int  `json:"version"`
+}
+
+// Payload is the body of every delivery.
+type Payload struct {
+	ID        uint            `json:"id"` // ID of the event, shared by its deliveries to different webhooks
+	Event     string          `json:"event"`
+	CreatedOn time.Time       `json:"created_on"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Body returns the JSON body delivered for event. It is the same on every attempt.
+func Body(event *models.WebhookEvent) ([]byte, error) {
+	return json.Marshal(Payload{ID: event.ID, Event: event.Event, CreatedOn: event.CreatedOn.UTC(), Data: json.RawMessage(event.Payload)})
+}
+
+// Sign returns the signature header of body sent at timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of body sent at timestamp.
+// Receivers should also reject timestamps too far from their own clock.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+// NewSecret returns a random signing secret.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+const (
+	firstRetry = 30 * time.Second
+	maxRetry   = 6 * time.Hour
+)
+
+// Backoff returns how long to wait after the attempts-th failed attempt:
+// 30 seconds, doubling with each attempt, up to 6 hours.
+func Backoff(attempts int) time.Duration {
+	wait := firstRetry
+	for i := 1; i < attempts && wait < maxRetry; i++ {
+		wait *= 2
+	}
+	return min(wait, maxRetry)
+}
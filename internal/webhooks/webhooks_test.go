@@ -0,0 +1,158 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"kora_1/internal/config"
+	"kora_1/internal/models"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	signature := Sign("secret", 1700000000, body)
+
+	// Computed independently with: printf '1700000000.{"id":1}' | openssl dgst -sha256 -hmac secret
+	if want := "sha256=3dd1b9aef568d75f6790a84bd2e5dfa1f44409eef3cbdbd3f10b837376100c11"; signature != want {
+		t.Fatalf("got %q, want %q", signature, want)
+	}
+	if !Verify("secret", 1700000000, body, signature) {
+		t.Error("signature does not verify")
+	}
+	for name, ok := range map[string]bool{
+		"other secret":    Verify("other", 1700000000, body, signature),
+		"other timestamp": Verify("secret", 1700000001, body, signature),
+		"other body":      Verify("secret", 1700000000, []byte(`{"id":2}`), signature),
+	} {
+		if ok {
+			t.Errorf("%s verifies", name)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempts, want := range map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		8:  64 * time.Minute,
+		10: 256 * time.Minute,
+		11: 6 * time.Hour,
+		50: 6 * time.Hour,
+	} {
+		if got := Backoff(attempts); got != want {
+			t.Errorf("Backoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
+
+var testConfig = config.Webhooks{Timeout: time.Second, MaxAttempts: 3}
+
+func testDelivery(url string) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:      7,
+		Status:  models.DeliveryPending,
+		Webhook: &models.Webhook{URL: url, Secret: "secret"},
+		Event: &models.WebhookEvent{
+			ID:        3,
+			Event:     SubmissionCreated,
+			Payload:   `{"submission_id":12}`,
+			CreatedOn: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestSendSignsTheBody(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	status, err := send(context.Background(), srv.Client(), testDelivery(srv.URL), time.Unix(1700000000, 0))
+	if err != nil || status == nil || *status != http.StatusNoContent {
+		t.Fatalf("got %v, %v", status, err)
+	}
+
+	if got.Header.Get(EventHeader) != SubmissionCreated || got.Header.Get(DeliveryHeader) != "7" || got.Header.Get(TimestampHeader) != "1700000000" {
+		t.Errorf("headers: %v", got.Header)
+	}
+	timestamp, _ := strconv.ParseInt(got.Header.Get(TimestampHeader), 10, 64)
+	if !Verify("secret", timestamp, body, got.Header.Get(SignatureHeader)) {
+		t.Error("signature does not verify")
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.ID != 3 || payload.Event != SubmissionCreated || string(payload.Data) != `{"submission_id":12}` {
+		t.Errorf("got %+v", payload)
+	}
+}
+
+func TestSendFailsOnErrorStatusAndRedirect(t *testing.T) {
+	for _, code := range []int{http.StatusInternalServerError, http.StatusFound} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if code == http.StatusFound {
+				w.Header().Set("Location", "/elsewhere")
+			}
+			w.WriteHeader(code)
+		}))
+		client := NewDispatcher(nil, testConfig).client
+
+		status, err := send(context.Background(), client, testDelivery(srv.URL), time.Now())
+		if err == nil || status == nil || *status != code {
+			t.Errorf("%d: got %v, %v", code, status, err)
+		}
+		srv.Close()
+	}
+}
+
+func TestSettle(t *testing.T) {
+	now := time.Now()
+	ok, unavailable := http.StatusOK, http.StatusServiceUnavailable
+
+	delivery := testDelivery("")
+	settle(delivery, now, &unavailable, errors.New("receiver answered 503"), 3)
+	if delivery.Status != models.DeliveryPending || delivery.Attempts != 1 || !delivery.NextAttemptOn.Equal(now.Add(30*time.Second)) || *delivery.ResponseStatus != unavailable {
+		t.Errorf("after a first failure: %+v", delivery)
+	}
+
+	settle(delivery, now, &ok, nil, 3)
+	if delivery.Status != models.DeliverySucceeded || delivery.Attempts != 2 || delivery.DeliveredOn == nil || delivery.LastError != "" {
+		t.Errorf("after success: %+v", delivery)
+	}
+
+	delivery = testDelivery("")
+	for range 3 {
+		settle(delivery, now, nil, errors.New("connection refused"), 3)
+	}
+	if delivery.Status != models.DeliveryFailed || delivery.Attempts != 3 || delivery.ResponseStatus != nil || delivery.LastError != "connection refused" {
+		t.Errorf("after the last attempt: %+v", delivery)
+	}
+}
+
+func TestLastErrorKeepsCharactersWhole(t *testing.T) {
+	long := strings.Repeat("a", maxErrorLength-1) + "é and more"
+	got := lastError(errors.New(long))
+	if want := strings.Repeat("a", maxErrorLength-1); got != want {
+		t.Errorf("lastError cut a character: got %d bytes ending %q, want %d", len(got), got[len(got)-3:], len(want))
+	}
+
+	if got := lastError(errors.New("bad \xff byte")); !utf8.ValidString(got) {
+		t.Errorf("lastError(invalid UTF-8) = %q, want valid UTF-8", got)
+	}
+}
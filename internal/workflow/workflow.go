@@ -0,0 +1,148 @@
+// Package workflow implements the submission lifecycle: the statuses a
+// submission can be in and the transitions between them that a service allows.
+package workflow
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Submission statuses.
+const (
+	Draft       = "draft"
+	Submitted   = "submitted"
+	UnderReview = "under_review"
+	Approved    = "approved"
+	Rejected    = "rejected"
+	Returned    = "returned" // Returned to the applicant for correction
+)
+
+// Actors that may move a submission.
+const (
+	Applicant = "applicant" // The user who created the submission
+	Reviewer  = "reviewer"  // A reviewer assigned to the submission's service, or an admin
+)
+
+var ErrIllegalTransition = errors.New("transition is not allowed")
+
+// Statuses lists every status in lifecycle order.
+var Statuses = []string{Draft, Submitted, UnderReview, Approved, Rejected, Returned}
+
+// Transition allows an actor to move a submission from one status to another.
+type Transition struct {
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Actor           string `json:"actor"`
+	CommentRequired bool   `json:"comment_required"`
+}
+
+// Default is the lifecycle used by services that have not configured their own.
+var Default = []Transition{
+	{From: Draft, To: Submitted, Actor: Applicant},
+	{From: Submitted, To: UnderReview, Actor: Reviewer},
+	{From: UnderReview, To: Approved, Actor: Reviewer},
+	{From: UnderReview, To: Rejected, Actor: Reviewer, CommentRequired: true},
+	{From: UnderReview, To: Returned, Actor: Reviewer, CommentRequired: true},
+	{From: Returned, To: Submitted, Actor: Applicant},
+}
+
+// Machine is a validated set of transitions.
+type Machine struct {
+	transitions []Transition
+}
+
+// New validates a set of transitions and returns the machine they describe.
+// Every status and actor must be known, a transition may not loop back to its
+// own status, and each (from, to, actor) triple may appear only once.
+func New(transitions []Transition) (*Machine, error) {
+	seen := make(map[Transition]bool, len(transitions))
+	for i, t := range transitions {
+		switch {
+		case !IsStatus(t.From):
+			return nil, fmt.Errorf("transition %d: unknown status %q", i, t.From)
+		case !IsStatus(t.To):
+			return nil, fmt.Errorf("transition %d: unknown status %q", i, t.To)
+		case t.From == t.To:
+			return nil, fmt.Errorf("transition %d: %s cannot transition to itself", i, t.From)
+		case t.Actor != Applicant && t.Actor != Reviewer:
+			return nil, fmt.Errorf("transition %d: actor must be %q or %q", i, Applicant, Reviewer)
+		}
+
+		key := Transition{From: t.From, To: t.To, Actor: t.Actor}
+		if seen[key] {
+			return nil, fmt.Errorf("transition %d: %s -> %s for %s is listed twice", i, t.From, t.To, t.Actor)
+		}
+		seen[key] = true
+	}
+	return &Machine{transitions: append([]Transition(nil), transitions...)}, nil
+}
+
+// DefaultMachine returns the machine for the Default lifecycle.
+func DefaultMachine() *Machine {
+	return &Machine{transitions: Default}
+}
+
+// Transitions returns the machine's transitions.
+func (m *Machine) Transitions() []Transition {
+	return append([]Transition(nil), m.transitions...)
+}
+
+// Find returns the transition from one status to another that any of the
+// actors may take.
+func (m *Machine) Find(from, to string, actors ...string) (Transition, error) {
+	for _, t := range m.transitions {
+		if t.From != from || t.To != to {
+			continue
+		}
+		for _, actor := range actors {
+			if t.Actor == actor {
+				return t, nil
+			}
+		}
+	}
+	return Transition{}, ErrIllegalTransition
+}
+
+// Next lists the statuses the actors may move a submission to from its current status.
+func (m *Machine) Next(from string, actors ...string) []string {
+	var next []string
+	seen := make(map[string]bool)
+	for _, t := range m.transitions {
+		if t.From != from || seen[t.To] {
+			continue
+		}
+		for _, actor := range actors {
+			if t.Actor == actor {
+				next = append(next, t.To)
+				seen[t.To] = true
+				break
+			}
+		}
+	}
+	return next
+}
+
+// Submit returns the status a submission enters when its applicant submits it
+// from the given status: Submitted when the lifecycle allows it, otherwise the
+// only status the applicant may move it to.
+func (m *Machine) Submit(from string) (string, error) {
+	next := m.Next(from, Applicant)
+	for _, to := range next {
+		if to == Submitted {
+			return Submitted, nil
+		}
+	}
+	if len(next) != 1 {
+		return "", ErrIllegalTransition
+	}
+	return next[0], nil
+}
+
+func IsStatus(status string) bool {
+	for _, s := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package workflow
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDefaultMachine(t *testing.T) {
+	m := DefaultMachine()
+
+	tests := []struct {
+		from, to string
+		actors   []string
+		wantErr  bool
+	}{
+		{Draft, Submitted, []string{Applicant}, false},
+		{Draft, Submitted, []string{Reviewer}, true},
+		{Submitted, UnderReview, []string{Reviewer}, false},
+		{Submitted, Approved, []string{Reviewer}, true},
+		{UnderReview, Returned, []string{Applicant, Reviewer}, false},
+		{Returned, Submitted, []string{Applicant}, false},
+		{Approved, Submitted, []string{Applicant, Reviewer}, true},
+	}
+	for _, tt := range tests {
+		_, err := m.Find(tt.from, tt.to, tt.actors...)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Find(%s, %s, %v) error = %v, wantErr %v", tt.from, tt.to, tt.actors, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrIllegalTransition) {
+			t.Errorf("Find(%s, %s) error = %v, want ErrIllegalTransition", tt.from, tt.to, err)
+		}
+	}
+
+	if got, want := m.Next(UnderReview, Reviewer), []string{Approved, Rejected, Returned}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Next(under_review) = %v, want %v", got, want)
+	}
+	if got := m.Next(UnderReview, Applicant); got != nil {
+		t.Errorf("Next(under_review, applicant) = %v, want none", got)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	if got, err := DefaultMachine().Submit(Returned); got != Submitted || err != nil {
+		t.Errorf("default Submit(returned) = %q, %v, want %q", got, err, Submitted)
+	}
+
+	custom, err := New([]Transition{
+		{From: Draft, To: UnderReview, Actor: Applicant},
+		{From: Draft, To: Rejected, Actor: Reviewer},
+		{From: Returned, To: UnderReview, Actor: Applicant},
+		{From: Returned, To: Rejected, Actor: Applicant},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := custom.Submit(Draft); got != UnderReview || err != nil {
+		t.Errorf("Submit(draft) = %q, %v, want %q", got, err, UnderReview)
+	}
+	if _, err := custom.Submit(Returned); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("Submit(returned) with two applicant edges: error = %v, want ErrIllegalTransition", err)
+	}
+	if _, err := custom.Submit(Approved); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("Submit(approved) error = %v, want ErrIllegalTransition", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Default); err != nil {
+		t.Fatalf("New(Default) error = %v", err)
+	}
+
+	invalid := [][]Transition{
+		{{From: "pending", To: Submitted, Actor: Applicant}},
+		{{From: Submitted, To: Submitted, Actor: Reviewer}},
+		{{From: Submitted, To: Approved, Actor: "clerk"}},
+		{{From: Submitted, To: Approved, Actor: Reviewer}, {From: Submitted, To: Approved, Actor: Reviewer, CommentRequired: true}},
+	}
+	for _, transitions := range invalid {
+		if _, err := New(transitions); err == nil {
+			t.Errorf("New(%v) error = nil, want error", transitions)
+		}
+	}
+}